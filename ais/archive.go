@@ -14,9 +14,11 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/memsys"
 	"github.com/vmihailenco/msgpack"
 )
 
@@ -81,15 +83,19 @@ func (goi *getObjInfo) freadArch(file *os.File) (cos.ReadCloseSizer, error) {
 	if err != nil {
 		return nil, err
 	}
-	archname := filepath.Join(goi.lom.Bck().Name, goi.lom.ObjName)
-	filename := goi.archive.filename
+	return freadArch(goi.lom, file, mime, goi.archive.filename)
+}
+
+// read archived file by scanning the archive (compare with archOpen below)
+func freadArch(lom *cluster.LOM, file *os.File, mime, filename string) (cos.ReadCloseSizer, error) {
+	archname := filepath.Join(lom.Bck().Name, lom.ObjName)
 	switch mime {
 	case cos.ExtTar:
 		return freadTar(file, filename, archname)
 	case cos.ExtTarTgz, cos.ExtTgz:
 		return freadTgz(file, filename, archname)
 	case cos.ExtZip:
-		return freadZip(file, filename, archname, goi.lom.SizeBytes())
+		return freadZip(file, filename, archname, lom.SizeBytes())
 	case cos.ExtMsgpack:
 		return freadMsgpack(file, filename, archname)
 	default:
@@ -98,14 +104,50 @@ func (goi *getObjInfo) freadArch(file *os.File) (cos.ReadCloseSizer, error) {
 	}
 }
 
-func (goi *getObjInfo) mime(file *os.File) (m string, err error) {
+// open archived file via the archive's index or, if the archive cannot be indexed, by scanning it;
+// the caller must take (at least) a read lock
+func archOpen(lom *cluster.LOM, file *os.File, mime, filename string) (cos.ReadCloseSizer, error) {
+	idx, err := lom.ArchIdx(mime)
+	if err != nil {
+		if cluster.IsErrArchIdxUnsupported(err) {
+			return freadArch(lom, file, mime, filename)
+		}
+		return nil, err
+	}
+	entry := idx.Find(filename)
+	if entry == nil {
+		return nil, notFoundInArch(filename, filepath.Join(lom.Bck().Name, lom.ObjName))
+	}
+	return idx.Open(file, entry, 0, entry.Size)
+}
+
+// look up archived file in the archive's index (that gets built upon first access)
+func (goi *getObjInfo) archLookup(file *os.File) (idx *cluster.ArchIdx, entry *cluster.ArchIdxEntry, err error) {
+	mime, err := goi.mime(file)
+	if err != nil {
+		return
+	}
+	if idx, err = goi.lom.ArchIdx(mime); err != nil {
+		return
+	}
+	if entry = idx.Find(goi.archive.filename); entry == nil {
+		err = notFoundInArch(goi.archive.filename, filepath.Join(goi.lom.Bck().Name, goi.lom.ObjName))
+	}
+	return
+}
+
+func (goi *getObjInfo) mime(file *os.File) (string, error) {
+	return archMime(goi.archive.mime, goi.lom.ObjName, file, goi.t.smm)
+}
+
+func archMime(mime, objName string, file *os.File, smm *memsys.MMSA) (m string, err error) {
 	// either ok or non-empty user-defined mime type (that must work)
-	if m, err = cos.Mime(goi.archive.mime, goi.lom.ObjName); err == nil || goi.archive.mime != "" {
+	if m, err = cos.Mime(mime, objName); err == nil || mime != "" {
 		return
 	}
 	// otherwise, by magic
 	var (
-		buf, slab = goi.t.smm.AllocSize(sizeDetectMime)
+		buf, slab = smm.AllocSize(sizeDetectMime)
 		n         int
 	)
 	n, err = file.Read(buf)
@@ -117,7 +159,7 @@ func (goi *getObjInfo) mime(file *os.File) (m string, err error) {
 	}
 	if m == "" {
 		if err == nil {
-			err = cos.NewUnknownMimeError(goi.lom.ObjName)
+			err = cos.NewUnknownMimeError(objName)
 		} else {
 			err = cos.NewUnknownMimeError(err.Error())
 		}
@@ -30,8 +30,17 @@ import (
 
 type (
 	// TokenList is a list of tokens pushed by authn
+	// (when metasync-ed, it also includes the version of S3 access keys - the keys
+	// themselves are never metasync-ed: proxies pull them from the primary)
 	tokenList struct {
 		authn.TokenList
+		S3KeysVer int64 `json:"s3keys_ver,omitempty"`
+	}
+
+	// S3 access keys (with secrets) that proxies pull from the primary
+	s3KeysMsg struct {
+		Keys    []*authn.S3Key `json:"keys"`
+		Version int64          `json:"version"`
 	}
 
 	authList map[string]*authn.Token
@@ -43,7 +52,10 @@ type (
 		// list of invalid tokens(revoked or of deleted users)
 		// Authn sends these tokens to primary for broadcasting
 		revokedTokens map[string]bool
-		version       int64
+		// S3 access keys (to verify AWS SigV4 signed requests) - proxies only
+		s3keys  map[string]*authn.S3Key
+		s3ver   int64
+		version int64
 	}
 )
 
@@ -91,6 +103,56 @@ func (a *authManager) updateRevokedList(tokens *tokenList) {
 	a.Unlock()
 }
 
+// Replace all S3 access keys with the ones pushed by AuthN (primary)
+func (a *authManager) updateS3Keys(keys []*authn.S3Key) {
+	a.Lock()
+	a.version++
+	a.s3ver++
+	a._setS3Keys(keys)
+	a.Unlock()
+}
+
+// Replace all S3 access keys with the ones pulled from the primary
+func (a *authManager) setS3Keys(msg *s3KeysMsg) {
+	a.Lock()
+	if msg.Version > a.s3ver {
+		a.s3ver = msg.Version
+		a._setS3Keys(msg.Keys)
+	}
+	a.Unlock()
+}
+
+func (a *authManager) _setS3Keys(keys []*authn.S3Key) {
+	a.s3keys = make(map[string]*authn.S3Key, len(keys))
+	for _, key := range keys {
+		a.s3keys[key.AccessKey] = key
+	}
+}
+
+func (a *authManager) s3KeysVer() (ver int64) {
+	a.Lock()
+	ver = a.s3ver
+	a.Unlock()
+	return
+}
+
+func (a *authManager) s3KeyList() *s3KeysMsg {
+	a.Lock()
+	msg := &s3KeysMsg{Keys: make([]*authn.S3Key, 0, len(a.s3keys)), Version: a.s3ver}
+	for _, key := range a.s3keys {
+		msg.Keys = append(msg.Keys, key)
+	}
+	a.Unlock()
+	return msg
+}
+
+func (a *authManager) s3Key(accessKey string) (key *authn.S3Key, ok bool) {
+	a.Lock()
+	key, ok = a.s3keys[accessKey]
+	a.Unlock()
+	return
+}
+
 // Checks if a token is valid:
 //   - must not be revoked one
 //   - must not be expired
@@ -151,9 +213,9 @@ func (a *authManager) revokedTokenList() *tokenList {
 		tlist.Tokens[idx] = token
 		idx++
 	}
-
+	s3ver := a.s3ver
 	a.Unlock()
-	return &tokenList{TokenList: tlist}
+	return &tokenList{TokenList: tlist, S3KeysVer: s3ver}
 }
 
 // as revs
@@ -21,6 +21,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/authn"
@@ -34,6 +35,7 @@ import (
 	"github.com/NVIDIA/aistore/nl"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/sys"
+	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xact"
 	"github.com/NVIDIA/aistore/xact/xreg"
 	jsoniter "github.com/json-iterator/go"
@@ -81,7 +83,9 @@ type (
 			mtx  sync.RWMutex
 			pool nodeRegPool
 		}
-		qm lsobjMem
+		qm       lsobjMem
+		batches  sync.Map     // get-batch requests in progress (see prxbatch.go)
+		batchBuf atomic.Int64 // total size of the entries buffered by all get-batch requests
 	}
 )
 
@@ -118,6 +122,9 @@ func (p *proxy) init(config *cmn.Config) {
 	daemon.rg.add(ps)
 	p.statsT = ps
 
+	sc := transport.Init(ps, config) // init transport sub-system (to receive get-batch streams)
+	daemon.rg.add(sc)
+
 	k := newPalive(p, ps, startedUp)
 	daemon.rg.add(k)
 	p.keepalive = k
@@ -180,6 +187,7 @@ func (p *proxy) Run() error {
 	p.authn = &authManager{
 		tokens:        make(map[string]*authn.Token),
 		revokedTokens: make(map[string]bool),
+		s3keys:        make(map[string]*authn.S3Key),
 		version:       1,
 	}
 
@@ -201,17 +209,19 @@ func (p *proxy) Run() error {
 		{r: apc.Download, h: p.downloadHandler, net: accessNetPublic},
 		{r: apc.ETL, h: p.etlHandler, net: accessNetPublic},
 		{r: apc.Sort, h: p.dsortHandler, net: accessNetPublic},
+		{r: apc.Batch, h: p.batchHandler, net: accessNetPublic},
 
 		{r: apc.IC, h: p.ic.handler, net: accessNetIntraControl},
 		{r: apc.Daemon, h: p.daemonHandler, net: accessNetPublicControl},
 		{r: apc.Cluster, h: p.clusterHandler, net: accessNetPublicControl},
-		{r: apc.Tokens, h: p.tokenHandler, net: accessNetPublic},
+		{r: apc.Tokens, h: p.tokenHandler, net: accessNetPublicControl},
 
 		{r: apc.Metasync, h: p.metasyncHandler, net: accessNetIntraControl},
 		{r: apc.Health, h: p.healthHandler, net: accessNetPublicControl},
 		{r: apc.Vote, h: p.voteHandler, net: accessNetIntraControl},
 
 		{r: apc.Notifs, h: p.notifs.handler, net: accessNetIntraControl},
+		{r: apc.ObjStream, h: transport.RxAnyStream, net: accessControlData},
 
 		{r: "/", h: p.httpCloudHandler, net: accessNetPublic},
 
@@ -224,6 +234,9 @@ func (p *proxy) Run() error {
 		{r: "/" + apc.AISScheme, h: p.easyURLHandler, net: accessNetPublic},
 	}
 	p.registerNetworkHandlers(networkHandlers)
+	if err := transport.HandleObjStream(batchTrname, p.recvBatch); err != nil {
+		cos.ExitLogf("%s: failed to register %q: %v", p, batchTrname, err)
+	}
 
 	glog.Infof("%s: [%s net] listening on: %s", p, cmn.NetPublic, p.si.PublicNet.DirectURL)
 	if p.si.PublicNet.DirectURL != p.si.IntraControlNet.DirectURL {
@@ -673,6 +686,9 @@ func (p *proxy) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	if err := p.checkBypassGovernance(w, r); err != nil {
+		return
+	}
 	smap := p.owner.smap.get()
 	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -839,6 +855,9 @@ func (p *proxy) metasyncHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	if errTokens == nil && revokedTokens != nil {
 		p.authn.updateRevokedList(revokedTokens)
+		if revokedTokens.S3KeysVer > p.authn.s3KeysVer() {
+			go p.pullS3Keys()
+		}
 	}
 	// 3. respond
 	if errConf == nil && errSmap == nil && errBMD == nil && errRMD == nil && errTokens == nil && errEtlMD == nil {
@@ -1060,6 +1079,10 @@ func (p *proxy) hpostBucket(w http.ResponseWriter, r *http.Request, msg *apc.Act
 			p.writeErrf(w, r, "cannot rename bucket %q as %q", bckFrom, bckTo)
 			return
 		}
+		if bckFrom.Props.ObjLock.Enabled {
+			p.writeErrStatusf(w, r, http.StatusForbidden, "cannot rename bucket %q with object lock enabled", bckFrom)
+			return
+		}
 
 		bckFrom.Provider = apc.ProviderAIS
 		bckTo.Provider = apc.ProviderAIS
@@ -1301,6 +1324,31 @@ func (p *proxy) listObjects(w http.ResponseWriter, r *http.Request, bck *cluster
 		lsmsg.SetFlag(apc.LsPresent)
 	}
 
+	// Soft-deleted objects are listed from the local storage only (and never cached).
+	if lsmsg.IsFlagSet(apc.LsDeleted) {
+		if lsmsg.Filter != "" {
+			p.writeErrf(w, r, "%s: object filter %q cannot be used when listing deleted objects", bck, lsmsg.Filter)
+			return
+		}
+		lsmsg.SetFlag(apc.LsPresent)
+		lsmsg.Flags &^= apc.UseListObjsCache
+	}
+
+	// Object filter is evaluated by the targets against local metadata
+	// (and the list-objects cache is keyed by prefix only - see `lsmsg.Filter`).
+	if lsmsg.Filter != "" {
+		if lsmsg.IsFlagSet(apc.LsNameOnly) {
+			p.writeErrf(w, r, "%s: object filter %q cannot be used when listing names only", bck, lsmsg.Filter)
+			return
+		}
+		if _, err := cmn.ParseObjFilter(lsmsg.Filter); err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+		lsmsg.SetFlag(apc.LsPresent)
+		lsmsg.Flags &^= apc.UseListObjsCache
+	}
+
 	locationIsAIS := bck.IsAIS() || lsmsg.IsFlagSet(apc.LsPresent)
 	if lsmsg.UUID == "" {
 		var nl nl.NotifListener
@@ -1454,7 +1502,7 @@ func (p *proxy) httpobjpost(w http.ResponseWriter, r *http.Request) {
 	}
 	apireq := apiReqAlloc(1, apc.URLPathObjects.L, false)
 	defer apiReqFree(apireq)
-	if msg.Action == apc.ActRenameObject {
+	if msg.Action == apc.ActRenameObject || msg.Action == apc.ActUndeleteObject {
 		apireq.after = 2
 	}
 	if err := p.parseReq(w, r, apireq); err != nil {
@@ -1482,6 +1530,16 @@ func (p *proxy) httpobjpost(w http.ResponseWriter, r *http.Request) {
 		}
 		p.objMv(w, r, bck, apireq.items[1], msg)
 		return
+	case apc.ActUndeleteObject:
+		if err := p.checkACL(w, r, bck, apc.AcePUT); err != nil {
+			return
+		}
+		if !bck.IsAIS() || bck.IsRemote() {
+			p.writeErrActf(w, r, msg.Action, "not supported for buckets with remote backends (%s)", bck)
+			return
+		}
+		p.objUndelete(w, r, bck, apireq.items[1], msg)
+		return
 	case apc.ActPromote:
 		if err := p.checkACL(w, r, bck, apc.AcePromote); err != nil {
 			return
@@ -1648,6 +1706,21 @@ func (p *proxy) httpbckpatch(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if nprops.Replication.Enabled {
+		// replication destination must exist and must not be the bucket itself (or its backend)
+		dstBck, _ := nprops.Replication.DstBck() // (validated above)
+		if dstBck.Equal(bck.Bucket()) || dstBck.Equal(&nprops.BackendBck) {
+			p.writeErrf(w, r, "cannot replicate bucket %s onto itself (%s)", bck, dstBck)
+			return
+		}
+		dst := cluster.CloneBck(&dstBck)
+		args := bckInitArgs{p: p, w: w, r: r, bck: dst, msg: msg, dpq: apireq.dpq, query: apireq.query}
+		args.createAIS = false
+		args.lookupRemote = true
+		if _, err = args.initAndTry(dst.Name); err != nil {
+			return
+		}
+	}
 	if xactID, err = p.setBucketProps(msg, bck, nprops); err != nil {
 		p.writeErr(w, r, err)
 		return
@@ -1702,6 +1775,9 @@ func (p *proxy) httpobjpatch(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	if err := p.checkBypassGovernance(w, r); err != nil {
+		return
+	}
 	smap := p.owner.smap.get()
 	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -2178,6 +2254,22 @@ func (p *proxy) objMv(w http.ResponseWriter, r *http.Request, bck *cluster.Bck,
 	p.statsT.Add(stats.RenameCount, 1)
 }
 
+// redirect to the HRW target that (soft-)deleted the object - see cmn.SoftDelConf
+func (p *proxy) objUndelete(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string, msg *apc.ActionMsg) {
+	started := time.Now()
+	smap := p.owner.smap.get()
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%q %s/%s => %s", msg.Action, bck.Name, objName, si)
+	}
+	redirectURL := p.redirectURL(r, si, started, cmn.NetIntraControl)
+	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+}
+
 func (p *proxy) doListRange(method, bucket string, msg *apc.ActionMsg, query url.Values) (xactID string, err error) {
 	var (
 		smap   = p.owner.smap.get()
@@ -2705,8 +2797,12 @@ func (p *proxy) tokenHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodDelete:
 		p.httpTokenDelete(w, r)
+	case http.MethodPut:
+		p.httpS3KeysPut(w, r)
+	case http.MethodGet:
+		p.httpS3KeysGet(w, r)
 	default:
-		cmn.WriteErr405(w, r, http.MethodDelete)
+		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodPut, http.MethodGet)
 	}
 }
 
@@ -5,14 +5,19 @@
 package ais
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/authn"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	jsoniter "github.com/json-iterator/go"
 )
 
 func (p *proxy) httpTokenDelete(w http.ResponseWriter, r *http.Request) {
@@ -33,6 +38,90 @@ func (p *proxy) httpTokenDelete(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// PUT /v1/tokens/s3keys - AuthN pushes S3 access keys
+// (the request must carry AuthN-signed admin token - whether AuthN is enabled or not)
+func (p *proxy) httpS3KeysPut(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := p.checkRESTItems(w, r, 1, false, apc.URLPathTokens.L)
+	if err != nil {
+		return
+	}
+	if apiItems[0] != apc.S3Keys {
+		p.writeErrURL(w, r)
+		return
+	}
+	token, err := p.validateToken(r.Header)
+	if err == nil && !token.IsAdmin {
+		err = authn.ErrNoPermissions
+	}
+	if err != nil {
+		p.writeErr(w, r, err, p.aclErrToCode(err))
+		return
+	}
+	if p.forwardCP(w, r, nil, "update S3 access keys") {
+		return
+	}
+	list := &authn.S3KeyList{}
+	if err := cmn.ReadJSON(w, r, list); err != nil {
+		return
+	}
+	if list.Keys == nil {
+		list.Keys = []*authn.S3Key{}
+	}
+	p.authn.updateS3Keys(list.Keys)
+	if p.owner.smap.get().isPrimary(p.si) {
+		msg := p.newAmsgStr(apc.ActNewPrimary, nil)
+		_ = p.metasyncer.sync(revsPair{p.authn.revokedTokenList(), msg})
+	}
+}
+
+// GET /v1/tokens/s3keys - proxies pull S3 access keys from the primary
+// (see `tokenList.S3KeysVer`)
+func (p *proxy) httpS3KeysGet(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := p.checkRESTItems(w, r, 1, false, apc.URLPathTokens.L)
+	if err != nil {
+		return
+	}
+	if apiItems[0] != apc.S3Keys {
+		p.writeErrURL(w, r)
+		return
+	}
+	if err := p.isIntraCall(r.Header, false /*from primary*/); err != nil {
+		p.writeErr(w, r, err, http.StatusForbidden)
+		return
+	}
+	if caller := p.owner.smap.get().GetProxy(r.Header.Get(apc.HdrCallerID)); caller == nil {
+		p.writeErrf(w, r, "%s: S3 access keys can be only requested by proxies", p)
+		return
+	}
+	p.writeJSON(w, r, p.authn.s3KeyList(), "get-s3keys")
+}
+
+func (p *proxy) pullS3Keys() {
+	smap := p.owner.smap.get()
+	if smap.isPrimary(p.si) {
+		return
+	}
+	cargs := allocCargs()
+	{
+		cargs.si = smap.Primary
+		cargs.req = cmn.HreqArgs{Method: http.MethodGet, Path: apc.URLPathTokens.Join(apc.S3Keys)}
+		cargs.timeout = cmn.Timeout.CplaneOperation()
+	}
+	res := p.call(cargs)
+	freeCargs(cargs)
+	defer freeCR(res)
+	if res.err != nil {
+		glog.Errorf("%s: failed to pull S3 access keys from %s: %v", p, smap.Primary, res.err)
+		return
+	}
+	msg := &s3KeysMsg{}
+	if err := jsoniter.Unmarshal(res.bytes, msg); err != nil {
+		glog.Error(fmt.Errorf(cmn.FmtErrUnmarshal, p, "S3 access keys", cmn.BytesHead(res.bytes), err))
+		return
+	}
+	p.authn.setS3Keys(msg)
+}
+
 // Read a token from request header and validates it
 // Header format:
 //		'Authorization: Bearer <token>'
@@ -56,6 +145,14 @@ func (p *proxy) validateToken(hdr http.Header) (*authn.Token, error) {
 	return auth, nil
 }
 
+// bypassing object lock retention (governance mode) is admin-only - see cmn.ObjLockConf
+func (p *proxy) checkBypassGovernance(w http.ResponseWriter, r *http.Request) error {
+	if !cos.IsParseBool(r.Header.Get(apc.HdrBypassGovernance)) {
+		return nil
+	}
+	return p.checkACL(w, r, nil, apc.AceAdmin)
+}
+
 // When AuthN is on, accessing a bucket requires two permissions:
 //   - access to the bucket is granted to a user
 //   - bucket ACL allows the required operation
@@ -89,18 +186,26 @@ func (p *proxy) _checkACL(hdr http.Header, bck *cluster.Bck, ace apc.AccessAttrs
 		return nil
 	}
 	var (
-		token  *authn.Token
-		cfg    = cmn.GCO.Get()
-		bucket *cmn.Bck
-		err    error
+		token *authn.Token
+		cfg   = cmn.GCO.Get()
+		err   error
 	)
 	if cfg.Auth.Enabled {
 		token, err = p.validateToken(hdr)
 		if err != nil {
 			return err
 		}
-		uid := p.owner.smap.Get().UUID
+	}
+	return p.checkTokenACL(cfg, token, bck, ace)
+}
 
+// Given the token (nil when AuthN is disabled), check user and bucket permissions
+func (p *proxy) checkTokenACL(cfg *cmn.Config, token *authn.Token, bck *cluster.Bck, ace apc.AccessAttrs) error {
+	if cfg.Auth.Enabled {
+		var (
+			bucket *cmn.Bck
+			uid    = p.owner.smap.Get().UUID
+		)
 		if bck != nil {
 			bucket = (*cmn.Bck)(bck)
 		}
@@ -127,3 +232,42 @@ func (p *proxy) _checkACL(hdr http.Header, bck *cluster.Bck, ace apc.AccessAttrs
 	}
 	return bck.Allow(ace)
 }
+
+// S3 API: when AuthN is enabled, a request must be either signed with one of
+// the S3 access keys (AWS Signature Version 4) or carry a regular AuthN token.
+// The access key (or token) permissions are then checked the same way as in
+// the native API. When AuthN is disabled, S3 requests are no different
+// from the native ones.
+func (p *proxy) checkS3ACL(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, ace apc.AccessAttrs) error {
+	cfg := cmn.GCO.Get()
+	if !cfg.Auth.Enabled {
+		return p.checkACL(w, r, bck, ace)
+	}
+	token, err := p.validateS3Request(r)
+	if err == nil {
+		err = p.checkTokenACL(cfg, token, bck, ace)
+	}
+	if err != nil {
+		p.writeErr(w, r, err, p.aclErrToCode(err))
+	}
+	return err
+}
+
+func (p *proxy) validateS3Request(r *http.Request) (*authn.Token, error) {
+	if !s3compat.IsSigV4(r) {
+		return p.validateToken(r.Header)
+	}
+	sig, err := s3compat.ParseSigV4(r)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := p.authn.s3Key(sig.AccessKey)
+	if !ok {
+		return nil, fmt.Errorf("unknown S3 access key %q", sig.AccessKey)
+	}
+	if err := sig.Verify(r, key.Secret, time.Now()); err != nil {
+		glog.Errorf("S3 access key %s (user %q): %v", key.AccessKey, key.UserID, err)
+		return nil, err
+	}
+	return p.authn.validateToken(key.Token)
+}
@@ -0,0 +1,453 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"archive/tar"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+// get-batch (see cmn.GetBatchMsg)
+//
+// The proxy groups the requested entries by their owning targets and sends each target its
+// (indexed) share of the batch. Targets read the objects (and archived files), and send them
+// back to the proxy over the intra-cluster data network (transport stream "get-batch"),
+// whereby the proxy assembles the response in the request order.
+//
+// Entries that arrive out of order are buffered in memory until their turn comes. The tar
+// output is streamed as soon as the next-in-order entry is received; the msgpack output is
+// written once all the entries are received (the size of the msgpack map must be known upfront).
+//
+// The size of the buffered entries is limited, both per request (cmn.GetBatchMaxBuffered) and
+// in total, across all get-batch requests in progress (cmn.GetBatchMaxBufferedTotal) - exceeding
+// either limit fails the batch. In addition, new get-batch requests are refused under high
+// memory pressure.
+//
+// Missing (or otherwise failed) entries fail the entire batch unless `ContinueOnError` is
+// specified, in which case they are skipped. NOTE: the failure that occurs after the response
+// has already started streaming can only be reported by terminating the (partial) response.
+
+const batchTrname = "get-batch"
+
+type (
+	// proxy => target
+	batchReq struct {
+		ID      string       `json:"id"`
+		Entries []batchEntry `json:"entries"`
+	}
+	batchEntry struct {
+		cmn.GetBatchEntry
+		Idx int `json:"idx"` // index in the original request
+	}
+
+	// get-batch in progress
+	batchCtx struct {
+		msg      *cmn.GetBatchMsg
+		recvd    []*batchRecv // by index
+		mu       sync.Mutex
+		cond     *sync.Cond
+		buffered int64         // total size of the received (and not yet written) entries
+		total    *atomic.Int64 // ditto, all get-batch requests (see proxy.batchBuf)
+		aborted  atomic.Bool
+	}
+	batchRecv struct {
+		sgl     *memsys.SGL
+		size    int64 // buffered (see batchCtx.reserve)
+		atime   int64
+		errCode int
+		err     string
+		fatal   bool // fails the batch regardless of `ContinueOnError`
+	}
+)
+
+func packBatchOpaque(id string, idx, errCode int, errMsg string) []byte {
+	packer := cos.NewPacker(nil, cos.PackedStrLen(id)+cos.SizeofI32*2+cos.PackedStrLen(errMsg))
+	packer.WriteString(id)
+	packer.WriteUint32(uint32(idx))
+	packer.WriteUint32(uint32(errCode))
+	packer.WriteString(errMsg)
+	return packer.Bytes()
+}
+
+func unpackBatchOpaque(opaque []byte) (id string, idx, errCode int, errMsg string, err error) {
+	var (
+		unpacker = cos.NewUnpacker(opaque)
+		i, code  uint32
+	)
+	if id, err = unpacker.ReadString(); err != nil {
+		return
+	}
+	if i, err = unpacker.ReadUint32(); err != nil {
+		return
+	}
+	if code, err = unpacker.ReadUint32(); err != nil {
+		return
+	}
+	errMsg, err = unpacker.ReadString()
+	idx, errCode = int(i), int(code)
+	return
+}
+
+// GET /v1/batch
+func (p *proxy) batchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		cmn.WriteErr405(w, r, http.MethodGet)
+		return
+	}
+	if _, err := p.checkRESTItems(w, r, 0, false, apc.URLPathBatch.L); err != nil {
+		return
+	}
+	msg := &cmn.GetBatchMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		return
+	}
+	if err := msg.Validate(); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	if p.gmm.Pressure() >= memsys.PressureHigh {
+		p.writeErrStatusf(w, r, http.StatusServiceUnavailable, "%s: get-batch: high memory pressure, try again later", p)
+		return
+	}
+	// buckets
+	bcks := make(map[string]*cluster.Bck, 2)
+	for i := range msg.Entries {
+		e := &msg.Entries[i]
+		uname := e.Bck.MakeUname("")
+		bck, ok := bcks[uname]
+		if !ok {
+			var err error
+			bckArgs := allocInitBckArgs()
+			{
+				bckArgs.p = p
+				bckArgs.w = w
+				bckArgs.r = r
+				bckArgs.bck = cluster.CloneBck(&e.Bck)
+				bckArgs.perms = apc.AceGET
+				bckArgs.lookupRemote = true
+			}
+			bck, err = bckArgs.initAndTry(e.Bck.Name)
+			freeInitBckArgs(bckArgs)
+			if err != nil {
+				return
+			}
+			bcks[uname] = bck
+		}
+		e.Bck = *bck.Bucket()
+	}
+	// owners
+	var (
+		smap = p.owner.smap.get()
+		reqs = make(map[string]*batchReq, smap.CountActiveTargets())
+		id   = cos.GenUUID()
+	)
+	for i := range msg.Entries {
+		e := &msg.Entries[i]
+		tsi, err := cluster.HrwTarget(e.Bck.MakeUname(e.ObjName), &smap.Smap)
+		if err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+		req, ok := reqs[tsi.ID()]
+		if !ok {
+			req = &batchReq{ID: id}
+			reqs[tsi.ID()] = req
+		}
+		req.Entries = append(req.Entries, batchEntry{GetBatchEntry: *e, Idx: i})
+	}
+
+	ctx := newBatchCtx(msg, &p.batchBuf)
+	p.batches.Store(id, ctx)
+	for tid, req := range reqs {
+		go p.callBatch(smap.GetTarget(tid), req, ctx)
+	}
+	started := time.Now()
+	written, errCode, err := ctx.write(w)
+	ctx.abort()
+	p.batches.Delete(id)
+	if err != nil {
+		if !written {
+			p.writeErr(w, r, err, errCode)
+		} else {
+			glog.Errorf("%s: get-batch[%s]: %v - terminating the response", p, id, err)
+		}
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: get-batch[%s]: %d entries, %s, %v", p, id, len(msg.Entries), msg.Mime, time.Since(started))
+	}
+}
+
+func (p *proxy) callBatch(tsi *cluster.Snode, req *batchReq, ctx *batchCtx) {
+	cargs := allocCargs()
+	{
+		cargs.si = tsi
+		cargs.req = cmn.HreqArgs{Method: http.MethodPost, Path: apc.URLPathBatch.S, Body: cos.MustMarshal(req)}
+		cargs.timeout = apc.LongTimeout
+	}
+	res := p.call(cargs)
+	freeCargs(cargs)
+	// (all the entries received by now - see transport.Stream.Fin)
+	errCode, errMsg := http.StatusInternalServerError, fmt.Sprintf("not received from %s", tsi)
+	if res.err != nil {
+		errCode, errMsg = res.status, res.err.Error()
+	}
+	freeCR(res)
+	ctx.mu.Lock()
+	for i := range req.Entries {
+		idx := req.Entries[i].Idx
+		if ctx.recvd[idx] == nil {
+			ctx.recvd[idx] = &batchRecv{errCode: errCode, err: errMsg}
+		}
+	}
+	ctx.cond.Broadcast()
+	ctx.mu.Unlock()
+}
+
+// receive get-batch entries from targets (see t.sendBatchEntry)
+func (p *proxy) recvBatch(hdr transport.ObjHdr, objReader io.Reader, err error) error {
+	defer transport.DrainAndFreeReader(objReader)
+	if err != nil && !cos.IsEOF(err) {
+		glog.Error(err)
+		return err
+	}
+	id, idx, errCode, errMsg, err := unpackBatchOpaque(hdr.Opaque)
+	if err != nil {
+		glog.Errorf("%s: get-batch: invalid header %s: %v", p, hdr.ObjName, err)
+		return err
+	}
+	v, ok := p.batches.Load(id)
+	if !ok {
+		return nil // done or aborted
+	}
+	ctx := v.(*batchCtx)
+	if idx < 0 || idx >= len(ctx.recvd) {
+		glog.Errorf("%s: get-batch[%s]: entry %s: index %d out of range [0, %d)", p, id, hdr.ObjName, idx, len(ctx.recvd))
+		return nil
+	}
+	rc := &batchRecv{errCode: errCode, err: errMsg, atime: hdr.ObjAttrs.Atime}
+	if errMsg == "" && !ctx.aborted.Load() {
+		if err := ctx.reserve(hdr.ObjAttrs.Size); err != nil {
+			rc.errCode, rc.err, rc.fatal = http.StatusRequestEntityTooLarge, err.Error(), true
+		} else {
+			rc.sgl, rc.size = p.gmm.NewSGL(hdr.ObjAttrs.Size), hdr.ObjAttrs.Size
+			if _, err := io.Copy(rc.sgl, objReader); err != nil {
+				ctx.release(rc.size)
+				rc.sgl.Free()
+				rc.sgl, rc.errCode, rc.err = nil, http.StatusInternalServerError, err.Error()
+			}
+		}
+	}
+	ctx.put(idx, rc)
+	return nil
+}
+
+//////////////
+// batchCtx //
+//////////////
+
+func newBatchCtx(msg *cmn.GetBatchMsg, total *atomic.Int64) *batchCtx {
+	ctx := &batchCtx{msg: msg, recvd: make([]*batchRecv, len(msg.Entries)), total: total}
+	ctx.cond = sync.NewCond(&ctx.mu)
+	return ctx
+}
+
+func (ctx *batchCtx) put(idx int, rc *batchRecv) {
+	ctx.mu.Lock()
+	if ctx.aborted.Load() || ctx.recvd[idx] != nil { // (duplicate)
+		if rc.sgl != nil {
+			ctx._unreserve(rc.size)
+			rc.sgl.Free()
+		}
+		ctx.mu.Unlock()
+		return
+	}
+	ctx.recvd[idx] = rc
+	ctx.cond.Broadcast()
+	ctx.mu.Unlock()
+}
+
+// account for the entry to be buffered
+func (ctx *batchCtx) reserve(size int64) (err error) {
+	ctx.mu.Lock()
+	switch {
+	case ctx.buffered+size > cmn.GetBatchMaxBuffered:
+		err = fmt.Errorf("get-batch: buffered entries exceed %s (consider requesting fewer entries)",
+			cos.B2S(cmn.GetBatchMaxBuffered, 0))
+	case ctx.total.Add(size) > cmn.GetBatchMaxBufferedTotal:
+		ctx.total.Sub(size)
+		err = fmt.Errorf("get-batch: entries buffered by all get-batch requests exceed %s (try again later)",
+			cos.B2S(cmn.GetBatchMaxBufferedTotal, 0))
+	default:
+		ctx.buffered += size
+	}
+	ctx.mu.Unlock()
+	return
+}
+
+func (ctx *batchCtx) release(size int64) {
+	ctx.mu.Lock()
+	ctx._unreserve(size)
+	ctx.mu.Unlock()
+}
+
+func (ctx *batchCtx) _unreserve(size int64) {
+	ctx.buffered -= size
+	ctx.total.Sub(size)
+}
+
+// wait for the idx-th entry
+func (ctx *batchCtx) get(idx int) (rc *batchRecv) {
+	ctx.mu.Lock()
+	for ctx.recvd[idx] == nil {
+		ctx.cond.Wait()
+	}
+	rc = ctx.recvd[idx]
+	ctx.mu.Unlock()
+	return
+}
+
+// free the buffered entries (including those that may still be arriving)
+func (ctx *batchCtx) abort() {
+	ctx.mu.Lock()
+	ctx.aborted.Store(true)
+	for _, rc := range ctx.recvd {
+		if rc != nil && rc.sgl != nil {
+			ctx._unreserve(rc.size)
+			rc.sgl.Free()
+			rc.sgl = nil
+		}
+	}
+	ctx.mu.Unlock()
+}
+
+func (ctx *batchCtx) failed(idx int, rc *batchRecv) error {
+	e := &ctx.msg.Entries[idx]
+	if rc.errCode == http.StatusNotFound {
+		return cmn.NewErrNotFound("get-batch: %s", e.NameInArch())
+	}
+	return fmt.Errorf("get-batch: failed to read %s: %s", e.NameInArch(), rc.err)
+}
+
+// write the entries, in order, formatted as ctx.msg.Mime
+func (ctx *batchCtx) write(w io.Writer) (written bool, errCode int, err error) {
+	if ctx.msg.Mime == cos.ExtMsgpack {
+		return ctx.writeMsgpack(w)
+	}
+	return ctx.writeTar(w)
+}
+
+func (ctx *batchCtx) writeTar(w io.Writer) (written bool, errCode int, err error) {
+	var tw *tar.Writer
+	for i := range ctx.msg.Entries {
+		rc := ctx.get(i)
+		if rc.err != "" {
+			if ctx.msg.ContinueOnError && !rc.fatal {
+				continue
+			}
+			return written, rc.errCode, ctx.failed(i, rc)
+		}
+		if tw == nil {
+			setBatchHeader(w, cmn.ContentTar)
+			tw, written = tar.NewWriter(w), true
+		}
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     ctx.msg.Entries[i].NameInArch(),
+			Size:     rc.sgl.Size(),
+			Mode:     int64(cos.PermRWR),
+			ModTime:  time.Unix(0, rc.atime),
+		}
+		if err = tw.WriteHeader(hdr); err == nil {
+			_, err = io.Copy(tw, rc.sgl)
+		}
+		ctx.free(rc)
+		if err != nil {
+			return
+		}
+	}
+	if tw == nil { // (all skipped)
+		setBatchHeader(w, cmn.ContentTar)
+		tw, written = tar.NewWriter(w), true
+	}
+	err = tw.Close()
+	return
+}
+
+// msgpack map (name => bin), with 32-bit map, str, and bin formats throughout
+func (ctx *batchCtx) writeMsgpack(w io.Writer) (written bool, errCode int, err error) {
+	var n int
+	for i := range ctx.msg.Entries {
+		rc := ctx.get(i)
+		if rc.err == "" {
+			n++
+		} else if !ctx.msg.ContinueOnError || rc.fatal {
+			return false, rc.errCode, ctx.failed(i, rc)
+		}
+	}
+	setBatchHeader(w, cmn.ContentMsgPack)
+	written = true
+	if err = writeMsgpackHdr(w, 0xdf, n); err != nil {
+		return
+	}
+	for i := range ctx.msg.Entries {
+		rc := ctx.get(i)
+		if rc.err != "" {
+			continue
+		}
+		name := ctx.msg.Entries[i].NameInArch()
+		if err = writeMsgpackHdr(w, 0xdb, len(name)); err != nil {
+			return
+		}
+		if _, err = io.WriteString(w, name); err != nil {
+			return
+		}
+		if err = writeMsgpackHdr(w, 0xc6, int(rc.sgl.Size())); err != nil {
+			return
+		}
+		_, err = io.Copy(w, rc.sgl)
+		ctx.free(rc)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (ctx *batchCtx) free(rc *batchRecv) {
+	ctx.mu.Lock()
+	ctx._unreserve(rc.size)
+	rc.sgl.Free()
+	rc.sgl = nil
+	ctx.mu.Unlock()
+}
+
+func writeMsgpackHdr(w io.Writer, format byte, l int) error {
+	var b [5]byte
+	b[0] = format
+	binary.BigEndian.PutUint32(b[1:], uint32(l))
+	_, err := w.Write(b[:])
+	return err
+}
+
+func setBatchHeader(w io.Writer, contentType string) {
+	if resp, ok := w.(http.ResponseWriter); ok {
+		resp.Header().Set(cmn.HdrContentType, contentType)
+	}
+}
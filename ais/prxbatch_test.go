@@ -0,0 +1,162 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tinylib/msgp/msgp"
+)
+
+var _ = Describe("get-batch", func() {
+	mm := memsys.PageMM()
+
+	makeMsg := func(mime string, coer bool, names ...string) *cmn.GetBatchMsg {
+		msg := &cmn.GetBatchMsg{Mime: mime, ContinueOnError: coer}
+		for _, name := range names {
+			msg.Entries = append(msg.Entries, cmn.GetBatchEntry{Bck: cmn.Bck{Name: "bck"}, ObjName: name})
+		}
+		Expect(msg.Validate()).NotTo(HaveOccurred())
+		return msg
+	}
+
+	// deliver entries in reverse order, concurrently with the writer
+	deliver := func(ctx *batchCtx, missing map[int]bool) {
+		go func() {
+			for i := len(ctx.msg.Entries) - 1; i >= 0; i-- {
+				if missing[i] {
+					ctx.put(i, &batchRecv{errCode: http.StatusNotFound, err: "not found"})
+					continue
+				}
+				sgl := mm.NewSGL(0)
+				sgl.Write([]byte(ctx.msg.Entries[i].ObjName))
+				ctx.put(i, &batchRecv{sgl: sgl})
+			}
+		}()
+	}
+
+	readTar := func(b []byte) (names []string) {
+		tr := tar.NewReader(bytes.NewReader(b))
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			data, err := io.ReadAll(tr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect("bck/" + string(data)).To(Equal(hdr.Name))
+			names = append(names, hdr.Name)
+		}
+	}
+
+	It("should pack and unpack opaque header", func() {
+		id, idx, errCode, errMsg, err := unpackBatchOpaque(packBatchOpaque("id", 42, http.StatusNotFound, "msg"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("id"))
+		Expect(idx).To(Equal(42))
+		Expect(errCode).To(Equal(http.StatusNotFound))
+		Expect(errMsg).To(Equal("msg"))
+	})
+
+	It("should write tar in the request order", func() {
+		var (
+			msg = makeMsg(cos.ExtTar, false, "a", "c", "b", "d")
+			ctx = newBatchCtx(msg, &atomic.Int64{})
+			buf = &bytes.Buffer{}
+		)
+		deliver(ctx, nil)
+		written, _, err := ctx.write(buf)
+		ctx.abort()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(written).To(BeTrue())
+		Expect(readTar(buf.Bytes())).To(Equal([]string{"bck/a", "bck/c", "bck/b", "bck/d"}))
+	})
+
+	It("should skip missing entries when continuing on error", func() {
+		var (
+			msg = makeMsg(cos.ExtTar, true, "a", "b", "c")
+			ctx = newBatchCtx(msg, &atomic.Int64{})
+			buf = &bytes.Buffer{}
+		)
+		deliver(ctx, map[int]bool{1: true})
+		_, _, err := ctx.write(buf)
+		ctx.abort()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(readTar(buf.Bytes())).To(Equal([]string{"bck/a", "bck/c"}))
+	})
+
+	It("should fail the batch on missing entry", func() {
+		var (
+			msg = makeMsg(cos.ExtTar, false, "a", "b", "c")
+			ctx = newBatchCtx(msg, &atomic.Int64{})
+			buf = &bytes.Buffer{}
+		)
+		deliver(ctx, map[int]bool{0: true})
+		written, errCode, err := ctx.write(buf)
+		ctx.abort()
+		Expect(err).To(HaveOccurred())
+		Expect(cmn.IsErrNotFound(err)).To(BeTrue())
+		Expect(errCode).To(Equal(http.StatusNotFound))
+		Expect(written).To(BeFalse())
+		Expect(buf.Len()).To(BeZero())
+	})
+
+	It("should limit the entries buffered by all get-batch requests", func() {
+		var (
+			total = &atomic.Int64{}
+			ctx1  = newBatchCtx(makeMsg(cos.ExtTar, false, "a"), total)
+			ctx2  = newBatchCtx(makeMsg(cos.ExtTar, false, "b"), total)
+			size  = int64(cmn.GetBatchMaxBuffered)
+		)
+		for total.Load()+size <= cmn.GetBatchMaxBufferedTotal {
+			ctx := newBatchCtx(makeMsg(cos.ExtTar, false, "x"), total)
+			Expect(ctx.reserve(size)).NotTo(HaveOccurred())
+		}
+		Expect(ctx1.reserve(1)).To(HaveOccurred())
+		Expect(ctx1.reserve(size + 1)).To(HaveOccurred()) // (per request)
+		total.Sub(size)
+		Expect(ctx1.reserve(size / 2)).NotTo(HaveOccurred())
+		Expect(ctx2.reserve(size / 2)).NotTo(HaveOccurred())
+		Expect(ctx2.reserve(1)).To(HaveOccurred())
+		ctx1.release(size / 2)
+		ctx2.release(size / 2)
+		Expect(total.Load()).To(Equal(cmn.GetBatchMaxBufferedTotal - size))
+	})
+
+	It("should write msgpack in the request order", func() {
+		var (
+			msg = makeMsg(cos.ExtMsgpack, true, "c", "a", "b")
+			ctx = newBatchCtx(msg, &atomic.Int64{})
+			buf = &bytes.Buffer{}
+		)
+		deliver(ctx, map[int]bool{2: true})
+		_, _, err := ctx.write(buf)
+		ctx.abort()
+		Expect(err).NotTo(HaveOccurred())
+
+		r := msgp.NewReader(buf)
+		sz, err := r.ReadMapHeader()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sz).To(Equal(uint32(2)))
+		for _, name := range []string{"c", "a"} {
+			key, err := r.ReadString()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal("bck/" + name))
+			data, err := r.ReadBytes(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(name))
+		}
+	})
+})
@@ -594,7 +594,7 @@ func (p *proxy) _updFinal(ctx *smapModifier, clone *smapX) {
 		cos.Assert(ctx.rmd != nil)
 		pairs = append(pairs, revsPair{ctx.rmd, aisMsg})
 	}
-	if len(tokens.Tokens) > 0 {
+	if len(tokens.Tokens) > 0 || tokens.S3KeysVer > 0 {
 		pairs = append(pairs, revsPair{tokens, aisMsg})
 	}
 	debug.Assert(clone._sgl != nil)
@@ -5,6 +5,7 @@
 package ais
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -157,6 +158,10 @@ func (p *proxy) downloadHandler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet, http.MethodDelete:
 		p.httpDownloadAdmin(w, r)
 	case http.MethodPost:
+		if r.URL.Path == apc.URLPathDownloadRetry.S {
+			p.httpDownloadRetry(w, r)
+			return
+		}
 		p.httpDownloadPost(w, r)
 	default:
 		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodGet, http.MethodPost)
@@ -260,8 +265,77 @@ func (p *proxy) httpDownloadPost(w http.ResponseWriter, r *http.Request) {
 	_respWithID(w, id)
 }
 
+// POST /v1/download/retry
+// Starts new job to re-download objects that failed in the given (finished) job.
+// Requires admin permissions and, same as starting a new download, write access
+// to the destination bucket of the failed downloads.
+func (p *proxy) httpDownloadRetry(w http.ResponseWriter, r *http.Request) {
+	payload := &downloader.DlAdminBody{}
+	if err := cmn.ReadJSON(w, r, payload); err != nil {
+		return
+	}
+	if payload.ID == "" {
+		p.writeErrMsg(w, r, "UUID not specified")
+		return
+	}
+	if err := p.checkACL(w, r, nil, apc.AceAdmin); err != nil {
+		return
+	}
+	var (
+		id   = cos.GenUUID()
+		smap = p.owner.smap.get()
+		body = cos.MustMarshal(payload)
+	)
+	bck, errCode, err := p.retryDownloadBck(body)
+	if err != nil {
+		p.writeErrStatusf(w, r, errCode, "Error retrying download %q: %v.", payload.ID, err.Error())
+		return
+	}
+	args := bckInitArgs{p: p, w: w, r: r, reqBody: body, bck: bck, perms: apc.AccessRW}
+	args.lookupRemote = true
+	if _, err := args.initAndTry(bck.Name); err != nil {
+		return
+	}
+	if errCode, err := p.broadcastStartDownloadRequest(r, id, body); err != nil {
+		p.writeErrStatusf(w, r, errCode, "Error retrying download %q: %v.", payload.ID, err.Error())
+		return
+	}
+	nl := downloader.NewDownloadNL(id, apc.Retry, &smap.Smap, downloader.DownloadProgressInterval)
+	nl.SetOwner(equalIC)
+	p.ic.registerEqual(regIC{nl: nl, smap: smap})
+
+	_respWithID(w, id)
+}
+
 // Helper methods
 
+// returns the destination bucket of the job's failed downloads (as persisted by the targets)
+func (p *proxy) retryDownloadBck(body []byte) (bck *cluster.Bck, errCode int, err error) {
+	args := allocBcArgs()
+	args.req = cmn.HreqArgs{Method: http.MethodGet, Path: apc.URLPathDownloadRetry.S, Body: body}
+	args.timeout = cmn.Timeout.CplaneOperation()
+	results := p.bcastGroup(args)
+	freeBcArgs(args)
+	defer freeBcastRes(results)
+
+	errCode, err = http.StatusNotFound, errors.New("no failed downloads to retry")
+	for _, res := range results {
+		if res.err != nil {
+			if res.status != http.StatusNotFound {
+				return nil, res.status, res.err
+			}
+			continue
+		}
+		b := &cmn.Bck{}
+		if err := jsoniter.Unmarshal(res.bytes, b); err != nil {
+			err = fmt.Errorf(cmn.FmtErrUnmarshal, p, "bucket", cmn.BytesHead(res.bytes), err)
+			return nil, http.StatusInternalServerError, err
+		}
+		bck, errCode, err = cluster.CloneBck(b), 0, nil
+	}
+	return
+}
+
 func (p *proxy) validateStartDownloadRequest(w http.ResponseWriter, r *http.Request,
 	body []byte) (dlb downloader.DlBody, dlBase downloader.DlBase, ok bool) {
 	if err := jsoniter.Unmarshal(body, &dlb); err != nil {
@@ -276,6 +350,10 @@ func (p *proxy) validateStartDownloadRequest(w http.ResponseWriter, r *http.Requ
 		p.writeErr(w, r, err)
 		return
 	}
+	if err = dlBase.Validate(); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
 	bck := cluster.CloneBck(&dlBase.Bck)
 	args := bckInitArgs{p: p, w: w, r: r, reqBody: body, bck: bck, perms: apc.AccessRW}
 	args.createAIS = true
@@ -283,6 +361,21 @@ func (p *proxy) validateStartDownloadRequest(w http.ResponseWriter, r *http.Requ
 	if _, err = args.initAndTry(bck.Name); err != nil {
 		return
 	}
+	// manifest download: the user must be able to read the manifest
+	if dlb.Type == downloader.DlTypeManifest {
+		payload := &downloader.DlManifestBody{}
+		if err = jsoniter.Unmarshal(dlb.RawMessage, payload); err != nil {
+			err = fmt.Errorf(cmn.FmtErrUnmarshal, p, "manifest download", cmn.BytesHead(dlb.RawMessage), err)
+			p.writeErr(w, r, err)
+			return
+		}
+		mbck := cluster.CloneBck(&payload.ManifestBck)
+		margs := bckInitArgs{p: p, w: w, r: r, reqBody: body, bck: mbck, perms: apc.AceGET}
+		margs.lookupRemote = true
+		if _, err = margs.initAndTry(mbck.Name); err != nil {
+			return
+		}
+	}
 	ok = true
 	return
 }
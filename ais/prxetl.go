@@ -100,6 +100,17 @@ func (p *proxy) handleETLPut(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// local-process ETL runs user-specified command on each target
+	if _, ok := initMsg.(*etl.InitProcMsg); ok {
+		if err := p.checkACL(w, r, nil, apc.AceAdmin); err != nil {
+			return
+		}
+		if err := etl.CheckProcEnabled(cmn.GCO.Get()); err != nil {
+			p.writeErr(w, r, err, http.StatusForbidden)
+			return
+		}
+	}
+
 	etlMD := p.owner.etl.get()
 	if etlMD.get(initMsg.ID()) != nil {
 		p.writeErrf(w, r, "ETL with ID %q exists", initMsg.ID())
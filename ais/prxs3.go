@@ -41,6 +41,7 @@ func (p *proxy) s3Handler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		return
 	}
+	s3compat.VerifyPayload(r)
 
 	switch r.Method {
 	case http.MethodHead:
@@ -56,7 +57,7 @@ func (p *proxy) s3Handler(w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		if len(apiItems) == 0 {
 			// nothing  - list all the buckets
-			p.bckNamesToS3(w)
+			p.bckNamesToS3(w, r)
 			return
 		}
 		q := r.URL.Query()
@@ -78,6 +79,14 @@ func (p *proxy) s3Handler(w http.ResponseWriter, r *http.Request) {
 			p.bckListS3(w, r, apiItems[0])
 			return
 		}
+		if _, mpt := q[s3compat.QparamMptUploadID]; mpt {
+			p.redirectObjS3(w, r, apiItems, apc.AceObjHEAD) // list parts
+			return
+		}
+		if _, tagging := q[s3compat.QparamTagging]; tagging {
+			p.redirectObjS3(w, r, apiItems, apc.AceObjHEAD) // get tags
+			return
+		}
 		// object data otherwise
 		p.getObjS3(w, r, apiItems)
 	case http.MethodPut:
@@ -95,13 +104,32 @@ func (p *proxy) s3Handler(w http.ResponseWriter, r *http.Request) {
 			p.putBckS3(w, r, apiItems[0])
 			return
 		}
+		q := r.URL.Query()
+		if _, mpt := q[s3compat.QparamMptUploadID]; mpt {
+			p.redirectObjS3(w, r, apiItems, apc.AcePUT) // upload part
+			return
+		}
+		if _, tagging := q[s3compat.QparamTagging]; tagging {
+			p.redirectObjS3(w, r, apiItems, apc.AcePUT) // put tags
+			return
+		}
 		p.putObjS3(w, r, apiItems)
 	case http.MethodPost:
+		q := r.URL.Query()
+		if len(apiItems) > 1 {
+			_, uploads := q[s3compat.QparamMptUploads]
+			_, mpt := q[s3compat.QparamMptUploadID]
+			if !uploads && !mpt {
+				p.writeErr(w, r, errS3Req)
+				return
+			}
+			p.redirectObjS3(w, r, apiItems, apc.AcePUT) // start or complete upload
+			return
+		}
 		if len(apiItems) != 1 {
 			p.writeErr(w, r, errS3Req)
 			return
 		}
-		q := r.URL.Query()
 		if _, multiple := q[s3compat.QparamMultiDelete]; !multiple {
 			p.writeErr(w, r, errS3Req)
 			return
@@ -122,6 +150,15 @@ func (p *proxy) s3Handler(w http.ResponseWriter, r *http.Request) {
 			p.delBckS3(w, r, apiItems[0])
 			return
 		}
+		q := r.URL.Query()
+		if _, mpt := q[s3compat.QparamMptUploadID]; mpt {
+			p.redirectObjS3(w, r, apiItems, apc.AcePUT) // abort upload
+			return
+		}
+		if _, tagging := q[s3compat.QparamTagging]; tagging {
+			p.redirectObjS3(w, r, apiItems, apc.AcePUT) // delete tags
+			return
+		}
 		p.delObjS3(w, r, apiItems)
 	default:
 		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodGet, http.MethodHead,
@@ -130,7 +167,10 @@ func (p *proxy) s3Handler(w http.ResponseWriter, r *http.Request) {
 }
 
 // GET s3/
-func (p *proxy) bckNamesToS3(w http.ResponseWriter) {
+func (p *proxy) bckNamesToS3(w http.ResponseWriter, r *http.Request) {
+	if err := p.checkS3ACL(w, r, nil, apc.AceListBuckets); err != nil {
+		return
+	}
 	var (
 		bmd  = p.owner.bmd.get()
 		qbck = cmn.QueryBcks{Provider: apc.ProviderAIS}
@@ -157,10 +197,21 @@ func (p *proxy) putBckS3(w http.ResponseWriter, r *http.Request, bucket string)
 		p.writeErr(w, r, err)
 		return
 	}
+	if err := p.checkS3ACL(w, r, nil, apc.AceCreateBucket); err != nil {
+		return
+	}
 	msg := apc.ActionMsg{Action: apc.ActCreateBck}
 	if p.forwardCP(w, r, nil, msg.Action+"-"+bucket) {
 		return
 	}
+	if propsToUpdate := s3compat.ObjLockToUpdate(r.Header); propsToUpdate != nil {
+		var err error
+		bck.Props = defaultBckProps(bckPropsArgs{bck: bck})
+		if bck.Props, err = p.makeNewBckProps(bck, propsToUpdate, true /*creating*/); err != nil {
+			p.writeErr(w, r, err)
+			return
+		}
+	}
 	if err := p.createBucket(&msg, bck); err != nil {
 		errCode := http.StatusInternalServerError
 		if _, ok := err.(*cmn.ErrBucketAlreadyExists); ok {
@@ -179,8 +230,7 @@ func (p *proxy) delBckS3(w http.ResponseWriter, r *http.Request, bucket string)
 		p.writeErr(w, r, err, http.StatusNotFound)
 		return
 	}
-	if err := bck.Allow(apc.AceDestroyBucket); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bck, apc.AceDestroyBucket); err != nil {
 		return
 	}
 	if p.forwardCP(w, r, nil, msg.Action+"-"+bucket) {
@@ -205,8 +255,7 @@ func (p *proxy) delMultipleObjs(w http.ResponseWriter, r *http.Request, bucket s
 		p.writeErr(w, r, err, http.StatusNotFound)
 		return
 	}
-	if err := bck.Allow(apc.AceObjDELETE); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bck, apc.AceObjDELETE); err != nil {
 		return
 	}
 	decoder := xml.NewDecoder(r.Body)
@@ -249,8 +298,7 @@ func (p *proxy) headBckS3(w http.ResponseWriter, r *http.Request, bucket string)
 		p.writeErr(w, r, err, http.StatusNotFound)
 		return
 	}
-	if err := bck.Allow(apc.AceBckHEAD); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bck, apc.AceBckHEAD); err != nil {
 		return
 	}
 	// From AWS docs:
@@ -272,6 +320,9 @@ func (p *proxy) bckListS3(w http.ResponseWriter, r *http.Request, bucket string)
 		p.writeErr(w, r, err)
 		return
 	}
+	if err := p.checkS3ACL(w, r, bck, apc.AceObjLIST); err != nil {
+		return
+	}
 	lsmsg := apc.ListObjsMsg{UUID: cos.GenUUID(), TimeFormat: time.RFC3339}
 	lsmsg.AddProps(apc.GetPropsSize, apc.GetPropsChecksum, apc.GetPropsAtime, apc.GetPropsVersion)
 	s3compat.FillMsgFromS3Query(r.URL.Query(), &lsmsg)
@@ -316,8 +367,7 @@ func (p *proxy) copyObjS3(w http.ResponseWriter, r *http.Request, items []string
 		p.writeErr(w, r, err)
 		return
 	}
-	if err := bckSrc.Allow(apc.AceGET); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bckSrc, apc.AceGET); err != nil {
 		return
 	}
 	bckDst := cluster.NewBck(items[0], apc.ProviderAIS, cmn.NsGlobal)
@@ -334,8 +384,7 @@ func (p *proxy) copyObjS3(w http.ResponseWriter, r *http.Request, items []string
 		smap = p.owner.smap.get()
 		err  error
 	)
-	if err = bckDst.Allow(apc.AcePUT); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err = p.checkS3ACL(w, r, bckDst, apc.AcePUT); err != nil {
 		return
 	}
 	objName := strings.Trim(parts[1], "/")
@@ -368,8 +417,7 @@ func (p *proxy) directPutObjS3(w http.ResponseWriter, r *http.Request, items []s
 		smap = p.owner.smap.get()
 		err  error
 	)
-	if err = bck.Allow(apc.AcePUT); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err = p.checkS3ACL(w, r, bck, apc.AcePUT); err != nil {
 		return
 	}
 	objName := path.Join(items[1:]...)
@@ -411,8 +459,7 @@ func (p *proxy) getObjS3(w http.ResponseWriter, r *http.Request, items []string)
 		smap = p.owner.smap.get()
 		err  error
 	)
-	if err = bck.Allow(apc.AceGET); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err = p.checkS3ACL(w, r, bck, apc.AceGET); err != nil {
 		return
 	}
 	objName := path.Join(items[1:]...)
@@ -440,8 +487,7 @@ func (p *proxy) headObjS3(w http.ResponseWriter, r *http.Request, items []string
 		p.writeErr(w, r, err)
 		return
 	}
-	if err := bck.Allow(apc.AceObjHEAD); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err := p.checkS3ACL(w, r, bck, apc.AceObjHEAD); err != nil {
 		return
 	}
 	smap := p.owner.smap.get()
@@ -474,10 +520,14 @@ func (p *proxy) delObjS3(w http.ResponseWriter, r *http.Request, items []string)
 		smap = p.owner.smap.get()
 		err  error
 	)
-	if err = bck.Allow(apc.AceObjDELETE); err != nil {
-		p.writeErr(w, r, err, http.StatusForbidden)
+	if err = p.checkS3ACL(w, r, bck, apc.AceObjDELETE); err != nil {
 		return
 	}
+	if s3compat.IsBypassGovernance(r.Header) {
+		if err = p.checkS3ACL(w, r, nil, apc.AceAdmin); err != nil {
+			return
+		}
+	}
 	objName := path.Join(items[1:]...)
 	si, err = cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -491,6 +541,36 @@ func (p *proxy) delObjS3(w http.ResponseWriter, r *http.Request, items []string)
 	p.s3Redirect(w, r, si, redirectURL, bck.Name)
 }
 
+// [METHOD] s3/bckName/objName?uploads|uploadId=<ID>[&partNumber=<N>]
+// [METHOD] s3/bckName/objName?tagging
+// Multipart upload and object tagging requests are redirected to the target
+// that owns the object (see also: ais/tgts3mpt.go and ais/tgts3.go)
+func (p *proxy) redirectObjS3(w http.ResponseWriter, r *http.Request, items []string, ace apc.AccessAttrs) {
+	started := time.Now()
+	bck := cluster.NewBck(items[0], apc.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd); err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	if err := p.checkS3ACL(w, r, bck, ace); err != nil {
+		return
+	}
+	var (
+		smap    = p.owner.smap.get()
+		objName = path.Join(items[1:]...)
+	)
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.writeErr(w, r, err)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("AISS3 redirect: %s %s/%s => %s", r.Method, bck, objName, si)
+	}
+	redirectURL := p.redirectURL(r, si, started, cmn.NetIntraData)
+	p.s3Redirect(w, r, si, redirectURL, bck.Name)
+}
+
 // GET s3/bk-name?versioning
 func (p *proxy) getBckVersioningS3(w http.ResponseWriter, r *http.Request, bucket string) {
 	bck := cluster.NewBck(bucket, apc.ProviderAIS, cmn.NsGlobal)
@@ -498,6 +578,9 @@ func (p *proxy) getBckVersioningS3(w http.ResponseWriter, r *http.Request, bucke
 		p.writeErr(w, r, err)
 		return
 	}
+	if err := p.checkS3ACL(w, r, bck, apc.AceBckHEAD); err != nil {
+		return
+	}
 	resp := s3compat.NewVersioningConfiguration(bck.Props.Versioning.Enabled)
 	sgl := memsys.PageMM().NewSGL(0)
 	resp.MustMarshal(sgl)
@@ -532,6 +615,9 @@ func (p *proxy) putBckVersioningS3(w http.ResponseWriter, r *http.Request, bucke
 		p.writeErrMsg(w, r, "bucket does not exist", http.StatusNotFound)
 		return
 	}
+	if err := p.checkS3ACL(w, r, bck, apc.AcePATCH); err != nil {
+		return
+	}
 	decoder := xml.NewDecoder(r.Body)
 	defer cos.Close(r.Body)
 	vconf := &s3compat.VersioningConfiguration{}
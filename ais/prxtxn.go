@@ -318,6 +318,10 @@ func (p *proxy) setBucketProps(msg *apc.ActionMsg, bck *cluster.Bck, nprops *cmn
 		// do nothing here (caller's responsible for validation)
 	case apc.ActResetBprops:
 		var remoteBckProps http.Header
+		if bprops.ObjLock.Enabled {
+			err = fmt.Errorf("%q has object lock enabled - cannot reset its props", bck)
+			return
+		}
 		if bck.IsRemote() {
 			if backend := bck.Backend(); backend != nil {
 				err = fmt.Errorf("%q has backend %q - detach it prior to resetting the props", bck, backend)
@@ -1063,6 +1067,19 @@ func (p *proxy) makeNewBckProps(bck *cluster.Bck, propsToUpdate *cmn.BucketProps
 			nprops.EC.ParitySlices = 1
 		}
 	}
+	if bprops.ObjLock.Enabled {
+		if !nprops.ObjLock.Enabled {
+			err = fmt.Errorf("%s: once enabled, object lock cannot be disabled (bucket %s)", p.si, bck)
+			return
+		}
+		if bprops.ObjLock.IsCompliance() && !nprops.ObjLock.IsCompliance() {
+			err = fmt.Errorf("%s: cannot change object lock mode of the bucket %s from %q to %q",
+				p.si, bck, cmn.ObjLockCompliance, nprops.ObjLock.Mode)
+			return
+		}
+	} else if nprops.ObjLock.Enabled && nprops.ObjLock.Mode == "" {
+		nprops.ObjLock.Mode = cmn.ObjLockGovernance
+	}
 	if !bprops.Mirror.Enabled && nprops.Mirror.Enabled {
 		if nprops.Mirror.Copies == 1 {
 			nprops.Mirror.Copies = cos.MaxI64(cfg.Mirror.Copies, 2)
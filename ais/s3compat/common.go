@@ -15,6 +15,12 @@ const (
 	QparamPolicy      = "policy"
 	QparamACL         = "acl"
 	QparamMultiDelete = "delete"
+	QparamTagging     = "tagging"
+
+	// Multipart upload
+	QparamMptUploads    = "uploads"
+	QparamMptUploadID   = "uploadId"
+	QparamMptPartNumber = "partNumber"
 
 	versioningEnabled  = "Enabled"
 	versioningDisabled = "Suspended"
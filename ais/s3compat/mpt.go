@@ -0,0 +1,301 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// Multipart upload: the state of all active uploads is kept in memory by
+// the (HRW) target that owns the destination object. Uploaded parts are
+// stored as work files and get concatenated into the final object when the
+// upload completes. While completing (or aborting), the upload is locked
+// - see `LockUpload` - so that its parts cannot be added or replaced.
+
+type (
+	// Response for CreateMultipartUpload
+	InitiateMptUploadResult struct {
+		Ns       string `xml:"xmlns,attr"`
+		Bucket   string `xml:"Bucket"`
+		Key      string `xml:"Key"`
+		UploadID string `xml:"UploadId"`
+	}
+
+	// Request body of CompleteMultipartUpload
+	CompleteMptUpload struct {
+		Parts []*PartInfo `xml:"Part"`
+	}
+	PartInfo struct {
+		ETag       string `xml:"ETag"`
+		PartNumber int64  `xml:"PartNumber"`
+		Size       int64  `xml:"Size,omitempty"`
+	}
+
+	// Response for CompleteMultipartUpload
+	CompleteMptUploadResult struct {
+		Ns     string `xml:"xmlns,attr"`
+		Bucket string `xml:"Bucket"`
+		Key    string `xml:"Key"`
+		ETag   string `xml:"ETag"`
+	}
+
+	// Response for ListParts
+	ListPartsResult struct {
+		Ns       string      `xml:"xmlns,attr"`
+		Bucket   string      `xml:"Bucket"`
+		Key      string      `xml:"Key"`
+		UploadID string      `xml:"UploadId"`
+		Parts    []*PartInfo `xml:"Part"`
+	}
+
+	// Part of an active upload (stored as a work file)
+	MptPart struct {
+		MD5  string // MD5 of the part (as hex string)
+		FQN  string // FQN of the corresponding work file
+		Size int64  // part size in bytes
+		Num  int64  // part number (1..10000)
+	}
+	mptUpload struct {
+		bckName string
+		objName string
+		parts   []*MptPart // sorted by part number
+		locked  bool       // being completed or aborted
+	}
+	mptUploads struct {
+		sync.RWMutex
+		m map[string]*mptUpload // by upload ID
+	}
+)
+
+const (
+	mptMinPartNum = 1
+	mptMaxPartNum = 10000
+
+	mptETagDelim = "-" // as in "<md5-of-md5s>-<number-of-parts>"
+)
+
+var ups = &mptUploads{m: make(map[string]*mptUpload, 8)}
+
+// Start a new multipart upload
+func InitUpload(id, bckName, objName string) {
+	ups.Lock()
+	ups.m[id] = &mptUpload{
+		bckName: bckName,
+		objName: objName,
+		parts:   make([]*MptPart, 0, 16),
+	}
+	ups.Unlock()
+}
+
+// Add a part to an active upload. A part with the same number replaces
+// an existing one - the previous part (if any) is returned to the caller
+// to remove its work file.
+func AddPart(id string, npart *MptPart) (prev *MptPart, err error) {
+	ups.Lock()
+	defer ups.Unlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return nil, errNoUpload(id)
+	}
+	if upload.locked {
+		return nil, errUploadLocked(id)
+	}
+	idx := sort.Search(len(upload.parts), func(i int) bool { return upload.parts[i].Num >= npart.Num })
+	if idx < len(upload.parts) && upload.parts[idx].Num == npart.Num {
+		prev = upload.parts[idx]
+		upload.parts[idx] = npart
+		return
+	}
+	upload.parts = append(upload.parts, nil)
+	copy(upload.parts[idx+1:], upload.parts[idx:])
+	upload.parts[idx] = npart
+	return
+}
+
+// Lock active upload to complete or abort it. Returns "not found" error if
+// there's no such upload, and "locked" error if it is already being completed
+// or aborted. Work files of the parts of a locked upload are neither replaced
+// nor removed until the upload is finished (or unlocked).
+func LockUpload(id string) error {
+	ups.Lock()
+	defer ups.Unlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return errNoUpload(id)
+	}
+	if upload.locked {
+		return errUploadLocked(id)
+	}
+	upload.locked = true
+	return nil
+}
+
+// Unlock upload (e.g., when failing to complete it) to allow the client
+// to upload parts and retry.
+func UnlockUpload(id string) {
+	ups.Lock()
+	if upload, ok := ups.m[id]; ok {
+		upload.locked = false
+	}
+	ups.Unlock()
+}
+
+// Validate the list of parts sent by a client with CompleteMultipartUpload
+// and return the corresponding (uploaded) parts in the specified order.
+func CheckParts(id string, parts []*PartInfo) ([]*MptPart, error) {
+	ups.RLock()
+	defer ups.RUnlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return nil, errNoUpload(id)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("upload %q: empty list of parts", id)
+	}
+	res := make([]*MptPart, 0, len(parts))
+	for i, part := range parts {
+		if i > 0 && part.PartNumber <= parts[i-1].PartNumber {
+			return nil, fmt.Errorf("upload %q: parts must be listed in ascending order (%d after %d)",
+				id, part.PartNumber, parts[i-1].PartNumber)
+		}
+		mpart := upload.getPart(part.PartNumber)
+		if mpart == nil {
+			return nil, fmt.Errorf("upload %q: part %d not found", id, part.PartNumber)
+		}
+		if etag := UnquoteETag(part.ETag); etag != "" && etag != mpart.MD5 {
+			return nil, fmt.Errorf("upload %q: part %d ETag mismatch (%q vs %q)", id, part.PartNumber, etag, mpart.MD5)
+		}
+		res = append(res, mpart)
+	}
+	return res, nil
+}
+
+// Return the list of all uploaded parts
+func ListParts(id string) ([]*PartInfo, error) {
+	ups.RLock()
+	defer ups.RUnlock()
+	upload, ok := ups.m[id]
+	if !ok {
+		return nil, errNoUpload(id)
+	}
+	parts := make([]*PartInfo, 0, len(upload.parts))
+	for _, part := range upload.parts {
+		parts = append(parts, &PartInfo{ETag: QuoteETag(part.MD5), PartNumber: part.Num, Size: part.Size})
+	}
+	return parts, nil
+}
+
+// Remove upload (on complete or abort) and return all its parts
+// so that the caller could cleanup the corresponding work files.
+func FinishUpload(id string) (parts []*MptPart, err error) {
+	ups.Lock()
+	upload, ok := ups.m[id]
+	if ok {
+		delete(ups.m, id)
+		parts = upload.parts
+	} else {
+		err = errNoUpload(id)
+	}
+	ups.Unlock()
+	return
+}
+
+// Return bucket and object names of an active upload
+func UploadObj(id string) (bckName, objName string, ok bool) {
+	ups.RLock()
+	upload, exists := ups.m[id]
+	if exists {
+		bckName, objName, ok = upload.bckName, upload.objName, true
+	}
+	ups.RUnlock()
+	return
+}
+
+func ParsePartNum(s string) (int64, error) {
+	partNum, err := strconv.ParseInt(s, 10, 16)
+	if err != nil || partNum < mptMinPartNum || partNum > mptMaxPartNum {
+		return 0, fmt.Errorf("invalid part number %q (must be in 1-%d range)", s, mptMaxPartNum)
+	}
+	return partNum, nil
+}
+
+// S3-compatible ETag of a multipart object: MD5 of the concatenated binary
+// MD5s of all its parts followed by "-<number of parts>".
+func CompositeETag(parts []*MptPart) (string, error) {
+	h := md5.New()
+	for _, part := range parts {
+		b, err := hex.DecodeString(part.MD5)
+		if err != nil {
+			return "", fmt.Errorf("part %d: invalid MD5 %q: %v", part.Num, part.MD5, err)
+		}
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)) + mptETagDelim + strconv.Itoa(len(parts)), nil
+}
+
+func QuoteETag(etag string) string { return "\"" + etag + "\"" }
+
+func UnquoteETag(etag string) string {
+	if len(etag) > 1 && etag[0] == '"' && etag[len(etag)-1] == '"' {
+		return etag[1 : len(etag)-1]
+	}
+	return etag
+}
+
+func errNoUpload(id string) error {
+	return cmn.NewErrNotFound("upload %q", id)
+}
+
+func errUploadLocked(id string) error {
+	return fmt.Errorf("upload %q is being completed or aborted", id)
+}
+
+func (upload *mptUpload) getPart(num int64) *MptPart {
+	idx := sort.Search(len(upload.parts), func(i int) bool { return upload.parts[i].Num >= num })
+	if idx < len(upload.parts) && upload.parts[idx].Num == num {
+		return upload.parts[idx]
+	}
+	return nil
+}
+
+func NewInitiateMptUploadResult(bckName, objName, id string) *InitiateMptUploadResult {
+	return &InitiateMptUploadResult{Ns: s3Namespace, Bucket: bckName, Key: objName, UploadID: id}
+}
+
+func (r *InitiateMptUploadResult) MustMarshal(sgl *memsys.SGL) {
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(r)
+	cos.AssertNoErr(err)
+}
+
+func NewCompleteMptUploadResult(bckName, objName, etag string) *CompleteMptUploadResult {
+	return &CompleteMptUploadResult{Ns: s3Namespace, Bucket: bckName, Key: objName, ETag: QuoteETag(etag)}
+}
+
+func (r *CompleteMptUploadResult) MustMarshal(sgl *memsys.SGL) {
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(r)
+	cos.AssertNoErr(err)
+}
+
+func NewListPartsResult(bckName, objName, id string, parts []*PartInfo) *ListPartsResult {
+	return &ListPartsResult{Ns: s3Namespace, Bucket: bckName, Key: objName, UploadID: id, Parts: parts}
+}
+
+func (r *ListPartsResult) MustMarshal(sgl *memsys.SGL) {
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(r)
+	cos.AssertNoErr(err)
+}
@@ -0,0 +1,116 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func md5hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestMptCompositeETag(t *testing.T) {
+	parts := []*MptPart{
+		{Num: 1, MD5: md5hex("part-one")},
+		{Num: 2, MD5: md5hex("part-two")},
+	}
+	h := md5.New()
+	for _, part := range parts {
+		b, _ := hex.DecodeString(part.MD5)
+		h.Write(b)
+	}
+	expected := hex.EncodeToString(h.Sum(nil)) + "-2"
+	etag, err := CompositeETag(parts)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, etag == expected, "expected %q, got %q", expected, etag)
+
+	_, err = CompositeETag([]*MptPart{{Num: 1, MD5: "not-hex"}})
+	tassert.Errorf(t, err != nil, "expected error on invalid MD5")
+}
+
+func TestMptUploadParts(t *testing.T) {
+	const id = "test-upload"
+	InitUpload(id, "bck", "obj")
+
+	// out-of-order and duplicate parts
+	for _, num := range []int64{3, 1, 2} {
+		prev, err := AddPart(id, &MptPart{Num: num, MD5: md5hex("a"), FQN: "a"})
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, prev == nil, "unexpected previous part %d", num)
+	}
+	prev, err := AddPart(id, &MptPart{Num: 2, MD5: md5hex("b"), FQN: "b"})
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, prev != nil && prev.FQN == "a", "expected part 2 to be replaced")
+
+	listed, err := ListParts(id)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(listed) == 3, "expected 3 parts, got %d", len(listed))
+	for i, part := range listed {
+		tassert.Errorf(t, part.PartNumber == int64(i+1), "parts out of order: %d at %d", part.PartNumber, i)
+	}
+
+	// complete with a subset of parts
+	parts, err := CheckParts(id, []*PartInfo{{PartNumber: 1}, {PartNumber: 2, ETag: QuoteETag(md5hex("b"))}})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(parts) == 2 && parts[1].FQN == "b", "unexpected parts %+v", parts)
+
+	_, err = CheckParts(id, []*PartInfo{{PartNumber: 2}, {PartNumber: 1}})
+	tassert.Errorf(t, err != nil, "expected error on descending part numbers")
+	_, err = CheckParts(id, []*PartInfo{{PartNumber: 2, ETag: md5hex("a")}})
+	tassert.Errorf(t, err != nil, "expected ETag mismatch")
+	_, err = CheckParts(id, []*PartInfo{{PartNumber: 4}})
+	tassert.Errorf(t, err != nil, "expected error on missing part")
+
+	all, err := FinishUpload(id)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(all) == 3, "expected 3 parts, got %d", len(all))
+	_, _, ok := UploadObj(id)
+	tassert.Errorf(t, !ok, "upload %q must be gone", id)
+	_, err = AddPart(id, &MptPart{Num: 1})
+	tassert.Errorf(t, err != nil, "expected error adding part to a finished upload")
+}
+
+func TestMptLockUpload(t *testing.T) {
+	const id = "test-locked-upload"
+	InitUpload(id, "bck", "obj")
+	_, err := AddPart(id, &MptPart{Num: 1, MD5: md5hex("a"), FQN: "a"})
+	tassert.CheckFatal(t, err)
+
+	tassert.CheckFatal(t, LockUpload(id))
+	err = LockUpload(id)
+	tassert.Errorf(t, err != nil && !cmn.IsErrNotFound(err), "expected locked upload error, got %v", err)
+	_, err = AddPart(id, &MptPart{Num: 1, MD5: md5hex("b"), FQN: "b"})
+	tassert.Errorf(t, err != nil, "expected error replacing part of a locked upload")
+	parts, err := CheckParts(id, []*PartInfo{{PartNumber: 1}})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, parts[0].FQN == "a", "part 1 must not be replaced")
+
+	UnlockUpload(id)
+	_, err = AddPart(id, &MptPart{Num: 2, MD5: md5hex("b"), FQN: "b"})
+	tassert.CheckError(t, err)
+
+	_, err = FinishUpload(id)
+	tassert.CheckFatal(t, err)
+	err = LockUpload(id)
+	tassert.Errorf(t, cmn.IsErrNotFound(err), "expected not found error, got %v", err)
+}
+
+func TestMptParsePartNum(t *testing.T) {
+	for _, s := range []string{"1", "42", "10000"} {
+		_, err := ParsePartNum(s)
+		tassert.CheckError(t, err)
+	}
+	for _, s := range []string{"", "0", "-1", "10001", "abc"} {
+		_, err := ParsePartNum(s)
+		tassert.Errorf(t, err != nil, "expected error parsing %q", s)
+	}
+}
@@ -115,6 +115,10 @@ func FormatTime(t time.Time) string {
 }
 
 func lomMD5(lom *cluster.LOM) string {
+	// multipart upload (see mpt.go)
+	if v, exists := lom.GetCustomKey(cmn.ETag); exists && lom.Bck().IsAIS() {
+		return v
+	}
 	if v, exists := lom.GetCustomKey(cmn.SourceObjMD); exists && v == apc.ProviderAmazon {
 		if v, exists := lom.GetCustomKey(cmn.MD5ObjMD); exists {
 			return v
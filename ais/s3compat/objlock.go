@@ -0,0 +1,80 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// S3 object lock is mapped onto the bucket's object lock (`cmn.ObjLockConf`)
+// and the object's retain-until custom metadata (`cmn.RetainUntilObjMD`).
+// Per-object modes are not supported: the mode, if specified, must be the bucket's.
+
+// S3 object lock headers
+const (
+	HeaderObjLockMode        = "x-amz-object-lock-mode"
+	HeaderObjLockRetainUntil = "x-amz-object-lock-retain-until-date"
+	HeaderBypassGovernance   = "x-amz-bypass-governance-retention"
+	HeaderBckObjLockEnabled  = "x-amz-bucket-object-lock-enabled"
+)
+
+var errObjLockHeaders = errors.New("object lock mode and retain-until date must be specified together")
+
+// PUT: S3 object lock headers => retain-until
+func ObjLockFromHeader(hdr http.Header, lom *cluster.LOM) error {
+	mode, until := hdr.Get(HeaderObjLockMode), hdr.Get(HeaderObjLockRetainUntil)
+	if mode == "" && until == "" {
+		return nil
+	}
+	if mode == "" || until == "" {
+		return errObjLockHeaders
+	}
+	conf := &lom.Bprops().ObjLock
+	if !conf.Enabled {
+		return fmt.Errorf("bucket %s does not have object lock enabled", lom.Bck())
+	}
+	if !strings.EqualFold(mode, conf.Mode) {
+		return fmt.Errorf("object lock mode %q does not match the bucket's (%s)", mode, strings.ToUpper(conf.Mode))
+	}
+	t, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %v", HeaderObjLockRetainUntil, until, err)
+	}
+	lom.SetRetainUntil(t)
+	return nil
+}
+
+// HEAD and GET: retain-until => S3 object lock headers
+func SetObjLockHeaders(hdr http.Header, lom *cluster.LOM) {
+	bprops := lom.Bprops()
+	if bprops == nil || !bprops.ObjLock.Enabled {
+		return
+	}
+	conf := &bprops.ObjLock
+	if until := lom.RetainUntil(); !until.IsZero() {
+		hdr.Set(HeaderObjLockMode, strings.ToUpper(conf.Mode))
+		hdr.Set(HeaderObjLockRetainUntil, until.UTC().Format(time.RFC3339))
+	}
+}
+
+func IsBypassGovernance(hdr http.Header) bool {
+	return strings.EqualFold(hdr.Get(HeaderBypassGovernance), "true")
+}
+
+// PUT bucket: enable object lock at creation time (in governance mode)
+func ObjLockToUpdate(hdr http.Header) *cmn.BucketPropsToUpdate {
+	if !strings.EqualFold(hdr.Get(HeaderBckObjLockEnabled), "true") {
+		return nil
+	}
+	enabled := true
+	return &cmn.BucketPropsToUpdate{ObjLock: &cmn.ObjLockConfToUpdate{Enabled: &enabled}}
+}
@@ -0,0 +1,356 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// AWS Signature Version 4: authenticating requests via `Authorization` header
+// and via query string parameters (presigned URLs). See:
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+//
+// Requests authenticated via `Authorization` header must sign the host and the
+// payload: unsigned (and streaming) payloads are refused. The payload itself is
+// verified against the signed hash (`x-amz-content-sha256`) by whoever reads it -
+// see `VerifyPayload`. Presigned URLs do not sign the payload (as per AWS spec).
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4Terminator = "aws4_request"
+	sigV4DateFormat = "20060102T150405Z"
+	sigV4ScopeDate  = "20060102"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	sigV4MaxSkew    = 15 * time.Minute   // max clock skew between client and cluster
+	sigV4MaxExpires = 7 * 24 * time.Hour // max validity of a presigned URL
+
+	// Headers
+	HeaderAmzDate   = "X-Amz-Date"
+	HeaderAmzSHA256 = "X-Amz-Content-Sha256"
+
+	// Presigned URL query parameters
+	QparamAmzAlgorithm     = "X-Amz-Algorithm"
+	QparamAmzCredential    = "X-Amz-Credential"
+	QparamAmzDate          = "X-Amz-Date"
+	QparamAmzExpires       = "X-Amz-Expires"
+	QparamAmzSignedHeaders = "X-Amz-SignedHeaders"
+	QparamAmzSignature     = "X-Amz-Signature"
+)
+
+type SigV4 struct {
+	date          time.Time
+	AccessKey     string
+	scopeDate     string
+	region        string
+	service       string
+	signature     string
+	signedHeaders []string
+	expires       time.Duration // presigned URL only
+	presigned     bool
+}
+
+var (
+	ErrNoSigV4       = errors.New("missing AWS signature (v4)")
+	ErrSigV4Mismatch = errors.New("AWS signature (v4) does not match")
+	ErrSigV4Expired  = errors.New("AWS signature (v4) expired")
+	ErrSigV4Payload  = errors.New("AWS signature (v4): payload does not match " + HeaderAmzSHA256)
+)
+
+// verifies payload against its signed SHA256 (upon reading it in its entirety)
+type payloadReader struct {
+	io.ReadCloser
+	h        hash.Hash
+	expected string
+}
+
+// IsSigV4 returns true if the request carries AWS SigV4 authentication info
+func IsSigV4(r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get(apc.HdrAuthorization), sigV4Algorithm) {
+		return true
+	}
+	return r.URL.Query().Get(QparamAmzAlgorithm) == sigV4Algorithm
+}
+
+// ParseSigV4 parses AWS SigV4 authentication info either from the request's
+// `Authorization` header or from its query (presigned URL)
+func ParseSigV4(r *http.Request) (*SigV4, error) {
+	if hdr := r.Header.Get(apc.HdrAuthorization); hdr != "" {
+		return parseSigV4Header(r, hdr)
+	}
+	if q := r.URL.Query(); q.Get(QparamAmzAlgorithm) != "" {
+		return parseSigV4Query(r)
+	}
+	return nil, ErrNoSigV4
+}
+
+// Authorization: AWS4-HMAC-SHA256 Credential=<access-key>/<date>/<region>/<service>/aws4_request,
+// SignedHeaders=<h1;h2;...>, Signature=<hex>
+func parseSigV4Header(r *http.Request, hdr string) (*SigV4, error) {
+	if !strings.HasPrefix(hdr, sigV4Algorithm+" ") {
+		return nil, ErrNoSigV4
+	}
+	sig := &SigV4{}
+	for _, field := range strings.Split(hdr[len(sigV4Algorithm)+1:], ",") {
+		field = strings.TrimSpace(field)
+		idx := strings.IndexByte(field, '=')
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid AWS signature (v4) header field %q", field)
+		}
+		val := field[idx+1:]
+		switch field[:idx] {
+		case "Credential":
+			if err := sig.parseCredential(val); err != nil {
+				return nil, err
+			}
+		case "SignedHeaders":
+			sig.signedHeaders = strings.Split(val, ";")
+		case "Signature":
+			sig.signature = val
+		}
+	}
+	date, err := time.Parse(sigV4DateFormat, r.Header.Get(HeaderAmzDate))
+	if err != nil {
+		return nil, fmt.Errorf("invalid or missing %s header: %v", HeaderAmzDate, err)
+	}
+	sig.date = date
+	if !isPayloadHash(r.Header.Get(HeaderAmzSHA256)) {
+		return nil, fmt.Errorf("AWS signature (v4): %s must be the (hex-encoded) SHA256 of the payload, got %q",
+			HeaderAmzSHA256, r.Header.Get(HeaderAmzSHA256))
+	}
+	return sig, sig.validate()
+}
+
+func parseSigV4Query(r *http.Request) (*SigV4, error) {
+	q := r.URL.Query()
+	if algo := q.Get(QparamAmzAlgorithm); algo != sigV4Algorithm {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", algo)
+	}
+	sig := &SigV4{presigned: true, signature: q.Get(QparamAmzSignature)}
+	if err := sig.parseCredential(q.Get(QparamAmzCredential)); err != nil {
+		return nil, err
+	}
+	if sh := q.Get(QparamAmzSignedHeaders); sh != "" {
+		sig.signedHeaders = strings.Split(sh, ";")
+	}
+	date, err := time.Parse(sigV4DateFormat, q.Get(QparamAmzDate))
+	if err != nil {
+		return nil, fmt.Errorf("invalid or missing %s: %v", QparamAmzDate, err)
+	}
+	sig.date = date
+	secs, err := strconv.ParseInt(q.Get(QparamAmzExpires), 10, 64)
+	if err != nil || secs <= 0 || time.Duration(secs)*time.Second > sigV4MaxExpires {
+		return nil, fmt.Errorf("invalid %s %q", QparamAmzExpires, q.Get(QparamAmzExpires))
+	}
+	sig.expires = time.Duration(secs) * time.Second
+	return sig, sig.validate()
+}
+
+// <access-key>/<date>/<region>/<service>/aws4_request
+func (sig *SigV4) parseCredential(cred string) error {
+	parts := strings.Split(cred, "/")
+	if len(parts) != 5 || parts[0] == "" || parts[4] != sigV4Terminator {
+		return fmt.Errorf("invalid AWS signature (v4) credential %q", cred)
+	}
+	sig.AccessKey, sig.scopeDate, sig.region, sig.service = parts[0], parts[1], parts[2], parts[3]
+	return nil
+}
+
+func (sig *SigV4) validate() error {
+	if sig.AccessKey == "" || sig.signature == "" || len(sig.signedHeaders) == 0 {
+		return ErrNoSigV4
+	}
+	if !cos.StringInSlice("host", sig.signedHeaders) {
+		return errors.New("AWS signature (v4): 'host' header must be signed")
+	}
+	if sig.date.Format(sigV4ScopeDate) != sig.scopeDate {
+		return fmt.Errorf("AWS signature (v4) credential date %q does not match request date %s",
+			sig.scopeDate, sig.date.Format(sigV4DateFormat))
+	}
+	return nil
+}
+
+// Verify validates request time and signature given the secret part of the access key
+func (sig *SigV4) Verify(r *http.Request, secret string, now time.Time) error {
+	if sig.presigned {
+		if now.After(sig.date.Add(sig.expires)) {
+			return ErrSigV4Expired
+		}
+		if sig.date.After(now.Add(sigV4MaxSkew)) {
+			return fmt.Errorf("AWS signature (v4): request date %s is in the future", sig.date.Format(sigV4DateFormat))
+		}
+	} else if d := now.Sub(sig.date); d > sigV4MaxSkew || d < -sigV4MaxSkew {
+		return fmt.Errorf("AWS signature (v4): request time is too skewed (%v)", d)
+	}
+	expected := sig.sign(r, secret)
+	if !hmac.Equal([]byte(expected), []byte(sig.signature)) {
+		return ErrSigV4Mismatch
+	}
+	return nil
+}
+
+func (sig *SigV4) sign(r *http.Request, secret string) string {
+	var (
+		scope   = sig.scopeDate + "/" + sig.region + "/" + sig.service + "/" + sigV4Terminator
+		creq    = sig.canonicalRequest(r)
+		hash    = sha256.Sum256([]byte(creq))
+		toSign  = sigV4Algorithm + "\n" + sig.date.Format(sigV4DateFormat) + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+		signKey = hmacSHA256([]byte("AWS4"+secret), sig.scopeDate)
+	)
+	signKey = hmacSHA256(signKey, sig.region)
+	signKey = hmacSHA256(signKey, sig.service)
+	signKey = hmacSHA256(signKey, sigV4Terminator)
+	return hex.EncodeToString(hmacSHA256(signKey, toSign))
+}
+
+func (sig *SigV4) canonicalRequest(r *http.Request) string {
+	var sb strings.Builder
+	sb.WriteString(r.Method)
+	sb.WriteByte('\n')
+
+	// URI
+	uri := uriEncode(r.URL.Path, false /*encode slash*/)
+	if uri == "" {
+		uri = "/"
+	}
+	sb.WriteString(uri)
+	sb.WriteByte('\n')
+
+	// query
+	var (
+		q    = r.URL.Query()
+		keys = make([]string, 0, len(q))
+	)
+	for k := range q {
+		if sig.presigned && k == QparamAmzSignature {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		vals := q[k]
+		sort.Strings(vals)
+		for j, v := range vals {
+			if i > 0 || j > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(uriEncode(k, true))
+			sb.WriteByte('=')
+			sb.WriteString(uriEncode(v, true))
+		}
+	}
+	sb.WriteByte('\n')
+
+	// headers
+	for _, name := range sig.signedHeaders {
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(canonicalHeaderValue(r, name))
+		sb.WriteByte('\n')
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(strings.Join(sig.signedHeaders, ";"))
+	sb.WriteByte('\n')
+
+	// payload
+	if sig.presigned {
+		sb.WriteString(unsignedPayload)
+	} else {
+		sb.WriteString(r.Header.Get(HeaderAmzSHA256))
+	}
+	return sb.String()
+}
+
+// VerifyPayload makes the request's body verify itself against the signed
+// SHA256 (if any): reading the body fails at the end with `ErrSigV4Payload`
+// when the payload does not match.
+func VerifyPayload(r *http.Request) {
+	expected := r.Header.Get(HeaderAmzSHA256)
+	if !isPayloadHash(expected) || r.Body == nil || r.Body == http.NoBody {
+		return
+	}
+	r.Body = &payloadReader{ReadCloser: r.Body, h: sha256.New(), expected: strings.ToLower(expected)}
+}
+
+func (pr *payloadReader) Read(b []byte) (n int, err error) {
+	n, err = pr.ReadCloser.Read(b)
+	pr.h.Write(b[:n])
+	if err == io.EOF && hex.EncodeToString(pr.h.Sum(nil)) != pr.expected {
+		err = ErrSigV4Payload
+	}
+	return
+}
+
+func isPayloadHash(v string) bool {
+	if len(v) != 2*sha256.Size {
+		return false
+	}
+	_, err := hex.DecodeString(v)
+	return err == nil
+}
+
+func canonicalHeaderValue(r *http.Request, name string) string {
+	var vals []string
+	switch name {
+	case "host":
+		vals = []string{r.Host}
+	case "content-length":
+		if r.ContentLength >= 0 && r.Header.Get(name) == "" {
+			vals = []string{strconv.FormatInt(r.ContentLength, 10)}
+		} else {
+			vals = r.Header.Values(name)
+		}
+	default:
+		vals = r.Header.Values(name)
+	}
+	for i, v := range vals {
+		vals[i] = strings.Join(strings.Fields(v), " ") // trim and collapse sequential spaces
+	}
+	return strings.Join(vals, ",")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// URI-encode every byte except the unreserved characters: 'A'-'Z', 'a'-'z',
+// '0'-'9', '-', '.', '_', and '~' (and, optionally, forward slash)
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9'),
+			c == '-', c == '.', c == '_', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(hexDigits[c>>4])
+			sb.WriteByte(hexDigits[c&0xf])
+		}
+	}
+	return sb.String()
+}
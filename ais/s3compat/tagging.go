@@ -0,0 +1,87 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// Object tags are stored as (user) custom metadata - the same key-value pairs
+// that list-objects returns and filters on (see `cmn.ObjFilter`).
+// System keys (checksums, versions, etc.) are never exposed as tags and are
+// preserved when tags are replaced or deleted.
+
+// S3 limits
+const (
+	maxTags        = 10
+	maxTagKeyLen   = 128
+	maxTagValueLen = 256
+)
+
+type (
+	// GET(PUT) s3/bckName/objName?tagging response(request)
+	Tagging struct {
+		XMLName xml.Name `xml:"Tagging"`
+		TagSet  TagSet   `xml:"TagSet"`
+	}
+	TagSet struct {
+		Tags []Tag `xml:"Tag"`
+	}
+	Tag struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	}
+)
+
+var systemMD = cos.NewStringSet(
+	cmn.SourceObjMD, cmn.VersionObjMD, cmn.CRC32CObjMD, cmn.MD5ObjMD, cmn.ETag, cmn.OrigURLObjMD, cmn.WebObjMD,
+	cmn.RetainUntilObjMD,
+)
+
+func IsSystemMD(key string) bool { return systemMD.Contains(key) }
+
+func NewTagging(md cos.SimpleKVs) *Tagging {
+	tagging := &Tagging{TagSet: TagSet{Tags: make([]Tag, 0, len(md))}}
+	for k, v := range md {
+		if !IsSystemMD(k) {
+			tagging.TagSet.Tags = append(tagging.TagSet.Tags, Tag{Key: k, Value: v})
+		}
+	}
+	sort.Slice(tagging.TagSet.Tags, func(i, j int) bool { return tagging.TagSet.Tags[i].Key < tagging.TagSet.Tags[j].Key })
+	return tagging
+}
+
+func (t *Tagging) Validate() error {
+	if len(t.TagSet.Tags) > maxTags {
+		return fmt.Errorf("too many tags (%d), the maximum is %d", len(t.TagSet.Tags), maxTags)
+	}
+	keys := make(cos.StringSet, len(t.TagSet.Tags))
+	for _, tag := range t.TagSet.Tags {
+		switch {
+		case tag.Key == "" || len(tag.Key) > maxTagKeyLen:
+			return fmt.Errorf("invalid tag key %q (expecting 1 to %d characters)", tag.Key, maxTagKeyLen)
+		case len(tag.Value) > maxTagValueLen:
+			return fmt.Errorf("tag %q: value is too long (maximum %d characters)", tag.Key, maxTagValueLen)
+		case IsSystemMD(tag.Key):
+			return fmt.Errorf("tag key %q is reserved", tag.Key)
+		case keys.Contains(tag.Key):
+			return fmt.Errorf("duplicate tag key %q", tag.Key)
+		}
+		keys.Add(tag.Key)
+	}
+	return nil
+}
+
+func (t *Tagging) MustMarshal(sgl *memsys.SGL) {
+	sgl.Write([]byte(xml.Header))
+	err := xml.NewEncoder(sgl).Encode(t)
+	cos.AssertNoErr(err)
+}
@@ -0,0 +1,41 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestTagging(t *testing.T) {
+	md := cos.SimpleKVs{"label": "train", "epoch": "7", cmn.SourceObjMD: "aws", cmn.MD5ObjMD: "abc"}
+	tagging := NewTagging(md)
+	tassert.Fatalf(t, len(tagging.TagSet.Tags) == 2, "expected 2 tags, got %+v", tagging.TagSet.Tags)
+	tassert.Errorf(t, tagging.TagSet.Tags[0].Key == "epoch" && tagging.TagSet.Tags[1].Key == "label",
+		"expected sorted user keys, got %+v", tagging.TagSet.Tags)
+
+	body := `<Tagging><TagSet><Tag><Key>k1</Key><Value>v1</Value></Tag><Tag><Key>k2</Key><Value></Value></Tag></TagSet></Tagging>`
+	req := &Tagging{}
+	tassert.CheckFatal(t, xml.Unmarshal([]byte(body), req))
+	tassert.CheckFatal(t, req.Validate())
+	tassert.Errorf(t, len(req.TagSet.Tags) == 2 && req.TagSet.Tags[0].Value == "v1", "unexpected %+v", req.TagSet.Tags)
+
+	invalid := []*Tagging{
+		{TagSet: TagSet{Tags: []Tag{{Key: "", Value: "v"}}}},
+		{TagSet: TagSet{Tags: []Tag{{Key: strings.Repeat("k", maxTagKeyLen+1)}}}},
+		{TagSet: TagSet{Tags: []Tag{{Key: "k", Value: strings.Repeat("v", maxTagValueLen+1)}}}},
+		{TagSet: TagSet{Tags: []Tag{{Key: "k"}, {Key: "k"}}}},
+		{TagSet: TagSet{Tags: []Tag{{Key: cmn.VersionObjMD}}}},
+		{TagSet: TagSet{Tags: make([]Tag, maxTags+1)}},
+	}
+	for i, tagging := range invalid {
+		tassert.Errorf(t, tagging.Validate() != nil, "%d: expected validation error", i)
+	}
+}
@@ -31,11 +31,13 @@ import (
 	"github.com/NVIDIA/aistore/cmn/feat"
 	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/dsort"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/etl"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/fs/health"
+	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/nl"
@@ -303,13 +305,19 @@ func (t *target) Run() error {
 		glog.Errorln("")
 	}
 
-	// register object type and workfile type
+	// register object type, workfile type, and soft-deleted objects
 	if err := fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{}); err != nil {
 		cos.ExitLogf("%v", err)
 	}
 	if err := fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{}); err != nil {
 		cos.ExitLogf("%v", err)
 	}
+	if err := fs.CSM.Reg(fs.DeletedObjType, &fs.DeletedObjContentResolver{}); err != nil {
+		cos.ExitLogf("%v", err)
+	}
+	if err := fs.CSM.Reg(fs.ArchIdxType, &fs.ArchIdxContentResolver{}); err != nil {
+		cos.ExitLogf("%v", err)
+	}
 
 	// Init meta-owners and load local instances
 	t.owner.bmd.init()
@@ -373,16 +381,25 @@ func (t *target) Run() error {
 	}
 	t.db = db
 	defer cos.Close(db)
+	t.initRepl()
 
 	// transactions
 	t.transactions.init(t)
 
+	// bucket lifecycle rules
+	hk.Reg(apc.ActLifecycle+hk.NameSuffix, t.lcycleHK, lcycleInterval)
+	// bucket replication
+	hk.Reg(apc.ActReplicate+hk.NameSuffix, t.replHK, replInterval)
+
 	t.reb = reb.New(t, config)
 	t.res = res.New(t)
 
 	// register storage target's handler(s) and start listening
 	t.initRecvHandlers()
 
+	go t.initRetained()
+	go t.rmOrphanMptParts()
+
 	ec.Init(t)
 	mirror.Init()
 
@@ -403,6 +420,8 @@ func (t *target) Run() error {
 	dsort.InitManagers(db)
 	dsort.RegisterNode(t.owner.smap, t.owner.bmd, t.si, t, t.statsT)
 
+	downloader.InitSync(t, t.statsT)
+
 	defer etl.StopAll(t) // Always try to stop running ETLs.
 
 	err = t.htrun.run()
@@ -448,6 +467,7 @@ func (t *target) initRecvHandlers() {
 
 		{r: apc.Download, h: t.downloadHandler, net: accessNetIntraControl},
 		{r: apc.Sort, h: dsort.SortHandler, net: accessControlData},
+		{r: apc.Batch, h: t.batchHandler, net: accessNetIntraControl},
 		{r: apc.ETL, h: t.etlHandler, net: accessNetAll},
 
 		{r: "/" + apc.S3, h: t.s3Handler, net: accessNetPublicData},
@@ -528,8 +548,10 @@ func (t *target) ecHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		t.httpecget(w, r)
+	case http.MethodPost:
+		t.httpecpost(w, r)
 	default:
-		cmn.WriteErr405(w, r, http.MethodGet)
+		cmn.WriteErr405(w, r, http.MethodGet, http.MethodPost)
 	}
 }
 
@@ -1002,8 +1024,11 @@ func (t *target) getObject(w http.ResponseWriter, r *http.Request, dpq *dpq, bck
 		originalURL := dpq.origURL // query.Get(apc.QparamOrigURL)
 		goi.ctx = context.WithValue(goi.ctx, cos.CtxOriginalURL, originalURL)
 	}
-	if errCode, err := goi.getObject(); err != nil && err != errSendingResp {
-		t.writeErr(w, r, err, errCode)
+	if errCode, err := goi.getObject(); err != nil {
+		if err != errSendingResp {
+			t.writeErr(w, r, err, errCode)
+		}
+		t.statsBck(goi.lom.Bucket(), cos.NamedVal64{Name: stats.ErrCount, Value: 1})
 	}
 	lom = goi.lom
 	freeGetObjInfo(goi)
@@ -1106,6 +1131,9 @@ func (t *target) httpobjput(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		t.fsErr(err, lom.FQN)
 		t.writeErr(w, r, err, errCode)
+		if !t2tput {
+			t.statsBck(lom.Bucket(), cos.NamedVal64{Name: stats.ErrCount, Value: 1})
+		}
 	}
 }
 
@@ -1133,15 +1161,20 @@ func (t *target) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	errCode, err := t.DeleteObject(lom, evict)
+	bypass := cos.IsParseBool(r.Header.Get(apc.HdrBypassGovernance))
+	errCode, err := t.deleteObject(lom, evict, bypass)
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			t.writeErrSilentf(w, r, http.StatusNotFound, "object %s/%s doesn't exist", lom.Bucket(), lom.ObjName)
 		} else {
 			t.writeErr(w, r, err, errCode)
 		}
+		t.statsBck(lom.Bucket(), cos.NamedVal64{Name: stats.ErrCount, Value: 1})
 		return
 	}
+	if !evict {
+		t.statsBck(lom.Bucket(), cos.NamedVal64{Name: stats.DeleteCount, Value: 1})
+	}
 	// EC cleanup if EC is enabled
 	ec.ECM.CleanupObject(lom)
 }
@@ -1160,11 +1193,38 @@ func (t *target) httpobjpost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		t.objMv(w, r, msg)
+	case apc.ActUndeleteObject:
+		if isRedirect(r.URL.Query()) == "" {
+			t.writeErrf(w, r, "%s: %s-%s(obj) is expected to be redirected", t.si, r.Method, msg.Action)
+			return
+		}
+		t.objUndelete(w, r)
 	default:
 		t.writeErrAct(w, r, msg.Action)
 	}
 }
 
+func (t *target) objUndelete(w http.ResponseWriter, r *http.Request) {
+	apireq := apiReqAlloc(2, apc.URLPathObjects.L, false)
+	defer apiReqFree(apireq)
+	if err := t.parseReq(w, r, apireq); err != nil {
+		return
+	}
+	lom := cluster.AllocLOM(apireq.items[1])
+	defer cluster.FreeLOM(lom)
+	if err := lom.InitBck(apireq.bck.Bucket()); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	if errCode, err := t.undeleteObject(lom); err != nil {
+		t.writeErr(w, r, err, errCode)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: undeleted %s", t, lom)
+	}
+}
+
 // HEAD /v1/objects/<bucket-name>/<object-name>
 func (t *target) httpobjhead(w http.ResponseWriter, r *http.Request) {
 	apireq := apiReqAlloc(2, apc.URLPathObjects.L, false)
@@ -1271,6 +1331,7 @@ func (t *target) headObject(w http.ResponseWriter, r *http.Request, query url.Va
 				op.EC.DataSlices = md.Data
 				op.EC.ParitySlices = md.Parity
 				op.EC.IsECCopy = md.IsCopy
+				op.EC.Policy = md.Policy
 				op.EC.Generation = md.Generation
 			}
 		}
@@ -1334,6 +1395,11 @@ func (t *target) httpobjpatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	delOldSetNew := cos.IsParseBool(apireq.query.Get(apc.QparamNewCustom))
+	bypass := cos.IsParseBool(r.Header.Get(apc.HdrBypassGovernance))
+	if errCode, err := checkRetainUntil(lom, custom, delOldSetNew, bypass); err != nil {
+		t.writeErr(w, r, err, errCode)
+		return
+	}
 	if delOldSetNew {
 		lom.SetCustomMD(custom)
 	} else {
@@ -1344,6 +1410,29 @@ func (t *target) httpobjpatch(w http.ResponseWriter, r *http.Request) {
 	lom.Persist()
 }
 
+// object lock: retain-until can be extended but not shortened or removed
+// (except in governance mode when bypassing - see cmn.ObjLockConf)
+func checkRetainUntil(lom *cluster.LOM, custom cos.SimpleKVs, delOldSetNew, bypass bool) (errCode int, err error) {
+	var (
+		until time.Time
+		prev  = lom.RetainUntil()
+	)
+	if s, ok := custom[cmn.RetainUntilObjMD]; ok {
+		if until, err = time.Parse(time.RFC3339, s); err != nil {
+			return http.StatusBadRequest, fmt.Errorf("%s: invalid %q (expecting RFC 3339 time): %v",
+				lom, cmn.RetainUntilObjMD, err)
+		}
+	} else if !delOldSetNew {
+		return
+	}
+	if until.Before(prev) {
+		if err = lom.CheckRetained(bypass); err != nil {
+			errCode = http.StatusForbidden
+		}
+	}
+	return
+}
+
 //////////////////////
 // httpec* handlers //
 //////////////////////
@@ -1387,6 +1476,48 @@ func (t *target) sendECMetafile(w http.ResponseWriter, r *http.Request, bck *clu
 	w.Write(md.NewPack())
 }
 
+// POST /v1/ec/metas/bucket-name (body: object names)
+func (t *target) httpecpost(w http.ResponseWriter, r *http.Request) {
+	apireq := apiReqAlloc(2, apc.URLPathEC.L, false)
+	apireq.bckIdx = 1
+	if err := t.parseReq(w, r, apireq); err != nil {
+		apiReqFree(apireq)
+		return
+	}
+	if apireq.items[0] == ec.URLMetas {
+		t.sendECMetafiles(w, r, apireq.bck)
+	} else {
+		t.writeErrURL(w, r)
+	}
+	apiReqFree(apireq)
+}
+
+// Returns metadata of multiple CTs (the ones that are not found are omitted).
+func (t *target) sendECMetafiles(w http.ResponseWriter, r *http.Request, bck *cluster.Bck) {
+	var objNames []string
+	if err := cmn.ReadJSON(w, r, &objNames); err != nil {
+		return
+	}
+	if err := bck.Init(t.owner.bmd); err != nil {
+		if !cmn.IsErrRemoteBckNotFound(err) { // is ais
+			t.writeErrSilent(w, r, err)
+			return
+		}
+	}
+	mds := make(map[string]*ec.Metadata, len(objNames))
+	for _, objName := range objNames {
+		md, err := ec.ObjectMetadata(bck, objName)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				glog.Errorf("%s: %s/%s: %v", t, bck, objName, err)
+			}
+			continue
+		}
+		mds[objName] = md
+	}
+	t.writeJSON(w, r, mds, "ec-metas")
+}
+
 func (t *target) sendECCT(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string) {
 	lom := cluster.AllocLOM(objName)
 	defer cluster.FreeLOM(lom)
@@ -1543,6 +1674,9 @@ func (t *target) doAppendArch(r *http.Request, lom *cluster.LOM, started time.Ti
 		}
 		return http.StatusInternalServerError, err
 	}
+	if err := lom.CheckRetained(false /*bypass governance*/); err != nil {
+		return http.StatusForbidden, err
+	}
 	aaoi := &appendArchObjInfo{
 		started:  started,
 		t:        t,
@@ -1586,6 +1720,10 @@ func (t *target) putMirror(lom *cluster.LOM) {
 }
 
 func (t *target) DeleteObject(lom *cluster.LOM, evict bool) (int, error) {
+	return t.deleteObject(lom, evict, false /*bypass governance*/)
+}
+
+func (t *target) deleteObject(lom *cluster.LOM, evict, bypassGovernance bool) (int, error) {
 	var (
 		aisErr, backendErr         error
 		aisErrCode, backendErrCode int
@@ -1596,6 +1734,9 @@ func (t *target) DeleteObject(lom *cluster.LOM, evict bool) (int, error) {
 
 	delFromBackend = lom.Bck().IsRemote() && !evict
 	if err := lom.Load(false /*cache it*/, true /*locked*/); err == nil {
+		if err := lom.CheckRetained(bypassGovernance); err != nil {
+			return http.StatusForbidden, err
+		}
 		delFromAIS = true
 	} else if !cmn.IsObjNotExist(err) {
 		return 0, err
@@ -1614,7 +1755,11 @@ func (t *target) DeleteObject(lom *cluster.LOM, evict bool) (int, error) {
 	}
 	if delFromAIS {
 		size := lom.SizeBytes()
-		aisErr = lom.Remove()
+		if !evict && lom.Bprops().SoftDelete.Enabled {
+			aisErr = t.softDelete(lom)
+		} else {
+			aisErr = lom.Remove()
+		}
 		if aisErr != nil {
 			if !os.IsNotExist(aisErr) {
 				if backendErr != nil {
@@ -1633,9 +1778,69 @@ func (t *target) DeleteObject(lom *cluster.LOM, evict bool) (int, error) {
 	if backendErr != nil {
 		return backendErrCode, backendErr
 	}
+	if aisErr == nil && !evict {
+		t.replicate(lom.Bck(), mirror.ReplDel, lom.ObjName)
+	}
 	return aisErrCode, aisErr
 }
 
+// keep the object (sans copies) for a while so that it could be undeleted - see cmn.SoftDelConf
+// (caller must take w-lock)
+func (t *target) softDelete(lom *cluster.LOM) error {
+	if lom.HasCopies() {
+		if err := lom.DelAllCopies(); err != nil {
+			glog.Errorf("%s: failed to delete copies of %s: %v", t, lom, err)
+		}
+	}
+	lom.Uncache(true /*delDirty*/)
+	return lom.MpathInfo().SoftDeleteObj(lom.Bucket(), lom.ObjName, lom.FQN)
+}
+
+// undelete (restore) soft-deleted object and, if need be, its redundancy (copies or EC)
+func (t *target) undeleteObject(lom *cluster.LOM) (errCode int, err error) {
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err = lom.Load(false /*cache it*/, true /*locked*/); err == nil {
+		return http.StatusConflict, fmt.Errorf("%s: cannot undelete %s - object exists", t, lom)
+	}
+	if !cmn.IsObjNotExist(err) {
+		return 0, err
+	}
+	mi, dfqn := fs.FindDeletedObj(lom.Bucket(), lom.ObjName)
+	if mi == nil {
+		return http.StatusNotFound, cmn.NewErrNotFound("%s: deleted object %s", t.si, lom.FullName())
+	}
+	if mi.Path == lom.MpathInfo().Path {
+		err = cos.Rename(dfqn, lom.FQN)
+	} else {
+		// mountpaths have changed since the object was deleted
+		buf, slab := t.gmm.Alloc()
+		_, _, err = cos.CopyFile(dfqn, lom.FQN, buf, cos.ChecksumNone)
+		slab.Free(buf)
+		if err == nil {
+			var md []byte
+			if md, err = fs.GetXattr(dfqn, cluster.XattrLOM); err == nil {
+				err = fs.SetXattr(lom.FQN, cluster.XattrLOM, md)
+			}
+		}
+		if err == nil {
+			err = cos.RemoveFile(dfqn)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err = lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		return 0, err
+	}
+	if err = ec.ECM.EncodeObject(lom); err != nil && err != ec.ErrorECDisabled {
+		return 0, err
+	}
+	t.putMirror(lom)
+	t.replicate(lom.Bck(), mirror.ReplPut, lom.ObjName)
+	return 0, nil
+}
+
 ///////////////////
 // RENAME OBJECT //
 ///////////////////
@@ -1665,6 +1870,18 @@ func (t *target) objMv(w http.ResponseWriter, r *http.Request, msg *apc.ActionMs
 		t.writeErrf(w, r, "%s: cannot rename/move object %s onto itself", t.si, lom)
 		return
 	}
+	if lom.Bprops().ObjLock.Enabled {
+		var errRetained error
+		lom.Lock(false)
+		if lom.Load(true /*cache it*/, true /*locked*/) == nil {
+			errRetained = lom.CheckRetained(false /*bypass governance*/)
+		}
+		lom.Unlock(false)
+		if errRetained != nil {
+			t.writeErr(w, r, errRetained, http.StatusForbidden)
+			return
+		}
+	}
 	buf, slab := t.gmm.Alloc()
 	coi := allocCopyObjInfo()
 	{
@@ -1680,10 +1897,13 @@ func (t *target) objMv(w http.ResponseWriter, r *http.Request, msg *apc.ActionMs
 		t.writeErr(w, r, err)
 		return
 	}
+	// (the new object is replicated by `copyObject`)
 	// TODO: combine copy+delete under a single write lock
 	lom.Lock(true)
 	if err = lom.Remove(); err != nil {
 		glog.Warningf("%s: failed to delete renamed object %s (new name %s): %v", t, lom, msg.Name, err)
+	} else {
+		t.replicate(lom.Bck(), mirror.ReplDel, lom.ObjName)
 	}
 	lom.Unlock(true)
 }
@@ -1708,6 +1928,13 @@ func (t *target) fsErr(err error, filepath string) {
 	t.fshc.OnErr(filepath)
 }
 
+// statsBck updates per-bucket stats (see stats.Trunner.AddBck)
+func (t *target) statsBck(bck *cmn.Bck, nvs ...cos.NamedVal64) {
+	if tstats, ok := t.statsT.(*stats.Trunner); ok {
+		tstats.AddBck(bck, nvs...)
+	}
+}
+
 func (t *target) runResilver(args res.Args, wg *sync.WaitGroup) {
 	// with no cluster-wide UUID it's a local run
 	if args.UUID == "" {
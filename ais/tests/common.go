@@ -983,6 +983,8 @@ func initFS() {
 	_ = fs.CSM.Reg(fs.WorkfileType, &fs.WorkfileContentResolver{})
 	_ = fs.CSM.Reg(fs.ECSliceType, &fs.ECSliceContentResolver{})
 	_ = fs.CSM.Reg(fs.ECMetaType, &fs.ECMetaContentResolver{})
+	_ = fs.CSM.Reg(fs.DeletedObjType, &fs.DeletedObjContentResolver{})
+	_ = fs.CSM.Reg(fs.ArchIdxType, &fs.ArchIdxContentResolver{})
 }
 
 func initMountpaths(t *testing.T, proxyURL string) {
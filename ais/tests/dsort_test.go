@@ -76,6 +76,7 @@ type (
 		tarballSize           int
 		outputShardCnt        int
 		recordDuplicationsCnt int
+		outputShardRecords    int
 		recordExts            []string
 
 		extension       string
@@ -199,6 +200,9 @@ func (df *dsortFramework) init() {
 		pt, err := cos.ParseBashTemplate(df.outputTempl)
 		cos.AssertNoErr(err)
 		df.outputShardCnt = int(pt.Count())
+	} else if df.outputShardRecords > 0 {
+		df.outputShardSize = fmt.Sprintf("%d records", df.outputShardRecords)
+		df.outputShardCnt = (df.tarballCnt * df.fileInTarballCnt) / df.outputShardRecords
 	} else {
 		outputShardSize := int64(10 * df.fileInTarballCnt * df.fileInTarballSize)
 		df.outputShardSize = cos.B2S(outputShardSize, 0)
@@ -277,6 +281,8 @@ func (df *dsortFramework) createInputShards() {
 				err = archive.CreateTarWithRandomFiles(tarName, df.fileInTarballCnt, df.fileInTarballSize, duplication, nil, nil)
 			} else if df.extension == cos.ExtZip {
 				err = archive.CreateZipWithRandomFiles(tarName, df.fileInTarballCnt, df.fileInTarballSize, nil)
+			} else if df.extension == cos.ExtMsgpack {
+				err = archive.CreateMsgpackWithRandomFiles(tarName, df.fileInTarballCnt, df.fileInTarballSize, nil)
 			} else {
 				df.m.t.Fail()
 			}
@@ -385,6 +391,8 @@ func (df *dsortFramework) checkOutputShards(zeros int) {
 				files, err = archive.GetFileInfosFromTarBuffer(buffer, gzipped)
 			} else if df.extension == cos.ExtZip {
 				files, err = archive.GetFileInfosFromZipBuffer(buffer)
+			} else if df.extension == cos.ExtMsgpack {
+				files, err = archive.GetFileInfosFromMsgpackBuffer(buffer)
 			}
 
 			tassert.CheckFatal(df.m.t, err)
@@ -1115,6 +1123,86 @@ func TestDistributedSortZip(t *testing.T) {
 	)
 }
 
+func TestDistributedSortMsgpack(t *testing.T) {
+	tutils.CheckSkip(t, tutils.SkipTestArgs{Long: true})
+
+	runDSortTest(
+		t, dsortTestSpec{p: true, types: dsorterTypes},
+		func(dsorterType string, t *testing.T) {
+			var (
+				err error
+				m   = &ioContext{
+					t: t,
+				}
+				df = &dsortFramework{
+					m:                m,
+					dsorterType:      dsorterType,
+					tarballCnt:       500,
+					fileInTarballCnt: 100,
+					extension:        cos.ExtMsgpack,
+					maxMemUsage:      "99%",
+				}
+			)
+
+			m.initWithCleanupAndSaveState()
+			m.expectTargets(3)
+			tutils.CreateBucketWithCleanup(t, m.proxyURL, m.bck, nil)
+
+			df.init()
+			df.createInputShards()
+
+			tlog.Logln("starting distributed sort (.msgpack)...")
+			df.start()
+
+			_, err = tutils.WaitForDSortToFinish(m.proxyURL, df.managerUUID)
+			tassert.CheckFatal(t, err)
+			tlog.Logln("finished distributed sort")
+
+			df.checkMetrics(false /* expectAbort */)
+			df.checkOutputShards(5)
+		},
+	)
+}
+
+func TestDistributedSortWithOutputShardRecords(t *testing.T) {
+	runDSortTest(
+		t, dsortTestSpec{p: true, types: dsorterTypes},
+		func(dsorterType string, t *testing.T) {
+			var (
+				err error
+				m   = &ioContext{
+					t: t,
+				}
+				df = &dsortFramework{
+					m:                  m,
+					dsorterType:        dsorterType,
+					tarballCnt:         100,
+					fileInTarballCnt:   10,
+					outputShardRecords: 50,
+					maxMemUsage:        "99%",
+				}
+			)
+
+			m.initWithCleanupAndSaveState()
+			m.expectTargets(3)
+			tutils.CreateBucketWithCleanup(t, m.proxyURL, m.bck, nil)
+
+			df.init()
+			df.createInputShards()
+
+			tlog.Logln("starting distributed sort with output shard size in records...")
+			df.start()
+
+			_, err = tutils.WaitForDSortToFinish(m.proxyURL, df.managerUUID)
+			tassert.CheckFatal(t, err)
+			tlog.Logln("finished distributed sort")
+
+			df.checkMetrics(false /* expectAbort */)
+			df.checkOutputShards(5)
+		},
+	)
+}
+
 func TestDistributedSortWithCompression(t *testing.T) {
 	tutils.CheckSkip(t, tutils.SkipTestArgs{Long: true})
 
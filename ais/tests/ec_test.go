@@ -5,6 +5,7 @@
 package integration
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
 	"os"
@@ -1020,6 +1021,73 @@ func TestECChecksum(t *testing.T) {
 	tassert.Fatalf(t, err != nil, "Object should not be restored when checksums are wrong")
 }
 
+// Corrupts a slice and checks that EC scrubber repairs it
+func TestECScrub(t *testing.T) {
+	if containers.DockerRunning() {
+		t.Skip(fmt.Sprintf("test %q requires Xattributes to be set, doesn't work with docker", t.Name()))
+	}
+
+	var (
+		proxyURL = tutils.RandomProxyURL()
+		bck      = cmn.Bck{
+			Name:     testBucketName + "-ec-scrub",
+			Provider: apc.ProviderAIS,
+		}
+		slicePath string
+	)
+
+	o := ecOptions{
+		minTargets: 4,
+		dataCnt:    2,
+		parityCnt:  1,
+		pattern:    "obj-scrub-%04d",
+	}.init(t, proxyURL)
+	baseParams := tutils.BaseAPIParams(proxyURL)
+	initMountpaths(t, proxyURL)
+
+	newLocalBckWithProps(t, baseParams, bck, defaultECBckProps(o), o)
+
+	objName := fmt.Sprintf(o.pattern, 1)
+	foundParts, mainObjPath := createECFile(t, baseParams, bck, objName, o)
+	for k := range foundParts {
+		ct, err := cluster.NewCTFromFQN(k, nil)
+		tassert.CheckFatal(t, err)
+		if k != mainObjPath && ct.ContentType() == fs.ECSliceType {
+			slicePath = k
+			break
+		}
+	}
+	tassert.Fatalf(t, slicePath != "", "no slices of %s found", objName)
+
+	orig, err := os.ReadFile(slicePath)
+	tassert.CheckFatal(t, err)
+	damaged := make([]byte, len(orig))
+	for i := range orig {
+		damaged[i] = ^orig[i]
+	}
+	tlog.Logf("Corrupting slice %s\n", slicePath)
+	tassert.CheckFatal(t, os.WriteFile(slicePath, damaged, cos.PermRWR))
+
+	xactArgs := api.XactReqArgs{Kind: apc.ActECScrub, Bck: bck, Timeout: rebalanceTimeout}
+	xactArgs.ID, err = api.StartXaction(baseParams, xactArgs)
+	tassert.CheckFatal(t, err)
+	_, err = api.WaitForXactionIC(baseParams, xactArgs)
+	tassert.CheckFatal(t, err)
+
+	// the main target re-encodes the object asynchronously
+	deadline := time.Now().Add(ECPutTimeOut)
+	for {
+		b, err := os.ReadFile(slicePath)
+		if err == nil && bytes.Equal(b, orig) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("slice %s has not been repaired", slicePath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
 func TestECEnabledDisabledEnabled(t *testing.T) {
 	tutils.CheckSkip(t, tutils.SkipTestArgs{Long: true})
 
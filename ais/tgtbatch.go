@@ -0,0 +1,126 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+// get-batch (target side): read the requested objects and archived files, and send them,
+// in order, to the requesting proxy over a transport stream (see ais/prxbatch.go)
+
+// POST /v1/batch
+func (t *target) batchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		cmn.WriteErr405(w, r, http.MethodPost)
+		return
+	}
+	if _, err := t.checkRESTItems(w, r, 0, false, apc.URLPathBatch.L); err != nil {
+		return
+	}
+	req := &batchReq{}
+	if err := cmn.ReadJSON(w, r, req); err != nil {
+		return
+	}
+	var (
+		callerID = r.Header.Get(apc.HdrCallerID)
+		psi      = t.owner.smap.get().GetProxy(callerID)
+	)
+	if psi == nil {
+		t.writeErrf(w, r, "%s: get-batch[%s] from unknown proxy %q", t, req.ID, callerID)
+		return
+	}
+	var (
+		dstURL = psi.URL(cmn.NetIntraData) + transport.ObjURLPath(batchTrname)
+		stream = transport.NewObjStream(transport.NewIntraDataClient(), dstURL, psi.ID(), nil)
+		ctx    = context.Background()
+	)
+	for i := range req.Entries {
+		t.sendBatchEntry(ctx, stream, req.ID, &req.Entries[i])
+	}
+	stream.Fin()
+}
+
+func (t *target) sendBatchEntry(ctx context.Context, stream *transport.Stream, id string, entry *batchEntry) {
+	var (
+		lom = cluster.AllocLOM(entry.ObjName)
+		hdr = transport.ObjHdr{Bck: entry.Bck, ObjName: entry.ObjName}
+	)
+	defer cluster.FreeLOM(lom)
+	reader, errCode, err := t.openBatchEntry(ctx, lom, entry)
+	if err != nil {
+		if errCode == 0 {
+			errCode = http.StatusInternalServerError
+			if cmn.IsObjNotExist(err) || cmn.IsErrNotFound(err) || cmn.IsErrBucketNought(err) {
+				errCode = http.StatusNotFound
+			}
+		}
+		if errCode != http.StatusNotFound {
+			glog.Errorf("%s: get-batch[%s]: %v(%d)", t, id, err, errCode)
+		}
+		hdr.Opaque = packBatchOpaque(id, entry.Idx, errCode, err.Error())
+		stream.Send(&transport.Obj{Hdr: hdr})
+		return
+	}
+	size := reader.Size()
+	hdr.ObjAttrs.Size = size
+	hdr.ObjAttrs.Atime = lom.AtimeUnix()
+	hdr.Opaque = packBatchOpaque(id, entry.Idx, 0, "")
+	if err := stream.Send(&transport.Obj{Hdr: hdr, Reader: reader}); err != nil {
+		glog.Errorf("%s: get-batch[%s]: failed to send %s: %v", t, id, lom, err)
+		return
+	}
+	t.statsT.AddMany(
+		cos.NamedVal64{Name: stats.GetCount, Value: 1},
+		cos.NamedVal64{Name: stats.GetThroughput, Value: size},
+	)
+}
+
+// open the object (cold-GET remote object if need be) or the archived file
+func (t *target) openBatchEntry(ctx context.Context, lom *cluster.LOM, entry *batchEntry) (reader cos.ReadCloseSizer,
+	errCode int, err error) {
+	if err = lom.InitBck(&entry.Bck); err != nil {
+		return
+	}
+	if err = lom.Load(true /*cache it*/, false /*locked*/); err != nil {
+		if !cmn.IsObjNotExist(err) || !lom.Bck().IsRemote() {
+			return
+		}
+		if errCode, err = t.GetCold(ctx, lom, cmn.OwtGetLock); err != nil {
+			return
+		}
+	}
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	fh, err := os.Open(lom.FQN)
+	if err != nil {
+		return
+	}
+	if entry.ArchPath == "" {
+		return cos.NewSizedRC(fh, lom.SizeBytes()), 0, nil
+	}
+	var (
+		mime string
+		csl  cos.ReadCloseSizer
+	)
+	if mime, err = archMime("", lom.ObjName, fh, t.smm); err == nil {
+		csl, err = archOpen(lom, fh, mime, entry.ArchPath)
+	}
+	if err != nil {
+		fh.Close()
+		return
+	}
+	return cos.NewDeferRCS(csl, func() { fh.Close() }), 0, nil
+}
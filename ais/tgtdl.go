@@ -42,48 +42,34 @@ func (t *target) downloadHandler(w http.ResponseWriter, r *http.Request) {
 	downloaderXact := xctn.(*downloader.Downloader)
 	switch r.Method {
 	case http.MethodPost:
-		if _, err := t.checkRESTItems(w, r, 0, false, apc.URLPathDownload.L); err != nil {
+		items, err := t.checkRESTItems(w, r, 0, true, apc.URLPathDownload.L)
+		if err != nil {
 			return
 		}
 		var (
 			uuid             = r.URL.Query().Get(apc.QparamUUID)
-			dlb              = downloader.DlBody{}
 			progressInterval = downloader.DownloadProgressInterval
+			dlJob            downloader.DlJob
 		)
 		if uuid == "" {
 			debug.Assert(false)
 			t.writeErrMsg(w, r, "missing UUID in query")
 			return
 		}
-		if err := cmn.ReadJSON(w, r, &dlb); err != nil {
-			return
-		}
-
-		dlBodyBase := downloader.DlBase{}
-		if err := jsoniter.Unmarshal(dlb.RawMessage, &dlBodyBase); err != nil {
-			err = fmt.Errorf(cmn.FmtErrUnmarshal, t, "download message", cmn.BytesHead(dlb.RawMessage), err)
-			t.writeErr(w, r, err)
-			return
-		}
-
-		if dlBodyBase.ProgressInterval != "" {
-			if dur, err := time.ParseDuration(dlBodyBase.ProgressInterval); err == nil {
-				progressInterval = dur
-			} else {
-				t.writeErrf(w, r, "%s: invalid progress interval %q, err: %v",
-					t.si, dlBodyBase.ProgressInterval, err)
+		if len(items) > 0 {
+			if items[0] != apc.Retry {
+				t.writeErrAct(w, r, items[0])
 				return
 			}
-		}
-
-		bck := cluster.CloneBck(&dlBodyBase.Bck)
-		if err := bck.Init(t.Bowner()); err != nil {
-			t.writeErr(w, r, err)
-			return
-		}
-		dlJob, err := downloader.ParseStartDownloadRequest(t, bck, uuid, dlb, downloaderXact)
-		if err != nil {
-			t.writeErr(w, r, err)
+			payload := &downloader.DlAdminBody{}
+			if err := cmn.ReadJSON(w, r, payload); err != nil {
+				return
+			}
+			if dlJob, err = downloader.ParseRetryDownloadRequest(t, uuid, payload.ID, downloaderXact); err != nil {
+				t.writeErr(w, r, err)
+				return
+			}
+		} else if dlJob, progressInterval = t.parseStartDownload(w, r, uuid, downloaderXact); dlJob == nil {
 			return
 		}
 		if glog.FastV(4, glog.SmoduleAIS) {
@@ -101,13 +87,32 @@ func (t *target) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		}, dlJob)
 		response, statusCode, respErr = downloaderXact.Download(dlJob)
 	case http.MethodGet:
-		if _, err := t.checkRESTItems(w, r, 0, false, apc.URLPathDownload.L); err != nil {
+		items, err := t.checkRESTItems(w, r, 0, true, apc.URLPathDownload.L)
+		if err != nil {
 			return
 		}
 		payload := &downloader.DlAdminBody{}
 		if err := cmn.ReadJSON(w, r, payload); err != nil {
 			return
 		}
+		if len(items) > 0 {
+			// GET /v1/download/retry: destination bucket of the failed downloads (see proxy.httpDownloadRetry)
+			if items[0] != apc.Retry {
+				t.writeErrAct(w, r, items[0])
+				return
+			}
+			bck, err := downloader.RetryDownloadBck(t, payload.ID)
+			if err != nil {
+				statusCode := http.StatusInternalServerError
+				if cmn.IsErrNotFound(err) {
+					statusCode = http.StatusNotFound
+				}
+				t.writeErr(w, r, err, statusCode)
+				return
+			}
+			response, statusCode = bck, http.StatusOK
+			break
+		}
 		if err := payload.Validate(false /*requireID*/); err != nil {
 			debug.Assert(false)
 			t.writeErr(w, r, err)
@@ -169,3 +174,42 @@ func (t *target) downloadHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+// parses download request; returns nil job upon failure (having written the error)
+func (t *target) parseStartDownload(w http.ResponseWriter, r *http.Request, uuid string,
+	downloaderXact *downloader.Downloader) (dlJob downloader.DlJob, progressInterval time.Duration) {
+	dlb := downloader.DlBody{}
+	progressInterval = downloader.DownloadProgressInterval
+	if err := cmn.ReadJSON(w, r, &dlb); err != nil {
+		return
+	}
+
+	dlBodyBase := downloader.DlBase{}
+	if err := jsoniter.Unmarshal(dlb.RawMessage, &dlBodyBase); err != nil {
+		err = fmt.Errorf(cmn.FmtErrUnmarshal, t, "download message", cmn.BytesHead(dlb.RawMessage), err)
+		t.writeErr(w, r, err)
+		return
+	}
+
+	if dlBodyBase.ProgressInterval != "" {
+		if dur, err := time.ParseDuration(dlBodyBase.ProgressInterval); err == nil {
+			progressInterval = dur
+		} else {
+			t.writeErrf(w, r, "%s: invalid progress interval %q, err: %v",
+				t.si, dlBodyBase.ProgressInterval, err)
+			return
+		}
+	}
+
+	bck := cluster.CloneBck(&dlBodyBase.Bck)
+	if err := bck.Init(t.Bowner()); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	job, err := downloader.ParseStartDownloadRequest(t, bck, uuid, dlb, downloaderXact)
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	return job, progressInterval
+}
@@ -20,10 +20,6 @@ import (
 
 // [METHOD] /v1/etl
 func (t *target) etlHandler(w http.ResponseWriter, r *http.Request) {
-	if err := k8s.Detect(); err != nil {
-		t.writeErrSilent(w, r, err)
-		return
-	}
 	switch {
 	case r.Method == http.MethodPut:
 		t.handleETLPut(w, r)
@@ -61,11 +57,21 @@ func (t *target) handleETLPut(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// only local-process and WebAssembly ETLs can run without Kubernetes
+	if !etl.IsLocal(initMsg) {
+		if err := k8s.Detect(); err != nil {
+			t.writeErrSilent(w, r, err)
+			return
+		}
+	}
+
 	switch msg := initMsg.(type) {
 	case *etl.InitSpecMsg:
 		err = etl.InitSpec(t, *msg, etl.StartOpts{})
 	case *etl.InitCodeMsg:
 		err = etl.InitCode(t, *msg)
+	case *etl.InitProcMsg:
+		err = etl.InitProc(t, *msg)
 	}
 	if err != nil {
 		t.writeErr(w, r, err)
@@ -136,12 +142,9 @@ func (t *target) stopETL(w http.ResponseWriter, r *http.Request, etlID string) {
 	}
 }
 
+// NOTE: `uuid` may specify comma-separated ETL pipeline (see etl.Pipeline)
 func (t *target) doETL(w http.ResponseWriter, r *http.Request, uuid string, bck *cluster.Bck, objName string) {
-	var (
-		comm etl.Communicator
-		err  error
-	)
-	comm, err = etl.GetCommunicator(uuid, t.si)
+	pipeline, err := etl.NewPipeline(etl.ParsePipeline(uuid), t.si)
 	if err != nil {
 		if cmn.IsErrNotFound(err) {
 			smap := t.owner.smap.Get()
@@ -154,7 +157,12 @@ func (t *target) doETL(w http.ResponseWriter, r *http.Request, uuid string, bck
 		t.writeErr(w, r, err)
 		return
 	}
-	if err := comm.OnlineTransform(w, r, bck, objName); err != nil {
+	if err := pipeline.OnlineTransform(w, r, bck, objName); err != nil {
+		if _, ok := err.(*cmn.ErrETL); ok { // pipeline stage error
+			t.writeErr(w, r, err)
+			return
+		}
+		comm := pipeline.Comm(0)
 		t.writeErr(w, r, cmn.NewErrETL(&cmn.ETLErrorContext{
 			UUID:    uuid,
 			PodName: comm.PodName(),
@@ -18,6 +18,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/dbdriver"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
@@ -157,6 +158,7 @@ func (t *target) GetCold(ctx context.Context, lom *cluster.LOM, owt cmn.OWT) (er
 	}
 
 	// 2. get from remote
+	started := mono.NanoTime()
 	if errCode, err = t.Backend(lom.Bck()).GetObj(ctx, lom, owt); err != nil {
 		if owt != cmn.OwtGetPrefetchLock {
 			lom.Unlock(true)
@@ -176,6 +178,7 @@ func (t *target) GetCold(ctx context.Context, lom *cluster.LOM, owt cmn.OWT) (er
 			t.statsT.AddMany(
 				cos.NamedVal64{Name: stats.GetColdCount, Value: 1},
 				cos.NamedVal64{Name: stats.GetColdSize, Value: lom.SizeBytes()},
+				cos.NamedVal64{Name: stats.GetColdLatency, Value: mono.SinceNano(started)},
 			)
 			lom.DowngradeLock()
 		} else {
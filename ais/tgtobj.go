@@ -6,13 +6,16 @@ package ais
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
 	"encoding"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"strconv"
 	"strings"
@@ -29,6 +32,7 @@ import (
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/reb"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport"
@@ -178,6 +182,11 @@ func (poi *putObjInfo) putObject() (int, error) {
 			cos.NamedVal64{Name: stats.PutCount, Value: 1},
 			cos.NamedVal64{Name: stats.PutLatency, Value: int64(delta)},
 		)
+		poi.t.statsBck(poi.lom.Bucket(),
+			cos.NamedVal64{Name: stats.PutCount, Value: 1},
+			cos.NamedVal64{Name: stats.PutSize, Value: poi.lom.SizeBytes()},
+			cos.NamedVal64{Name: stats.PutLatency, Value: int64(delta)},
+		)
 	}
 	// xaction in-objs counters, promote first
 	if poi.t2t && poi.xctn != nil && poi.owt == cmn.OwtPromote {
@@ -227,6 +236,11 @@ func (poi *putObjInfo) finalize() (errCode int, err error) {
 		}
 	}
 	poi.t.putMirror(poi.lom)
+	// NOTE: objects migrated by rebalance (and other t2t transfers) are not replicated,
+	// while user-initiated copies are replicated by the sender (see coi.replicate)
+	if poi.owt == cmn.OwtPut || poi.owt == cmn.OwtFinalize || poi.owt == cmn.OwtPromote {
+		poi.t.replicate(poi.lom.Bck(), mirror.ReplPut, poi.lom.ObjName)
+	}
 	return
 }
 
@@ -268,6 +282,13 @@ func (poi *putObjInfo) fini() (errCode int, err error) {
 		defer lom.Unlock(true)
 	}
 
+	// object lock (WORM)
+	if lom.Bprops().ObjLock.Enabled && (poi.owt == cmn.OwtPut || poi.owt == cmn.OwtFinalize || poi.owt == cmn.OwtPromote) {
+		if errCode, err = poi.retain(); err != nil {
+			return
+		}
+	}
+
 	// ais versioning
 	if bck.IsAIS() && lom.VersionConf().Enabled {
 		if poi.owt == cmn.OwtPut || poi.owt == cmn.OwtFinalize || poi.owt == cmn.OwtPromote {
@@ -298,6 +319,35 @@ func (poi *putObjInfo) fini() (errCode int, err error) {
 	return
 }
 
+// refuse to overwrite retained object; apply the bucket's default retention to the new one
+// (compare with lom.CheckRetained)
+func (poi *putObjInfo) retain() (errCode int, err error) {
+	var (
+		lom  = poi.lom
+		conf = &lom.Bprops().ObjLock
+	)
+	if _, ok := lom.GetCustomKey(cmn.RetainUntilObjMD); ok && lom.RetainUntil().IsZero() {
+		err = fmt.Errorf("PUT (%s): invalid %q (expecting RFC 3339 time)", poi.loghdr(), cmn.RetainUntilObjMD)
+		return http.StatusBadRequest, err
+	}
+	existing := cluster.AllocLOM(lom.ObjName)
+	defer cluster.FreeLOM(existing)
+	if err = existing.InitBck(lom.Bucket()); err != nil {
+		return
+	}
+	if existing.Load(false /*cache it*/, true /*locked*/) == nil {
+		if err = existing.CheckRetained(false /*bypass governance*/); err != nil {
+			return http.StatusForbidden, err
+		}
+	}
+	if conf.Retention > 0 {
+		if until := time.Now().Add(conf.Retention.D()); lom.RetainUntil().Before(until) {
+			lom.SetRetainUntil(until)
+		}
+	}
+	return
+}
+
 // via backend.PutObj()
 func (poi *putObjInfo) putRemote() (errCode int, err error) {
 	var (
@@ -676,7 +726,7 @@ func (goi *getObjInfo) restoreFromAny(skipLomRestore bool) (doubleCheck bool, er
 		ecEnabled            = goi.lom.Bprops().EC.Enabled
 		// TODO: if there're not enough EC targets to restore a sliced object,
 		//       we might still be able to restore it from its full replica
-		enoughECRestoreTargets = goi.lom.Bprops().EC.RequiredRestoreTargets() <= smap.CountActiveTargets()
+		enoughECRestoreTargets = ec.RequiredRestoreTargets(goi.lom) <= smap.CountActiveTargets()
 	)
 	if running {
 		doubleCheck = true
@@ -810,10 +860,13 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 
 	var (
 		rrange     *cmn.HTTPRange
+		ranges     []cmn.HTTPRange
 		reader     io.Reader = lmfh
 		size                 = goi.lom.SizeBytes()
 		cksumConf            = goi.lom.CksumConf()
 		cksumRange bool
+		archIdx    *cluster.ArchIdx
+		archEntry  *cluster.ArchIdxEntry
 	)
 	defer func() {
 		if lmfh != nil {
@@ -823,19 +876,43 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 			slab.Free(buf)
 		}
 	}()
+	// archived file: look it up in the archive's index
+	if goi.archive.filename != "" {
+		if archIdx, archEntry, err = goi.archLookup(lmfh); err != nil {
+			switch {
+			case cmn.IsErrNotFound(err):
+				errCode = http.StatusNotFound
+				return
+			case !cluster.IsErrArchIdxUnsupported(err):
+				err = cmn.NewErrFailedTo(goi.t, "extract "+goi.archive.filename+" from", goi.lom, err)
+				return
+			}
+			err = nil // not indexable - will scan the archive (see freadArch)
+		} else {
+			size = archEntry.Size
+		}
+	}
 	// parse, validate, set response header
 	if hdr != nil {
 		// read range
 		if goi.ranges.Range != "" {
 			rsize := size
-			if goi.ranges.Size > 0 {
+			if goi.ranges.Size > 0 && archEntry == nil {
 				rsize = goi.ranges.Size
 			}
-			if rrange, errCode, err = goi.parseRange(hdr, rsize); err != nil {
+			if ranges, errCode, err = goi.parseRange(hdr, rsize); err != nil {
 				return
 			}
-			if rrange != nil {
+			if len(ranges) > 0 && goi.archive.filename != "" && archIdx == nil {
+				err = fmt.Errorf(cmn.FmtErrUnsupported, goi.t, "range-reading files of non-indexable archives")
+				errCode = http.StatusRequestedRangeNotSatisfiable
+				return
+			}
+			if len(ranges) > 0 {
 				cksumRange = cksumConf.Type != cos.ChecksumNone && cksumConf.EnableReadRange
+			}
+			if len(ranges) == 1 {
+				rrange = &ranges[0]
 				size = rrange.Length // Content-Length
 			}
 		}
@@ -844,10 +921,28 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 
 	// reader
 	w := goi.w
-	if rrange == nil {
+	if len(ranges) > 1 {
+		var cksumType string
+		if cksumRange {
+			cksumType = cksumConf.Type
+		}
+		buf, slab = goi.t.gmm.Alloc()
+		if reader, size, err = goi.multiRange(hdr, lmfh, ranges, cksumType, buf); err != nil {
+			errCode = http.StatusInternalServerError
+			return
+		}
+	} else if rrange == nil {
 		if goi.archive.filename != "" {
 			var csl cos.ReadCloseSizer
-			csl, err = goi.freadArch(lmfh)
+			if archIdx != nil {
+				csl, err = archIdx.Open(lmfh, archEntry, 0, archEntry.Size)
+				if hdr != nil && archEntry.Cksum != "" {
+					hdr.Set(apc.HdrObjCksumType, archIdx.CksumType)
+					hdr.Set(apc.HdrObjCksumVal, archEntry.Cksum)
+				}
+			} else {
+				csl, err = goi.freadArch(lmfh)
+			}
 			if err != nil {
 				if cmn.IsErrNotFound(err) {
 					errCode = http.StatusNotFound
@@ -870,7 +965,19 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 		}
 	} else {
 		buf, slab = goi.t.gmm.AllocSize(rrange.Length)
-		reader = io.NewSectionReader(lmfh, rrange.Start, rrange.Length)
+		if archIdx != nil {
+			var csl cos.ReadCloseSizer
+			if csl, err = archIdx.Open(lmfh, archEntry, rrange.Start, rrange.Length); err != nil {
+				err = cmn.NewErrFailedTo(goi.t, "extract "+goi.archive.filename+" from", goi.lom, err)
+				return
+			}
+			defer func() {
+				csl.Close()
+			}()
+			reader = csl
+		} else {
+			reader = io.NewSectionReader(lmfh, rrange.Start, rrange.Length)
+		}
 		if cksumRange {
 			var (
 				cksum *cos.CksumHash
@@ -892,6 +999,9 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 	// set Content-Length
 	if hdr != nil {
 		hdr.Set(cmn.HdrContentLength, strconv.FormatInt(size, 10))
+		if len(ranges) > 1 {
+			goi.w.(http.ResponseWriter).WriteHeader(http.StatusPartialContent)
+		}
 	}
 
 	// transmit
@@ -928,13 +1038,16 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, errCode int, err erro
 		cos.NamedVal64{Name: stats.GetLatency, Value: delta},
 		cos.NamedVal64{Name: stats.GetCount, Value: 1},
 	)
+	goi.t.statsBck(goi.lom.Bucket(),
+		cos.NamedVal64{Name: stats.GetCount, Value: 1},
+		cos.NamedVal64{Name: stats.GetSize, Value: written},
+		cos.NamedVal64{Name: stats.GetLatency, Value: delta},
+	)
 	return
 }
 
 // parse, validate, set response header
-func (goi *getObjInfo) parseRange(hdr http.Header, size int64) (rrange *cmn.HTTPRange, errCode int, err error) {
-	var ranges []cmn.HTTPRange
-
+func (goi *getObjInfo) parseRange(hdr http.Header, size int64) (ranges []cmn.HTTPRange, errCode int, err error) {
 	ranges, err = cmn.ParseMultiRange(goi.ranges.Range, size)
 	if err != nil {
 		if _, ok := err.(*cmn.ErrRangeNoOverlap); ok {
@@ -947,19 +1060,71 @@ func (goi *getObjInfo) parseRange(hdr http.Header, size int64) (rrange *cmn.HTTP
 	if len(ranges) == 0 {
 		return
 	}
-	if len(ranges) > 1 {
-		err = fmt.Errorf(cmn.FmtErrUnsupported, goi.t, "multi-range")
+	if goi.archive.filename != "" && len(ranges) > 1 {
+		err = fmt.Errorf(cmn.FmtErrUnsupported, goi.t, "multi-range reading archived files")
 		errCode = http.StatusRequestedRangeNotSatisfiable
 		return
 	}
-	if goi.archive.filename != "" {
-		err = fmt.Errorf(cmn.FmtErrUnsupported, goi.t, "range-reading archived files")
-		errCode = http.StatusRequestedRangeNotSatisfiable
+	hdr.Set(cmn.HdrAcceptRanges, "bytes")
+	if len(ranges) > 1 {
+		// (compare with net/http.ServeContent)
+		var total int64
+		for i := range ranges {
+			total += ranges[i].Length
+		}
+		if total > size {
+			err = fmt.Errorf("%s: total length of the requested ranges (%d) exceeds the size of %s (%d)",
+				goi.t, total, goi.lom, size)
+			errCode = http.StatusRequestedRangeNotSatisfiable
+		}
 		return
 	}
-	rrange = &ranges[0]
-	hdr.Set(cmn.HdrAcceptRanges, "bytes")
-	hdr.Set(cmn.HdrContentRange, rrange.ContentRange(size))
+	hdr.Set(cmn.HdrContentRange, ranges[0].ContentRange(size))
+	return
+}
+
+// multi-range: RFC 7233 multipart/byteranges reader and its size (Content-Length);
+// with non-empty `cksumType` each part includes the checksum of its range
+func (goi *getObjInfo) multiRange(hdr http.Header, lmfh *os.File, ranges []cmn.HTTPRange, cksumType string,
+	buf []byte) (reader io.Reader, size int64, err error) {
+	var (
+		bb      bytes.Buffer
+		mw      = multipart.NewWriter(&bb)
+		readers = make([]io.Reader, 0, 2*len(ranges)+1)
+		osize   = goi.lom.SizeBytes()
+	)
+	if goi.ranges.Size > 0 {
+		osize = goi.ranges.Size
+	}
+	for i := range ranges {
+		var (
+			rrange  = &ranges[i]
+			section = io.NewSectionReader(lmfh, rrange.Start, rrange.Length)
+			phdr    = make(textproto.MIMEHeader, 4)
+		)
+		phdr.Set(cmn.HdrContentType, cmn.ContentBinary)
+		phdr.Set(cmn.HdrContentRange, rrange.ContentRange(osize))
+		if cksumType != "" {
+			var cksum *cos.CksumHash
+			if _, cksum, err = cos.CopyAndChecksum(io.Discard, section, buf, cksumType); err != nil {
+				return
+			}
+			phdr.Set(apc.HdrObjCksumType, cksumType)
+			phdr.Set(apc.HdrObjCksumVal, cksum.Value())
+			section = io.NewSectionReader(lmfh, rrange.Start, rrange.Length)
+		}
+		if _, err = mw.CreatePart(phdr); err != nil {
+			return
+		}
+		readers = append(readers, bytes.NewReader(bb.Bytes()), section)
+		size += int64(bb.Len()) + rrange.Length
+		bb = bytes.Buffer{} // (the writer keeps writing into `bb`)
+	}
+	mw.Close()
+	readers = append(readers, &bb)
+	size += int64(bb.Len())
+	hdr.Set(cmn.HdrContentType, cmn.ContentByteRanges+"; boundary="+mw.Boundary())
+	reader = io.MultiReader(readers...)
 	return
 }
 
@@ -1146,6 +1311,9 @@ func (coi *copyObjInfo) copyObject(lom *cluster.LOM, objNameTo string) (size int
 			if lom.EqCksum(dst.Checksum()) {
 				return
 			}
+			if err = dst.CheckRetained(false /*bypass governance*/); err != nil {
+				return
+			}
 		} else if cmn.IsErrBucketNought(err) {
 			return
 		}
@@ -1156,6 +1324,7 @@ func (coi *copyObjInfo) copyObject(lom *cluster.LOM, objNameTo string) (size int
 		if coi.finalize {
 			coi.t.putMirror(dst2)
 		}
+		coi.replicate(objNameTo)
 	}
 	err = err2
 	if dst2 != nil {
@@ -1236,11 +1405,15 @@ func (coi *copyObjInfo) copyReader(lom *cluster.LOM, objNameTo string) (size int
 		}
 		params.Atime = lom.Atime()
 	}
+	owt := params.OWT
 	err = coi.t.PutObject(dst, params)
 	cluster.FreePutObjParams(params)
 	if err != nil {
 		return
 	}
+	if owt == cmn.OwtMigrate {
+		coi.replicate(objNameTo)
+	}
 	// xaction stats: inc locally processed (and see data mover for in and out objs)
 	size = lom.SizeBytes()
 	if coi.Xact != nil {
@@ -1273,10 +1446,20 @@ func (coi *copyObjInfo) sendRemote(lom *cluster.LOM, objNameTo string, tsi *clus
 		}
 	}
 	size, err = coi.doSend(lom, sargs)
+	if err == nil && !coi.dryRun && sargs.owt == cmn.OwtMigrate {
+		coi.replicate(objNameTo)
+	}
 	freeSnda(sargs)
 	return
 }
 
+// Replicate the copy, unless it gets replicated upon PUT by the destination
+// target (see poi.finalize). Note that `OwtMigrate` does not distinguish copying
+// from rebalancing - hence, the sender.
+func (coi *copyObjInfo) replicate(objNameTo string) {
+	coi.t.replicate(coi.BckTo, mirror.ReplPut, objNameTo)
+}
+
 // send object => designated target
 // * source is a LOM or a reader (that may be reading from remote)
 // * one of the two equivalent transmission mechanisms: PUT or transport Send
@@ -1454,6 +1637,7 @@ func (aaoi *appendArchObjInfo) finalize(fqn string) error {
 		}
 	}
 	aaoi.t.putMirror(aaoi.lom)
+	aaoi.t.replicate(aaoi.lom.Bck(), mirror.ReplPut, aaoi.lom.ObjName)
 	return nil
 }
 
@@ -5,11 +5,16 @@
 package ais
 
 import (
+	"bytes"
 	"flag"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"strconv"
 	"testing"
 	"time"
 
@@ -90,6 +95,82 @@ func TestMain(m *testing.M) {
 	m.Run()
 }
 
+func TestObjGetMultiRange(t_ *testing.T) {
+	const size = 64 * cos.KiB
+	lom := cluster.AllocLOM("multirange")
+	defer cluster.FreeLOM(lom)
+	err := lom.InitBck(&cmn.Bck{Name: testBucket, Provider: apc.ProviderAIS, Ns: cmn.NsGlobal})
+	if err != nil {
+		t_.Fatal(err)
+	}
+	r, _ := readers.NewRandReader(size, cos.ChecksumNone)
+	poi := &putObjInfo{
+		atime:   time.Now(),
+		t:       t,
+		lom:     lom,
+		r:       r,
+		workFQN: path.Join(testMountpath, "multirange.work"),
+	}
+	if _, err := poi.putObject(); err != nil {
+		t_.Fatal(err)
+	}
+	defer os.Remove(lom.FQN)
+	content, err := os.ReadFile(lom.FQN)
+	if err != nil {
+		t_.Fatal(err)
+	}
+
+	ranges := []cmn.HTTPRange{{Start: 0, Length: 100}, {Start: 4096, Length: 1}, {Start: size - 10, Length: 10}}
+	rec := httptest.NewRecorder()
+	goi := &getObjInfo{
+		atime:  time.Now().UnixNano(),
+		t:      t,
+		lom:    lom,
+		w:      rec,
+		ranges: byteRanges{Range: cmn.MultiRangeHdr(ranges).Get(cmn.HdrRange)},
+	}
+	if _, err := goi.getObject(); err != nil {
+		t_.Fatal(err)
+	}
+	if rec.Code != http.StatusPartialContent {
+		t_.Fatalf("expected status %d, got %d", http.StatusPartialContent, rec.Code)
+	}
+	if cl := rec.Header().Get(cmn.HdrContentLength); cl != strconv.Itoa(rec.Body.Len()) {
+		t_.Fatalf("content-length %s vs body length %d", cl, rec.Body.Len())
+	}
+	mediaType, params, err := mime.ParseMediaType(rec.Header().Get(cmn.HdrContentType))
+	if err != nil || mediaType != cmn.ContentByteRanges {
+		t_.Fatalf("unexpected content type %q (%v)", rec.Header().Get(cmn.HdrContentType), err)
+	}
+	mr := multipart.NewReader(rec.Body, params["boundary"])
+	for i, rr := range ranges {
+		part, err := mr.NextPart()
+		if err != nil {
+			t_.Fatalf("part %d: %v", i, err)
+		}
+		if cr := part.Header.Get(cmn.HdrContentRange); cr != rr.ContentRange(size) {
+			t_.Fatalf("part %d: expected content range %q, got %q", i, rr.ContentRange(size), cr)
+		}
+		b, err := io.ReadAll(part)
+		if err != nil {
+			t_.Fatal(err)
+		}
+		if !bytes.Equal(b, content[rr.Start:rr.Start+rr.Length]) {
+			t_.Fatalf("part %d: content mismatch", i)
+		}
+	}
+	if _, err := mr.NextPart(); err != io.EOF {
+		t_.Fatalf("expected EOF, got %v", err)
+	}
+
+	// total length of the ranges exceeding the object size
+	goi.w = httptest.NewRecorder()
+	goi.ranges = byteRanges{Range: "bytes=0-,0-"}
+	if errCode, err := goi.getObject(); err == nil || errCode != http.StatusRequestedRangeNotSatisfiable {
+		t_.Fatalf("expected range-not-satisfiable error, got %v(%d)", err, errCode)
+	}
+}
+
 func BenchmarkObjPut(b *testing.B) {
 	benches := []struct {
 		fileSize int64
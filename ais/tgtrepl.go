@@ -0,0 +1,92 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/mirror"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// how often to (re)start replication of the buckets with non-empty replication queues
+const replInterval = time.Minute
+
+// queue the op and wake up the bucket's replication xaction (see cmn.ReplicationConf)
+func (t *target) replicate(bck *cluster.Bck, op, objName string) {
+	if !bck.Props.Replication.Enabled {
+		return
+	}
+	if err := mirror.ReplEnqueue(t.db, bck.Bucket(), op, objName); err != nil {
+		glog.Errorf("%s: failed to queue replication (%s %s/%s): %v", t, op, bck, objName, err)
+		t.statsT.Add(stats.ReplErrCount, 1)
+		return
+	}
+	t.statsT.Add(stats.ReplBacklog, 1)
+	t.kickRepl(bck)
+}
+
+func (t *target) kickRepl(bck *cluster.Bck) {
+	rns := xreg.RenewReplicate(t, bck, t.statsT)
+	if rns.Err != nil {
+		glog.Errorf("%s: %v", t, rns.Err) // will be retried (see replHK below)
+		return
+	}
+	rns.Entry.Get().(*mirror.XactRepl).Kick()
+}
+
+// upon startup: account for the replication backlog persisted in the previous run(s)
+func (t *target) initRepl() {
+	keys, err := mirror.ReplQueued(t.db, nil)
+	if err != nil {
+		glog.Errorf("%s: failed to load replication queue: %v", t, err)
+		return
+	}
+	if len(keys) > 0 {
+		glog.Infof("%s: replication backlog %d", t, len(keys))
+		t.statsT.Add(stats.ReplBacklog, int64(len(keys)))
+	}
+}
+
+// periodically (re)start replication of the buckets with queued ops; discard the
+// queues of the buckets that do not exist or have replication disabled
+func (t *target) replHK() time.Duration {
+	if !t.ClusterStarted() || t.regstate.disabled.Load() {
+		return replInterval
+	}
+	keys, err := mirror.ReplQueued(t.db, nil)
+	if err != nil {
+		glog.Errorf("%s: failed to load replication queue: %v", t, err)
+		return replInterval
+	}
+	queued := make(map[string][]string, 4)
+	for _, key := range keys {
+		bck, _ := cmn.ParseUname(key)
+		uname := bck.MakeUname("")
+		queued[uname] = append(queued[uname], key)
+	}
+	bowner := t.owner.bmd
+	for uname, keys := range queued {
+		b, _ := cmn.ParseUname(uname)
+		bck := cluster.CloneBck(&b)
+		if err := bck.Init(bowner); err == nil && bck.Props.Replication.Enabled {
+			t.kickRepl(bck)
+			continue
+		}
+		var n int64
+		for _, key := range keys {
+			if err := t.db.Delete(mirror.ReplCollection, key); err == nil {
+				n++
+			}
+		}
+		glog.Warningf("%s: %s does not exist or is not replicated - discarded %d queued op(s)", t, bck, n)
+		t.statsT.Add(stats.ReplBacklog, -n)
+	}
+	return replInterval
+}
@@ -5,6 +5,7 @@
 package ais
 
 import (
+	"encoding/xml"
 	"net/http"
 	"path"
 	"strings"
@@ -21,24 +22,62 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 )
 
-// PUT s3/bckName/objName
+// [METHOD] s3/bckName/objName
 func (t *target) s3Handler(w http.ResponseWriter, r *http.Request) {
 	apiItems, err := t.checkRESTItems(w, r, 0, true, apc.URLPathS3.L)
 	if err != nil {
 		return
 	}
+	s3compat.VerifyPayload(r) // (redirected by proxy that has verified the signature)
 
+	q := r.URL.Query()
+	_, mpt := q[s3compat.QparamMptUploadID]
+	_, tagging := q[s3compat.QparamTagging]
 	switch r.Method {
 	case http.MethodHead:
 		t.headObjS3(w, r, apiItems)
 	case http.MethodGet:
+		if mpt {
+			t.listMptParts(w, r, apiItems)
+			return
+		}
+		if tagging {
+			t.getObjTaggingS3(w, r, apiItems)
+			return
+		}
 		t.getObjS3(w, r, apiItems)
 	case http.MethodPut:
+		if _, partNum := q[s3compat.QparamMptPartNumber]; partNum && mpt {
+			t.putMptPart(w, r, apiItems)
+			return
+		}
+		if tagging {
+			t.putObjTaggingS3(w, r, apiItems)
+			return
+		}
 		t.putObjS3(w, r, apiItems)
+	case http.MethodPost:
+		if _, uploads := q[s3compat.QparamMptUploads]; uploads {
+			t.startMpt(w, r, apiItems)
+			return
+		}
+		if mpt {
+			t.completeMpt(w, r, apiItems)
+			return
+		}
+		t.writeErr(w, r, errS3Req)
 	case http.MethodDelete:
+		if mpt {
+			t.abortMpt(w, r, apiItems)
+			return
+		}
+		if tagging {
+			t.delObjTaggingS3(w, r, apiItems)
+			return
+		}
 		t.delObjS3(w, r, apiItems)
 	default:
-		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodPut)
+		cmn.WriteErr405(w, r, http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut)
 	}
 }
 
@@ -142,6 +181,10 @@ func (t *target) directPutObjS3(w http.ResponseWriter, r *http.Request, items []
 		}
 	}
 	lom.SetAtimeUnix(started.UnixNano())
+	if err := s3compat.ObjLockFromHeader(r.Header, lom); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
 
 	// TODO: dual checksumming, e.g. lom.SetCustom(apc.ProviderAmazon, ...)
 
@@ -199,6 +242,7 @@ func (t *target) getObjS3(w http.ResponseWriter, r *http.Request, items []string
 	lom := cluster.AllocLOM(path.Join(items[1:]...))
 	t.getObject(w, r, dpq, bck, lom)
 	s3compat.SetETag(w.Header(), lom) // add etag/md5
+	s3compat.SetObjLockHeaders(w.Header(), lom)
 	cluster.FreeLOM(lom)
 	dpqFree(dpq)
 }
@@ -219,6 +263,7 @@ func (t *target) headObjS3(w http.ResponseWriter, r *http.Request, items []strin
 	lom := cluster.AllocLOM(objName)
 	t.headObject(w, r, r.URL.Query(), bck, lom)
 	s3compat.SetETag(w.Header(), lom) // add etag/md5
+	s3compat.SetObjLockHeaders(w.Header(), lom)
 	cluster.FreeLOM(lom)
 }
 
@@ -240,7 +285,7 @@ func (t *target) delObjS3(w http.ResponseWriter, r *http.Request, items []string
 		t.writeErr(w, r, err)
 		return
 	}
-	errCode, err := t.DeleteObject(lom, false)
+	errCode, err := t.deleteObject(lom, false /*evict*/, s3compat.IsBypassGovernance(r.Header))
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			err := cmn.NewErrNotFound("%s: %s", t.si, lom.FullName())
@@ -253,3 +298,114 @@ func (t *target) delObjS3(w http.ResponseWriter, r *http.Request, items []string
 	// EC cleanup if EC is enabled
 	ec.ECM.CleanupObject(lom)
 }
+
+//
+// object tagging (see also: s3compat/tagging.go)
+//
+
+// GET s3/bckName/objName?tagging
+func (t *target) getObjTaggingS3(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.taggingLOM(w, r, items, false /*exclusive*/)
+	if lom == nil {
+		return
+	}
+	tagging := s3compat.NewTagging(lom.GetCustomMD())
+	lom.Unlock(false)
+	cluster.FreeLOM(lom)
+
+	sgl := memsys.PageMM().NewSGL(0)
+	tagging.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
+// PUT s3/bckName/objName?tagging
+// replaces all existing tags (and keeps system metadata intact)
+func (t *target) putObjTaggingS3(w http.ResponseWriter, r *http.Request, items []string) {
+	tagging := &s3compat.Tagging{}
+	err := xml.NewDecoder(r.Body).Decode(tagging)
+	cos.Close(r.Body)
+	if err == nil {
+		err = tagging.Validate()
+	}
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	lom := t.taggingLOM(w, r, items, true /*exclusive*/)
+	if lom == nil {
+		return
+	}
+	md := make(cos.SimpleKVs, len(tagging.TagSet.Tags)+4)
+	for k, v := range lom.GetCustomMD() {
+		if s3compat.IsSystemMD(k) {
+			md[k] = v
+		}
+	}
+	for _, tag := range tagging.TagSet.Tags {
+		md[tag.Key] = tag.Value
+	}
+	lom.SetCustomMD(md)
+	t.persistTagging(w, r, lom)
+}
+
+// DELETE s3/bckName/objName?tagging
+func (t *target) delObjTaggingS3(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.taggingLOM(w, r, items, true /*exclusive*/)
+	if lom == nil {
+		return
+	}
+	md := lom.GetCustomMD()
+	for k := range md {
+		if !s3compat.IsSystemMD(k) {
+			delete(md, k)
+		}
+	}
+	if t.persistTagging(w, r, lom) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// returns loaded and locked LOM or nil (in which case the error is already written)
+func (t *target) taggingLOM(w http.ResponseWriter, r *http.Request, items []string, exclusive bool) *cluster.LOM {
+	if len(items) < 2 {
+		t.writeErr(w, r, errS3Obj)
+		return nil
+	}
+	bck := cluster.NewBck(items[0], apc.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd); err != nil {
+		t.writeErr(w, r, err)
+		return nil
+	}
+	lom := cluster.AllocLOM(path.Join(items[1:]...))
+	if err := lom.InitBck(bck.Bucket()); err != nil {
+		cluster.FreeLOM(lom)
+		t.writeErr(w, r, err)
+		return nil
+	}
+	lom.Lock(exclusive)
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		lom.Unlock(exclusive)
+		cluster.FreeLOM(lom)
+		if cmn.IsObjNotExist(err) {
+			t.writeErr(w, r, err, http.StatusNotFound)
+		} else {
+			t.writeErr(w, r, err)
+		}
+		return nil
+	}
+	return lom
+}
+
+// persists updated custom metadata, unlocks and frees the LOM
+func (t *target) persistTagging(w http.ResponseWriter, r *http.Request, lom *cluster.LOM) (ok bool) {
+	err := lom.Persist()
+	lom.Unlock(true)
+	if err != nil {
+		t.fsErr(err, lom.FQN)
+		t.writeErr(w, r, err)
+	}
+	cluster.FreeLOM(lom)
+	return err == nil
+}
@@ -0,0 +1,445 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+//
+// S3 multipart upload (see also: ais/s3compat/mpt.go)
+//
+
+// initialize bucket and LOM for a multipart request: s3/bckName/objName?...
+func (t *target) mptLOM(w http.ResponseWriter, r *http.Request, items []string) (lom *cluster.LOM) {
+	if len(items) < 2 {
+		t.writeErr(w, r, errS3Obj)
+		return
+	}
+	bck := cluster.NewBck(items[0], apc.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd); err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	lom = cluster.AllocLOM(path.Join(items[1:]...))
+	if err := lom.InitBck(bck.Bucket()); err != nil {
+		if cmn.IsErrRemoteBckNotFound(err) {
+			t.BMDVersionFixup(r)
+			err = lom.InitBck(bck.Bucket())
+		}
+		if err != nil {
+			cluster.FreeLOM(lom)
+			t.writeErr(w, r, err)
+			return nil
+		}
+	}
+	return
+}
+
+// validate upload ID and make sure it belongs to the object in question
+func (t *target) mptUploadID(w http.ResponseWriter, r *http.Request, lom *cluster.LOM) (id string) {
+	id = r.URL.Query().Get(s3compat.QparamMptUploadID)
+	bckName, objName, ok := s3compat.UploadObj(id)
+	if !ok {
+		t.writeErr(w, r, cmn.NewErrNotFound("%s: upload %q", t.si, id), http.StatusNotFound)
+		return ""
+	}
+	if bckName != lom.Bck().Name || objName != lom.ObjName {
+		err := fmt.Errorf("%s: upload %q does not belong to %s", t.si, id, lom)
+		t.writeErr(w, r, err)
+		return ""
+	}
+	return
+}
+
+// POST s3/bckName/objName?uploads
+// (CreateMultipartUpload)
+func (t *target) startMpt(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.writeErr(w, r, cs.Err, http.StatusInsufficientStorage)
+		return
+	}
+	id := cos.GenUUID()
+	s3compat.InitUpload(id, lom.Bck().Name, lom.ObjName)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: started multipart upload %q => %s", t.si, id, lom)
+	}
+	result := s3compat.NewInitiateMptUploadResult(lom.Bck().Name, lom.ObjName, id)
+	sgl := memsys.PageMM().NewSGL(0)
+	result.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
+// PUT s3/bckName/objName?partNumber=<N>&uploadId=<ID>
+// (UploadPart)
+func (t *target) putMptPart(w http.ResponseWriter, r *http.Request, items []string) {
+	if r.Header.Get(s3compat.HeaderObjSrc) != "" {
+		t.writeErrMsg(w, r, "multipart upload: copying parts (UploadPartCopy) is not supported",
+			http.StatusNotImplemented)
+		return
+	}
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	id := t.mptUploadID(w, r, lom)
+	if id == "" {
+		return
+	}
+	partNum, err := s3compat.ParsePartNum(r.URL.Query().Get(s3compat.QparamMptPartNumber))
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	if cs := fs.GetCapStatus(); cs.OOS {
+		t.writeErr(w, r, cs.Err, http.StatusInsufficientStorage)
+		return
+	}
+	var (
+		prefix = fs.WorkfileMptPart + "." + id + "." + strconv.FormatInt(partNum, 10)
+		fqn    = fs.CSM.Gen(lom, fs.WorkfileType, prefix)
+		cksum  = cos.NewCksumHash(cos.ChecksumMD5)
+	)
+	size, err := t.writeMptPart(lom, fqn, r.Body, cksum)
+	if err != nil {
+		t.fsErr(err, fqn)
+		t.writeErr(w, r, err)
+		return
+	}
+	cksum.Finalize()
+	npart := &s3compat.MptPart{MD5: cksum.Value(), FQN: fqn, Size: size, Num: partNum}
+	prev, err := s3compat.AddPart(id, npart)
+	if err != nil {
+		// aborted or completed (or being completed) in the meantime
+		if errRm := cos.RemoveFile(fqn); errRm != nil {
+			glog.Errorf(fmtNested, t, err, "remove", fqn, errRm)
+		}
+		t.writeErr(w, r, err, mptErrCode(err))
+		return
+	}
+	if prev != nil {
+		if errRm := cos.RemoveFile(prev.FQN); errRm != nil {
+			glog.Errorf("%s: failed to remove replaced part %d of upload %q: %v", t, partNum, id, errRm)
+		}
+	}
+	w.Header().Set(cmn.HdrETag, s3compat.QuoteETag(npart.MD5))
+}
+
+func (t *target) writeMptPart(lom *cluster.LOM, fqn string, reader io.ReadCloser,
+	cksum *cos.CksumHash) (size int64, err error) {
+	var (
+		fh        *os.File
+		buf, slab = t.gmm.Alloc()
+	)
+	defer func() {
+		slab.Free(buf)
+		cos.Close(reader)
+	}()
+	if fh, err = lom.CreateFile(fqn); err != nil {
+		return
+	}
+	size, err = io.CopyBuffer(cos.NewWriterMulti(cksum.H, cos.WriterOnly{Writer: fh}), reader, buf)
+	if errClose := fh.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		if errRm := cos.RemoveFile(fqn); errRm != nil {
+			glog.Errorf(fmtNested, t, err, "remove", fqn, errRm)
+		}
+	}
+	return
+}
+
+// POST s3/bckName/objName?uploadId=<ID>
+// (CompleteMultipartUpload)
+func (t *target) completeMpt(w http.ResponseWriter, r *http.Request, items []string) {
+	started := time.Now()
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	id := t.mptUploadID(w, r, lom)
+	if id == "" {
+		return
+	}
+	req := &s3compat.CompleteMptUpload{}
+	err := xml.NewDecoder(r.Body).Decode(req)
+	cos.Close(r.Body)
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	// lock the upload so that its parts are not replaced or removed while being concatenated
+	if err := s3compat.LockUpload(id); err != nil {
+		t.writeErr(w, r, err, mptErrCode(err))
+		return
+	}
+	finished := false
+	defer func() {
+		if !finished {
+			s3compat.UnlockUpload(id)
+		}
+	}()
+	parts, err := s3compat.CheckParts(id, req.Parts)
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+	etag, err := s3compat.CompositeETag(parts)
+	if err != nil {
+		t.writeErr(w, r, err)
+		return
+	}
+
+	// concatenate parts into a work file and finalize the object
+	workFQN := fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfilePut)
+	if err := t.concatMptParts(lom, workFQN, parts); err != nil {
+		t.fsErr(err, workFQN)
+		t.writeErr(w, r, err)
+		return
+	}
+	lom.SetAtimeUnix(started.UnixNano())
+	lom.SetCustomKey(cmn.ETag, etag)
+	poi := allocPutObjInfo()
+	{
+		poi.atime = started
+		poi.t = t
+		poi.lom = lom
+		poi.workFQN = workFQN
+		poi.owt = cmn.OwtPut
+		poi.restful = true
+	}
+	errCode, err := poi.finalize()
+	freePutObjInfo(poi)
+	if err != nil {
+		t.writeErr(w, r, err, errCode)
+		return
+	}
+	delta := int64(time.Since(started))
+	t.statsT.AddMany(
+		cos.NamedVal64{Name: stats.PutCount, Value: 1},
+		cos.NamedVal64{Name: stats.PutLatency, Value: delta},
+	)
+	t.statsBck(lom.Bucket(),
+		cos.NamedVal64{Name: stats.PutCount, Value: 1},
+		cos.NamedVal64{Name: stats.PutSize, Value: lom.SizeBytes()},
+		cos.NamedVal64{Name: stats.PutLatency, Value: delta},
+	)
+
+	// cleanup
+	finished = true
+	allParts, err := s3compat.FinishUpload(id)
+	if err != nil {
+		glog.Errorf("%s: completed %s but failed to remove upload %q: %v", t, lom, id, err)
+	}
+	t.removeMptParts(id, allParts)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: completed multipart upload %q => %s (%d parts)", t.si, id, lom, len(parts))
+	}
+
+	result := s3compat.NewCompleteMptUploadResult(lom.Bck().Name, lom.ObjName, etag)
+	sgl := memsys.PageMM().NewSGL(0)
+	result.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	s3compat.SetETag(w.Header(), lom)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
+// LOM is updated with the resulting size and checksum (in accordance with
+// the bucket's checksum configuration)
+func (t *target) concatMptParts(lom *cluster.LOM, workFQN string, parts []*s3compat.MptPart) (err error) {
+	var (
+		fh        *os.File
+		size      int64
+		cksum     *cos.CksumHash
+		writer    io.Writer
+		ckconf    = lom.CksumConf()
+		buf, slab = t.gmm.Alloc()
+	)
+	defer slab.Free(buf)
+	if fh, err = lom.CreateFile(workFQN); err != nil {
+		return
+	}
+	writer = cos.WriterOnly{Writer: fh}
+	if ckconf.Type != cos.ChecksumNone {
+		cksum = cos.NewCksumHash(ckconf.Type)
+		writer = cos.NewWriterMulti(cksum.H, writer)
+	}
+	for _, part := range parts {
+		var (
+			pfh     *os.File
+			written int64
+		)
+		if pfh, err = os.Open(part.FQN); err != nil {
+			break
+		}
+		written, err = io.CopyBuffer(writer, pfh, buf)
+		cos.Close(pfh)
+		if err != nil {
+			break
+		}
+		size += written
+	}
+	if errClose := fh.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		if errRm := cos.RemoveFile(workFQN); errRm != nil {
+			glog.Errorf(fmtNested, t, err, "remove", workFQN, errRm)
+		}
+		return
+	}
+	lom.SetSize(size)
+	if cksum != nil {
+		cksum.Finalize()
+		lom.SetCksum(&cksum.Cksum)
+	} else {
+		lom.SetCksum(cos.NoneCksum)
+	}
+	return
+}
+
+// DELETE s3/bckName/objName?uploadId=<ID>
+// (AbortMultipartUpload)
+func (t *target) abortMpt(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	id := t.mptUploadID(w, r, lom)
+	if id == "" {
+		return
+	}
+	if err := s3compat.LockUpload(id); err != nil {
+		t.writeErr(w, r, err, mptErrCode(err))
+		return
+	}
+	parts, err := s3compat.FinishUpload(id)
+	if err != nil {
+		t.writeErr(w, r, err, http.StatusNotFound)
+		return
+	}
+	t.removeMptParts(id, parts)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: aborted multipart upload %q => %s", t.si, id, lom)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET s3/bckName/objName?uploadId=<ID>
+// (ListParts)
+func (t *target) listMptParts(w http.ResponseWriter, r *http.Request, items []string) {
+	lom := t.mptLOM(w, r, items)
+	if lom == nil {
+		return
+	}
+	defer cluster.FreeLOM(lom)
+	id := t.mptUploadID(w, r, lom)
+	if id == "" {
+		return
+	}
+	parts, err := s3compat.ListParts(id)
+	if err != nil {
+		t.writeErr(w, r, err, http.StatusNotFound)
+		return
+	}
+	result := s3compat.NewListPartsResult(lom.Bck().Name, lom.ObjName, id, parts)
+	sgl := memsys.PageMM().NewSGL(0)
+	result.MustMarshal(sgl)
+	w.Header().Set(cmn.HdrContentType, cmn.ContentXML)
+	sgl.WriteTo(w)
+	sgl.Free()
+}
+
+func (t *target) removeMptParts(id string, parts []*s3compat.MptPart) {
+	for _, part := range parts {
+		if err := cos.RemoveFile(part.FQN); err != nil {
+			glog.Errorf("%s: failed to remove part %d of upload %q: %v", t, part.Num, id, err)
+		}
+	}
+}
+
+func mptErrCode(err error) int {
+	if cmn.IsErrNotFound(err) {
+		return http.StatusNotFound
+	}
+	return http.StatusConflict // locked: being completed or aborted
+}
+
+// Multipart uploads do not survive restarts (their state is kept in memory),
+// and so the part files left over from the previous run can be removed.
+// (to be called once the BMD is loaded - see `Run`)
+func (t *target) rmOrphanMptParts() {
+	var (
+		n                 int
+		bcks              []*cluster.Bck
+		provider          = apc.ProviderAIS
+		availablePaths, _ = fs.Get()
+		resolver          = fs.CSM.Resolver(fs.WorkfileType)
+		prefix            = fs.WorkfileMptPart + "."
+	)
+	t.owner.bmd.get().Range(&provider, &cmn.NsGlobal, func(bck *cluster.Bck) bool {
+		bcks = append(bcks, bck)
+		return false
+	})
+	callback := func(fqn string, de fs.DirEntry) error {
+		if de.IsDir() {
+			return nil
+		}
+		base := filepath.Base(fqn)
+		if !strings.HasPrefix(base, prefix) {
+			return nil
+		}
+		if _, old, ok := resolver.ParseUniqueFQN(base); ok && old {
+			if err := cos.RemoveFile(fqn); err != nil {
+				glog.Errorf("%s: failed to remove orphaned part %q: %v", t, fqn, err)
+			} else {
+				n++
+			}
+		}
+		return nil
+	}
+	for _, bck := range bcks {
+		for _, mi := range availablePaths {
+			opts := &fs.WalkOpts{Mi: mi, Bck: bck.Clone(), CTs: []string{fs.WorkfileType}, Callback: callback}
+			if err := fs.Walk(opts); err != nil {
+				glog.Errorf("%s: failed to cleanup orphaned multipart upload parts of %s on %s: %v", t, bck, mi, err)
+			}
+		}
+	}
+	if n > 0 {
+		glog.Infof("%s: removed %d orphaned multipart upload part(s)", t, n)
+	}
+}
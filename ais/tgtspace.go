@@ -6,6 +6,7 @@ package ais
 
 import (
 	"sync"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/api/apc"
@@ -21,6 +22,9 @@ import (
 	"github.com/NVIDIA/aistore/xact/xreg"
 )
 
+// how often to execute bucket lifecycle rules
+const lcycleInterval = time.Hour
+
 // triggers by an out-of-space condition or a suspicion of thereof
 func (t *target) OOS(csRefreshed *fs.CapStatus) (cs fs.CapStatus) {
 	var err error
@@ -116,3 +120,52 @@ func (t *target) runStoreCleanup(id string, wg *sync.WaitGroup, bcks ...cmn.Bck)
 	})
 	return space.RunCleanup(&ini)
 }
+
+func (t *target) runLifecycle(id string, wg *sync.WaitGroup, dryRun bool, bcks ...cmn.Bck) {
+	regToIC := id == ""
+	if regToIC {
+		id = cos.GenUUID()
+	}
+	rns := xreg.RenewLifecycle(id, dryRun)
+	if rns.Err != nil || rns.IsRunning() {
+		debug.Assert(rns.Err == nil || cmn.IsErrUsePrevXaction(rns.Err))
+		if wg != nil {
+			wg.Done()
+		}
+		return
+	}
+	xlc := rns.Entry.Get()
+	if regToIC && xlc.ID() == id {
+		// pre-existing UUID: notify IC members
+		regMsg := xactRegMsg{UUID: id, Kind: apc.ActLifecycle, Srcs: []string{t.si.ID()}}
+		msg := t.newAmsgActVal(apc.ActRegGlobalXaction, regMsg)
+		t.bcastAsyncIC(msg)
+	}
+	ini := space.IniLcycle{
+		T:       t,
+		Xaction: xlc.(*space.XactLcycle),
+		Buckets: bcks,
+		WG:      wg,
+	}
+	xlc.AddNotif(&xact.NotifXact{
+		NotifBase: nl.NotifBase{When: cluster.UponTerm, Dsts: []string{equalIC}, F: t.callerNotifyFin},
+		Xact:      xlc,
+	})
+	space.RunLifecycle(&ini)
+}
+
+// periodically execute bucket lifecycle rules (if any)
+func (t *target) lcycleHK() time.Duration {
+	if !t.ClusterStarted() || t.regstate.disabled.Load() {
+		return lcycleInterval
+	}
+	var enabled bool
+	t.owner.bmd.get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		enabled = bck.Props.Lifecycle.Enabled
+		return enabled
+	})
+	if enabled {
+		go t.runLifecycle("" /*uuid*/, nil /*wg*/, false /*dry-run*/)
+	}
+	return lcycleInterval
+}
@@ -20,7 +20,6 @@ import (
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/feat"
-	"github.com/NVIDIA/aistore/cmn/k8s"
 	"github.com/NVIDIA/aistore/etl"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/mirror"
@@ -482,11 +481,7 @@ func (t *target) validateBckRenTxn(bckFrom, bckTo *cluster.Bck, msg *aisMsg) err
 }
 
 func (t *target) etlDP(msg *apc.TCBMsg) (dp cluster.DP, err error) {
-	if err = k8s.Detect(); err != nil {
-		return
-	}
-	if msg.ID == "" {
-		err = apc.ErrETLMissingUUID
+	if err = msg.Validate(); err != nil {
 		return
 	}
 	return etl.NewOfflineDataProvider(msg, t.si)
@@ -855,6 +850,10 @@ func (t *target) destroyBucket(c *txnServerCtx) error {
 		if !nlp.TryLock(c.timeout.netw / 2) {
 			return cmn.NewErrBckIsBusy(c.bck.Bucket())
 		}
+		if err := t.checkRetainedBck(c.bck); err != nil {
+			nlp.Unlock()
+			return err
+		}
 		txn := newTxnBckBase(c.bck)
 		txn.fillFromCtx(c)
 		if err := t.transactions.begin(txn); err != nil {
@@ -872,6 +871,69 @@ func (t *target) destroyBucket(c *txnServerCtx) error {
 	return nil
 }
 
+// object lock: a bucket cannot be destroyed while it contains retained objects
+// (walking the bucket only when its latest retain-until time is not tracked yet - see cluster.RetainedUntil)
+func (t *target) checkRetainedBck(bck *cluster.Bck) error {
+	if bck.Init(t.owner.bmd) != nil || !bck.Props.ObjLock.Enabled {
+		return nil
+	}
+	if until, known := cluster.RetainedUntil(bck); known && !time.Now().Before(until) {
+		return nil
+	}
+	return t.scanRetained(bck, true /*stop at retained*/)
+}
+
+// walk the bucket to track the latest retain-until time; optionally, stop at (and return)
+// the first retained object
+func (t *target) scanRetained(bck *cluster.Bck, stop bool) (err error) {
+	var (
+		latest time.Time
+		scan   = cluster.BeginRetainedScan(bck)
+	)
+	cb := func(fqn string, de fs.DirEntry) error {
+		if de.IsDir() {
+			return nil
+		}
+		lom := cluster.AllocLOM("")
+		defer cluster.FreeLOM(lom)
+		if lom.InitFQN(fqn, bck.Bucket()) != nil || lom.Load(false /*cache it*/, false /*locked*/) != nil {
+			return nil
+		}
+		if until := lom.RetainUntil(); until.After(latest) {
+			latest = until
+		}
+		if stop {
+			return lom.CheckRetained(false /*bypass governance*/)
+		}
+		return nil
+	}
+	for _, mi := range fs.GetAvail() {
+		opts := &fs.WalkOpts{Mi: mi, CTs: []string{fs.ObjectType}, Callback: cb}
+		opts.Bck.Copy(bck.Bucket())
+		if err = fs.Walk(opts); err != nil {
+			return
+		}
+	}
+	scan.End(latest)
+	return
+}
+
+// upon startup: track the latest retain-until times of the buckets with object lock enabled
+func (t *target) initRetained() {
+	var bcks []*cluster.Bck
+	t.owner.bmd.get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		if bck.Props.ObjLock.Enabled {
+			bcks = append(bcks, bck)
+		}
+		return false
+	})
+	for _, bck := range bcks {
+		if err := t.scanRetained(bck, false /*stop at retained*/); err != nil {
+			glog.Errorf("%s: failed to scan %s for retained objects: %v", t, bck, err)
+		}
+	}
+}
+
 func (t *target) promote(c *txnServerCtx, hdr http.Header) (string, error) {
 	if err := c.bck.Init(t.owner.bmd); err != nil {
 		return "", err
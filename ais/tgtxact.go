@@ -157,6 +157,18 @@ func (t *target) cmdXactStart(xactMsg *xact.QueryMsg, bck *cluster.Bck) error {
 		wg.Add(1)
 		go t.runStoreCleanup(xactMsg.ID, wg, xactMsg.Buckets...)
 		wg.Wait()
+	case apc.ActLifecycle:
+		if bck != nil {
+			glog.Errorf(erfmb, xactMsg.Kind, bck)
+		}
+		ext := &xact.QueryMsgLcycle{}
+		if err := cos.MorphMarshal(xactMsg.Ext, ext); err != nil {
+			return err
+		}
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		go t.runLifecycle(xactMsg.ID, wg, ext.DryRun, xactMsg.Buckets...)
+		wg.Wait()
 	case apc.ActResilver:
 		if bck != nil {
 			glog.Errorf(erfmb, xactMsg.Kind, bck)
@@ -189,9 +201,26 @@ func (t *target) cmdXactStart(xactMsg *xact.QueryMsg, bck *cluster.Bck) error {
 	case apc.ActLoadLomCache:
 		rns := xreg.RenewBckLoadLomCache(t, xactMsg.ID, bck)
 		return rns.Err
+	case apc.ActECScrub:
+		rns := xreg.RenewECScrub(t, bck, xactMsg.ID)
+		if rns.Err != nil {
+			return rns.Err
+		}
+		xctn := rns.Entry.Get()
+		xctn.AddNotif(&xact.NotifXact{
+			NotifBase: nl.NotifBase{
+				When: cluster.UponTerm,
+				Dsts: []string{equalIC},
+				F:    t.callerNotifyFin,
+			},
+			Xact: xctn,
+		})
+		go xctn.Run(nil)
 	// 3. cannot start
 	case apc.ActPutCopies:
 		return fmt.Errorf("cannot start %q (is driven by PUTs into a mirrored bucket)", xactMsg)
+	case apc.ActReplicate:
+		return fmt.Errorf("cannot start %q (is driven by writes and deletes in a replicated bucket)", xactMsg)
 	case apc.ActDownload, apc.ActEvictObjects, apc.ActDeleteObjects, apc.ActMakeNCopies, apc.ActECEncode:
 		return fmt.Errorf("initiating %q must be done via a separate documented API", xactMsg)
 	// 4. unknown
@@ -28,11 +28,13 @@ const (
 	ActECGet          = "ec-get"    // erasure decode objects
 	ActECPut          = "ec-put"    // erasure encode objects
 	ActECRespond      = "ec-resp"   // respond to other targets' EC requests
+	ActECScrub        = "ec-scrub"  // verify and repair EC slices and replicas
 	ActETLInline      = "etl-inline"
 	ActETLBck         = "etl-bck"
 	ActElection       = "election"
 	ActEvictRemoteBck = "evict-remote-bck" // evict remote bucket's data
 	ActInvalListCache = "inval-listobj-cache"
+	ActLifecycle      = "lifecycle" // execute bucket lifecycle rules (see cmn.LifecycleConf)
 	ActLRU            = "lru"
 	ActList           = "list"
 	ActLoadLomCache   = "load-lom-cache"
@@ -42,6 +44,7 @@ const (
 	ActPromote        = "promote"
 	ActPutCopies      = "put-copies"
 	ActRebalance      = "rebalance"
+	ActReplicate      = "replicate" // asynchronous bucket replication (see cmn.ReplicationConf)
 	ActRenameObject   = "rename-obj"
 	ActResetBprops    = "reset-bprops"
 	ActResetConfig    = "reset-config"
@@ -52,6 +55,7 @@ const (
 	ActShutdown       = "shutdown"
 	ActStartGFN       = "start-gfn"
 	ActStoreCleanup   = "cleanup-store"
+	ActUndeleteObject = "undelete-obj"
 
 	// multi-object (via `SelectObjsMsg`)
 	ActCopyObjects     = "copy-listrange"
@@ -129,6 +133,9 @@ const (
 	HdrObjCustomMD  = HeaderPrefix + "custom-md"      // Object custom metadata.
 	HdrObjVersion   = HeaderPrefix + "version"        // Object version/generation - ais or cloud.
 
+	// Object lock: bypass governance-mode retention (see cmn.ObjLockConf).
+	HdrBypassGovernance = HeaderPrefix + "bypass-governance"
+
 	// Append object header.
 	HdrAppendHandle = HeaderPrefix + "append-handle"
 
@@ -138,6 +145,9 @@ const (
 	// Reverse proxy headers.
 	HdrNodeID  = HeaderPrefix + "node-id"
 	HdrNodeURL = HeaderPrefix + "node-url"
+
+	// ETL pipeline: per-stage stats (JSON), sent as HTTP trailer of the inline transform.
+	HdrETLPipelineStats = HeaderPrefix + "etl-pipeline-stats"
 )
 
 // AuthN consts
@@ -260,6 +270,7 @@ const (
 	QparamTotalCompressedSize       = "tcs"
 	QparamTotalInputShardsExtracted = "tise"
 	QparamTotalUncompressedSize     = "tunc"
+	QparamResumePhase               = "rph" // phase to resume the (previously aborted) job from
 
 	// 2PC transactions - control plane
 	QparamNetwTimeout  = "xnt" // [begin, start-commit] timeout
@@ -333,7 +344,7 @@ const (
 	ObjStatusOK = iota
 	ObjStatusMovedNode
 	ObjStatusMovedMpath
-	ObjStatusDeleted // TODO: reserved for future when we introduce delayed delete of the object/bucket
+	ObjStatusDeleted // soft-deleted object that can be restored (see LsDeleted)
 
 	// Flags
 	EntryIsCached = 1 << (EntryStatusBits + 1)
@@ -372,6 +383,7 @@ const (
 	Clusters  = "clusters" // AuthN
 	Roles     = "roles"    // AuthN
 	IC        = "ic"       // information center
+	Batch     = "batch"    // get-batch
 
 	// l3
 	SyncSmap = "syncsmap" // legacy
@@ -379,6 +391,7 @@ const (
 	Voteres    = "result"
 	VoteInit   = "init"
 	Mountpaths = "mountpaths"
+	S3Keys     = "s3keys" // AuthN: S3 access keys
 
 	// (see the corresponding action messages above)
 	Keepalive      = "keepalive"
@@ -402,6 +415,7 @@ const (
 	FinishedAck = "finished_ack"
 	List        = "list"
 	Remove      = "remove"
+	Retry       = "retry"
 	Next        = "next"
 	Peek        = "peek"
 	Discard     = "discard"
@@ -411,6 +425,7 @@ const (
 	ETL         = "etl"
 	ETLInitSpec = "init_spec"
 	ETLInitCode = "init_code"
+	ETLInitProc = "init_proc"
 	ETLInfo     = "info"
 	ETLList     = List
 	ETLLogs     = "logs"
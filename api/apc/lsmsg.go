@@ -15,7 +15,7 @@ import (
 const (
 	LsPresent   = 1 << iota // applies to buckets with remote backends (to _optimize-out_ listing the latter)
 	LsMisplaced             // include misplaced obj-s
-	LsDeleted               // include obj-s marked for deletion (TODO)
+	LsDeleted               // list (only) soft-deleted obj-s that can be undeleted (see cmn.SoftDelConf)
 	LsArchDir               // expand archives as directories
 	LsNameOnly              // return only object names and statuses (for faster listing)
 
@@ -50,6 +50,7 @@ type (
 		ContinuationToken string `json:"continuation_token"` // `BucketList.ContinuationToken`
 		Flags             uint64 `json:"flags,string"`       // enum {LsPresent, ...} - see above
 		PageSize          uint   `json:"pagesize"`           // max entries returned by list objects call
+		Filter            string `json:"filter,omitempty"`   // custom metadata (and size) predicate - see cmn.ObjFilter
 	}
 )
 
@@ -75,7 +76,8 @@ func (lsmsg *ListObjsMsg) NeedLocalMD() bool {
 	return lsmsg.WantProp(GetPropsAtime) ||
 		lsmsg.WantProp(GetPropsStatus) ||
 		lsmsg.WantProp(GetPropsCopies) ||
-		lsmsg.WantProp(GetPropsCached)
+		lsmsg.WantProp(GetPropsCached) ||
+		lsmsg.WantProp(GetPropsCustom)
 }
 
 // WantProp returns true if msg request requires to return propName property.
@@ -28,6 +28,7 @@ type (
 		Ext cos.SimpleKVs `json:"ext"`
 
 		ID             string       `json:"id,omitempty"`              // optional, ETL only
+		IDs            []string     `json:"ids,omitempty"`             // optional, ETL pipeline (instead of ID)
 		RequestTimeout cos.Duration `json:"request_timeout,omitempty"` // optional, ETL only
 
 		CopyBckMsg
@@ -39,12 +40,33 @@ type (
 ////////////
 
 func (msg *TCBMsg) Validate() error {
-	if msg.ID == "" {
+	if msg.ID != "" && len(msg.IDs) > 0 {
+		return errors.New("ETL ID and pipeline (IDs) are mutually exclusive")
+	}
+	ids := msg.ETLs()
+	if len(ids) == 0 {
 		return ErrETLMissingUUID
 	}
+	for _, id := range ids {
+		if id == "" {
+			return ErrETLMissingUUID
+		}
+	}
 	return nil
 }
 
+// ETLs returns ordered list of ETLs to transform each object: the output of each
+// transformer is the input of the next one.
+func (msg *TCBMsg) ETLs() []string {
+	if len(msg.IDs) > 0 {
+		return msg.IDs
+	}
+	if msg.ID == "" {
+		return nil
+	}
+	return []string{msg.ID}
+}
+
 // Replace extension and add suffix if provided.
 func (msg *TCBMsg) ToName(name string) string {
 	if msg.Ext != nil {
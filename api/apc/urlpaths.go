@@ -28,6 +28,7 @@ var (
 	URLPathHealth    = urlpath(Version, Health)
 	URLPathMetasync  = urlpath(Version, Metasync)
 	URLPathRebalance = urlpath(Version, Rebalance)
+	URLPathBatch     = urlpath(Version, Batch)
 
 	URLPathClu        = urlpath(Version, Cluster)
 	URLPathCluProxy   = urlpath(Version, Cluster, Proxy)
@@ -67,6 +68,7 @@ var (
 	URLPathDownload       = urlpath(Version, Download)
 	URLPathDownloadAbort  = urlpath(Version, Download, Abort)
 	URLPathDownloadRemove = urlpath(Version, Download, Remove)
+	URLPathDownloadRetry  = urlpath(Version, Download, Retry)
 
 	URLPathETL       = urlpath(Version, ETL)
 	URLPathETLObject = urlpath(Version, ETL, ETLObject)
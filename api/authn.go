@@ -287,3 +287,42 @@ func SetAuthNConfig(baseParams BaseParams, conf *authn.ConfigToUpdate) error {
 	}
 	return reqParams.DoHTTPRequest()
 }
+
+// Generate a new S3 access key for a user. The returned key includes the
+// secret - AuthN never returns it again.
+func AddS3KeyAuthN(baseParams BaseParams, userID string) (*authn.S3Key, error) {
+	key := &authn.S3Key{}
+	baseParams.Method = http.MethodPost
+	reqParams := allocRp()
+	defer freeRp(reqParams)
+	{
+		reqParams.BaseParams = baseParams
+		reqParams.Path = apc.URLPathUsers.Join(userID, apc.S3Keys)
+	}
+	err := reqParams.DoHTTPReqResp(key)
+	return key, err
+}
+
+func GetS3KeysAuthN(baseParams BaseParams, userID string) ([]*authn.S3Key, error) {
+	keys := make([]*authn.S3Key, 0, 2)
+	baseParams.Method = http.MethodGet
+	reqParams := allocRp()
+	defer freeRp(reqParams)
+	{
+		reqParams.BaseParams = baseParams
+		reqParams.Path = apc.URLPathUsers.Join(userID, apc.S3Keys)
+	}
+	err := reqParams.DoHTTPReqResp(&keys)
+	return keys, err
+}
+
+func DeleteS3KeyAuthN(baseParams BaseParams, userID, accessKey string) error {
+	baseParams.Method = http.MethodDelete
+	reqParams := allocRp()
+	defer freeRp(reqParams)
+	{
+		reqParams.BaseParams = baseParams
+		reqParams.Path = apc.URLPathUsers.Join(userID, apc.S3Keys, accessKey)
+	}
+	return reqParams.DoHTTPRequest()
+}
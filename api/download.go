@@ -78,6 +78,18 @@ func DownloadBackend(baseParams BaseParams, description string, bck cmn.Bck, pre
 	return DownloadWithParam(baseParams, downloader.DlTypeBackend, dlBody)
 }
 
+// DownloadManifest downloads objects listed in the manifest (CSV or JSONL) stored in AIS bucket.
+func DownloadManifest(baseParams BaseParams, description string, bck, manifestBck cmn.Bck, manifestObj string,
+	intervals ...time.Duration) (string, error) {
+	dlBody := downloader.DlManifestBody{ManifestBck: manifestBck, ManifestObj: manifestObj}
+	if len(intervals) > 0 {
+		dlBody.ProgressInterval = intervals[0].String()
+	}
+	dlBody.Bck = bck
+	dlBody.Description = description
+	return DownloadWithParam(baseParams, downloader.DlTypeManifest, dlBody)
+}
+
 func DownloadStatus(baseParams BaseParams, id string, onlyActiveTasks ...bool) (resp downloader.DlStatusResp, err error) {
 	dlBody := downloader.DlAdminBody{ID: id}
 	if len(onlyActiveTasks) > 0 {
@@ -143,6 +155,23 @@ func RemoveDownload(baseParams BaseParams, id string) error {
 	return err
 }
 
+// RetryDownload starts a new download job that re-queues failed downloads of
+// the finished job `id`; returns the ID of the new job.
+func RetryDownload(baseParams BaseParams, id string) (string, error) {
+	dlBody := downloader.DlAdminBody{ID: id}
+	baseParams.Method = http.MethodPost
+	reqParams := allocRp()
+	{
+		reqParams.BaseParams = baseParams
+		reqParams.Path = apc.URLPathDownloadRetry.S
+		reqParams.Body = cos.MustMarshal(dlBody)
+		reqParams.Header = http.Header{cmn.HdrContentType: []string{cmn.ContentJSON}}
+	}
+	newID, err := reqParams.doDlDownloadRequest()
+	freeRp(reqParams)
+	return newID, err
+}
+
 func (reqParams *ReqParams) doDlDownloadRequest() (string, error) {
 	var resp downloader.DlPostResp
 	err := reqParams.DoHTTPReqResp(&resp)
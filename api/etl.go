@@ -117,6 +117,12 @@ func ETLGetInitMsg(params BaseParams, id string) (initMsg etl.InitMsg, err error
 		return
 	}
 
+	if _, ok := msgInf["command"]; ok {
+		initMsg = &etl.InitProcMsg{}
+		err = jsoniter.Unmarshal(b, initMsg)
+		return
+	}
+
 	if _, ok := msgInf["spec"]; !ok {
 		err = fmt.Errorf("invalid response body: %s", b)
 		return
@@ -146,6 +152,7 @@ func etlPostAction(baseParams BaseParams, id, action string) (err error) {
 	return err
 }
 
+// Comma-separated `id` (e.g. "a,b,c") specifies the pipeline of ETLs - see `etl.PipelineSep`.
 // TODO: "if query has UUID then the request is ETL" is not good enough. Add ETL-specific
 //       query param and change the examples/docs (!4455)
 func ETLObject(baseParams BaseParams, id string, bck cmn.Bck, objName string, w io.Writer) (err error) {
@@ -6,8 +6,11 @@ package api
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/textproto"
 	"net/url"
@@ -32,6 +35,26 @@ const (
 type (
 	NewRequestCB func(args *cmn.HreqArgs) (*http.Request, error)
 
+	// GetObjectRanges response reader
+	ObjectRangesReader struct {
+		body     io.ReadCloser
+		hdr      http.Header
+		mr       *multipart.Reader // nil if the response is not multipart (single range)
+		validate bool
+		done     bool
+	}
+	// a single range (see GetObjectRanges)
+	ObjectRange struct {
+		Reader  io.Reader
+		Cksum   *cos.Cksum    // range checksum if returned by the cluster (nil otherwise)
+		Range   cmn.HTTPRange // requested range
+		ObjSize int64         // size of the entire object
+	}
+	rangeCksumReader struct {
+		r        io.Reader
+		cksum    *cos.CksumHash
+		expected *cos.Cksum
+	}
 	GetObjectInput struct {
 		// If not specified otherwise, the Writer field defaults to io.Discard
 		Writer io.Writer
@@ -312,6 +335,102 @@ func GetObjectReader(baseParams BaseParams, bck cmn.Bck, object string, options
 	return
 }
 
+// GetObjectRanges reads multiple byte ranges of the object in a single request
+// (RFC 7233 multipart/byteranges). The returned reader yields the ranges in the
+// requested order - see ObjectRangesReader.Next. Caller is responsible for closing the reader.
+//
+// With `validate` set, each range is validated against its checksum when the latter
+// is returned by the cluster (see `cmn.CksumConf.EnableReadRange`); the corresponding
+// range reader then returns `*cos.ErrBadCksum` at the end of the range.
+func GetObjectRanges(baseParams BaseParams, bck cmn.Bck, object string, ranges []cmn.HTTPRange,
+	validate bool) (*ObjectRangesReader, error) {
+	if len(ranges) == 0 {
+		return nil, errors.New("no ranges specified")
+	}
+	baseParams.Method = http.MethodGet
+	reqParams := allocRp()
+	{
+		reqParams.BaseParams = baseParams
+		reqParams.Path = apc.URLPathObjects.Join(bck.Name, object)
+		reqParams.Query = bck.AddToQuery(nil)
+		reqParams.Header = cmn.MultiRangeHdr(ranges)
+	}
+	resp, err := reqParams.do()
+	if err == nil {
+		if err = reqParams.checkResp(resp); err != nil {
+			resp.Body.Close()
+		}
+	}
+	freeRp(reqParams)
+	if err != nil {
+		return nil, err
+	}
+	rr := &ObjectRangesReader{body: resp.Body, hdr: resp.Header, validate: validate}
+	mediaType, params, _ := mime.ParseMediaType(resp.Header.Get(cmn.HdrContentType))
+	if mediaType == cmn.ContentByteRanges {
+		rr.mr = multipart.NewReader(resp.Body, params["boundary"])
+	}
+	return rr, nil
+}
+
+// Next returns the next range (and its reader that must be fully read prior
+// to calling Next again), or io.EOF when there are no more ranges.
+func (rr *ObjectRangesReader) Next() (*ObjectRange, error) {
+	var (
+		hdr    http.Header
+		reader io.Reader
+	)
+	if rr.mr == nil {
+		// single range (or the entire object)
+		if rr.done {
+			return nil, io.EOF
+		}
+		rr.done = true
+		hdr, reader = rr.hdr, rr.body
+	} else {
+		part, err := rr.mr.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		hdr, reader = http.Header(part.Header), part
+	}
+	or := &ObjectRange{Reader: reader}
+	if cr := hdr.Get(cmn.HdrContentRange); cr != "" {
+		var err error
+		if or.Range, or.ObjSize, err = cmn.ParseContentRange(cr); err != nil {
+			return nil, err
+		}
+	} else {
+		or.ObjSize, _ = strconv.ParseInt(hdr.Get(cmn.HdrContentLength), 10, 64)
+		or.Range = cmn.HTTPRange{Start: 0, Length: or.ObjSize}
+	}
+	// NOTE: a single-range response carries the range checksum only if enabled,
+	// and the checksum of the entire object otherwise - cannot tell the two apart
+	ty := hdr.Get(apc.HdrObjCksumType)
+	if ty == "" || ty == cos.ChecksumNone || (rr.mr == nil && or.Range.Length != or.ObjSize) {
+		return or, nil
+	}
+	or.Cksum = cos.NewCksum(ty, hdr.Get(apc.HdrObjCksumVal))
+	if rr.validate {
+		or.Reader = &rangeCksumReader{r: reader, expected: or.Cksum, cksum: cos.NewCksumHash(ty)}
+	}
+	return or, nil
+}
+
+func (rr *ObjectRangesReader) Close() error { return rr.body.Close() }
+
+func (cr *rangeCksumReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.cksum.H.Write(p[:n])
+	if err == io.EOF {
+		cr.cksum.Finalize()
+		if !cr.cksum.Equal(cr.expected) {
+			err = cos.NewBadDataCksumError(cr.cksum.Clone(), cr.expected, "range")
+		}
+	}
+	return
+}
+
 // GetObjectWithValidation has same behavior as GetObject, but performs checksum
 // validation of the object by comparing the checksum in the response header
 // with the calculated checksum value derived from the returned object.
@@ -387,6 +506,32 @@ func GetObjectWithResp(baseParams BaseParams, bck cmn.Bck, object string, option
 	return resp.Response, resp.n, nil
 }
 
+// GetBatch reads multiple objects and/or archived files (from possibly different buckets)
+// in a single request. The response is a single archive (`msg.Mime`: tar or msgpack) that
+// contains the requested entries in the request order, each named "bucket/object[/archpath]".
+// Returns the size of the response written to `w` (if specified, discarded otherwise).
+//
+// Unless `msg.ContinueOnError` is set, any missing entry fails the entire batch.
+func GetBatch(baseParams BaseParams, msg *cmn.GetBatchMsg, w io.Writer) (n int64, err error) {
+	if w == nil {
+		w = io.Discard
+	}
+	baseParams.Method = http.MethodGet
+	reqParams := allocRp()
+	{
+		reqParams.BaseParams = baseParams
+		reqParams.Path = apc.URLPathBatch.S
+		reqParams.Body = cos.MustMarshal(msg)
+		reqParams.Header = http.Header{cmn.HdrContentType: []string{cmn.ContentJSON}}
+	}
+	resp, err := reqParams.doResp(w)
+	freeRp(reqParams)
+	if err != nil {
+		return 0, err
+	}
+	return resp.n, nil
+}
+
 // PutObject creates an object from the body of the reader (`args.Reader`) and puts
 // it in the specified bucket.
 //
@@ -514,6 +659,22 @@ func RenameObject(baseParams BaseParams, bck cmn.Bck, oldName, newName string) e
 	return err
 }
 
+// UndeleteObject restores soft-deleted object (see `cmn.SoftDelConf` and `apc.LsDeleted`).
+func UndeleteObject(baseParams BaseParams, bck cmn.Bck, object string) error {
+	baseParams.Method = http.MethodPost
+	reqParams := allocRp()
+	{
+		reqParams.BaseParams = baseParams
+		reqParams.Path = apc.URLPathObjects.Join(bck.Name, object)
+		reqParams.Body = cos.MustMarshal(apc.ActionMsg{Action: apc.ActUndeleteObject})
+		reqParams.Header = http.Header{cmn.HdrContentType: []string{cmn.ContentJSON}}
+		reqParams.Query = bck.AddToQuery(nil)
+	}
+	err := reqParams.DoHTTPRequest()
+	freeRp(reqParams)
+	return err
+}
+
 // promote files and directories to ais objects
 func Promote(args *PromoteArgs) (xactID string, err error) {
 	actMsg := apc.ActionMsg{Action: apc.ActPromote, Name: args.SrcFQN}
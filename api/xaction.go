@@ -51,6 +51,7 @@ type (
 		// max time to wait and other "non-filters"
 		Timeout time.Duration
 		Force   bool // force
+		DryRun  bool // dry-run (lifecycle)
 		// more filters
 		OnlyRunning bool // look only for running xactions
 	}
@@ -71,6 +72,9 @@ func StartXaction(baseParams BaseParams, args XactReqArgs) (id string, err error
 		xactMsg.Ext = ext
 	} else if args.Kind == apc.ActStoreCleanup && args.Buckets != nil {
 		xactMsg.Buckets = args.Buckets
+	} else if args.Kind == apc.ActLifecycle {
+		xactMsg.Buckets = args.Buckets
+		xactMsg.Ext = &xact.QueryMsgLcycle{DryRun: args.DryRun}
 	}
 
 	msg := apc.ActionMsg{Action: apc.ActXactStart, Value: xactMsg}
@@ -172,3 +172,58 @@ func TestToken(t *testing.T) {
 		t.Errorf("Invalid error(must be 'token expired'): %v", err)
 	}
 }
+
+func TestS3Keys(t *testing.T) {
+	driver := mock.NewDBDriver()
+	mgr, err := NewUserManager(driver)
+	tassert.CheckError(t, err)
+	createUsers(mgr, t)
+	defer deleteUsers(mgr, true, t)
+
+	_, err = mgr.addS3Key("someuser")
+	tassert.Errorf(t, err != nil, "S3 access key generated for non-existing user")
+
+	key, err := mgr.addS3Key(users[0])
+	tassert.CheckFatal(t, err)
+	if len(key.AccessKey) != s3AccessKeyLen || len(key.Secret) != s3SecretLen {
+		t.Errorf("Invalid S3 access key %+v", key)
+	}
+	_, err = mgr.addS3Key(users[1])
+	tassert.CheckFatal(t, err)
+
+	// secrets must not be listed
+	keys, err := mgr.userS3Keys(users[0])
+	tassert.CheckFatal(t, err)
+	if len(keys) != 1 || keys[0].AccessKey != key.AccessKey || keys[0].Secret != "" {
+		t.Errorf("Invalid S3 access keys of %s: %+v", users[0], keys)
+	}
+
+	// list for clusters: secrets and tokens with user permissions
+	list, err := mgr.s3KeysWithTokens()
+	tassert.CheckFatal(t, err)
+	if len(list.Keys) != 2 {
+		t.Fatalf("Expected 2 S3 access keys, got %d", len(list.Keys))
+	}
+	for _, k := range list.Keys {
+		if k.Secret == "" {
+			t.Errorf("S3 access key %s: missing secret", k.AccessKey)
+		}
+		info, err := DecryptToken(k.Token, Conf.Server.Secret)
+		tassert.CheckFatal(t, err)
+		if info.UserID != k.UserID {
+			t.Errorf("S3 access key %s: token issued for %s instead of %s", k.AccessKey, info.UserID, k.UserID)
+		}
+	}
+
+	err = mgr.delS3Key(users[1], key.AccessKey)
+	tassert.Errorf(t, err != nil, "S3 access key of %s deleted on behalf of %s", users[0], users[1])
+	tassert.CheckError(t, mgr.delS3Key(users[0], key.AccessKey))
+
+	// deleting a user deletes its keys
+	tassert.CheckError(t, mgr.delUser(users[1]))
+	list, err = mgr.s3KeysWithTokens()
+	tassert.CheckFatal(t, err)
+	if len(list.Keys) != 0 {
+		t.Errorf("Expected no S3 access keys, got %d", len(list.Keys))
+	}
+}
@@ -24,6 +24,32 @@ func (m *UserManager) broadcastRevoked(token string) {
 	m.broadcast(http.MethodDelete, apc.Tokens, body)
 }
 
+// update the list of S3 access keys on all clusters
+func (m *UserManager) broadcastS3Keys() {
+	list, err := m.s3KeysWithTokens()
+	if err != nil {
+		glog.Errorf("Failed to generate S3 access key list: %v", err)
+		return
+	}
+	m.broadcast(http.MethodPut, cos.JoinWords(apc.Tokens, apc.S3Keys), cos.MustMarshal(list))
+}
+
+// periodically pushes S3 access keys to all clusters to refresh (expiring) tokens of the keys
+func (m *UserManager) refreshS3Keys() {
+	ticker := time.NewTicker(s3KeyTokenTime / 2)
+	defer ticker.Stop()
+	for range ticker.C {
+		keys, err := m.s3KeyList()
+		if err != nil {
+			glog.Errorf("Failed to read S3 access keys: %v", err)
+			continue
+		}
+		if len(keys) > 0 {
+			m.broadcastS3Keys()
+		}
+	}
+}
+
 // broadcast the request to all clusters. If a cluster has a few URLS,
 // it sends to the first working one. Clusters are processed in parallel.
 func (m *UserManager) broadcast(method, path string, body []byte) {
@@ -44,15 +70,16 @@ func (m *UserManager) broadcast(method, path string, body []byte) {
 				}
 			}
 			if err != nil {
-				glog.Errorf("Failed to sync revoked tokens with %q: %v", clu.ID, err)
+				glog.Errorf("Failed to execute %q with %q: %v", method+" "+path, clu.ID, err)
 			}
 		}(clu)
 	}
 	wg.Wait()
 }
 
-// Send valid and non-expired revoked token list to a cluster.
+// Send valid and non-expired revoked token list, and S3 access keys (if any) to a cluster.
 func (m *UserManager) syncTokenList(cluster *Cluster) {
+	m.syncS3Keys(cluster)
 	tokenList, err := m.generateRevokedTokenList()
 	if err != nil {
 		glog.Errorf("failed to sync token list with %q: %v", cluster.ID, err)
@@ -76,6 +103,10 @@ func (m *UserManager) syncTokenList(cluster *Cluster) {
 // Generic function to send everything to a proxy
 func (m *UserManager) proxyRequest(method, proxyURL, path string, injson []byte) error {
 	startRequest := time.Now()
+	token, err := m.clusterToken()
+	if err != nil {
+		return err
+	}
 	for {
 		url := proxyURL + cos.JoinWords(apc.Version, path)
 		request, err := http.NewRequest(method, url, bytes.NewBuffer(injson))
@@ -88,6 +119,7 @@ func (m *UserManager) proxyRequest(method, proxyURL, path string, injson []byte)
 			client = m.clientHTTPS
 		}
 		request.Header.Set(cmn.HdrContentType, cmn.ContentJSON)
+		request.Header.Set(apc.HdrAuthorization, apc.AuthenticationTypeBearer+" "+token)
 		response, err := client.Do(request)
 		var respCode int
 		if response != nil {
@@ -111,3 +143,22 @@ func (m *UserManager) proxyRequest(method, proxyURL, path string, injson []byte)
 		time.Sleep(proxyRetryTime)
 	}
 }
+
+func (m *UserManager) syncS3Keys(cluster *Cluster) {
+	list, err := m.s3KeysWithTokens()
+	if err != nil {
+		glog.Errorf("failed to sync S3 access keys with %q: %v", cluster.ID, err)
+		return
+	}
+	// (always push the full list - empty, when the last key gets deleted)
+	body := cos.MustMarshal(list)
+	for _, u := range cluster.URLs {
+		if err = m.proxyRequest(http.MethodPut, u, cos.JoinWords(apc.Tokens, apc.S3Keys), body); err == nil {
+			break
+		}
+		err = fmt.Errorf("failed to sync S3 access keys with %q: %v", cluster.ID, err)
+	}
+	if err != nil {
+		glog.Error(err)
+	}
+}
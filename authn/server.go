@@ -52,6 +52,7 @@ func (a *Server) Run() (err error) {
 	glog.Infof("Launching public server at %s", portstring)
 
 	a.registerPublicHandlers()
+	go a.users.refreshS3Keys()
 	a.h = &http.Server{Addr: portstring, Handler: a.mux}
 	if Conf.Net.HTTP.UseHTTPS {
 		if err = a.h.ListenAndServeTLS(Conf.Net.HTTP.Certificate, Conf.Net.HTTP.Key); err == nil {
@@ -155,6 +156,10 @@ func (a *Server) httpUserDel(w http.ResponseWriter, r *http.Request) {
 	if err = checkAuthorization(w, r); err != nil {
 		return
 	}
+	if len(apiItems) == 3 && apiItems[1] == apc.S3Keys {
+		a.delS3Key(w, r, apiItems[0], apiItems[2])
+		return
+	}
 	if err := a.users.delUser(apiItems[0]); err != nil {
 		glog.Errorf("Failed to delete user: %v\n", err)
 		cmn.WriteErrMsg(w, r, "Failed to delete user: "+err.Error())
@@ -166,6 +171,8 @@ func (a *Server) httpUserPost(w http.ResponseWriter, r *http.Request) {
 		return
 	} else if len(apiItems) == 0 {
 		a.userAdd(w, r)
+	} else if len(apiItems) == 2 && apiItems[1] == apc.S3Keys {
+		a.addS3Key(w, r, apiItems[0])
 	} else {
 		a.userLogin(w, r)
 	}
@@ -223,6 +230,10 @@ func (a *Server) httpUserGet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(items) == 2 && items[1] == apc.S3Keys {
+		a.getS3Keys(w, r, items[0])
+		return
+	}
 	if len(items) > 1 {
 		cmn.WriteErrMsg(w, r, "invalid request")
 		return
@@ -260,6 +271,41 @@ func (a *Server) httpUserGet(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, uInfo, "user info")
 }
 
+// Generates a new S3 access key for a user
+func (a *Server) addS3Key(w http.ResponseWriter, r *http.Request, userID string) {
+	if err := checkAuthorization(w, r); err != nil {
+		return
+	}
+	key, err := a.users.addS3Key(userID)
+	if err != nil {
+		cmn.WriteErr(w, r, err)
+		return
+	}
+	if glog.V(4) {
+		glog.Infof("Added S3 access key %s for user %s\n", key.AccessKey, userID)
+	}
+	writeJSON(w, key, "add S3 access key")
+}
+
+// Returns S3 access keys of a user (secrets are never returned)
+func (a *Server) getS3Keys(w http.ResponseWriter, r *http.Request, userID string) {
+	if err := checkAuthorization(w, r); err != nil {
+		return
+	}
+	keys, err := a.users.userS3Keys(userID)
+	if err != nil {
+		cmn.WriteErr(w, r, err)
+		return
+	}
+	writeJSON(w, keys, "list S3 access keys")
+}
+
+func (a *Server) delS3Key(w http.ResponseWriter, r *http.Request, userID, accessKey string) {
+	if err := a.users.delS3Key(userID, accessKey); err != nil {
+		cmn.WriteErr(w, r, err, http.StatusNotFound)
+	}
+}
+
 // Checks if the request header contains super-user credentials and they are
 // valid. Super-user is a user created at deployment time that cannot be
 // deleted/created via REST API
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
@@ -27,13 +28,23 @@ const (
 	rolesCollection    = "role"
 	revokedCollection  = "revoked"
 	clustersCollection = "cluster"
+	s3KeysCollection   = "s3key"
 
 	adminID   = "admin"
 	adminPass = "admin"
 
+	s3AccessKeyLen = 20 // as in AWS
+	s3SecretLen    = 40 // ditto
+
 	proxyTimeout     = 2 * time.Minute           // maximum time for syncing Authn data with primary proxy
 	proxyRetryTime   = 5 * time.Second           // an interval between primary proxy detection attempts
 	foreverTokenTime = 24 * 365 * 20 * time.Hour // kind of never-expired token
+
+	// tokens that carry permissions of S3 access keys expire and get refreshed
+	// (along with the keys) on all clusters every s3KeyTokenTime/2
+	s3KeyTokenTime = 24 * time.Hour
+	// token that AuthN uses to authenticate its own requests to clusters
+	clusterTokenTime = proxyTimeout + time.Minute
 )
 
 type (
@@ -220,7 +231,20 @@ func (m *UserManager) delUser(userID string) error {
 	if userID == adminID {
 		return errors.New("cannot remove built-in administrator account")
 	}
-	return m.db.Delete(usersCollection, userID)
+	if err := m.db.Delete(usersCollection, userID); err != nil {
+		return err
+	}
+	keys, err := m.userS3Keys(userID)
+	if err != nil || len(keys) == 0 {
+		return err
+	}
+	for _, key := range keys {
+		if err := m.db.Delete(s3KeysCollection, key.AccessKey); err != nil {
+			glog.Errorf("Failed to delete S3 access key %s of user %q: %v", key.AccessKey, userID, err)
+		}
+	}
+	go m.broadcastS3Keys()
+	return nil
 }
 
 // Unregister an existing cluster
@@ -261,7 +285,12 @@ func (m *UserManager) updateUser(userID string, updateReq *User) error {
 	uInfo.Clusters = MergeClusterACLs(uInfo.Clusters, updateReq.Clusters)
 	uInfo.Buckets = MergeBckACLs(uInfo.Buckets, updateReq.Buckets)
 
-	return m.db.Set(usersCollection, userID, uInfo)
+	if err := m.db.Set(usersCollection, userID, uInfo); err != nil {
+		return err
+	}
+	// S3 access keys carry user permissions
+	go m.broadcastS3Keys()
+	return nil
 }
 
 // Updates an existing role
@@ -284,7 +313,11 @@ func (m *UserManager) updateRole(role string, updateReq *Role) error {
 	rInfo.Clusters = MergeClusterACLs(rInfo.Clusters, updateReq.Clusters)
 	rInfo.Buckets = MergeBckACLs(rInfo.Buckets, updateReq.Buckets)
 
-	return m.db.Set(rolesCollection, role, rInfo)
+	if err := m.db.Set(rolesCollection, role, rInfo); err != nil {
+		return err
+	}
+	go m.broadcastS3Keys()
+	return nil
 }
 
 func (m *UserManager) lookupRole(roleID string) (*Role, error) {
@@ -371,7 +404,6 @@ func (m *UserManager) issueToken(userID, pwd string, ttl *time.Duration) (string
 	// generate token
 	Conf.RLock()
 	defer Conf.RUnlock()
-	issued := time.Now()
 	expDelta := time.Duration(Conf.Server.ExpirePeriod)
 	if ttl != nil {
 		expDelta = *ttl
@@ -379,8 +411,21 @@ func (m *UserManager) issueToken(userID, pwd string, ttl *time.Duration) (string
 	if expDelta == 0 {
 		expDelta = foreverTokenTime
 	}
-	expires = issued.Add(expDelta)
+	expires = time.Now().Add(expDelta)
+	return m.signToken(uInfo, expires)
+}
 
+// Creates a short-lived admin token for AuthN to authenticate itself with clusters
+func (m *UserManager) clusterToken() (string, error) {
+	Conf.RLock()
+	defer Conf.RUnlock()
+	svc := &User{ID: svcName, Roles: []string{AdminRole}}
+	return m.signToken(svc, time.Now().Add(clusterTokenTime))
+}
+
+// Creates a signed token that includes user ID, permissions, and token
+// expiration time. The caller must hold `Conf` lock.
+func (m *UserManager) signToken(uInfo *User, expires time.Time) (string, error) {
 	// put all useful info into token: who owns the token, when it was issued,
 	// when it expires and credentials to log in AWS, GCP etc.
 	// If a user is a super user, it is enough to pass only isAdmin marker
@@ -388,14 +433,14 @@ func (m *UserManager) issueToken(userID, pwd string, ttl *time.Duration) (string
 	if uInfo.IsAdmin() {
 		t = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 			"expires":  expires,
-			"username": userID,
+			"username": uInfo.ID,
 			"admin":    true,
 		})
 	} else {
 		m.fixClusterIDs(uInfo.Clusters)
 		t = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 			"expires":  expires,
-			"username": userID,
+			"username": uInfo.ID,
 			"buckets":  uInfo.Buckets,
 			"clusters": uInfo.Clusters,
 		})
@@ -529,3 +574,96 @@ func (m *UserManager) createRolesForCluster(clu *Cluster) {
 		}
 	}
 }
+
+// Generates a new S3 access key for an existing user. The secret part is
+// returned to the caller only once, at creation time.
+func (m *UserManager) addS3Key(userID string) (*S3Key, error) {
+	uInfo := &User{}
+	if err := m.db.Get(usersCollection, userID, uInfo); err != nil {
+		return nil, cmn.NewErrNotFound("user-manager: %s user %q", svcName, userID)
+	}
+	key := &S3Key{
+		AccessKey: strings.ToUpper(cos.RandStringStrong(s3AccessKeyLen)),
+		Secret:    cos.RandStringStrong(s3SecretLen),
+		UserID:    userID,
+	}
+	if _, err := m.db.GetString(s3KeysCollection, key.AccessKey); err == nil {
+		return nil, fmt.Errorf("S3 access key %s already exists", key.AccessKey) // (unlikely)
+	}
+	if err := m.db.Set(s3KeysCollection, key.AccessKey, key); err != nil {
+		return nil, err
+	}
+	go m.broadcastS3Keys()
+	return key, nil
+}
+
+// Deletes S3 access key of a given user
+func (m *UserManager) delS3Key(userID, accessKey string) error {
+	key := &S3Key{}
+	if err := m.db.Get(s3KeysCollection, accessKey, key); err != nil || key.UserID != userID {
+		return cmn.NewErrNotFound("user-manager: %s user %q S3 access key %s", svcName, userID, accessKey)
+	}
+	if err := m.db.Delete(s3KeysCollection, accessKey); err != nil {
+		return err
+	}
+	go m.broadcastS3Keys()
+	return nil
+}
+
+// Returns S3 access keys of a given user (without secrets)
+func (m *UserManager) userS3Keys(userID string) ([]*S3Key, error) {
+	keys, err := m.s3KeyList()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*S3Key, 0, 2)
+	for _, key := range keys {
+		if key.UserID == userID {
+			res = append(res, &S3Key{AccessKey: key.AccessKey, UserID: key.UserID})
+		}
+	}
+	return res, nil
+}
+
+func (m *UserManager) s3KeyList() ([]*S3Key, error) {
+	recs, err := m.db.GetAll(s3KeysCollection, "")
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*S3Key, 0, len(recs))
+	for _, str := range recs {
+		key := &S3Key{}
+		if err := jsoniter.Unmarshal([]byte(str), key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Returns all S3 access keys, each with a token that carries the current
+// permissions of the key's owner - to be sent to clusters.
+func (m *UserManager) s3KeysWithTokens() (*S3KeyList, error) {
+	keys, err := m.s3KeyList()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		list    = &S3KeyList{Keys: make([]*S3Key, 0, len(keys))}
+		expires = time.Now().Add(s3KeyTokenTime)
+	)
+	Conf.RLock()
+	defer Conf.RUnlock()
+	for _, key := range keys {
+		uInfo, err := m.lookupUser(key.UserID)
+		if err != nil {
+			glog.Errorf("S3 access key %s: failed to lookup user %q: %v", key.AccessKey, key.UserID, err)
+			continue
+		}
+		if key.Token, err = m.signToken(uInfo, expires); err != nil {
+			return nil, err
+		}
+		list.Keys = append(list.Keys, key)
+	}
+	return list, nil
+}
@@ -71,6 +71,20 @@ type (
 	TokenMsg struct {
 		Token string `json:"token"`
 	}
+	// S3 access key: access key ID and secret used to sign (AWS SigV4) requests
+	// to the S3-compatible API on behalf of a user
+	S3Key struct {
+		AccessKey string `json:"access_key"`
+		Secret    string `json:"secret_key,omitempty"`
+		UserID    string `json:"user_id"`
+		// token that carries the user's permissions
+		// (AuthN => cluster only; never returned to a user)
+		Token string `json:"token,omitempty"`
+	}
+	// S3 access keys pushed by AuthN to clusters (always the full list)
+	S3KeyList struct {
+		Keys []*S3Key `json:"keys"`
+	}
 )
 
 /////////////////////
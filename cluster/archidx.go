@@ -0,0 +1,397 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/jsp"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Archive (shard) index: the names, offsets, sizes, and checksums of the files
+// archived in a given tar, tgz, zip, or msgpack formatted object.
+//
+// The index is built upon the first access (see LOM.ArchIdx) and is stored as
+// `fs.ArchIdxType` content next to the object. It is valid for as long as the object's
+// size and mtime remain unchanged - otherwise, it gets rebuilt. Archive indexes are not
+// moved by rebalance (the new owner rebuilds the index), and orphaned indexes are removed
+// by the storage cleanup.
+//
+// Offsets are absolute offsets of the archived files' data in the object, except:
+// - tgz: offsets in the uncompressed tar stream (reading requires decompression);
+// - zip: offsets of the (possibly, compressed) data - see `Method` and `CSize`.
+
+type (
+	ArchIdx struct {
+		Mime      string          `json:"mime"`        // one of the cos.ArchExtensions
+		CksumType string          `json:"cksum_type"`  // type of the archived files' checksums
+		ObjSize   int64           `json:"size,string"` // size and mtime of the indexed object
+		ObjMtime  int64           `json:"mtime,string"`
+		Entries   []*ArchIdxEntry `json:"entries"` // sorted by name
+		byName    map[string]*ArchIdxEntry
+	}
+	ArchIdxEntry struct {
+		Name   string `json:"name"`
+		Offset int64  `json:"off,string"`
+		Size   int64  `json:"size,string"`            // (uncompressed) size
+		CSize  int64  `json:"csize,string,omitempty"` // zip: compressed size
+		Method uint16 `json:"method,omitempty"`       // zip: compression method
+		Cksum  string `json:"cksum,omitempty"`        // checksum (value) of the archived file
+	}
+
+	// counts bytes read from the underlying reader
+	archCounter struct {
+		r   io.Reader
+		off int64
+	}
+	archRC struct {
+		io.Reader
+		closer io.Closer
+		size   int64
+	}
+)
+
+var (
+	archIdxJspOpts = jsp.CCSign(cmn.MetaverArchIdx)
+
+	errArchIdxUnsupported = errors.New("cannot index")
+)
+
+func (lom *LOM) ArchIdxFQN() string {
+	return lom.mpathInfo.MakePathFQN(lom.Bucket(), fs.ArchIdxType, lom.ObjName)
+}
+
+// ArchIdx returns the object's archive index; loads the index or (re)builds
+// and stores it if need be. The caller must take (at least) a read lock.
+func (lom *LOM) ArchIdx(mime string) (idx *ArchIdx, err error) {
+	finfo, err := os.Stat(lom.FQN)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		idxFQN = lom.ArchIdxFQN()
+		loaded = &ArchIdx{}
+	)
+	if _, err = jsp.Load(idxFQN, loaded, archIdxJspOpts); err == nil &&
+		loaded.Mime == mime && loaded.ObjSize == finfo.Size() && loaded.ObjMtime == finfo.ModTime().UnixNano() {
+		loaded.init()
+		return loaded, nil
+	}
+	if idx, err = lom.buildArchIdx(mime, finfo); err != nil {
+		return nil, err
+	}
+	if err = jsp.Save(idxFQN, idx, archIdxJspOpts, nil); err != nil {
+		// not persisting is not fatal - the index will be rebuilt next time
+		lom.RemoveArchIdx()
+		err = nil
+	}
+	return idx, nil
+}
+
+// RemoveArchIdx removes the object's archive index, if exists.
+func (lom *LOM) RemoveArchIdx() (err error) {
+	if err = cos.RemoveFile(lom.ArchIdxFQN()); os.IsNotExist(err) {
+		err = nil
+	}
+	return
+}
+
+func IsErrArchIdxUnsupported(err error) bool { return errors.Is(err, errArchIdxUnsupported) }
+
+func (lom *LOM) buildArchIdx(mime string, finfo os.FileInfo) (idx *ArchIdx, err error) {
+	fh, err := os.Open(lom.FQN)
+	if err != nil {
+		return nil, err
+	}
+	idx = &ArchIdx{
+		Mime:      mime,
+		CksumType: lom.CksumConf().Type,
+		ObjSize:   finfo.Size(),
+		ObjMtime:  finfo.ModTime().UnixNano(),
+	}
+	switch mime {
+	case cos.ExtTar:
+		err = idx.fromTar(fh)
+	case cos.ExtTgz, cos.ExtTarTgz:
+		var gzr *gzip.Reader
+		if gzr, err = gzip.NewReader(fh); err == nil {
+			err = idx.fromTar(gzr)
+			gzr.Close()
+		}
+	case cos.ExtZip:
+		err = idx.fromZip(fh)
+	case cos.ExtMsgpack:
+		err = idx.fromMsgpack(fh)
+	default:
+		err = cos.NewUnknownMimeError(mime)
+	}
+	cos.Close(fh)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to index %s: %w", lom, mime, err)
+	}
+	sort.Slice(idx.Entries, func(i, j int) bool { return idx.Entries[i].Name < idx.Entries[j].Name })
+	idx.init()
+	return idx, nil
+}
+
+func (idx *ArchIdx) init() {
+	idx.byName = make(map[string]*ArchIdxEntry, len(idx.Entries))
+	for _, e := range idx.Entries {
+		idx.byName[e.Name] = e
+	}
+}
+
+// Find returns the archived file's entry, or nil if not found
+// (NOTE: in re `--absolute-names`, names with and without leading separator are the same)
+func (idx *ArchIdx) Find(name string) *ArchIdxEntry {
+	if e, ok := idx.byName[name]; ok {
+		return e
+	}
+	if name != "" && name[0] == filepath.Separator {
+		return idx.byName[name[1:]]
+	}
+	return idx.byName[string(filepath.Separator)+name]
+}
+
+// Open returns a reader of the [off, off + length) range of the archived file;
+// `r` is the (open) object.
+func (idx *ArchIdx) Open(r io.ReaderAt, e *ArchIdxEntry, off, length int64) (cos.ReadCloseSizer, error) {
+	debug.Assert(off >= 0 && off+length <= e.Size)
+	switch {
+	case idx.Mime == cos.ExtTgz || idx.Mime == cos.ExtTarTgz:
+		gzr, err := gzip.NewReader(io.NewSectionReader(r, 0, idx.ObjSize))
+		if err != nil {
+			return nil, err
+		}
+		return newArchRC(gzr, gzr, e.Offset+off, length)
+	case idx.Mime == cos.ExtZip && e.Method == zip.Deflate:
+		fr := flate.NewReader(io.NewSectionReader(r, e.Offset, e.CSize))
+		return newArchRC(fr, fr, off, length)
+	default:
+		sr := io.NewSectionReader(r, e.Offset+off, length)
+		return &archRC{Reader: sr, size: length}, nil
+	}
+}
+
+// skip `off` bytes of the (decompressed) stream and limit the rest to `length`
+func newArchRC(r io.Reader, closer io.Closer, off, length int64) (*archRC, error) {
+	if off > 0 {
+		if _, err := io.CopyN(io.Discard, r, off); err != nil {
+			closer.Close()
+			return nil, err
+		}
+	}
+	return &archRC{Reader: io.LimitReader(r, length), closer: closer, size: length}, nil
+}
+
+func (rc *archRC) Size() int64 { return rc.size }
+
+func (rc *archRC) Close() error {
+	if rc.closer == nil {
+		return nil
+	}
+	return rc.closer.Close()
+}
+
+// add archived file (reading it to the end to compute its checksum)
+func (idx *ArchIdx) add(name string, off, size int64, r io.Reader) (*ArchIdxEntry, error) {
+	_, cksum, err := cos.CopyAndChecksum(io.Discard, r, nil, idx.CksumType)
+	if err != nil {
+		return nil, err
+	}
+	e := &ArchIdxEntry{Name: name, Offset: off, Size: size}
+	if cksum != nil {
+		e.Cksum = cksum.Value()
+	}
+	idx.Entries = append(idx.Entries, e)
+	return e, nil
+}
+
+//
+// index: tar, tgz, zip, msgpack
+//
+
+func (idx *ArchIdx) fromTar(r io.Reader) error {
+	var (
+		cnt = &archCounter{r: r}
+		tr  = tar.NewReader(cnt)
+	)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if hdr.FileInfo().IsDir() {
+			continue
+		}
+		off := cnt.off
+		if _, err := idx.add(hdr.Name, off, hdr.Size, tr); err != nil {
+			return err
+		}
+		// e.g., sparse files: stored data is not contiguous
+		if cnt.off-off != hdr.Size {
+			return fmt.Errorf("%w %q (type %q)", errArchIdxUnsupported, hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+func (idx *ArchIdx) fromZip(fh *os.File) error {
+	zr, err := zip.NewReader(fh, idx.ObjSize)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if f.Method != zip.Store && f.Method != zip.Deflate {
+			return fmt.Errorf("%w %q (compression method %d)", errArchIdxUnsupported, f.Name, f.Method)
+		}
+		off, err := f.DataOffset()
+		if err != nil {
+			return err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		e, err := idx.add(f.Name, off, int64(f.UncompressedSize64), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		e.CSize, e.Method = int64(f.CompressedSize64), f.Method
+	}
+	return nil
+}
+
+// msgpack shard: a map of (string) names to (binary) file contents
+func (idx *ArchIdx) fromMsgpack(r io.Reader) error {
+	cnt := &archCounter{r: bufio.NewReader(r)}
+	n, err := cnt.mpMapLen()
+	if err != nil {
+		return err
+	}
+	for i := int64(0); i < n; i++ {
+		l, err := cnt.mpStrLen()
+		if err != nil {
+			return err
+		}
+		if err := idx.mpCheckLen(cnt, l); err != nil {
+			return err
+		}
+		name := make([]byte, l)
+		if _, err := io.ReadFull(cnt, name); err != nil {
+			return err
+		}
+		if l, err = cnt.mpStrLen(); err != nil {
+			return err
+		}
+		if err := idx.mpCheckLen(cnt, l); err != nil {
+			return err
+		}
+		off := cnt.off
+		if _, err := idx.add(string(name), off, l, io.LimitReader(cnt, l)); err != nil {
+			return err
+		}
+		if cnt.off-off != l {
+			return io.ErrUnexpectedEOF
+		}
+	}
+	return nil
+}
+
+// the length (read from the object) must not exceed the rest of the object
+func (idx *ArchIdx) mpCheckLen(cnt *archCounter, l int64) error {
+	if l > idx.ObjSize-cnt.off {
+		return fmt.Errorf("invalid msgpack length %d at offset %d (object size %d)", l, cnt.off, idx.ObjSize)
+	}
+	return nil
+}
+
+/////////////////
+// archCounter //
+/////////////////
+
+func (cnt *archCounter) Read(b []byte) (n int, err error) {
+	n, err = cnt.r.Read(b)
+	cnt.off += int64(n)
+	return
+}
+
+func (cnt *archCounter) readUint(size int) (uint64, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(cnt, b[:size]); err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b[:2])), nil
+	default:
+		return uint64(binary.BigEndian.Uint32(b[:4])), nil
+	}
+}
+
+// msgpack: fixmap, map 16/32
+func (cnt *archCounter) mpMapLen() (int64, error) {
+	c, err := cnt.readUint(1)
+	if err != nil {
+		return 0, err
+	}
+	var l uint64
+	switch b := byte(c); {
+	case b&0xf0 == 0x80:
+		l = uint64(b & 0x0f)
+	case b == 0xde:
+		l, err = cnt.readUint(2)
+	case b == 0xdf:
+		l, err = cnt.readUint(4)
+	default:
+		err = fmt.Errorf("%w: unexpected msgpack format 0x%x at offset %d", errArchIdxUnsupported, b, cnt.off-1)
+	}
+	return int64(l), err
+}
+
+// msgpack: str (fixstr, str 8/16/32) or bin (bin 8/16/32)
+func (cnt *archCounter) mpStrLen() (int64, error) {
+	c, err := cnt.readUint(1)
+	if err != nil {
+		return 0, err
+	}
+	var l uint64
+	switch b := byte(c); {
+	case b&0xe0 == 0xa0:
+		l = uint64(b & 0x1f)
+	case b == 0xd9 || b == 0xc4:
+		l, err = cnt.readUint(1)
+	case b == 0xda || b == 0xc5:
+		l, err = cnt.readUint(2)
+	case b == 0xdb || b == 0xc6:
+		l, err = cnt.readUint(4)
+	default:
+		err = fmt.Errorf("%w: unexpected msgpack format 0x%x at offset %d", errArchIdxUnsupported, b, cnt.off-1)
+	}
+	return int64(l), err
+}
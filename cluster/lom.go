@@ -504,6 +504,9 @@ func (lom *LOM) Remove() (err error) {
 			err = erc
 		}
 	}
+	if _, erm := cos.Mime("", lom.ObjName); erm == nil {
+		lom.RemoveArchIdx() // (archives identified by magic are left to the storage cleanup)
+	}
 	lom.md.bckID = 0
 	return
 }
@@ -637,3 +640,36 @@ rerr:
 	err = cmn.NewObjectAccessDenied(msg, apc.AccessOp(apc.AceDisconnectedBackend), bck.Props.Access)
 	return
 }
+
+//
+// object lock (WORM) - see cmn.ObjLockConf
+//
+
+// returns zero time if the object has no (valid) retain-until
+func (lom *LOM) RetainUntil() (until time.Time) {
+	if s, ok := lom.GetCustomKey(cmn.RetainUntilObjMD); ok {
+		until, _ = time.Parse(time.RFC3339, s)
+	}
+	return
+}
+
+func (lom *LOM) SetRetainUntil(until time.Time) {
+	lom.SetCustomKey(cmn.RetainUntilObjMD, until.UTC().Format(time.RFC3339))
+}
+
+// returns cmn.ErrObjRetained if the (loaded) object cannot be modified;
+// governance-mode retention can be bypassed
+func (lom *LOM) CheckRetained(bypassGovernance bool) error {
+	conf := &lom.Bprops().ObjLock
+	if !conf.Enabled {
+		return nil
+	}
+	until := lom.RetainUntil()
+	if !time.Now().Before(until) {
+		return nil
+	}
+	if bypassGovernance && !conf.IsCompliance() {
+		return nil
+	}
+	return cmn.NewErrObjRetained(lom.FullName(), conf.Mode, until)
+}
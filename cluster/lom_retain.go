@@ -0,0 +1,83 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"sync"
+	"time"
+)
+
+// Object lock: per-bucket latest retain-until time of the objects stored on this target.
+// The purpose is to tell - without walking the bucket - that none of its objects is
+// currently retained (e.g., when destroying the bucket).
+//
+// The time is tracked only after the bucket has been scanned (see BeginRetainedScan and
+// EndRetainedScan), whereby objects persisted during and after the scan update it as well.
+// When the retention of some object gets shortened or bypassed, the tracked time remains
+// an upper bound - the caller is then expected to re-scan.
+
+type (
+	retainedBck struct {
+		until int64 // unix nanoseconds
+		known bool  // the bucket has been fully scanned
+	}
+	RetainedScan struct {
+		rb  *retainedBck
+		bid uint64
+	}
+)
+
+var retained = struct {
+	sync.Mutex
+	m map[uint64]*retainedBck // by bucket ID
+}{m: make(map[uint64]*retainedBck, 4)}
+
+// (called upon persisting object metadata)
+func (lom *LOM) trackRetained() {
+	bprops := lom.Bprops()
+	if bprops == nil || !bprops.ObjLock.Enabled {
+		return
+	}
+	until := lom.RetainUntil()
+	if until.IsZero() {
+		return
+	}
+	retained.Lock()
+	if rb, ok := retained.m[bprops.BID]; ok && rb.until < until.UnixNano() {
+		rb.until = until.UnixNano()
+	}
+	retained.Unlock()
+}
+
+// returns the latest retain-until time of the bucket's objects, if known
+func RetainedUntil(bck *Bck) (until time.Time, known bool) {
+	retained.Lock()
+	if rb, ok := retained.m[bck.Props.BID]; ok && rb.known {
+		until, known = time.Unix(0, rb.until), true
+	}
+	retained.Unlock()
+	return
+}
+
+// starts tracking the bucket from scratch - to be called prior to scanning all its objects
+func BeginRetainedScan(bck *Bck) *RetainedScan {
+	scan := &RetainedScan{rb: &retainedBck{}, bid: bck.Props.BID}
+	retained.Lock()
+	retained.m[scan.bid] = scan.rb
+	retained.Unlock()
+	return scan
+}
+
+// records the latest retain-until time found by the (complete) scan
+func (scan *RetainedScan) End(until time.Time) {
+	retained.Lock()
+	if rb := scan.rb; retained.m[scan.bid] == rb { // (unless superseded by another scan)
+		if !until.IsZero() && rb.until < until.UnixNano() {
+			rb.until = until.UnixNano()
+		}
+		rb.known = true
+	}
+	retained.Unlock()
+}
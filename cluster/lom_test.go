@@ -5,11 +5,16 @@
 package cluster_test
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
 	"crypto/rand"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"time"
 
@@ -22,6 +27,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/vmihailenco/msgpack"
 )
 
 var _ = Describe("LOM", func() {
@@ -32,6 +38,8 @@ var _ = Describe("LOM", func() {
 		bucketLocalA = "LOM_TEST_Local_A"
 		bucketLocalB = "LOM_TEST_Local_B"
 		bucketLocalC = "LOM_TEST_Local_C"
+		bucketLocalD = "LOM_TEST_Local_D"
+		bucketLocalE = "LOM_TEST_Local_E"
 
 		bucketCloudA = "LOM_TEST_Cloud_A"
 		bucketCloudB = "LOM_TEST_Cloud_B"
@@ -42,6 +50,8 @@ var _ = Describe("LOM", func() {
 	var (
 		localBckA = cmn.Bck{Name: bucketLocalA, Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
 		localBckB = cmn.Bck{Name: bucketLocalB, Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
+		localBckD = cmn.Bck{Name: bucketLocalD, Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
+		localBckE = cmn.Bck{Name: bucketLocalE, Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
 		cloudBckA = cmn.Bck{Name: bucketCloudA, Provider: apc.ProviderAmazon, Ns: cmn.NsGlobal}
 	)
 
@@ -94,6 +104,14 @@ var _ = Describe("LOM", func() {
 		cluster.NewBck(bucketCloudA, apc.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{BID: 5}),
 		cluster.NewBck(bucketCloudB, apc.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{BID: 6}),
 		cluster.NewBck(sameBucketName, apc.ProviderAmazon, cmn.NsGlobal, &cmn.BucketProps{BID: 7}),
+		cluster.NewBck(
+			bucketLocalD, apc.ProviderAIS, cmn.NsGlobal,
+			&cmn.BucketProps{ObjLock: cmn.ObjLockConf{Enabled: true, Mode: cmn.ObjLockGovernance}, BID: 8},
+		),
+		cluster.NewBck(
+			bucketLocalE, apc.ProviderAIS, cmn.NsGlobal,
+			&cmn.BucketProps{ObjLock: cmn.ObjLockConf{Enabled: true, Mode: cmn.ObjLockCompliance}, BID: 9},
+		),
 	)
 
 	BeforeEach(func() {
@@ -578,6 +596,68 @@ var _ = Describe("LOM", func() {
 				Expect(exists).To(BeFalse())
 			})
 		})
+
+		Describe("ObjLock", func() {
+			testObject := "foldr/test-obj.ext"
+
+			It("should not allow to modify retained object", func() {
+				fqn := mis[0].MakePathFQN(&localBckD, fs.ObjectType, testObject)
+				lom := filePut(fqn, 0)
+				Expect(lom.CheckRetained(false)).NotTo(HaveOccurred())
+
+				until := time.Now().Add(time.Hour).Truncate(time.Second)
+				lom.SetRetainUntil(until)
+				Expect(persist(lom)).NotTo(HaveOccurred())
+				lom.Uncache(false)
+
+				lom = NewBasicLom(fqn)
+				Expect(lom.Load(false, false)).NotTo(HaveOccurred())
+				Expect(lom.RetainUntil().Equal(until)).To(BeTrue())
+				err := lom.CheckRetained(false)
+				Expect(cmn.IsErrObjRetained(err)).To(BeTrue())
+				Expect(lom.CheckRetained(true /*bypass governance*/)).NotTo(HaveOccurred())
+			})
+
+			It("should not allow to bypass compliance mode", func() {
+				fqn := mis[0].MakePathFQN(&localBckE, fs.ObjectType, testObject)
+				lom := filePut(fqn, 0)
+				lom.SetRetainUntil(time.Now().Add(time.Hour))
+				Expect(cmn.IsErrObjRetained(lom.CheckRetained(true))).To(BeTrue())
+			})
+
+			It("should allow to modify object once retention expires", func() {
+				fqn := mis[0].MakePathFQN(&localBckE, fs.ObjectType, testObject)
+				lom := filePut(fqn, 0)
+				lom.SetRetainUntil(time.Now().Add(-time.Second))
+				Expect(lom.CheckRetained(false)).NotTo(HaveOccurred())
+			})
+
+			It("should track the latest retain-until time once scanned", func() {
+				bck := cluster.CloneBck(&localBckD)
+				Expect(bck.Init(cluster.T.Bowner())).NotTo(HaveOccurred())
+				_, known := cluster.RetainedUntil(bck)
+				Expect(known).To(BeFalse())
+
+				scan := cluster.BeginRetainedScan(bck)
+				fqn := mis[0].MakePathFQN(&localBckD, fs.ObjectType, testObject)
+				lom := filePut(fqn, 0)
+				until := time.Now().Add(time.Hour).Truncate(time.Second)
+				lom.SetRetainUntil(until)
+				Expect(persist(lom)).NotTo(HaveOccurred()) // (persisted during the scan)
+				scan.End(until.Add(-time.Minute))
+
+				latest, known := cluster.RetainedUntil(bck)
+				Expect(known).To(BeTrue())
+				Expect(latest.Equal(until)).To(BeTrue())
+
+				// superseded by another scan
+				scan = cluster.BeginRetainedScan(bck)
+				cluster.BeginRetainedScan(bck)
+				scan.End(until)
+				_, known = cluster.RetainedUntil(bck)
+				Expect(known).To(BeFalse())
+			})
+		})
 	})
 
 	Describe("copy object methods", func() {
@@ -992,6 +1072,101 @@ var _ = Describe("LOM", func() {
 		})
 	})
 
+	Describe("archive index", func() {
+		files := map[string][]byte{
+			"a.txt":         []byte("0123456789"),
+			"dir/b.bin":     make([]byte, 100*cos.KiB),
+			"dir/sub/c.cls": []byte("c"),
+		}
+		_, _ = rand.Read(files["dir/b.bin"])
+
+		DescribeTable("should index and read archived files",
+			func(mime string) {
+				objName := "shards/shard" + mime
+				fqn := mis[0].MakePathFQN(&localBckB, fs.ObjectType, objName)
+				createTestArch(fqn, mime, files)
+				lom := NewBasicLom(fqn)
+
+				idx, err := lom.ArchIdx(mime)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(idx.Entries).To(HaveLen(len(files)))
+				Expect(idx.Entries[0].Name).To(Equal("a.txt")) // sorted
+				Expect(lom.ArchIdxFQN()).To(BeARegularFile())
+
+				fh, err := os.Open(fqn)
+				Expect(err).NotTo(HaveOccurred())
+				defer fh.Close()
+				for name, data := range files {
+					e := idx.Find(name)
+					Expect(e).NotTo(BeNil())
+					Expect(idx.Find("/" + name)).To(Equal(e))
+					Expect(e.Size).To(BeEquivalentTo(len(data)))
+					cksum, err := cos.ChecksumBytes(data, cos.ChecksumXXHash)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(e.Cksum).To(Equal(cksum.Value()))
+
+					// whole file and a range
+					rc, err := idx.Open(fh, e, 0, e.Size)
+					Expect(err).NotTo(HaveOccurred())
+					b, err := io.ReadAll(rc)
+					rc.Close()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(b).To(Equal(data))
+
+					off, length := e.Size/3, e.Size/2
+					rc, err = idx.Open(fh, e, off, length)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rc.Size()).To(Equal(length))
+					b, err = io.ReadAll(rc)
+					rc.Close()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(b).To(Equal(data[off : off+length]))
+				}
+				Expect(idx.Find("nonexistent")).To(BeNil())
+
+				// loaded from disk
+				idx2, err := lom.ArchIdx(mime)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(idx2.Entries).To(Equal(idx.Entries))
+
+				// rebuilt when the object changes
+				time.Sleep(10 * time.Millisecond)
+				createTestArch(fqn, mime, map[string][]byte{"d.txt": []byte("d")})
+				idx3, err := lom.ArchIdx(mime)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(idx3.Entries).To(HaveLen(1))
+				Expect(idx3.Find("d.txt")).NotTo(BeNil())
+
+				Expect(lom.RemoveArchIdx()).NotTo(HaveOccurred())
+				Expect(lom.ArchIdxFQN()).NotTo(BeAnExistingFile())
+			},
+			Entry("tar", cos.ExtTar),
+			Entry("tgz", cos.ExtTgz),
+			Entry("zip", cos.ExtZip),
+			Entry("msgpack", cos.ExtMsgpack),
+		)
+
+		It("should fail to index msgpack with invalid lengths", func() {
+			objName := "shards/corrupted" + cos.ExtMsgpack
+			fqn := mis[0].MakePathFQN(&localBckB, fs.ObjectType, objName)
+			for _, b := range [][]byte{
+				{0x81, 0xdb, 0xff, 0xff, 0xff, 0xff, 'a'},                         // name
+				{0x81, 0xa1, 'a', 0xc6, 0xff, 0xff, 0xff, 0xf0, 0x01, 0x02, 0x03}, // content
+			} {
+				_ = os.Remove(fqn)
+				f, err := cos.CreateFile(fqn)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = f.Write(b)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(f.Close()).NotTo(HaveOccurred())
+				lom := NewBasicLom(fqn)
+				_, err = lom.ArchIdx(cos.ExtMsgpack)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid msgpack length"))
+			}
+		})
+	})
+
 	Describe("local and cloud bucket with the same name", func() {
 		It("should have different fqn", func() {
 			testObject := "foldr/test-obj.ext"
@@ -1070,6 +1245,61 @@ func createTestFile(fqn string, size int) {
 	}
 }
 
+func createTestArch(fqn, mime string, files map[string][]byte) {
+	var (
+		buf   bytes.Buffer
+		err   error
+		names = make([]string, 0, len(files))
+	)
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names))) // (unsorted archive)
+	switch mime {
+	case cos.ExtTar, cos.ExtTgz:
+		var (
+			w  io.Writer = &buf
+			gw *gzip.Writer
+		)
+		if mime == cos.ExtTgz {
+			gw = gzip.NewWriter(&buf)
+			w = gw
+		}
+		tw := tar.NewWriter(w)
+		for _, name := range names {
+			hdr := &tar.Header{Name: name, Size: int64(len(files[name])), Mode: 0o644, Typeflag: tar.TypeReg}
+			Expect(tw.WriteHeader(hdr)).NotTo(HaveOccurred())
+			_, err = tw.Write(files[name])
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).NotTo(HaveOccurred())
+		if gw != nil {
+			Expect(gw.Close()).NotTo(HaveOccurred())
+		}
+	case cos.ExtZip:
+		zw := zip.NewWriter(&buf)
+		for i, name := range names {
+			method := zip.Deflate
+			if i == 0 {
+				method = zip.Store
+			}
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write(files[name])
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zw.Close()).NotTo(HaveOccurred())
+	case cos.ExtMsgpack:
+		Expect(msgpack.NewEncoder(&buf).Encode(files)).NotTo(HaveOccurred())
+	}
+	_ = os.Remove(fqn)
+	f, err := cos.CreateFile(fqn)
+	Expect(err).NotTo(HaveOccurred())
+	_, err = f.Write(buf.Bytes())
+	Expect(err).NotTo(HaveOccurred())
+	Expect(f.Close()).NotTo(HaveOccurred())
+}
+
 func getTestFileHash(fqn string) (hash string) {
 	reader, _ := os.Open(fqn)
 	_, cksum, err := cos.CopyAndChecksum(io.Discard, reader, nil, cos.ChecksumXXHash)
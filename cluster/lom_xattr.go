@@ -161,6 +161,7 @@ func (lom *LOM) Persist() (err error) {
 	atime := lom.AtimeUnix()
 	// caller is expected to set atime
 	debug.Assert(isValidAtime(atime))
+	lom.trackRetained()
 
 	if atime < 0 /*prefetch*/ || !lom.WritePolicy().IsImmediate() /*write-never or delayed*/ {
 		lom.md.makeDirty()
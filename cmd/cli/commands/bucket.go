@@ -191,6 +191,9 @@ func _doListObj(c *cli.Context, bck cmn.Bck, prefix string, listArch bool) error
 	if flagIsSet(c, listCachedFlag) {
 		msg.SetFlag(apc.LsPresent)
 	}
+	if flagIsSet(c, listDeletedFlag) {
+		msg.SetFlag(apc.LsDeleted)
+	}
 	if listArch {
 		msg.SetFlag(apc.LsArchDir)
 	}
@@ -221,6 +224,12 @@ func _doListObj(c *cli.Context, bck cmn.Bck, prefix string, listArch bool) error
 	if flagIsSet(c, startAfterFlag) {
 		msg.StartAfter = parseStrFlag(c, startAfterFlag)
 	}
+	if flagIsSet(c, objMDFilterFlag) {
+		if flagIsSet(c, nameOnlyFlag) {
+			return incorrectUsageMsg(c, errFmtExclusive, objMDFilterFlag.Name, nameOnlyFlag.Name)
+		}
+		msg.Filter = parseStrFlag(c, objMDFilterFlag)
+	}
 	pageSize := parseIntFlag(c, pageSizeFlag)
 	limit := parseIntFlag(c, objLimitFlag)
 	if pageSize < 0 {
@@ -66,6 +66,8 @@ var (
 			listCachedFlag,
 			listArchFlag,
 			nameOnlyFlag,
+			objMDFilterFlag,
+			listDeletedFlag,
 		},
 		subcmdSummary: {
 			listCachedFlag,
@@ -357,7 +359,7 @@ func multiObjBckCopy(c *cli.Context, fromBck, toBck cmn.Bck, listObjs, tmplObjs
 	msg.ContinueOnError = flagIsSet(c, continueOnErrorFlag)
 	var xactID string
 	if len(etlID) != 0 {
-		msg.ID = etlID[0]
+		setETLPipeline(&msg.TCBMsg, etlID[0])
 		operation = "ETL objects"
 		xactID, err = api.ETLMultiObj(defaultAPIParams, fromBck, msg)
 	} else {
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/downloader"
 	"github.com/urfave/cli"
 )
@@ -38,12 +39,14 @@ const (
 	commandEvict     = "evict"
 	commandPrefetch  = "prefetch"
 	commandGet       = "get"
+	commandGetBatch  = "get-batch"
 	commandList      = "ls"
 	commandPromote   = "promote"
 	commandPut       = "put"
 	commandSetCustom = "set-custom"
 	commandRemove    = "rm"
 	commandRename    = "mv"
+	commandUndelete  = "undelete"
 	commandSet       = "set"
 	commandMirror    = "mirror"
 	commandStart     = apc.ActXactStart
@@ -78,6 +81,7 @@ const (
 	subcmdStop       = "stop"
 	subcmdStart      = "start"
 	subcmdLRU        = apc.ActLRU
+	subcmdLifecycle  = apc.ActLifecycle
 	subcmdMembership = "add-remove-nodes"
 	subcmdShutdown   = "shutdown"
 	subcmdAttach     = "attach"
@@ -115,6 +119,7 @@ const (
 	subcmdShowRemoteAIS    = "remote-cluster"
 	subcmdShowCluster      = subcmdCluster
 	subcmdShowClusterStats = "stats"
+	subcmdShowPerformance  = "performance"
 
 	subcmdShowStorage  = commandStorage
 	subcmdShowMpath    = subcmdMountpath
@@ -220,6 +225,7 @@ const (
 
 	// Objects
 	getObjectArgument        = "BUCKET/OBJECT_NAME [OUT_FILE|-]"
+	getBatchArgument         = "BUCKET/OBJECT_NAME [BUCKET/OBJECT_NAME...] OUT_FILE|-"
 	putPromoteObjectArgument = "FILE|DIRECTORY BUCKET/[OBJECT_NAME]"
 	concatObjectArgument     = "FILE|DIRECTORY [FILE|DIRECTORY...] BUCKET/OBJECT_NAME"
 	objectArgument           = "BUCKET/OBJECT_NAME"
@@ -306,6 +312,10 @@ var (
 	forceFlag       = cli.BoolFlag{Name: "force,f", Usage: "force an action"}
 	rawFlag         = cli.BoolFlag{Name: "raw", Usage: "display exact values instead of human-readable ones"}
 
+	bckStatsFlag = cli.BoolFlag{
+		Name:  "buckets",
+		Usage: "show per-bucket statistics (requires 'bucket_stats.enabled' in the cluster config)",
+	}
 	allXactionsFlag = cli.BoolFlag{Name: "all", Usage: "show all xactions, including finished"}
 	allItemsFlag    = cli.BoolFlag{Name: "all", Usage: "list all items"} // TODO: differentiate bucket names vs objects
 	allJobsFlag     = cli.BoolFlag{Name: "all", Usage: "remove all finished jobs"}
@@ -316,6 +326,10 @@ var (
 		Name:  "start-after",
 		Usage: "list bucket's content alphabetically starting with the first name *after* the specified",
 	}
+	objMDFilterFlag = cli.StringFlag{
+		Name:  "filter",
+		Usage: "list only objects that match custom metadata (and size) predicate, e.g.: \"label=train AND size>1MB\"",
+	}
 	objLimitFlag = cli.IntFlag{Name: "limit", Usage: "limit object count", Value: 0} // TODO: specify default as unlimited
 	pageSizeFlag = cli.IntFlag{Name: "page-size", Usage: "maximum number of entries by list objects call", Value: 1000}
 	copiesFlag   = cli.IntFlag{Name: "copies", Usage: "number of object replicas", Value: 1, Required: true}
@@ -368,6 +382,44 @@ var (
 		Value: downloader.DownloadProgressInterval.String(),
 		Usage: "progress interval for continuous monitoring, valid time units: 'ns', 'us', 'ms', 's', 'm', and 'h' (e.g. '10s')",
 	}
+	dlMaxAttemptsFlag = cli.IntFlag{
+		Name:  "max-attempts",
+		Usage: "max number of attempts to download each object (default: 10, or 1 for remote bucket)",
+	}
+	dlBackoffFlag = cli.StringFlag{
+		Name:  "backoff",
+		Usage: "delay before retrying failed download, doubles with each next retry (e.g. '1s')",
+	}
+	dlRetryCodesFlag = cli.StringFlag{
+		Name:  "retry-codes",
+		Usage: "comma-separated HTTP status codes to retry on (default: all except 404, 403, and other terminal ones)",
+	}
+	dlManifestFlag = cli.BoolFlag{
+		Name:  "manifest",
+		Usage: "source is a manifest (CSV or JSONL object in AIS bucket) that lists URLs to download",
+	}
+	dlHeaderFlag = cli.StringSliceFlag{
+		Name: "header",
+		Usage: "custom HTTP request header 'Name: value' (repeatable); value may embed credential references," +
+			" e.g. 'X-Api-Key: {{env:API_KEY}}'",
+	}
+	dlAuthBearerFlag = cli.StringFlag{
+		Name:  "auth-bearer",
+		Usage: "bearer token or credential reference ('env:NAME' or 'file:PATH') to authenticate with the source",
+	}
+	dlAuthBasicFlag = cli.StringFlag{
+		Name:  "auth-basic",
+		Usage: "basic auth credentials 'USER:PASSWORD'; password may be a credential reference ('env:NAME' or 'file:PATH')",
+	}
+	dlRetryFailedFlag = cli.StringFlag{
+		Name:  "retry-failed",
+		Usage: "start a new job to retry failed downloads of the given (finished) download job",
+	}
+	dlScheduleFlag = cli.StringFlag{
+		Name: "schedule",
+		Usage: "re-run remote bucket download on a schedule, e.g. '30m', '@every 2h', '@hourly', '@daily', '@weekly'" +
+			" (with --sync, also remove objects deleted from the remote bucket)",
+	}
 	// dSort
 	fileSizeFlag = cli.StringFlag{Name: "fsize", Value: "1024", Usage: "size of file in a shard"}
 	logFlag      = cli.StringFlag{Name: "log", Usage: "path to file where the metrics will be saved"}
@@ -379,8 +431,12 @@ var (
 		Name: "conc", Value: 10,
 		Usage: "limits number of concurrent put requests and number of concurrent shards created",
 	}
-	fileCountFlag = cli.IntFlag{Name: "fcount", Value: 5, Usage: "number of files inside single shard"}
-	specFileFlag  = cli.StringFlag{Name: "file,f", Value: "", Usage: "path to file with dSort specification"}
+	fileCountFlag   = cli.IntFlag{Name: "fcount", Value: 5, Usage: "number of files inside single shard"}
+	specFileFlag    = cli.StringFlag{Name: "file,f", Value: "", Usage: "path to file with dSort specification"}
+	dsortResumeFlag = cli.StringFlag{
+		Name:  "resume",
+		Usage: "ID of the aborted resumable dSort job to resume (the specification must be the same)",
+	}
 
 	// multi-object
 	listFlag     = cli.StringFlag{Name: "list", Usage: "comma-separated list of object names, e.g.: 'o1,o2,o3'"}
@@ -394,6 +450,10 @@ var (
 		Name:  "cached",
 		Usage: "list only those objects from a remote bucket that are present (ie., cached) in the cluster",
 	}
+	listDeletedFlag = cli.BoolFlag{
+		Name:  "deleted",
+		Usage: "list only soft-deleted objects that can be undeleted (see bucket property 'soft_delete')",
+	}
 	enableFlag    = cli.BoolFlag{Name: "enable", Usage: "enable"}
 	disableFlag   = cli.BoolFlag{Name: "disable", Usage: "disable"}
 	recursiveFlag = cli.BoolFlag{Name: "recursive,r", Usage: "recursive operation"}
@@ -437,6 +497,21 @@ var (
 	}
 	// end archive
 
+	// get-batch
+	batchSpecFlag = cli.StringFlag{
+		Name:  "spec,s",
+		Usage: "path to JSON file with the list of entries to read (use '-' for STDIN)",
+	}
+	batchMimeFlag = cli.StringFlag{
+		Name:  "mime",
+		Value: cos.ExtTar,
+		Usage: "output format: " + cos.ExtTar + " or " + cos.ExtMsgpack,
+	}
+	batchContinueOnErrorFlag = cli.BoolFlag{
+		Name:  "cont-on-err",
+		Usage: "skip missing objects and archived files (default: fail the entire batch)",
+	}
+
 	sourceBckFlag = cli.StringFlag{Name: "source-bck", Usage: "source bucket"}
 
 	// AuthN
@@ -475,6 +550,19 @@ var (
 		Name:  "wait-timeout",
 		Usage: "determines how long ais target should wait for pod to become ready",
 	}
+	// "wasm" runtime limits
+	etlMemLimitFlag = cli.StringFlag{
+		Name:  "mem-limit",
+		Usage: "(wasm runtime) memory limit of a single module instance, e.g. 64MiB",
+	}
+	etlExecTimeoutFlag = cli.DurationFlag{
+		Name:  "exec-timeout",
+		Usage: "(wasm runtime) time limit to transform a single object",
+	}
+	etlMaxInstancesFlag = cli.IntFlag{
+		Name:  "max-instances",
+		Usage: "(wasm runtime) max number of module instances running concurrently on each target (default: number of CPUs)",
+	}
 	waitFlag = cli.BoolFlag{
 		Name:  "wait",
 		Usage: "wait until the operation is finished",
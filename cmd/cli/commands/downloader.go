@@ -409,4 +409,27 @@ func printDownloadStatus(w io.Writer, d downloader.DlStatusResp, verbose bool) {
 	} else if d.ErrorCnt > 0 {
 		fmt.Fprintf(w, "Errors (%d) occurred during the download. To see detailed info run `ais show job download %s -v`\n", d.ErrorCnt, d.ID)
 	}
+	if verbose && len(d.Headers) > 0 {
+		fmt.Fprintln(w, "Request headers:")
+		names := make([]string, 0, len(d.Headers))
+		for name := range d.Headers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "\t%s: %s\n", name, d.Headers[name])
+		}
+	}
+	if len(d.SyncRuns) > 0 {
+		fmt.Fprintln(w, "Completed sync runs:")
+		for _, run := range d.SyncRuns {
+			fmt.Fprintf(w, "\t#%d %s: %d file%s downloaded (skipped: %d), %d error%s",
+				run.Run, run.StartedTime.Format(time.RFC3339), run.FinishedCnt-run.SkippedCnt,
+				cos.NounEnding(run.FinishedCnt-run.SkippedCnt), run.SkippedCnt, run.ErrorCnt, cos.NounEnding(run.ErrorCnt))
+			if run.Aborted {
+				fmt.Fprint(w, " (aborted)")
+			}
+			fmt.Fprintln(w)
+		}
+	}
 }
@@ -33,6 +33,9 @@ var (
 			commTypeFlag,
 			waitTimeoutFlag,
 			etlUUID,
+			etlMemLimitFlag,
+			etlExecTimeoutFlag,
+			etlMaxInstancesFlag,
 		},
 		subcmdSpec: {
 			fromFileFlag,
@@ -104,14 +107,14 @@ var (
 	objCmdETL = cli.Command{
 		Name:         subcmdObject,
 		Usage:        "transform an object",
-		ArgsUsage:    "ETL_ID BUCKET/OBJECT_NAME OUTPUT",
+		ArgsUsage:    "ETL_ID[,ETL_ID...] BUCKET/OBJECT_NAME OUTPUT",
 		Action:       etlObjectHandler,
 		BashComplete: etlIDCompletions,
 	}
 	bckCmdETL = cli.Command{
 		Name:         subcmdBucket,
 		Usage:        "transform bucket and put results into another bucket",
-		ArgsUsage:    "ETL_ID SRC_BUCKET DST_BUCKET",
+		ArgsUsage:    "ETL_ID[,ETL_ID...] SRC_BUCKET DST_BUCKET",
 		Action:       etlBucketHandler,
 		Flags:        etlSubcmdsFlags[subcmdBucket],
 		BashComplete: manyBucketsCompletions([]cli.BashCompleteFunc{etlIDCompletions}, 1, 2),
@@ -243,6 +246,14 @@ func etlInitCodeHandler(c *cli.Context) (err error) {
 	}
 	msg.WaitTimeout = cos.Duration(parseDurationFlag(c, waitTimeoutFlag))
 
+	if flagIsSet(c, etlMemLimitFlag) {
+		if msg.MemLimit, err = parseByteFlagToInt(c, etlMemLimitFlag); err != nil {
+			return
+		}
+	}
+	msg.ExecTimeout = cos.Duration(parseDurationFlag(c, etlExecTimeoutFlag))
+	msg.MaxInstances = parseIntFlag(c, etlMaxInstancesFlag)
+
 	if err := msg.Validate(); err != nil {
 		return err
 	}
@@ -423,12 +434,12 @@ func etlBucketHandler(c *cli.Context) (err error) {
 	}
 
 	msg := &apc.TCBMsg{
-		ID: id,
 		CopyBckMsg: apc.CopyBckMsg{
 			Prefix: parseStrFlag(c, cpBckPrefixFlag),
 			DryRun: flagIsSet(c, cpBckDryRunFlag),
 		},
 	}
+	setETLPipeline(msg, id)
 
 	if flagIsSet(c, etlExtFlag) {
 		mapStr := parseStrFlag(c, etlExtFlag)
@@ -476,6 +487,16 @@ func etlBucketHandler(c *cli.Context) (err error) {
 	return nil
 }
 
+// comma-separated list of ETL IDs specifies the pipeline: each ETL transforms
+// the output of the previous one
+func setETLPipeline(msg *apc.TCBMsg, etlID string) {
+	if ids := makeList(etlID); len(ids) > 1 {
+		msg.IDs = ids
+	} else {
+		msg.ID = etlID
+	}
+}
+
 func handleETLHTTPError(err error, etlID string) error {
 	if httpErr, ok := err.(*cmn.ErrHTTP); ok {
 		// TODO: How to find out if it's transformation not found, and not object not found?
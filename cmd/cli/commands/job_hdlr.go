@@ -50,6 +50,7 @@ func xactionCmds() cli.Commands {
 		apc.ActMakeNCopies,
 		apc.ActLoadLomCache,
 		apc.ActLRU,
+		apc.ActLifecycle,
 		apc.ActStoreCleanup,
 		apc.ActResilver,
 	)
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -45,9 +46,19 @@ var (
 			waitFlag,
 			limitBytesPerHourFlag,
 			syncFlag,
+			dlScheduleFlag,
+			dlMaxAttemptsFlag,
+			dlBackoffFlag,
+			dlRetryCodesFlag,
+			dlHeaderFlag,
+			dlManifestFlag,
+			dlAuthBearerFlag,
+			dlAuthBasicFlag,
+			dlRetryFailedFlag,
 		},
 		subcmdStartDsort: {
 			specFileFlag,
+			dsortResumeFlag,
 		},
 		commandPrefetch: append(
 			baseLstRngFlags,
@@ -57,6 +68,10 @@ var (
 			listBucketsFlag,
 			forceFlag,
 		},
+		subcmdLifecycle: {
+			listBucketsFlag,
+			dryRunFlag,
+		},
 	}
 
 	jobStartSubcmds = cli.Command{
@@ -91,6 +106,12 @@ var (
 				Flags:  startCmdsFlags[subcmdLRU],
 				Action: startLRUHandler,
 			},
+			{
+				Name:   subcmdLifecycle,
+				Usage:  "execute bucket lifecycle rules (delete, evict, reduce copies of aged objects)",
+				Flags:  startCmdsFlags[subcmdLifecycle],
+				Action: startLifecycleHandler,
+			},
 			{
 				Name:         subcmdStgCleanup,
 				Usage:        "perform storage cleanup: remove deleted objects and old/obsolete workfiles",
@@ -187,6 +208,15 @@ func startDownloadHandler(c *cli.Context) error {
 		id               string
 	)
 
+	if flagIsSet(c, dlRetryFailedFlag) {
+		id, err := api.RetryDownload(defaultAPIParams, parseStrFlag(c, dlRetryFailedFlag))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.App.Writer, id)
+		return bgDownload(c, id)
+	}
+
 	if c.NArg() == 0 {
 		return missingArgumentsError(c, "source", "destination")
 	}
@@ -204,10 +234,15 @@ func startDownloadHandler(c *cli.Context) error {
 		}
 	}
 
+	var (
+		source dlSource
+		err    error
+	)
 	src, dst := c.Args().Get(0), c.Args().Get(1)
-	source, err := parseSource(src)
-	if err != nil {
-		return err
+	if !flagIsSet(c, dlManifestFlag) {
+		if source, err = parseSource(src); err != nil {
+			return err
+		}
 	}
 	bck, pathSuffix, err := parseDest(c, dst)
 	if err != nil {
@@ -223,6 +258,15 @@ func startDownloadHandler(c *cli.Context) error {
 		return err
 	}
 
+	retry, err := parseDlRetryFlags(c)
+	if err != nil {
+		return err
+	}
+	headers, auth, err := parseDlAuthFlags(c)
+	if err != nil {
+		return err
+	}
+
 	basePayload := downloader.DlBase{
 		Bck:              bck,
 		Timeout:          timeout,
@@ -232,6 +276,9 @@ func startDownloadHandler(c *cli.Context) error {
 			Connections:  parseIntFlag(c, limitConnectionsFlag),
 			BytesPerHour: int(limitBPH),
 		},
+		Retry:   retry,
+		Headers: headers,
+		Auth:    auth,
 	}
 
 	if basePayload.Bck.Props, err = api.HeadBucket(defaultAPIParams, basePayload.Bck); err != nil {
@@ -243,7 +290,9 @@ func startDownloadHandler(c *cli.Context) error {
 
 	// Heuristics to determine the download type.
 	var dlType downloader.DlType
-	if objectsListPath != "" {
+	if flagIsSet(c, dlManifestFlag) {
+		dlType = downloader.DlTypeManifest
+	} else if objectsListPath != "" {
 		dlType = downloader.DlTypeMulti
 	} else if strings.Contains(source.link, "{") && strings.Contains(source.link, "}") {
 		dlType = downloader.DlTypeRange
@@ -287,6 +336,13 @@ func startDownloadHandler(c *cli.Context) error {
 		}
 	}
 
+	if flagIsSet(c, dlScheduleFlag) {
+		if dlType != downloader.DlTypeBackend {
+			return fmt.Errorf("option %q is supported only when downloading remote bucket", dlScheduleFlag.Name)
+		}
+		dlType = downloader.DlTypeSync
+	}
+
 	switch dlType {
 	case downloader.DlTypeSingle:
 		payload := downloader.DlSingleBody{
@@ -323,6 +379,17 @@ func startDownloadHandler(c *cli.Context) error {
 			Template: source.link,
 		}
 		id, err = api.DownloadWithParam(defaultAPIParams, dlType, payload)
+	case downloader.DlTypeManifest:
+		manifestBck, manifestObj, errV := parseBckObjectURI(c, src)
+		if errV != nil {
+			return errV
+		}
+		payload := downloader.DlManifestBody{
+			DlBase:      basePayload,
+			ManifestBck: manifestBck,
+			ManifestObj: manifestObj,
+		}
+		id, err = api.DownloadWithParam(defaultAPIParams, dlType, payload)
 	case downloader.DlTypeBackend:
 		payload := downloader.DlBackendBody{
 			DlBase: basePayload,
@@ -330,6 +397,14 @@ func startDownloadHandler(c *cli.Context) error {
 			Prefix: source.backend.prefix,
 		}
 		id, err = api.DownloadWithParam(defaultAPIParams, dlType, payload)
+	case downloader.DlTypeSync:
+		payload := downloader.DlSyncBody{
+			DlBase:   basePayload,
+			Prefix:   source.backend.prefix,
+			Schedule: parseStrFlag(c, dlScheduleFlag),
+			Delete:   flagIsSet(c, syncFlag),
+		}
+		id, err = api.DownloadWithParam(defaultAPIParams, dlType, payload)
 	default:
 		cos.Assert(false)
 	}
@@ -340,6 +415,11 @@ func startDownloadHandler(c *cli.Context) error {
 
 	fmt.Fprintln(c.App.Writer, id)
 
+	// scheduled sync job keeps running until aborted
+	if dlType == downloader.DlTypeSync {
+		return bgDownload(c, id)
+	}
+
 	if flagIsSet(c, progressBarFlag) {
 		return pbDownload(c, id)
 	}
@@ -351,6 +431,47 @@ func startDownloadHandler(c *cli.Context) error {
 	return bgDownload(c, id)
 }
 
+func parseDlRetryFlags(c *cli.Context) (retry downloader.DlRetry, err error) {
+	retry.MaxAttempts = parseIntFlag(c, dlMaxAttemptsFlag)
+	retry.Backoff = parseStrFlag(c, dlBackoffFlag)
+	if codes := parseStrFlag(c, dlRetryCodesFlag); codes != "" {
+		for _, s := range makeList(codes) {
+			code, err := strconv.Atoi(s)
+			if err != nil {
+				return retry, fmt.Errorf("invalid HTTP status code %q in --%s", s, dlRetryCodesFlag.Name)
+			}
+			retry.Codes = append(retry.Codes, code)
+		}
+	}
+	return retry, retry.Validate()
+}
+
+func parseDlAuthFlags(c *cli.Context) (headers map[string]string, auth downloader.DlAuth, err error) {
+	if hdrs := c.StringSlice(dlHeaderFlag.Name); len(hdrs) > 0 {
+		headers = make(map[string]string, len(hdrs))
+		for _, h := range hdrs {
+			parts := strings.SplitN(h, ":", 2)
+			if len(parts) != 2 {
+				return nil, auth, fmt.Errorf("invalid header %q in --%s (expecting 'Name: value')", h, dlHeaderFlag.Name)
+			}
+			headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	switch {
+	case flagIsSet(c, dlAuthBearerFlag) && flagIsSet(c, dlAuthBasicFlag):
+		return nil, auth, incorrectUsageMsg(c, errFmtExclusive, dlAuthBearerFlag.Name, dlAuthBasicFlag.Name)
+	case flagIsSet(c, dlAuthBearerFlag):
+		auth = downloader.DlAuth{Type: downloader.DlAuthBearer, Token: parseStrFlag(c, dlAuthBearerFlag)}
+	case flagIsSet(c, dlAuthBasicFlag):
+		parts := strings.SplitN(parseStrFlag(c, dlAuthBasicFlag), ":", 2)
+		if len(parts) != 2 {
+			return nil, auth, fmt.Errorf("invalid --%s (expecting 'USER:PASSWORD')", dlAuthBasicFlag.Name)
+		}
+		auth = downloader.DlAuth{Type: downloader.DlAuthBasic, Username: parts[0], Password: parts[1]}
+	}
+	return
+}
+
 func pbDownload(c *cli.Context, id string) (err error) {
 	refreshRate := calcRefreshRate(c)
 	downloadingResult, err := newDownloaderPB(defaultAPIParams, id, refreshRate).run()
@@ -489,6 +610,10 @@ func startDsortHandler(c *cli.Context) (err error) {
 		}
 	}
 
+	if flagIsSet(c, dsortResumeFlag) {
+		rs.Resume = parseStrFlag(c, dsortResumeFlag)
+	}
+
 	if id, err = api.StartDSort(defaultAPIParams, rs); err != nil {
 		return
 	}
@@ -531,6 +656,32 @@ func startLRUHandler(c *cli.Context) (err error) {
 	return
 }
 
+func startLifecycleHandler(c *cli.Context) (err error) {
+	printDryRunHeader(c)
+
+	var buckets []cmn.Bck
+	if flagIsSet(c, listBucketsFlag) {
+		bckArgs := makeList(parseStrFlag(c, listBucketsFlag))
+		buckets = make([]cmn.Bck, len(bckArgs))
+		for idx, bckArg := range bckArgs {
+			bck, err := parseBckURI(c, bckArg)
+			if err != nil {
+				return err
+			}
+			buckets[idx] = bck
+		}
+	}
+	var (
+		id       string
+		xactArgs = api.XactReqArgs{Kind: apc.ActLifecycle, Buckets: buckets, DryRun: flagIsSet(c, dryRunFlag)}
+	)
+	if id, err = api.StartXaction(defaultAPIParams, xactArgs); err != nil {
+		return
+	}
+	fmt.Fprintf(c.App.Writer, "Started %s %q, %s\n", apc.ActLifecycle, id, xactProgressMsg(id))
+	return
+}
+
 func startPrefetchHandler(c *cli.Context) (err error) {
 	printDryRunHeader(c)
 
@@ -152,6 +152,72 @@ func getObject(c *cli.Context, outFile string, silent bool) (err error) {
 	return
 }
 
+// Get multiple objects and/or archived files as a single archive
+// (the list is given either as command-line arguments or as a JSON spec).
+func getBatch(c *cli.Context, uris []string, outFile string) (err error) {
+	var (
+		msg      = &cmn.GetBatchMsg{}
+		specPath = parseStrFlag(c, batchSpecFlag)
+		archPath = parseStrFlag(c, archpathFlag)
+	)
+	if specPath != "" && len(uris) > 0 {
+		return incorrectUsageMsg(c, "%q flag and object names in the command line are mutually exclusive",
+			batchSpecFlag.Name)
+	}
+	if specPath != "" {
+		var specBytes []byte
+		if specPath == fileStdIO {
+			specBytes, err = io.ReadAll(os.Stdin)
+		} else {
+			specBytes, err = os.ReadFile(specPath)
+		}
+		if err != nil {
+			return
+		}
+		if err = jsoniter.Unmarshal(specBytes, &msg.Entries); err != nil {
+			return fmt.Errorf("invalid get-batch specification %q: %v", specPath, err)
+		}
+	} else {
+		if len(uris) == 0 {
+			return missingArgumentsError(c, "object names in the form bucket/object")
+		}
+		msg.Entries = make([]cmn.GetBatchEntry, 0, len(uris))
+		for _, uri := range uris {
+			bck, objName, err := parseBckObjectURI(c, uri)
+			if err != nil {
+				return err
+			}
+			msg.Entries = append(msg.Entries, cmn.GetBatchEntry{Bck: bck, ObjName: objName, ArchPath: archPath})
+		}
+	}
+	msg.Mime = parseStrFlag(c, batchMimeFlag)
+	msg.ContinueOnError = flagIsSet(c, batchContinueOnErrorFlag)
+	if err = msg.Validate(); err != nil {
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if outFile != fileStdIO {
+		var file *os.File
+		if file, err = os.Create(outFile); err != nil {
+			return
+		}
+		defer func() {
+			file.Close()
+			if err != nil {
+				os.Remove(outFile)
+			}
+		}()
+		w = file
+	}
+	n, err := api.GetBatch(defaultAPIParams, msg, w)
+	if err != nil || outFile == fileStdIO {
+		return
+	}
+	fmt.Fprintf(c.App.Writer, "GET %d entries as %q [%s]\n", len(msg.Entries), outFile, cos.B2S(n, 2))
+	return
+}
+
 // Promote AIS-colocated files and directories to objects.
 
 func promote(c *cli.Context, bck cmn.Bck, objName, fqn string) error {
@@ -18,8 +18,9 @@ import (
 
 var (
 	objectCmdsFlags = map[string][]cli.Flag{
-		commandRemove: baseLstRngFlags,
-		commandRename: {},
+		commandRemove:   baseLstRngFlags,
+		commandRename:   {},
+		commandUndelete: {},
 		commandGet: {
 			offsetFlag,
 			lengthFlag,
@@ -63,6 +64,12 @@ var (
 			recursiveFlag,
 			progressBarFlag,
 		},
+		commandGetBatch: {
+			batchSpecFlag,
+			archpathFlag,
+			batchMimeFlag,
+			batchContinueOnErrorFlag,
+		},
 		commandCat: {
 			offsetFlag,
 			lengthFlag,
@@ -105,6 +112,14 @@ var (
 		Subcommands: []cli.Command{
 			objectCmdGet,
 			objectCmdPut,
+			{
+				Name:         commandGetBatch,
+				Usage:        "get multiple objects and/or archived files as a single archive (in the specified order)",
+				ArgsUsage:    getBatchArgument,
+				Flags:        objectCmdsFlags[commandGetBatch],
+				Action:       getBatchHandler,
+				BashComplete: bucketCompletions(bckCompletionsOpts{multiple: true, separator: true}),
+			},
 			objectCmdSetCustom,
 			makeAlias(showCmdObject, "", true, commandShow), // alias for `ais show`
 			{
@@ -126,6 +141,16 @@ var (
 					multiple: true, separator: true,
 				}),
 			},
+			{
+				Name:      commandUndelete,
+				Usage:     "restore soft-deleted object(s) - see bucket property 'soft_delete'",
+				ArgsUsage: objectArgument + "...",
+				Flags:     objectCmdsFlags[commandUndelete],
+				Action:    undeleteObjectHandler,
+				BashComplete: bucketCompletions(bckCompletionsOpts{
+					multiple: true, separator: true,
+				}),
+			},
 			{
 				Name:         commandPromote,
 				Usage:        "promote files and directories to ais (ie., replicate files and convert them to objects)",
@@ -235,11 +260,39 @@ func removeObjectHandler(c *cli.Context) (err error) {
 	return multiObjOp(c, commandRemove)
 }
 
+func undeleteObjectHandler(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, objectArgument)
+	}
+	for _, uri := range c.Args() {
+		bck, objName, err := parseBckObjectURI(c, uri)
+		if err != nil {
+			return err
+		}
+		if objName == "" {
+			return incorrectUsageMsg(c, "no object specified in %q", uri)
+		}
+		if err := api.UndeleteObject(defaultAPIParams, bck, objName); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.Writer, "%q undeleted\n", bck.String()+"/"+objName)
+	}
+	return nil
+}
+
 func getHandler(c *cli.Context) (err error) {
 	outFile := c.Args().Get(1) // empty string if arg not given
 	return getObject(c, outFile, false /*silent*/)
 }
 
+func getBatchHandler(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "output file")
+	}
+	outFile := c.Args().Get(c.NArg() - 1)
+	return getBatch(c, c.Args()[:c.NArg()-1], outFile)
+}
+
 func createArchMultiObjHandler(c *cli.Context) (err error) {
 	var (
 		bckTo, bckFrom cmn.Bck
@@ -20,6 +20,7 @@ import (
 	"github.com/NVIDIA/aistore/cmd/cli/templates"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/xact"
 	"github.com/urfave/cli"
 )
@@ -107,10 +108,15 @@ var (
 		subcmdShowLog: {
 			logSevFlag,
 		},
+		subcmdShowPerformance: {
+			jsonFlag,
+			refreshFlag,
+		},
 		subcmdShowClusterStats: {
 			jsonFlag,
 			rawFlag,
 			refreshFlag,
+			bckStatsFlag,
 		},
 	}
 
@@ -129,6 +135,7 @@ var (
 			showCmdStorage,
 			showCmdJob,
 			showCmdLog,
+			showCmdPerformance,
 		},
 	}
 
@@ -245,6 +252,15 @@ var (
 		BashComplete: daemonCompletions(completeAllDaemons),
 	}
 
+	showCmdPerformance = cli.Command{
+		Name:         subcmdShowPerformance,
+		Usage:        "show datapath latency percentiles (p50, p90, p99) across all targets or for a given target",
+		ArgsUsage:    optionalDaemonIDArgument,
+		Flags:        showCmdsFlags[subcmdShowPerformance],
+		Action:       showPerformanceHandler,
+		BashComplete: daemonCompletions(completeTargets),
+	}
+
 	showCmdJob = cli.Command{
 		Name:  subcmdShowJob,
 		Usage: "show running and completed jobs (xactions)",
@@ -775,6 +791,114 @@ func showClusterTotalStats(c *cli.Context) (err error) {
 	return templates.DisplayOutput(props, c.App.Writer, templates.ConfigTmpl, false)
 }
 
+// per-bucket stats of a given target or, if unspecified, summed up across all targets
+func showBucketStats(c *cli.Context, node *cluster.Snode) error {
+	type bckStatsRow struct {
+		Name string
+		stats.BckStats
+	}
+	var perTarget []stats.BckStatsMap
+	if node != nil {
+		if !node.IsTarget() {
+			return fmt.Errorf("per-bucket statistics are only available for targets (%s is a proxy)", node)
+		}
+		ds, err := api.GetDaemonStats(defaultAPIParams, node)
+		if err != nil {
+			return err
+		}
+		perTarget = append(perTarget, ds.Buckets)
+	} else {
+		st, err := api.GetClusterStats(defaultAPIParams)
+		if err != nil {
+			return err
+		}
+		for _, ds := range st.Target {
+			perTarget = append(perTarget, ds.Buckets)
+		}
+	}
+	total := make(stats.BckStatsMap, 8)
+	for _, bcks := range perTarget {
+		for name, bs := range bcks {
+			if t, ok := total[name]; ok {
+				t.Merge(bs)
+			} else {
+				total[name] = bs
+			}
+		}
+	}
+	if flagIsSet(c, jsonFlag) {
+		return templates.DisplayOutput(total, c.App.Writer, "", true)
+	}
+	if len(total) == 0 {
+		fmt.Fprintln(c.App.Writer, "No per-bucket statistics (hint: check 'bucket_stats' in the cluster config)")
+		return nil
+	}
+	rows := make([]*bckStatsRow, 0, len(total))
+	for name, bs := range total {
+		rows = append(rows, &bckStatsRow{Name: name, BckStats: *bs})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	return templates.DisplayOutput(rows, c.App.Writer, templates.BucketStatsTmpl, false)
+}
+
+func showPerformanceHandler(c *cli.Context) (err error) {
+	var node *cluster.Snode
+	if daemonID := argDaemonID(c); daemonID != "" {
+		smap, err := api.GetClusterMap(defaultAPIParams)
+		if err != nil {
+			return err
+		}
+		if node = smap.GetTarget(daemonID); node == nil {
+			return fmt.Errorf("target %q does not exist", daemonID)
+		}
+	}
+	refresh := flagIsSet(c, refreshFlag)
+	sleep := calcRefreshRate(c)
+	for {
+		if err = showPerformance(c, node); err != nil || !refresh {
+			return err
+		}
+		time.Sleep(sleep)
+	}
+}
+
+// latency histograms (since nodes' startup) of a given target or, if unspecified, merged across all targets
+func showPerformance(c *cli.Context, node *cluster.Snode) error {
+	type latencyRow struct {
+		Name          string
+		Count         int64
+		Avg           int64
+		P50, P90, P99 int64
+	}
+	var lhs stats.LatencyHists
+	if node != nil {
+		ds, err := api.GetDaemonStats(defaultAPIParams, node)
+		if err != nil {
+			return err
+		}
+		lhs = ds.Latencies
+	} else {
+		st, err := api.GetClusterStats(defaultAPIParams)
+		if err != nil {
+			return err
+		}
+		lhs = st.Latencies()
+	}
+	if flagIsSet(c, jsonFlag) {
+		return templates.DisplayOutput(lhs, c.App.Writer, "", true)
+	}
+	rows := make([]*latencyRow, 0, len(lhs))
+	for name, lh := range lhs {
+		row := &latencyRow{Name: strings.TrimSuffix(name, ".ns"), Count: lh.Count, P50: lh.P50, P90: lh.P90, P99: lh.P99}
+		if lh.Count > 0 {
+			row.Avg = lh.Sum / lh.Count
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	return templates.DisplayOutput(rows, c.App.Writer, templates.PerformanceTmpl, false)
+}
+
 func showClusterStatsHandler(c *cli.Context) (err error) {
 	smap, err := api.GetClusterMap(defaultAPIParams)
 	if err != nil {
@@ -791,7 +915,9 @@ func showClusterStatsHandler(c *cli.Context) (err error) {
 	sleep := calcRefreshRate(c)
 
 	for {
-		if node != nil {
+		if flagIsSet(c, bckStatsFlag) {
+			err = showBucketStats(c, node)
+		} else if node != nil {
 			err = showDaemonStats(c, node)
 		} else {
 			err = showClusterTotalStats(c)
@@ -135,6 +135,9 @@ func objectPropList(bck cmn.Bck, props *cmn.ObjectProps, selection []string) (pr
 			propValue = templates.FmtEC(
 				props.EC.Generation, props.EC.DataSlices, props.EC.ParitySlices, props.EC.IsECCopy,
 			)
+			if props.EC.Policy != "" {
+				propValue += " policy " + props.EC.Policy
+			}
 		case apc.GetPropsCustom:
 			if custom := props.GetCustomMD(); len(custom) == 0 {
 				propValue = templates.NotSetVal
@@ -834,6 +837,10 @@ func bckPropList(props *cmn.BucketProps, verbose bool) (propList []prop) {
 			{"ec", props.EC.String()},
 			{"lru", props.LRU.String()},
 			{"versioning", props.Versioning.String()},
+			{"soft_delete", props.SoftDelete.String()},
+			{"obj_lock", props.ObjLock.String()},
+			{"lifecycle", props.Lifecycle.String()},
+			{"replication", props.Replication.String()},
 		}
 		if props.Provider == apc.ProviderHTTP {
 			origURL := props.Extra.HTTP.OrigURLBck
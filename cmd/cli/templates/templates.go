@@ -176,6 +176,23 @@ const (
 		"{{$v.Bck}}\t {{$v.ObjCount}}\t {{FormatBytesUnsigned $v.Size 2}}\t {{FormatFloat $v.UsedPct}}%\n" +
 		"{{end}}"
 
+	// Per-bucket stats (`show cluster stats --buckets`)
+	BucketStatsTmpl = "BUCKET\t GET\t GET SIZE\t GET LATENCY\t PUT\t PUT SIZE\t PUT LATENCY\t DELETE\t ERRORS\n" +
+		"{{range $v := . }}" +
+		"{{$v.Name}}\t {{$v.GetCount}}\t {{FormatBytesSigned $v.GetSize 2}}\t " +
+		"{{if (eq $v.GetLatency 0)}}-{{else}}{{FormatDur $v.GetLatency}}{{end}}\t " +
+		"{{$v.PutCount}}\t {{FormatBytesSigned $v.PutSize 2}}\t " +
+		"{{if (eq $v.PutLatency 0)}}-{{else}}{{FormatDur $v.PutLatency}}{{end}}\t " +
+		"{{$v.DeleteCount}}\t {{$v.ErrCount}}\n" +
+		"{{end}}"
+
+	// Command `show performance`
+	PerformanceTmpl = "LATENCY\t COUNT\t AVERAGE\t P50\t P90\t P99\n" +
+		"{{range $v := . }}" +
+		"{{$v.Name}}\t {{$v.Count}}\t {{FormatDur $v.Avg}}\t " +
+		"{{FormatDur $v.P50}}\t {{FormatDur $v.P90}}\t {{FormatDur $v.P99}}\n" +
+		"{{end}}"
+
 	// Bucket summary validate templates
 	BucketSummaryValidateTmpl = "BUCKET\t OBJECTS\t MISPLACED\t MISSING COPIES\n" + bucketSummaryValidateBody
 	bucketSummaryValidateBody = "{{range $v := . }}" +
@@ -295,6 +312,7 @@ var (
 		"status":     "{{FormatObjStatus $obj}}",
 		"copies":     "{{$obj.Copies}}",
 		"cached":     "{{FormatObjIsCached $obj}}",
+		"custom":     "{{if $obj.Custom}}{{$obj.Custom}}{{else}}-{{end}}",
 	}
 
 	ObjStatMap = map[string]string{
@@ -6,9 +6,12 @@
 package cmn
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn/cos"
@@ -54,6 +57,18 @@ type (
 		// EC defines erasure coding setting for the bucket
 		EC ECConf `json:"ec"`
 
+		// SoftDelete: keep deleted objects (that can be listed and undeleted) for a while
+		SoftDelete SoftDelConf `json:"soft_delete"`
+
+		// ObjLock: write-once-read-many (WORM) retention of the bucket's objects
+		ObjLock ObjLockConf `json:"obj_lock"`
+
+		// Lifecycle: age-based expiration (and eviction, and mirror reduction) rules
+		Lifecycle LifecycleConf `json:"lifecycle"`
+
+		// Replication: asynchronous replication of the bucket's writes and deletes
+		Replication ReplicationConf `json:"replication"`
+
 		// Bucket access attributes - see Allow* above
 		Access apc.AccessAttrs `json:"access,string"`
 
@@ -110,6 +125,10 @@ type (
 		EC          *ECConfToUpdate          `json:"ec,omitempty"`
 		Access      *apc.AccessAttrs         `json:"access,string,omitempty"`
 		WritePolicy *WritePolicyConfToUpdate `json:"write_policy,omitempty"`
+		SoftDelete  *SoftDelConfToUpdate     `json:"soft_delete,omitempty"`
+		ObjLock     *ObjLockConfToUpdate     `json:"obj_lock,omitempty"`
+		Lifecycle   *LifecycleConfToUpdate   `json:"lifecycle,omitempty"`
+		Replication *ReplicationConfToUpdate `json:"replication,omitempty"`
 		Extra       *ExtraToUpdate           `json:"extra,omitempty"`
 		Force       bool                     `json:"force,omitempty" copy:"skip" list:"omit"`
 	}
@@ -118,6 +137,90 @@ type (
 		Name     *string `json:"name"`
 		Provider *string `json:"provider"`
 	}
+
+	// When enabled, deleted objects are not removed right away - instead, they are
+	// moved aside (on the same mountpath) and can be:
+	// - listed (see apc.LsDeleted) and restored (see api.UndeleteObject)
+	//   during the retention period;
+	// - removed by the storage cleanup (see space.RunCleanup) after that.
+	// Supported only for ais buckets without remote backend.
+	SoftDelConf struct {
+		Retention cos.Duration `json:"retention"` // how long to keep deleted objects (zero: DefaultSoftDelRetention)
+		Enabled   bool         `json:"enabled"`
+	}
+	SoftDelConfToUpdate struct {
+		Retention *cos.Duration `json:"retention,omitempty"`
+		Enabled   *bool         `json:"enabled,omitempty"`
+	}
+
+	// Object lock (WORM): objects cannot be overwritten, appended, renamed, evicted,
+	// or deleted until their respective retain-until times (see RetainUntilObjMD).
+	// - retain-until is set at PUT time: user-specified or now + default `Retention`
+	//   (if non-zero); it can be extended but not shortened;
+	// - in governance mode, retention can be bypassed (see apc.HdrBypassGovernance)
+	//   and the bucket can be switched to compliance - but not vice versa;
+	// - once enabled, object lock cannot be disabled.
+	// Supported only for ais buckets without remote backend.
+	ObjLockConf struct {
+		Mode      string       `json:"mode"`      // ObjLockGovernance | ObjLockCompliance
+		Retention cos.Duration `json:"retention"` // default retention of new objects (zero: none)
+		Enabled   bool         `json:"enabled"`
+	}
+	ObjLockConfToUpdate struct {
+		Mode      *string       `json:"mode,omitempty"`
+		Retention *cos.Duration `json:"retention,omitempty"`
+		Enabled   *bool         `json:"enabled,omitempty"`
+	}
+
+	// Lifecycle rules are executed by the (periodic and user-startable) lifecycle
+	// xaction (see space.RunLifecycle) against objects older than the rules' respective ages,
+	// where age is the time since the object was last written (mtime).
+	// When multiple rules apply to a given object the most destructive action wins:
+	// delete, then evict, then reduce-copies.
+	LifecycleConf struct {
+		Rules   []LifecycleRule `json:"rules"`
+		Enabled bool            `json:"enabled"`
+	}
+	LifecycleConfToUpdate struct {
+		Rules   *[]LifecycleRule `json:"rules,omitempty"`
+		Enabled *bool            `json:"enabled,omitempty"`
+	}
+	LifecycleRule struct {
+		Prefix string       `json:"prefix,omitempty"` // object name prefix (empty: all objects)
+		Action string       `json:"action"`           // LcycleDelete | LcycleEvict | LcycleReduceCopies
+		Age    cos.Duration `json:"age"`              // minimum time since last modification
+		Copies int          `json:"copies,omitempty"` // number of copies to keep (LcycleReduceCopies)
+	}
+
+	// Asynchronous (continuous) replication to another bucket: a remote ais bucket
+	// (that is, a bucket of an attached ais cluster) or a cloud bucket.
+	// Successful PUTs (including appends, promotions, and renames) and deletes are
+	// recorded in the durable per-target queue and forwarded, in order, to the
+	// destination by the replication xaction (see mirror.XactRepl).
+	// Disabling replication discards the queue.
+	ReplicationConf struct {
+		Dst     string `json:"dst"` // destination bucket, e.g. "ais://@uuid#ns/bucket" or "s3://bucket"
+		Enabled bool   `json:"enabled"`
+	}
+	ReplicationConfToUpdate struct {
+		Dst     *string `json:"dst,omitempty"`
+		Enabled *bool   `json:"enabled,omitempty"`
+	}
+)
+
+const DefaultSoftDelRetention = 24 * time.Hour
+
+// object lock modes
+const (
+	ObjLockGovernance = "governance"
+	ObjLockCompliance = "compliance"
+)
+
+// lifecycle actions (in the decreasing order of precedence)
+const (
+	LcycleDelete       = "delete"        // delete objects (including remote objects, if any)
+	LcycleEvict        = "evict"         // evict cached copies of remote objects
+	LcycleReduceCopies = "reduce-copies" // reduce the number of local (mirrored) copies
 )
 
 // By default, created buckets inherit their properties from the cluster (global) configuration.
@@ -218,6 +321,21 @@ func (bp *BucketProps) Validate(targetCnt int) error {
 	if bp.Mirror.Enabled && bp.EC.Enabled {
 		return fmt.Errorf("cannot enable mirroring and ec at the same time for the same bucket")
 	}
+	if bp.SoftDelete.Enabled && (bp.Provider != apc.ProviderAIS || !bp.BackendBck.IsEmpty()) {
+		return fmt.Errorf("soft delete is supported only for ais buckets without remote backend")
+	}
+	if bp.SoftDelete.Retention < 0 {
+		return fmt.Errorf("invalid soft delete retention %v (expecting non-negative duration)", bp.SoftDelete.Retention)
+	}
+	if err := bp.ObjLock.validate(bp); err != nil {
+		return err
+	}
+	if err := bp.Lifecycle.validate(bp); err != nil {
+		return err
+	}
+	if err := bp.Replication.validate(); err != nil {
+		return err
+	}
 	return softErr
 }
 
@@ -249,6 +367,173 @@ func NewBucketPropsToUpdate(nvs cos.SimpleKVs) (props *BucketPropsToUpdate, err
 	return
 }
 
+/////////////////
+// SoftDelConf //
+/////////////////
+
+func (c *SoftDelConf) RetentionTime() time.Duration {
+	if c.Retention == 0 {
+		return DefaultSoftDelRetention
+	}
+	return c.Retention.D()
+}
+
+func (c *SoftDelConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return "Enabled, retention " + c.RetentionTime().String()
+}
+
+func (c *ObjLockConf) validate(bp *BucketProps) error {
+	if !c.Enabled {
+		return nil
+	}
+	if bp.Provider != apc.ProviderAIS || !bp.BackendBck.IsEmpty() {
+		return fmt.Errorf("object lock is supported only for ais buckets without remote backend")
+	}
+	if c.Mode != ObjLockGovernance && c.Mode != ObjLockCompliance {
+		return fmt.Errorf("invalid object lock mode %q (expecting %q or %q)", c.Mode, ObjLockGovernance, ObjLockCompliance)
+	}
+	if c.Retention < 0 {
+		return fmt.Errorf("invalid object lock retention %v (expecting non-negative duration)", c.Retention)
+	}
+	return nil
+}
+
+func (c *ObjLockConf) IsCompliance() bool { return c.Enabled && c.Mode == ObjLockCompliance }
+
+func (c *ObjLockConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	if c.Retention == 0 {
+		return "Enabled, " + c.Mode
+	}
+	return "Enabled, " + c.Mode + ", default retention " + c.Retention.String()
+}
+
+///////////////////
+// LifecycleConf //
+///////////////////
+
+func (c *LifecycleConf) validate(bp *BucketProps) error {
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Age <= 0 {
+			return fmt.Errorf("lifecycle rule #%d: invalid age %v (expecting positive duration)", i+1, rule.Age)
+		}
+		switch rule.Action {
+		case LcycleDelete:
+		case LcycleEvict:
+			if bp.Provider == apc.ProviderAIS && bp.BackendBck.IsEmpty() {
+				return fmt.Errorf("lifecycle rule #%d: cannot %s objects of ais bucket without remote backend",
+					i+1, rule.Action)
+			}
+		case LcycleReduceCopies:
+			if rule.Copies < 1 {
+				return fmt.Errorf("lifecycle rule #%d: invalid number of copies %d (expecting positive integer)",
+					i+1, rule.Copies)
+			}
+			continue
+		default:
+			return fmt.Errorf("lifecycle rule #%d: invalid action %q (expecting one of: %q, %q, %q)",
+				i+1, rule.Action, LcycleDelete, LcycleEvict, LcycleReduceCopies)
+		}
+		if rule.Copies != 0 {
+			return fmt.Errorf("lifecycle rule #%d: number of copies applies only to %q", i+1, LcycleReduceCopies)
+		}
+	}
+	if c.Enabled && len(c.Rules) == 0 {
+		return errors.New("cannot enable lifecycle without rules")
+	}
+	return nil
+}
+
+// Rule returns the rule to apply to the named object of a given age, or nil if none.
+func (c *LifecycleConf) Rule(objName string, age time.Duration) (rule *LifecycleRule) {
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		if age < r.Age.D() || !strings.HasPrefix(objName, r.Prefix) {
+			continue
+		}
+		if rule == nil || r.precedence() < rule.precedence() {
+			rule = r
+		}
+	}
+	return
+}
+
+func (c *LifecycleConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	rules := make([]string, 0, len(c.Rules))
+	for i := range c.Rules {
+		rules = append(rules, c.Rules[i].String())
+	}
+	return "Enabled, " + strings.Join(rules, "; ")
+}
+
+func (r *LifecycleRule) precedence() int {
+	switch r.Action {
+	case LcycleDelete:
+		return 0
+	case LcycleEvict:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (r *LifecycleRule) String() (s string) {
+	s = r.Action
+	if r.Action == LcycleReduceCopies {
+		s += " to " + strconv.Itoa(r.Copies)
+	}
+	if r.Prefix != "" {
+		s += " " + r.Prefix + "*"
+	}
+	return s + " after " + r.Age.String()
+}
+
+/////////////////////
+// ReplicationConf //
+/////////////////////
+
+func (c *ReplicationConf) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	_, err := c.DstBck()
+	return err
+}
+
+// DstBck parses and validates the replication destination.
+func (c *ReplicationConf) DstBck() (bck Bck, err error) {
+	if c.Dst == "" {
+		return bck, errors.New("replication destination is not specified")
+	}
+	var objName string
+	if bck, objName, err = ParseBckObjectURI(c.Dst, ParseURIOpts{}); err != nil {
+		return bck, fmt.Errorf("invalid replication destination %q: %v", c.Dst, err)
+	}
+	switch {
+	case bck.Name == "" || objName != "":
+		err = fmt.Errorf("invalid replication destination %q (expecting bucket URI)", c.Dst)
+	case !bck.IsRemote() || bck.IsHTTP():
+		err = fmt.Errorf("invalid replication destination %s (expecting remote ais or cloud bucket)", bck)
+	}
+	return
+}
+
+func (c *ReplicationConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return "Enabled, to " + c.Dst
+}
+
 func (c *ExtraProps) ValidateAsProps(arg ...interface{}) error {
 	provider, ok := arg[0].(string)
 	debug.Assert(ok)
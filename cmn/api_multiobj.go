@@ -6,9 +6,12 @@
 package cmn
 
 import (
+	"errors"
+	"fmt"
 	"path/filepath"
 
 	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn/cos"
 )
 
 // used in multi-object (list|range) operations
@@ -44,6 +47,28 @@ type (
 		// flags
 		ContinueOnError bool `json:"coer"` // keep running in presence of errors in a any given multi-object transaction
 	}
+
+	// GetBatchMsg is used in get-batch operations that read multiple objects and/or archived
+	// files (in any order and from any number of buckets) and return them, in the request order,
+	// as a single streamed archive (see GetBatchEntry.NameInArch for the names of the archived files)
+	GetBatchMsg struct {
+		Entries         []GetBatchEntry `json:"entries"`
+		Mime            string          `json:"mime"` // cos.ExtTar (default) or cos.ExtMsgpack
+		ContinueOnError bool            `json:"coer"` // skip missing entries (default: fail the entire batch)
+	}
+	GetBatchEntry struct {
+		Bck      Bck    `json:"bck"`
+		ObjName  string `json:"objname"`
+		ArchPath string `json:"archpath,omitempty"` // optional: file in the (archived) object
+	}
+)
+
+// get-batch limits: the proxy buffers (in memory) the entries that arrive out of order
+// and, in msgpack format, all the entries - per request and in total (all requests)
+const (
+	GetBatchMaxEntries       = 64 * 1024
+	GetBatchMaxBuffered      = cos.GiB
+	GetBatchMaxBufferedTotal = 4 * cos.GiB
 )
 
 // NOTE: empty SelectObjsMsg{} corresponds to (range = entire bucket)
@@ -55,3 +80,41 @@ func (lrm *SelectObjsMsg) HasTemplate() bool { return lrm.Template != "" }
 // ArchiveMsg //
 ////////////////
 func (msg *ArchiveMsg) FullName() string { return filepath.Join(msg.ToBck.Name, msg.ArchName) }
+
+/////////////////
+// GetBatchMsg //
+/////////////////
+
+func (msg *GetBatchMsg) Validate() error {
+	if len(msg.Entries) == 0 {
+		return errors.New("get-batch: empty list of entries")
+	}
+	if len(msg.Entries) > GetBatchMaxEntries {
+		return fmt.Errorf("get-batch: too many entries (%d > %d)", len(msg.Entries), GetBatchMaxEntries)
+	}
+	switch msg.Mime {
+	case "":
+		msg.Mime = cos.ExtTar
+	case cos.ExtTar, cos.ExtMsgpack:
+	default:
+		return fmt.Errorf("get-batch: invalid output format %q (expecting %q or %q)", msg.Mime, cos.ExtTar, cos.ExtMsgpack)
+	}
+	for i := range msg.Entries {
+		e := &msg.Entries[i]
+		if err := e.Bck.Validate(); err != nil {
+			return err
+		}
+		if e.ObjName == "" {
+			return fmt.Errorf("get-batch: entry #%d (%s): missing object name", i, e.Bck)
+		}
+	}
+	return nil
+}
+
+// the name of the entry in the resulting archive: "bucket/object[/archpath]"
+func (e *GetBatchEntry) NameInArch() string {
+	if e.ArchPath == "" {
+		return filepath.Join(e.Bck.Name, e.ObjName)
+	}
+	return filepath.Join(e.Bck.Name, e.ObjName, e.ArchPath)
+}
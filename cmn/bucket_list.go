@@ -29,6 +29,8 @@ type BucketEntry struct {
 	TargetURL string `json:"target_url,omitempty" msg:"t,omitempty"`  // URL of target which has the entry
 	Copies    int16  `json:"copies,omitempty" msg:"c,omitempty"`      // ## copies (non-replicated = 1)
 	Flags     uint16 `json:"flags,omitempty" msg:"f,omitempty"`       // object flags, like CheckExists, IsMoved etc
+
+	Custom map[string]string `json:"custom,omitempty" msg:"m,omitempty"` // custom metadata (user-defined and system)
 }
 
 func (be *BucketEntry) CheckExists() bool  { return be.Flags&apc.EntryIsCached != 0 }
@@ -38,6 +40,17 @@ func (be *BucketEntry) Status() uint16     { return be.Flags & apc.EntryStatusMa
 func (be *BucketEntry) IsInsideArch() bool { return be.Flags&apc.EntryInArch != 0 }
 func (be *BucketEntry) String() string     { return "{" + be.Name + "}" }
 
+// SetCustom copies (non-empty) custom metadata
+func (be *BucketEntry) SetCustom(md cos.SimpleKVs) {
+	if len(md) == 0 {
+		return
+	}
+	be.Custom = make(map[string]string, len(md))
+	for k, v := range md {
+		be.Custom[k] = v
+	}
+}
+
 func (be *BucketEntry) CopyWithProps(propsSet cos.StringSet) (ne *BucketEntry) {
 	ne = &BucketEntry{Name: be.Name}
 	if propsSet.Contains(apc.GetPropsSize) {
@@ -58,6 +71,9 @@ func (be *BucketEntry) CopyWithProps(propsSet cos.StringSet) (ne *BucketEntry) {
 	if propsSet.Contains(apc.GetPropsCopies) {
 		ne.Copies = be.Copies
 	}
+	if propsSet.Contains(apc.GetPropsCustom) {
+		ne.Custom = be.Custom
+	}
 	return
 }
 
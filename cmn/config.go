@@ -46,6 +46,10 @@ const (
 const (
 	MinSliceCount = 1  // minimum number of data or parity slices
 	MaxSliceCount = 32 // maximum --/--
+
+	// codecs (Reed-Solomon with different encoding matrices - not interchangeable)
+	ECCodecRS       = "reed-solomon"        // Vandermonde matrix (default)
+	ECCodecRSCauchy = "reed-solomon-cauchy" // Cauchy matrix
 )
 
 const (
@@ -122,6 +126,7 @@ type (
 		Transport   TransportConf   `json:"transport"`
 		Memsys      MemsysConf      `json:"memsys"`
 		WritePolicy WritePolicyConf `json:"write_policy"`
+		BckStats    BckStatsConf    `json:"bucket_stats"`
 		Features    feat.Flags      `json:"features,string" allow:"cluster"` // feature flags (to flip assorted defaults)
 		// read-only
 		LastUpdated string `json:"lastupdate_time"`       // timestamp
@@ -155,6 +160,7 @@ type (
 		Transport   *TransportConfToUpdate   `json:"transport,omitempty"`
 		Memsys      *MemsysConfToUpdate      `json:"memsys,omitempty"`
 		WritePolicy *WritePolicyConfToUpdate `json:"write_policy,omitempty"`
+		BckStats    *BckStatsConfToUpdate    `json:"bucket_stats,omitempty"`
 		Proxy       *ProxyConfToUpdate       `json:"proxy,omitempty"`
 		Features    *feat.Flags              `json:"features,string,omitempty"`
 
@@ -221,18 +227,38 @@ type (
 	ECConf struct {
 		ObjSizeLimit int64  `json:"objsize_limit"` // objects below this size are replicated instead of EC'ed
 		Compression  string `json:"compression"`   // enum { CompressAlways, ... } in api/apc/compression.go
-		DataSlices   int    `json:"data_slices"`   // number of data slices
-		ParitySlices int    `json:"parity_slices"` // number of parity slices/replicas
-		Enabled      bool   `json:"enabled"`       // EC is enabled
-		DiskOnly     bool   `json:"disk_only"`     // if true, EC does not use SGL - data goes directly to drives
+		Codec        string `json:"codec"`         // enum { ECCodecRS, ECCodecRSCauchy } (empty: ECCodecRS)
+		// Policies override the layout above for objects that match by name prefix
+		// and/or size class; the first matching policy wins (see `ECConf.Policy`)
+		Policies     []ECPolicy `json:"policies"`
+		DataSlices   int        `json:"data_slices"`   // number of data slices
+		ParitySlices int        `json:"parity_slices"` // number of parity slices/replicas
+		Enabled      bool       `json:"enabled"`       // EC is enabled
+		DiskOnly     bool       `json:"disk_only"`     // if true, EC does not use SGL - data goes directly to drives
 	}
 	ECConfToUpdate struct {
-		Enabled      *bool   `json:"enabled,omitempty"`
-		ObjSizeLimit *int64  `json:"objsize_limit,omitempty"`
-		DataSlices   *int    `json:"data_slices,omitempty"`
-		ParitySlices *int    `json:"parity_slices,omitempty"`
-		Compression  *string `json:"compression,omitempty"`
-		DiskOnly     *bool   `json:"disk_only,omitempty"`
+		Enabled      *bool       `json:"enabled,omitempty"`
+		ObjSizeLimit *int64      `json:"objsize_limit,omitempty"`
+		DataSlices   *int        `json:"data_slices,omitempty"`
+		ParitySlices *int        `json:"parity_slices,omitempty"`
+		Compression  *string     `json:"compression,omitempty"`
+		Codec        *string     `json:"codec,omitempty"`
+		Policies     *[]ECPolicy `json:"policies,omitempty"`
+		DiskOnly     *bool       `json:"disk_only,omitempty"`
+	}
+	// ECPolicy is the layout of objects (erasure coded or replicated) that match
+	// the policy. The layout an object was written with is recorded in its EC
+	// metadata, so that restoring and rebalancing do not depend on the current
+	// configuration.
+	ECPolicy struct {
+		Name         string `json:"name"`               // (recorded in EC metadata)
+		Prefix       string `json:"prefix,omitempty"`   // object name prefix (empty: all objects)
+		MinSize      int64  `json:"min_size,omitempty"` // objects of at least this size
+		MaxSize      int64  `json:"max_size,omitempty"` // objects smaller than this size (0: unlimited)
+		Codec        string `json:"codec,omitempty"`    // (empty: ECConf.Codec)
+		DataSlices   int    `json:"data_slices"`        // number of data slices (ignored when replicating)
+		ParitySlices int    `json:"parity_slices"`      // number of parity slices/replicas
+		Replicate    bool   `json:"replicate"`          // replicate objects instead of EC'ing them
 	}
 
 	LogConf struct {
@@ -505,9 +531,16 @@ type (
 
 	DownloaderConf struct {
 		Timeout cos.Duration `json:"timeout"`
+		// credentials that download requests may reference (see downloader/headers.go):
+		// "file:NAME" - file NAME in the CredsDir directory (empty - not allowed);
+		// "env:NAME" - environment variable NAME that has CredsEnvPrefix (empty - not allowed)
+		CredsDir       string `json:"creds_dir,omitempty"`
+		CredsEnvPrefix string `json:"creds_env_prefix,omitempty"`
 	}
 	DownloaderConfToUpdate struct {
-		Timeout *cos.Duration `json:"timeout,omitempty"`
+		Timeout        *cos.Duration `json:"timeout,omitempty"`
+		CredsDir       *string       `json:"creds_dir,omitempty"`
+		CredsEnvPrefix *string       `json:"creds_env_prefix,omitempty"`
 	}
 
 	DSortConf struct {
@@ -575,6 +608,18 @@ type (
 		MinPctFree     *int          `json:"min_pct_free,omitempty" list:"readonly"`
 	}
 
+	// per-bucket usage statistics (targets only)
+	BckStatsConf struct {
+		Enabled bool `json:"enabled"`
+		// cardinality cap: buckets in excess of this number get accounted
+		// as a single "other" bucket (0 - use default)
+		MaxBuckets int `json:"max_buckets"`
+	}
+	BckStatsConfToUpdate struct {
+		Enabled    *bool `json:"enabled,omitempty"`
+		MaxBuckets *int  `json:"max_buckets,omitempty"`
+	}
+
 	WritePolicyConf struct {
 		Data apc.WritePolicy `json:"data"`
 		MD   apc.WritePolicy `json:"md"`
@@ -741,6 +786,7 @@ var (
 	_ Validator = (*TransportConf)(nil)
 	_ Validator = (*MemsysConf)(nil)
 	_ Validator = (*WritePolicyConf)(nil)
+	_ Validator = (*BckStatsConf)(nil)
 
 	_ PropsValidator = (*CksumConf)(nil)
 	_ PropsValidator = (*SpaceConf)(nil)
@@ -863,6 +909,30 @@ func (c *PeriodConf) Validate() error {
 	return nil
 }
 
+//////////////////
+// BckStatsConf //
+//////////////////
+
+const (
+	DfltBckStatsMaxBuckets = 256
+	maxBckStatsMaxBuckets  = 10000
+)
+
+func (c *BckStatsConf) Validate() error {
+	if c.MaxBuckets < 0 || c.MaxBuckets > maxBckStatsMaxBuckets {
+		return fmt.Errorf("invalid bucket_stats.max_buckets=%d (expected range [0, %d])",
+			c.MaxBuckets, maxBckStatsMaxBuckets)
+	}
+	return nil
+}
+
+func (c *BckStatsConf) MaxBcks() int {
+	if c.MaxBuckets == 0 {
+		return DfltBckStatsMaxBuckets
+	}
+	return c.MaxBuckets
+}
+
 /////////////
 // LogConf //
 /////////////
@@ -1200,6 +1270,20 @@ func (c *ECConf) Validate() error {
 		return fmt.Errorf("invalid ec.parity_slices: %d (expected value in range [%d, %d])",
 			c.ParitySlices, MinSliceCount, MaxSliceCount)
 	}
+	if err := validateECCodec(c.Codec); err != nil {
+		return fmt.Errorf("invalid ec.codec: %v", err)
+	}
+	names := make(cos.StringSet, len(c.Policies))
+	for i := range c.Policies {
+		p := &c.Policies[i]
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("invalid ec.policies #%d: %v", i+1, err)
+		}
+		if names.Contains(p.Name) {
+			return fmt.Errorf("invalid ec.policies #%d: duplicate name %q", i+1, p.Name)
+		}
+		names.Add(p.Name)
+	}
 	j := apc.WritePolicy(c.Compression)
 	return j.Validate()
 }
@@ -1217,29 +1301,168 @@ func (c *ECConf) ValidateAsProps(arg ...interface{}) (err error) {
 	if required <= targetCnt {
 		return
 	}
-	err = fmt.Errorf("%v: EC configuration (%d data and %d parity slices) requires at least %d (have %d)",
-		ErrNotEnoughTargets, c.DataSlices, c.ParitySlices, required, targetCnt)
-	if c.ParitySlices > targetCnt {
+	err = fmt.Errorf("%v: EC configuration (%s) requires at least %d (have %d)",
+		ErrNotEnoughTargets, c.layout(), required, targetCnt)
+	parity := c.ParitySlices
+	for i := range c.Policies {
+		parity = cos.Max(parity, c.Policies[i].ParitySlices)
+	}
+	if parity > targetCnt {
 		return
 	}
 	return NewErrSoft(err.Error())
 }
 
+func (c *ECConf) layout() string {
+	s := fmt.Sprintf("%d data and %d parity slices", c.DataSlices, c.ParitySlices)
+	if len(c.Policies) > 0 {
+		s += fmt.Sprintf(", %d policies", len(c.Policies))
+	}
+	return s
+}
+
 func (c *ECConf) String() string {
 	if !c.Enabled {
 		return "Disabled"
 	}
 	objSizeLimit := c.ObjSizeLimit
-	return fmt.Sprintf("%d:%d (%s)", c.DataSlices, c.ParitySlices, cos.B2S(objSizeLimit, 0))
+	s := fmt.Sprintf("%d:%d (%s)", c.DataSlices, c.ParitySlices, cos.B2S(objSizeLimit, 0))
+	if c.Codec != "" && c.Codec != ECCodecRS {
+		s += ", " + c.Codec
+	}
+	for i := range c.Policies {
+		s += "; " + c.Policies[i].String()
+	}
+	return s
+}
+
+// Policy returns the layout to EC (or replicate) the named object of a given
+// size with: the first matching policy or, if none matches, the default one
+// (named "") that replicates objects smaller than `ObjSizeLimit`.
+func (c *ECConf) Policy(objName string, size int64) ECPolicy {
+	for i := range c.Policies {
+		if p := &c.Policies[i]; p.matches(objName, size) {
+			policy := *p
+			if policy.Codec == "" {
+				policy.Codec = c.Codec
+			}
+			if policy.Replicate {
+				policy.DataSlices = 1 // (see ec.Metadata)
+			}
+			return policy
+		}
+	}
+	return ECPolicy{
+		Codec:        c.Codec,
+		DataSlices:   c.DataSlices,
+		ParitySlices: c.ParitySlices,
+		Replicate:    size < c.ObjSizeLimit,
+	}
 }
 
+// RequiredEncodeTargets returns the number of targets to EC any object of the bucket.
 func (c *ECConf) RequiredEncodeTargets() int {
 	// data slices + parity slices + 1 target for original object
-	return c.DataSlices + c.ParitySlices + 1
+	required := c.DataSlices + c.ParitySlices + 1
+	for i := range c.Policies {
+		required = cos.Max(required, c.Policies[i].RequiredEncodeTargets())
+	}
+	return required
+}
+
+// MinEncodeTargets returns the number of targets to EC at least some objects of
+// the bucket - that is, the smallest of the (default and policies') layouts.
+func (c *ECConf) MinEncodeTargets() int {
+	required := c.DataSlices + c.ParitySlices + 1
+	for i := range c.Policies {
+		required = cos.Min(required, c.Policies[i].RequiredEncodeTargets())
+	}
+	return required
 }
 
+// RequiredRestoreTargets returns the minimum number of targets to restore an
+// object of the bucket (the actual number depends on the object's layout).
 func (c *ECConf) RequiredRestoreTargets() int {
-	return c.DataSlices
+	required := c.DataSlices
+	for i := range c.Policies {
+		required = cos.Min(required, c.Policies[i].RequiredRestoreTargets())
+	}
+	return required
+}
+
+func validateECCodec(codec string) error {
+	switch codec {
+	case "", ECCodecRS, ECCodecRSCauchy:
+		return nil
+	default:
+		return fmt.Errorf("unknown codec %q (expecting %q or %q)", codec, ECCodecRS, ECCodecRSCauchy)
+	}
+}
+
+//////////////
+// ECPolicy //
+//////////////
+
+func (p *ECPolicy) validate() error {
+	if p.Name == "" {
+		return errors.New("missing name")
+	}
+	if p.MinSize < 0 || p.MaxSize < 0 {
+		return fmt.Errorf("%q: invalid (negative) size class [%d, %d)", p.Name, p.MinSize, p.MaxSize)
+	}
+	if p.MaxSize != 0 && p.MaxSize <= p.MinSize {
+		return fmt.Errorf("%q: empty size class [%d, %d)", p.Name, p.MinSize, p.MaxSize)
+	}
+	if !p.Replicate && (p.DataSlices < MinSliceCount || p.DataSlices > MaxSliceCount) {
+		return fmt.Errorf("%q: invalid data_slices %d (expected value in range [%d, %d])",
+			p.Name, p.DataSlices, MinSliceCount, MaxSliceCount)
+	}
+	if p.ParitySlices < MinSliceCount || p.ParitySlices > MaxSliceCount {
+		return fmt.Errorf("%q: invalid parity_slices %d (expected value in range [%d, %d])",
+			p.Name, p.ParitySlices, MinSliceCount, MaxSliceCount)
+	}
+	if err := validateECCodec(p.Codec); err != nil {
+		return fmt.Errorf("%q: %v", p.Name, err)
+	}
+	return nil
+}
+
+func (p *ECPolicy) matches(objName string, size int64) bool {
+	return strings.HasPrefix(objName, p.Prefix) && size >= p.MinSize && (p.MaxSize == 0 || size < p.MaxSize)
+}
+
+func (p *ECPolicy) RequiredEncodeTargets() int {
+	if p.Replicate {
+		return p.ParitySlices + 1
+	}
+	return p.DataSlices + p.ParitySlices + 1
+}
+
+func (p *ECPolicy) RequiredRestoreTargets() int {
+	if p.Replicate {
+		return 1
+	}
+	return p.DataSlices
+}
+
+func (p *ECPolicy) String() (s string) {
+	if p.Replicate {
+		s = fmt.Sprintf("%s: %d replicas", p.Name, p.ParitySlices)
+	} else {
+		s = fmt.Sprintf("%s: %d:%d", p.Name, p.DataSlices, p.ParitySlices)
+	}
+	if p.Prefix != "" {
+		s += " " + p.Prefix + "*"
+	}
+	if p.MinSize != 0 || p.MaxSize != 0 {
+		s += " [" + cos.B2S(p.MinSize, 0) + ", "
+		if p.MaxSize == 0 {
+			s += "-)"
+		} else {
+			s += cos.B2S(p.MaxSize, 0) + ")"
+		}
+	}
+	return
 }
 
 /////////////////////
@@ -1644,6 +1867,9 @@ func (c *DownloaderConf) Validate() error {
 	if j := c.Timeout.D(); j < time.Second || j > time.Hour {
 		return fmt.Errorf("invalid downloader.timeout=%s (expected range [1s, 1h])", j)
 	}
+	if c.CredsDir != "" && !filepath.IsAbs(c.CredsDir) {
+		return fmt.Errorf("invalid downloader.creds_dir=%q (expecting absolute path)", c.CredsDir)
+	}
 	return nil
 }
 
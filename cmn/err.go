@@ -107,6 +107,11 @@ type (
 		name   string // object's name
 		d1, d2 uint64 // lom.md.(bucket-ID) and lom.bck.(bucket-ID), respectively
 	}
+	ErrObjRetained struct {
+		name  string    // object's name
+		mode  string    // object lock mode
+		until time.Time // retain-until
+	}
 	ErrAborted struct {
 		what string
 		ctx  string
@@ -445,6 +450,21 @@ func isErrObjDefunct(err error) bool {
 	return ok
 }
 
+// ErrObjRetained
+
+func (e *ErrObjRetained) Error() string {
+	return fmt.Sprintf("%s is locked (%s mode) until %s", e.name, e.mode, e.until.Format(time.RFC3339))
+}
+
+func NewErrObjRetained(name, mode string, until time.Time) *ErrObjRetained {
+	return &ErrObjRetained{name, mode, until}
+}
+
+func IsErrObjRetained(err error) bool {
+	_, ok := err.(*ErrObjRetained)
+	return ok
+}
+
 // ErrAborted
 
 func NewErrAborted(what, ctx string, err error) *ErrAborted {
@@ -916,6 +936,8 @@ func WriteErr(w http.ResponseWriter, r *http.Request, err error, opts ...int /*[
 		status = opts[0]
 	} else if errf, ok := err.(*ErrFailedTo); ok {
 		status = errf.status
+	} else if IsErrObjRetained(err) {
+		status = http.StatusForbidden
 	}
 	httpErr.init(r, err.Error(), status)
 	httpErr.write(w, r, l > 1)
@@ -15,6 +15,7 @@ const (
 	DontLookupRemoteBck
 	SkipVC // (skip loading existing object's metadata, Version and Checksum in particular)
 	DontAutoDetectFshare
+	EnableProcETL // (allow ETL that runs an arbitrary command as a local process on each target)
 )
 
 var all = []struct {
@@ -25,6 +26,7 @@ var all = []struct {
 	{name: "DontLookupRemoteBck", value: DontLookupRemoteBck},
 	{name: "SkipVC", value: SkipVC},
 	{name: "DontAutoDetectFshare", value: DontAutoDetectFshare},
+	{name: "EnableProcETL", value: EnableProcETL},
 }
 
 func (cflags Flags) IsSet(flag Flags) bool { return cflags&flag == flag }
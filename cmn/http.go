@@ -45,6 +45,9 @@ const (
 	ContentMsgPack = "application/msgpack"
 	ContentXML     = "application/xml"
 	ContentBinary  = "application/octet-stream"
+	ContentTar     = "application/x-tar"
+
+	ContentByteRanges = "multipart/byteranges" // Ref: https://datatracker.ietf.org/doc/html/rfc7233#appendix-A
 )
 
 type (
@@ -310,6 +313,34 @@ func RangeHdr(start, length int64) (hdr http.Header) {
 	return
 }
 
+// multi-range (see ParseMultiRange)
+func MultiRangeHdr(ranges []HTTPRange) (hdr http.Header) {
+	if len(ranges) == 0 {
+		return hdr
+	}
+	specs := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		specs = append(specs, strconv.FormatInt(r.Start, 10)+"-"+strconv.FormatInt(r.Start+r.Length-1, 10))
+	}
+	hdr = make(http.Header, 1)
+	hdr.Add(HdrRange, HdrRangeValPrefix+strings.Join(specs, ","))
+	return
+}
+
+// ParseContentRange parses "bytes <first>-<last>/<size>" (see HTTPRange.ContentRange)
+func ParseContentRange(s string) (r HTTPRange, size int64, err error) {
+	var (
+		first, last int64
+		n           int
+	)
+	n, err = fmt.Sscanf(s, HdrContentRangeValPrefix+"%d-%d/%d", &first, &last, &size)
+	if err != nil || n != 3 || first < 0 || last < first {
+		return r, 0, fmt.Errorf("invalid %s %q", HdrContentRange, s)
+	}
+	r.Start, r.Length = first, last-first+1
+	return
+}
+
 func NetworkCallWithRetry(args *RetryArgs) (err error) {
 	var (
 		hardErrCnt, softErrCnt, iter uint
@@ -15,6 +15,7 @@ import (
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	jsoniter "github.com/json-iterator/go"
 )
 
 const (
@@ -378,6 +379,13 @@ func (f *field) SetValue(src interface{}, force ...bool) error {
 			dst = dst.Elem()                        // dereference pointer
 			goto reflectDst
 		case reflect.Slice:
+			// A slice of structs is specified in JSON (e.g. lifecycle rules)
+			if dst.Type().Elem().Kind() == reflect.Struct {
+				if err := jsoniter.Unmarshal([]byte(s), dst.Addr().Interface()); err != nil {
+					return fmt.Errorf("invalid %s value %q: %v", f.name, s, err)
+				}
+				break
+			}
 			// A slice value looks like: "[value1 value2]"
 			s := strings.TrimPrefix(srcVal.String(), "[")
 			s = strings.TrimSuffix(s, "]")
@@ -0,0 +1,182 @@
+// Package cmn provides common constants, types, and utilities for AIS clients
+// and AIStore.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+)
+
+// Object filter: a predicate over object's custom metadata and size that
+// list-objects evaluates on the targets (see `apc.ListObjsMsg.Filter`).
+//
+// Syntax:
+//   <term> [ AND|OR <term> ... ]
+//   <term> := <key> <op> <value>, where <op> is one of: =, !=, >, >=, <, <=
+//
+// - AND takes precedence over OR; keywords are case-insensitive;
+// - `size` is the reserved key that denotes object size; its value may
+//   include units, e.g. "size>1MB";
+// - all other keys are custom metadata keys; `=` and `!=` compare strings,
+//   other operators require numeric values on both sides;
+// - an object that does not have a given custom key does not match the term.
+//
+// Example: "label=train AND size>1MB OR label=validation"
+
+const ObjFilterSize = "size"
+
+var errEmptyFilter = errors.New("empty object filter")
+
+type (
+	ObjFilter struct {
+		expr string
+		or   [][]*filterTerm // disjunction of conjunctions
+	}
+	filterTerm struct {
+		key string
+		op  string
+		val string
+		num float64 // numeric value (size in bytes for `ObjFilterSize`)
+	}
+)
+
+// in the order of matching (two-character operators first)
+var filterOps = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+func ParseObjFilter(expr string) (*ObjFilter, error) {
+	var (
+		f     = &ObjFilter{expr: expr}
+		and   []*filterTerm
+		words []string
+	)
+	if strings.TrimSpace(expr) == "" {
+		return nil, errEmptyFilter
+	}
+	fields := strings.Fields(expr)
+	for i := 0; i <= len(fields); i++ {
+		var keyword string
+		if i < len(fields) {
+			keyword = strings.ToUpper(fields[i])
+			if keyword != "AND" && keyword != "OR" {
+				words = append(words, fields[i])
+				continue
+			}
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("invalid object filter %q: missing term", expr)
+		}
+		term, err := parseFilterTerm(strings.Join(words, " "))
+		if err != nil {
+			return nil, fmt.Errorf("invalid object filter %q: %v", expr, err)
+		}
+		words = words[:0]
+		and = append(and, term)
+		if keyword != "AND" {
+			f.or = append(f.or, and)
+			and = nil
+		}
+	}
+	return f, nil
+}
+
+func parseFilterTerm(s string) (*filterTerm, error) {
+	for i := 0; i < len(s); i++ {
+		for _, op := range filterOps {
+			if !strings.HasPrefix(s[i:], op) {
+				continue
+			}
+			term := &filterTerm{
+				key: strings.TrimSpace(s[:i]),
+				op:  op,
+				val: strings.Trim(strings.TrimSpace(s[i+len(op):]), `"'`),
+			}
+			return term, term.init()
+		}
+	}
+	return nil, fmt.Errorf("term %q: missing operator (expecting one of %v)", s, filterOps)
+}
+
+func (term *filterTerm) init() (err error) {
+	if term.key == "" || term.val == "" {
+		return fmt.Errorf("term \"%s%s%s\": missing key or value", term.key, term.op, term.val)
+	}
+	switch {
+	case term.key == ObjFilterSize:
+		var size int64
+		size, err = cos.S2B(term.val)
+		term.num = float64(size)
+	case term.op != "=" && term.op != "!=":
+		term.num, err = strconv.ParseFloat(term.val, 64)
+	}
+	if err != nil {
+		err = fmt.Errorf("term \"%s%s%s\": invalid numeric value: %v", term.key, term.op, term.val, err)
+	}
+	return
+}
+
+func (term *filterTerm) match(oah ObjAttrsHolder) bool {
+	var (
+		val float64
+		err error
+	)
+	if term.key == ObjFilterSize {
+		val = float64(oah.SizeBytes())
+	} else {
+		v, ok := oah.GetCustomKey(term.key)
+		if !ok {
+			return false
+		}
+		switch term.op {
+		case "=":
+			return v == term.val
+		case "!=":
+			return v != term.val
+		}
+		if val, err = strconv.ParseFloat(v, 64); err != nil {
+			return false
+		}
+	}
+	switch term.op {
+	case "=":
+		return val == term.num
+	case "!=":
+		return val != term.num
+	case ">":
+		return val > term.num
+	case ">=":
+		return val >= term.num
+	case "<":
+		return val < term.num
+	default:
+		return val <= term.num
+	}
+}
+
+///////////////
+// ObjFilter //
+///////////////
+
+func (f *ObjFilter) String() string { return f.expr }
+
+func (f *ObjFilter) Match(oah ObjAttrsHolder) bool {
+	for _, and := range f.or {
+		matched := true
+		for _, term := range and {
+			if !term.match(oah) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
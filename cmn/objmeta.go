@@ -32,6 +32,9 @@ const (
 	ETag         = "ETag"
 
 	OrigURLObjMD = "orig_url"
+
+	// object lock (WORM): RFC 3339 time until which the object is retained - see ObjLockConf
+	RetainUntilObjMD = "retain-until"
 )
 
 // provider-specific header keys
@@ -60,10 +63,11 @@ type ObjectProps struct {
 		Paths  []string `json:"paths,omitempty"`
 	} `json:"mirror"`
 	EC struct {
-		Generation   int64 `json:"generation"`
-		DataSlices   int   `json:"data"`
-		ParitySlices int   `json:"parity"`
-		IsECCopy     bool  `json:"replicated"`
+		Generation   int64  `json:"generation"`
+		DataSlices   int    `json:"data"`
+		ParitySlices int    `json:"parity"`
+		IsECCopy     bool   `json:"replicated"`
+		Policy       string `json:"policy,omitempty"` // EC policy the object was written with (empty: bucket's default)
 	} `json:"ec"`
 	DaemonID string `json:"daemon_id"`
 	Present  bool   `json:"present"`
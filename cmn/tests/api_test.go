@@ -8,6 +8,7 @@ import (
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -186,4 +187,32 @@ var _ = Describe("API", func() {
 			),
 		)
 	})
+
+	Describe("GetBatchMsg", func() {
+		entry := cmn.GetBatchEntry{Bck: cmn.Bck{Name: "bck"}, ObjName: "obj"}
+
+		It("should default to tar and normalize the provider", func() {
+			msg := &cmn.GetBatchMsg{Entries: []cmn.GetBatchEntry{entry}}
+			Expect(msg.Validate()).NotTo(HaveOccurred())
+			Expect(msg.Mime).To(Equal(cos.ExtTar))
+			Expect(msg.Entries[0].Bck.Provider).To(Equal(apc.ProviderAIS))
+		})
+
+		It("should name archived files", func() {
+			e := entry
+			Expect(e.NameInArch()).To(Equal("bck/obj"))
+			e.ArchPath = "dir/file.txt"
+			Expect(e.NameInArch()).To(Equal("bck/obj/dir/file.txt"))
+		})
+
+		DescribeTable("should fail to validate",
+			func(msg cmn.GetBatchMsg) {
+				Expect(msg.Validate()).To(HaveOccurred())
+			},
+			Entry("no entries", cmn.GetBatchMsg{}),
+			Entry("invalid mime", cmn.GetBatchMsg{Entries: []cmn.GetBatchEntry{entry}, Mime: cos.ExtZip}),
+			Entry("no object name", cmn.GetBatchMsg{Entries: []cmn.GetBatchEntry{{Bck: cmn.Bck{Name: "bck"}}}}),
+			Entry("invalid bucket", cmn.GetBatchMsg{Entries: []cmn.GetBatchEntry{{ObjName: "obj"}}}),
+		)
+	})
 })
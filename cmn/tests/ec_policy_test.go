@@ -0,0 +1,75 @@
+// Package test provides tests for common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package tests
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestECPolicy(t *testing.T) {
+	conf := &cmn.ECConf{
+		Enabled:      true,
+		DataSlices:   2,
+		ParitySlices: 2,
+		ObjSizeLimit: 256 * cos.KiB,
+		Policies: []cmn.ECPolicy{
+			{Name: "shards", Prefix: "shards/", MinSize: cos.MiB, DataSlices: 8, ParitySlices: 2, Codec: cmn.ECCodecRSCauchy},
+			{Name: "small", MaxSize: 64 * cos.KiB, ParitySlices: 3, Replicate: true},
+		},
+	}
+	testCases := []struct {
+		objName string
+		size    int64
+		name    string
+		data    int
+		parity  int
+		copy    bool
+	}{
+		{"shards/a.tar", 10 * cos.MiB, "shards", 8, 2, false},
+		{"shards/a.tar", 100 * cos.KiB, "", 2, 2, true}, // default: below `ObjSizeLimit`
+		{"shards/a.tar", 10 * cos.KiB, "small", 1, 3, true},
+		{"other/a.tar", 10 * cos.MiB, "", 2, 2, false},
+		{"other/a.tar", 64 * cos.KiB, "", 2, 2, true},
+	}
+	for _, tc := range testCases {
+		p := conf.Policy(tc.objName, tc.size)
+		tassert.Errorf(t, p.Name == tc.name && p.DataSlices == tc.data && p.ParitySlices == tc.parity && p.Replicate == tc.copy,
+			"%s (%s): expected %q %d:%d (replicate %t), got %+v",
+			tc.objName, cos.B2S(tc.size, 0), tc.name, tc.data, tc.parity, tc.copy, p)
+	}
+	tassert.Errorf(t, conf.Policy("shards/a.tar", 10*cos.MiB).Codec == cmn.ECCodecRSCauchy, "expected policy's codec")
+	tassert.Errorf(t, conf.RequiredEncodeTargets() == 11, "expected 11 targets to encode, got %d", conf.RequiredEncodeTargets())
+	tassert.Errorf(t, conf.MinEncodeTargets() == 4, "expected 4 targets to encode some objects, got %d", conf.MinEncodeTargets())
+	tassert.Errorf(t, conf.RequiredRestoreTargets() == 1, "expected 1 target to restore, got %d", conf.RequiredRestoreTargets())
+
+	cksum := cmn.CksumConf{Type: cos.ChecksumXXHash}
+	bp := &cmn.BucketProps{Provider: apc.ProviderAIS, Cksum: cksum, EC: *conf}
+	tassert.CheckError(t, bp.Validate(11))
+	err := bp.Validate(5)
+	tassert.Errorf(t, err != nil && cmn.IsErrSoft(err), "expected soft error (not enough targets), got %v", err)
+
+	invalid := []cmn.ECPolicy{
+		{DataSlices: 2, ParitySlices: 2},
+		{Name: "a", ParitySlices: 2},
+		{Name: "a", DataSlices: 2},
+		{Name: "a", DataSlices: 2, ParitySlices: 2, MinSize: cos.MiB, MaxSize: cos.KiB},
+		{Name: "a", DataSlices: 2, ParitySlices: 2, Codec: "lrc"},
+	}
+	for i := range invalid {
+		c := *conf
+		c.Policies = []cmn.ECPolicy{invalid[i]}
+		tassert.Errorf(t, c.Validate() != nil, "%d: expected validation error", i)
+	}
+	c := *conf
+	c.Policies = []cmn.ECPolicy{conf.Policies[0], conf.Policies[0]}
+	tassert.Errorf(t, c.Validate() != nil, "expected duplicate name error")
+	c.Policies, c.Codec = nil, "lrc"
+	tassert.Errorf(t, c.Validate() != nil, "expected invalid codec error")
+}
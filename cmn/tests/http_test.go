@@ -84,3 +84,33 @@ func TestMatchRESTItems(t *testing.T) {
 		}
 	}
 }
+
+func TestMultiRange(t *testing.T) {
+	const size = 1000
+	ranges := []cmn.HTTPRange{{Start: 0, Length: 10}, {Start: 500, Length: 1}, {Start: 990, Length: 10}}
+	hdr := cmn.MultiRangeHdr(ranges)
+	if s := hdr.Get(cmn.HdrRange); s != "bytes=0-9,500-500,990-999" {
+		t.Fatalf("unexpected range header %q", s)
+	}
+	parsed, err := cmn.ParseMultiRange(hdr.Get(cmn.HdrRange), size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, ranges) {
+		t.Fatalf("ranges are not equal (got: %v, expected: %v)", parsed, ranges)
+	}
+	for _, r := range ranges {
+		cr, osize, err := cmn.ParseContentRange(r.ContentRange(size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cr != r || osize != size {
+			t.Fatalf("content range: got %v/%d, expected %v/%d", cr, osize, r, size)
+		}
+	}
+	for _, s := range []string{"", "bytes 10-5/100", "bytes -1-5/100", "bytes=0-9/100", "bytes 0-9"} {
+		if _, _, err := cmn.ParseContentRange(s); err == nil {
+			t.Fatalf("%q: expected error", s)
+		}
+	}
+}
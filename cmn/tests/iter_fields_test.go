@@ -5,6 +5,8 @@
 package tests
 
 import (
+	"time"
+
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
@@ -75,6 +77,8 @@ var _ = Describe("IterFields", func() {
 					"ec.data_slices":   0,
 					"ec.objsize_limit": int64(0),
 					"ec.compression":   "",
+					"ec.codec":         "",
+					"ec.policies":      []cmn.ECPolicy(nil),
 					"ec.disk_only":     false,
 
 					"versioning.enabled":           false,
@@ -97,6 +101,19 @@ var _ = Describe("IterFields", func() {
 
 					"write_policy.data": apc.WritePolicy(""),
 					"write_policy.md":   apc.WritePolicy(""),
+
+					"soft_delete.enabled":   false,
+					"soft_delete.retention": cos.Duration(0),
+
+					"obj_lock.mode":      "",
+					"obj_lock.retention": cos.Duration(0),
+					"obj_lock.enabled":   false,
+
+					"lifecycle.rules":   []cmn.LifecycleRule(nil),
+					"lifecycle.enabled": false,
+
+					"replication.dst":     "",
+					"replication.enabled": false,
 				},
 			),
 			Entry("list BucketPropsToUpdate fields",
@@ -127,6 +144,8 @@ var _ = Describe("IterFields", func() {
 					"ec.data_slices":   (*int)(nil),
 					"ec.objsize_limit": (*int64)(nil),
 					"ec.compression":   (*string)(nil),
+					"ec.codec":         (*string)(nil),
+					"ec.policies":      (*[]cmn.ECPolicy)(nil),
 					"ec.disk_only":     (*bool)(nil),
 
 					"versioning.enabled":           (*bool)(nil),
@@ -147,6 +166,19 @@ var _ = Describe("IterFields", func() {
 					"write_policy.data": (*apc.WritePolicy)(nil),
 					"write_policy.md":   api.WritePolicy(apc.WriteDelayed),
 
+					"soft_delete.enabled":   (*bool)(nil),
+					"soft_delete.retention": (*cos.Duration)(nil),
+
+					"obj_lock.mode":      (*string)(nil),
+					"obj_lock.retention": (*cos.Duration)(nil),
+					"obj_lock.enabled":   (*bool)(nil),
+
+					"lifecycle.rules":   (*[]cmn.LifecycleRule)(nil),
+					"lifecycle.enabled": (*bool)(nil),
+
+					"replication.dst":     (*string)(nil),
+					"replication.enabled": (*bool)(nil),
+
 					"extra.hdfs.ref_directory": (*string)(nil),
 				},
 			),
@@ -269,6 +301,8 @@ var _ = Describe("IterFields", func() {
 
 					"access":          "12", // type == uint64
 					"write_policy.md": apc.WriteNever,
+
+					"lifecycle.rules": `[{"prefix": "tmp/", "action": "delete", "age": "168h"}]`, // type == []struct
 				},
 				&cmn.BucketPropsToUpdate{
 					Versioning: &cmn.VersionConfToUpdate{
@@ -293,6 +327,11 @@ var _ = Describe("IterFields", func() {
 					WritePolicy: &cmn.WritePolicyConfToUpdate{
 						MD: api.WritePolicy(apc.WriteNever),
 					},
+					Lifecycle: &cmn.LifecycleConfToUpdate{
+						Rules: &[]cmn.LifecycleRule{
+							{Prefix: "tmp/", Action: cmn.LcycleDelete, Age: cos.Duration(168 * time.Hour)},
+						},
+					},
 				},
 			),
 		)
@@ -310,6 +349,9 @@ var _ = Describe("IterFields", func() {
 			Entry("readonly field", &cmn.BucketProps{}, map[string]interface{}{
 				"provider": apc.ProviderAIS,
 			}),
+			Entry("invalid JSON", &cmn.BucketProps{}, map[string]interface{}{
+				"lifecycle.rules": "delete tmp/ after 7 days",
+			}),
 			Entry("field not found", &Foo{}, map[string]interface{}{
 				"foo.bar": 2,
 			}),
@@ -0,0 +1,63 @@
+// Package test provides tests for common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestLifecycleRule(t *testing.T) {
+	const day = 24 * time.Hour
+	conf := &cmn.LifecycleConf{
+		Enabled: true,
+		Rules: []cmn.LifecycleRule{
+			{Action: cmn.LcycleReduceCopies, Age: cos.Duration(14 * day), Copies: 1},
+			{Prefix: "tmp/", Action: cmn.LcycleDelete, Age: cos.Duration(7 * day)},
+			{Action: cmn.LcycleEvict, Age: cos.Duration(30 * day)},
+		},
+	}
+	testCases := []struct {
+		objName string
+		age     time.Duration
+		action  string
+	}{
+		{"tmp/a", day, ""},
+		{"tmp/a", 8 * day, cmn.LcycleDelete},
+		{"tmp/a", 40 * day, cmn.LcycleDelete},
+		{"data/a", 8 * day, ""},
+		{"data/a", 15 * day, cmn.LcycleReduceCopies},
+		{"data/a", 31 * day, cmn.LcycleEvict},
+	}
+	for _, tc := range testCases {
+		var action string
+		if rule := conf.Rule(tc.objName, tc.age); rule != nil {
+			action = rule.Action
+		}
+		tassert.Errorf(t, action == tc.action, "%s (age %v): expected %q, got %q", tc.objName, tc.age, tc.action, action)
+	}
+
+	cksum := cmn.CksumConf{Type: cos.ChecksumXXHash}
+	remote := &cmn.BucketProps{Provider: apc.ProviderAmazon, Cksum: cksum, Lifecycle: *conf}
+	tassert.CheckError(t, remote.Validate(1))
+
+	invalid := []cmn.LifecycleConf{
+		{Enabled: true},
+		{Rules: []cmn.LifecycleRule{{Action: cmn.LcycleDelete}}},
+		{Rules: []cmn.LifecycleRule{{Action: "archive", Age: cos.Duration(day)}}},
+		{Rules: []cmn.LifecycleRule{{Action: cmn.LcycleEvict, Age: cos.Duration(day)}}}, // ais bucket
+		{Rules: []cmn.LifecycleRule{{Action: cmn.LcycleReduceCopies, Age: cos.Duration(day)}}},
+		{Rules: []cmn.LifecycleRule{{Action: cmn.LcycleDelete, Age: cos.Duration(day), Copies: 1}}},
+	}
+	for i := range invalid {
+		bp := &cmn.BucketProps{Provider: apc.ProviderAIS, Cksum: cksum, Lifecycle: invalid[i]}
+		tassert.Errorf(t, bp.Validate(1) != nil, "%d: expected validation error", i)
+	}
+}
@@ -0,0 +1,48 @@
+// Package test provides tests for common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package tests
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestObjFilter(t *testing.T) {
+	oa := &cmn.ObjAttrs{
+		Size:     2 * cos.MiB,
+		CustomMD: cos.SimpleKVs{"label": "train", "epoch": "7"},
+	}
+	testCases := []struct {
+		expr    string
+		matches bool
+	}{
+		{"label=train", true},
+		{"label = train", true},
+		{"label='train'", true},
+		{"label!=train", false},
+		{"label=test", false},
+		{"size>1MB", true},
+		{"size > 1MiB and label=train", true},
+		{"size<=1MB AND label=train", false},
+		{"size<=1MB OR label=train", true},
+		{"label=test AND size>1MB OR epoch>=7", true},
+		{"epoch<7", false},
+		{"missing=x", false},
+		{"missing!=x", false},
+	}
+	for _, tc := range testCases {
+		f, err := cmn.ParseObjFilter(tc.expr)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, f.Match(oa) == tc.matches, "%q: expected match=%t", tc.expr, tc.matches)
+	}
+
+	for _, expr := range []string{"", "label", "=train", "label=", "AND label=x", "label=x OR", "epoch>seven", "size>big"} {
+		_, err := cmn.ParseObjFilter(expr)
+		tassert.Errorf(t, err != nil, "%q: expected parsing error", expr)
+	}
+}
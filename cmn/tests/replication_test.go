@@ -0,0 +1,45 @@
+// Package test provides tests for common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package tests
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/devtools/tassert"
+)
+
+func TestReplicationConf(t *testing.T) {
+	cksum := cmn.CksumConf{Type: cos.ChecksumXXHash}
+	valid := []struct {
+		dst string
+		bck cmn.Bck
+	}{
+		{"s3://dst", cmn.Bck{Name: "dst", Provider: apc.ProviderAmazon}},
+		{"gs://dst/", cmn.Bck{Name: "dst", Provider: apc.ProviderGoogle}},
+		{"ais://@uuid#ns/dst", cmn.Bck{Name: "dst", Provider: apc.ProviderAIS, Ns: cmn.Ns{UUID: "uuid", Name: "ns"}}},
+	}
+	for _, tc := range valid {
+		bp := &cmn.BucketProps{Provider: apc.ProviderAIS, Cksum: cksum}
+		bp.Replication = cmn.ReplicationConf{Dst: tc.dst, Enabled: true}
+		tassert.CheckError(t, bp.Validate(1))
+		bck, err := bp.Replication.DstBck()
+		tassert.CheckError(t, err)
+		tassert.Errorf(t, bck.Equal(&tc.bck), "%s: expected %s, got %s", tc.dst, tc.bck, bck)
+	}
+
+	invalid := []string{"", "dst", "ais://dst", "s3://", "s3://dst/obj", "ht://dst"}
+	for _, dst := range invalid {
+		bp := &cmn.BucketProps{Provider: apc.ProviderAIS, Cksum: cksum}
+		bp.Replication = cmn.ReplicationConf{Dst: dst, Enabled: true}
+		tassert.Errorf(t, bp.Validate(1) != nil, "%q: expected validation error", dst)
+	}
+
+	// disabled replication is not validated
+	bp := &cmn.BucketProps{Provider: apc.ProviderAIS, Cksum: cksum, Replication: cmn.ReplicationConf{Dst: "dst"}}
+	tassert.CheckError(t, bp.Validate(1))
+}
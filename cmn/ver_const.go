@@ -32,7 +32,8 @@ const (
 	MetaverVMD   = 1 // Volume MD (jsp)
 	MetaverEtlMD = 1 // ETL MD (jsp)
 
-	MetaverLOM = 1 // LOM
+	MetaverLOM     = 1 // LOM
+	MetaverArchIdx = 1 // archive (shard) member index (jsp)
 
 	MetaverConfig      = 2 // Global Configuration (jsp)
 	MetaverAuthNConfig = 1 // Authn config (jsp) // ditto
@@ -163,6 +163,10 @@ cat > $AIS_CONF_FILE <<EOL
 		"data": "${WRITE_POLICY_DATA:-}",
 		"md": "${WRITE_POLICY_MD:-}"
 	},
+	"bucket_stats": {
+		"enabled":     false,
+		"max_buckets": 256
+	},
 	"features": "0"
 }
 EOL
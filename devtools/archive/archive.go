@@ -18,6 +18,7 @@ import (
 
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/tinylib/msgp/msgp"
 )
 
 type (
@@ -218,6 +219,40 @@ func CreateZipWithRandomFiles(zipName string, fileCnt, fileSize int, randomNames
 	return nil
 }
 
+// CreateMsgpackWithRandomFiles creates msgpack shard (a single map: file name => content)
+// with specified number of files.
+func CreateMsgpackWithRandomFiles(shardName string, fileCnt, fileSize int, randomNames []string) error {
+	f, err := cos.CreateFile(shardName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := msgp.NewWriter(f)
+	if err := w.WriteMapHeader(uint32(fileCnt)); err != nil {
+		return err
+	}
+	b := make([]byte, fileSize)
+	for i := 0; i < fileCnt; i++ {
+		var fileName string
+		if randomNames == nil {
+			fileName = fmt.Sprintf("%d.txt", rand.Int()) // generate random names
+		} else {
+			fileName = randomNames[i]
+		}
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		if err := w.WriteString(fileName); err != nil {
+			return err
+		}
+		if err := w.WriteBytes(b); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
 // GetFileInfosFromTarBuffer returns all file infos contained in buffer which
 // presumably is tar or gzipped tar.
 func GetFileInfosFromTarBuffer(buffer bytes.Buffer, gzipped bool) ([]os.FileInfo, error) {
@@ -295,3 +330,31 @@ func GetFileInfosFromZipBuffer(buffer bytes.Buffer) ([]os.FileInfo, error) {
 
 	return files, nil
 }
+
+// GetFileInfosFromMsgpackBuffer returns all file infos contained in buffer which
+// presumably is msgpack shard.
+func GetFileInfosFromMsgpackBuffer(buffer bytes.Buffer) ([]os.FileInfo, error) {
+	r := msgp.NewReader(&buffer)
+	cnt, err := r.ReadMapHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]os.FileInfo, 0, cnt)
+	for i := uint32(0); i < cnt; i++ {
+		name, err := r.ReadString()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.ReadBytesHeader()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.R.Skip(int(size)); err != nil {
+			return nil, err
+		}
+		files = append(files, newDummyFile(name, int64(size)))
+	}
+
+	return files, nil
+}
@@ -150,6 +150,8 @@ func PrepareObjects(t *testing.T, desc ObjectsDesc) *ObjectsOut {
 	_ = fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{})
 	_ = fs.CSM.Reg(fs.ECSliceType, &fs.ECSliceContentResolver{})
 	_ = fs.CSM.Reg(fs.ECMetaType, &fs.ECMetaContentResolver{})
+	_ = fs.CSM.Reg(fs.DeletedObjType, &fs.DeletedObjContentResolver{})
+	_ = fs.CSM.Reg(fs.ArchIdxType, &fs.ArchIdxContentResolver{})
 
 	dir := t.TempDir()
 
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,12 +22,15 @@ import (
 )
 
 const (
-	DlTypeSingle  DlType = "single"
-	DlTypeRange   DlType = "range"
-	DlTypeMulti   DlType = "multi"
-	DlTypeBackend DlType = "backend"
+	DlTypeSingle   DlType = "single"
+	DlTypeRange    DlType = "range"
+	DlTypeMulti    DlType = "multi"
+	DlTypeBackend  DlType = "backend"
+	DlTypeSync     DlType = "sync"
+	DlTypeManifest DlType = "manifest"
 
 	DownloadProgressInterval = 10 * time.Second
+	MinSyncInterval          = time.Minute // minimum interval between sync job runs
 )
 
 type (
@@ -56,21 +60,37 @@ type (
 		Aborted       bool      `json:"aborted"`
 		StartedTime   time.Time `json:"started_time"`
 		FinishedTime  time.Time `json:"finished_time"`
+
+		Headers map[string]string `json:"headers,omitempty"` // request headers, with credentials redacted
 	}
 
 	DlJobInfos []*DlJobInfo
 
+	// Summary of a single completed run of the (scheduled) sync job
+	DlSyncRun struct {
+		Run          int64     `json:"run"`
+		FinishedCnt  int       `json:"finished_cnt"`
+		ScheduledCnt int       `json:"scheduled_cnt"`
+		SkippedCnt   int       `json:"skipped_cnt"`
+		ErrorCnt     int       `json:"error_cnt"`
+		Aborted      bool      `json:"aborted"`
+		StartedTime  time.Time `json:"started_time"`
+		FinishedTime time.Time `json:"finished_time"`
+	}
+
 	DlStatusResp struct {
 		DlJobInfo
 		CurrentTasks  []TaskDlInfo  `json:"current_tasks,omitempty"`
 		FinishedTasks []TaskDlInfo  `json:"finished_tasks,omitempty"`
 		Errs          []TaskErrInfo `json:"download_errors,omitempty"`
+		SyncRuns      []DlSyncRun   `json:"sync_runs,omitempty"` // sync jobs only: stats of the completed runs
 	}
 )
 
 func IsType(a string) bool {
 	b := DlType(a)
-	return b == DlTypeMulti || b == DlTypeBackend || b == DlTypeSingle || b == DlTypeRange
+	return b == DlTypeMulti || b == DlTypeBackend || b == DlTypeSingle || b == DlTypeRange || b == DlTypeSync ||
+		b == DlTypeManifest
 }
 
 func (j *DlJobInfo) Aggregate(rhs *DlJobInfo) {
@@ -81,6 +101,9 @@ func (j *DlJobInfo) Aggregate(rhs *DlJobInfo) {
 	j.Total += rhs.Total
 	j.AllDispatched = j.AllDispatched && rhs.AllDispatched
 	j.Aborted = j.Aborted || rhs.Aborted
+	if j.Headers == nil {
+		j.Headers = rhs.Headers
+	}
 	if j.StartedTime.After(rhs.StartedTime) {
 		j.StartedTime = rhs.StartedTime
 	}
@@ -192,20 +215,91 @@ func (d *DlStatusResp) Aggregate(rhs DlStatusResp) *DlStatusResp {
 	d.CurrentTasks = append(d.CurrentTasks, rhs.CurrentTasks...)
 	d.FinishedTasks = append(d.FinishedTasks, rhs.FinishedTasks...)
 	d.Errs = append(d.Errs, rhs.Errs...)
+	d.SyncRuns = aggregateSyncRuns(d.SyncRuns, rhs.SyncRuns)
 	return d
 }
 
+func (r *DlSyncRun) Aggregate(rhs *DlSyncRun) {
+	r.FinishedCnt += rhs.FinishedCnt
+	r.ScheduledCnt += rhs.ScheduledCnt
+	r.SkippedCnt += rhs.SkippedCnt
+	r.ErrorCnt += rhs.ErrorCnt
+	r.Aborted = r.Aborted || rhs.Aborted
+	if r.StartedTime.After(rhs.StartedTime) {
+		r.StartedTime = rhs.StartedTime
+	}
+	if r.FinishedTime.Before(rhs.FinishedTime) {
+		r.FinishedTime = rhs.FinishedTime
+	}
+}
+
+// merges per-target runs by their sequence numbers
+func aggregateSyncRuns(lhs, rhs []DlSyncRun) []DlSyncRun {
+	for i := range rhs {
+		var found bool
+		for j := range lhs {
+			if lhs[j].Run == rhs[i].Run {
+				lhs[j].Aggregate(&rhs[i])
+				found = true
+				break
+			}
+		}
+		if !found {
+			lhs = append(lhs, rhs[i])
+		}
+	}
+	sort.Slice(lhs, func(i, j int) bool { return lhs[i].Run < lhs[j].Run })
+	return lhs
+}
+
 type DlLimits struct {
 	Connections  int `json:"connections"`
 	BytesPerHour int `json:"bytes_per_hour"`
 }
 
+// Retry policy for failed object downloads. Zero values imply defaults:
+// up to 10 attempts (1 attempt when downloading from remote bucket), no delay
+// in-between, and all HTTP codes except the terminal ones (404, 403, etc.) are retriable.
+type DlRetry struct {
+	MaxAttempts int    `json:"max_attempts"` // max number of attempts per object, including the first one
+	Backoff     string `json:"backoff"`      // delay before the first retry; doubles with each next retry
+	MaxBackoff  string `json:"max_backoff"`  // upper bound on the (growing) delay
+	Codes       []int  `json:"codes"`        // HTTP status codes to retry on
+}
+
+func (r *DlRetry) Validate() error {
+	if r.MaxAttempts < 0 {
+		return fmt.Errorf("'retry.max_attempts' must be non-negative (got: %d)", r.MaxAttempts)
+	}
+	if r.Backoff != "" {
+		if d, err := time.ParseDuration(r.Backoff); err != nil || d < 0 {
+			return fmt.Errorf("invalid 'retry.backoff' %q", r.Backoff)
+		}
+	}
+	if r.MaxBackoff != "" {
+		if d, err := time.ParseDuration(r.MaxBackoff); err != nil || d < 0 {
+			return fmt.Errorf("invalid 'retry.max_backoff' %q", r.MaxBackoff)
+		}
+	}
+	for _, code := range r.Codes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid HTTP status code %d in 'retry.codes'", code)
+		}
+	}
+	return nil
+}
+
 type DlBase struct {
 	Description      string   `json:"description"`
 	Bck              cmn.Bck  `json:"bucket"`
 	Timeout          string   `json:"timeout"`
 	ProgressInterval string   `json:"progress_interval"`
 	Limits           DlLimits `json:"limits"`
+	Retry            DlRetry  `json:"retry"`
+
+	// HTTP request headers (templates) and credentials to access the source, see headers.go
+	Headers map[string]string `json:"headers,omitempty"`
+	Auth    DlAuth            `json:"auth"`
 }
 
 func (b *DlBase) Validate() error {
@@ -223,7 +317,16 @@ func (b *DlBase) Validate() error {
 	if b.Limits.BytesPerHour < 0 {
 		return fmt.Errorf("'limit.bytes_per_hour' must be non-negative (got: %d)", b.Limits.BytesPerHour)
 	}
-	return nil
+	if err := validateHeaders(b.Headers); err != nil {
+		return err
+	}
+	if err := b.Auth.Validate(); err != nil {
+		return err
+	}
+	if err := b.validateCreds(&cmn.GCO.Get().Downloader); err != nil {
+		return err
+	}
+	return b.Retry.Validate()
 }
 
 type DlSingleObj struct {
@@ -320,11 +423,11 @@ func (b *DlSingleBody) Describe() string {
 	if b.Description != "" {
 		return b.Description
 	}
-	return fmt.Sprintf("%s -> %s/%s", b.Link, b.Bck, b.ObjName)
+	return fmt.Sprintf("%s -> %s/%s", redactLink(b.Link), b.Bck, b.ObjName)
 }
 
 func (b *DlSingleBody) String() string {
-	return fmt.Sprintf("Link: %q, Bucket: %q, ObjName: %q.", b.Link, b.Bck, b.ObjName)
+	return fmt.Sprintf("Link: %q, Bucket: %q, ObjName: %q.", redactLink(b.Link), b.Bck, b.ObjName)
 }
 
 // Range request
@@ -348,11 +451,11 @@ func (b *DlRangeBody) Describe() string {
 	if b.Description != "" {
 		return b.Description
 	}
-	return fmt.Sprintf("%s -> %s", b.Template, b.Bck)
+	return fmt.Sprintf("%s -> %s", redactLink(b.Template), b.Bck)
 }
 
 func (b *DlRangeBody) String() string {
-	return fmt.Sprintf("bucket: %q, template: %q", b.Bck, b.Template)
+	return fmt.Sprintf("bucket: %q, template: %q", b.Bck, redactLink(b.Template))
 }
 
 // Multi request
@@ -412,6 +515,34 @@ func (b *DlMultiBody) String() string {
 	return fmt.Sprintf("bucket: %q", b.Bck)
 }
 
+// Manifest request: download objects listed in the manifest stored in AIS bucket (see manifest.go)
+type DlManifestBody struct {
+	DlBase
+	ManifestBck cmn.Bck `json:"manifest_bck"`
+	ManifestObj string  `json:"manifest_obj"`
+	Format      string  `json:"format"` // "csv" or "jsonl" (default: determined by the manifest's extension)
+}
+
+func (b *DlManifestBody) Validate() error {
+	if b.ManifestBck.Name == "" {
+		return errors.New("missing 'manifest_bck.name' in the request body")
+	}
+	if b.ManifestObj == "" {
+		return errors.New("missing 'manifest_obj' in the request body")
+	}
+	if _, err := manifestFormat(b.Format, b.ManifestObj); err != nil {
+		return err
+	}
+	return b.DlBase.Validate()
+}
+
+func (b *DlManifestBody) Describe() string {
+	if b.Description != "" {
+		return b.Description
+	}
+	return fmt.Sprintf("manifest %s/%s -> %s", b.ManifestBck, b.ManifestObj, b.Bck)
+}
+
 // Backend download request
 type DlBackendBody struct {
 	DlBase
@@ -428,3 +559,55 @@ func (b *DlBackendBody) Describe() string {
 	}
 	return fmt.Sprintf("remote bucket prefetch -> %s", b.Bck)
 }
+
+// Sync (scheduled, incremental) download request
+type DlSyncBody struct {
+	DlBase
+	Prefix   string `json:"prefix"`
+	Suffix   string `json:"suffix"`
+	Schedule string `json:"schedule"` // see `ParseSchedule`
+	Delete   bool   `json:"delete"`   // remove local objects that vanished from the remote bucket
+}
+
+func (b *DlSyncBody) Validate() error {
+	if err := b.DlBase.Validate(); err != nil {
+		return err
+	}
+	// job definition is persisted to run on schedule (and survive restarts)
+	if b.inlineSecrets() {
+		return errors.New("sync job requires credentials and sensitive headers to reference secrets " +
+			"(\"env:NAME\" or \"file:PATH\") rather than pass them inline")
+	}
+	_, err := ParseSchedule(b.Schedule)
+	return err
+}
+
+func (b *DlSyncBody) Describe() string {
+	if b.Description != "" {
+		return b.Description
+	}
+	return fmt.Sprintf("remote bucket sync (%s) -> %s", b.Schedule, b.Bck)
+}
+
+// ParseSchedule converts sync job schedule to the interval between consecutive runs.
+// Supported formats: Go duration ("30m", "2h"), "@every <duration>", "@hourly", "@daily", and "@weekly".
+func ParseSchedule(schedule string) (interval time.Duration, err error) {
+	switch s := strings.TrimSpace(schedule); s {
+	case "":
+		return 0, errors.New("missing 'schedule' in the request body")
+	case "@hourly":
+		interval = time.Hour
+	case "@daily":
+		interval = 24 * time.Hour
+	case "@weekly":
+		interval = 7 * 24 * time.Hour
+	default:
+		if interval, err = time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(s, "@every"))); err != nil {
+			return 0, fmt.Errorf("invalid schedule %q: %v", schedule, err)
+		}
+	}
+	if interval < MinSyncInterval {
+		return 0, fmt.Errorf("invalid schedule %q: interval must be at least %v", schedule, MinSyncInterval)
+	}
+	return interval, nil
+}
@@ -11,11 +11,15 @@ import (
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/dbdriver"
+	jsoniter "github.com/json-iterator/go"
 )
 
 const (
 	downloaderErrors     = "errors"
 	downloaderTasks      = "tasks"
+	downloaderSync       = "sync"     // sync job definitions
+	downloaderSyncRuns   = "syncruns" // per-run stats of sync jobs
+	downloaderDeadLetter = "failed"   // failed downloads to retry (see `retryDlJob`)
 	downloaderCollection = "downloads"
 
 	// Number of errors stored in memory. When the number of errors exceeds
@@ -25,23 +29,44 @@ const (
 	// Number of tasks stored in memory. When the number of tasks exceeds
 	// this number, then all errors will be flushed to disk
 	taskInfoCacheSize = 1000
+
+	// Number of the most recent runs of a sync job whose stats are retained
+	syncRunsMax = 100
 )
 
 var errJobNotFound = errors.New("job not found")
 
-type downloaderDB struct {
-	mtx    sync.RWMutex
-	driver dbdriver.Driver
+type (
+	downloaderDB struct {
+		mtx    sync.RWMutex
+		driver dbdriver.Driver
 
-	errCache      map[string][]TaskErrInfo // memory cache for errors, see: errCacheSize
-	taskInfoCache map[string][]TaskDlInfo  // memory cache for tasks, see: taskInfoCacheSize
-}
+		errCache      map[string][]TaskErrInfo // memory cache for errors, see: errCacheSize
+		taskInfoCache map[string][]TaskDlInfo  // memory cache for tasks, see: taskInfoCacheSize
+		failedCache   map[string]*deadLetter   // memory cache for failed downloads, see: errCacheSize
+	}
+
+	// failed downloads of the job, along with the job's request
+	deadLetter struct {
+		Def  DlBase          `json:"def"` // without inline secrets (see `DlBase.persistable`)
+		Objs []deadLetterObj `json:"objs"`
+		// true when the job's inline secrets were omitted
+		NoSecrets bool `json:"no_secrets,omitempty"`
+	}
+	deadLetterObj struct {
+		ObjName    string `json:"name"`
+		Link       string `json:"link,omitempty"` // empty when downloading from remote bucket
+		CksumType  string `json:"cksum_type,omitempty"`
+		CksumValue string `json:"cksum_value,omitempty"` // (e.g., provided by the manifest)
+	}
+)
 
 func newDownloadDB(driver dbdriver.Driver) *downloaderDB {
 	return &downloaderDB{
 		driver:        driver,
 		errCache:      make(map[string][]TaskErrInfo, 10),
 		taskInfoCache: make(map[string][]TaskDlInfo, 10),
+		failedCache:   make(map[string]*deadLetter, 10),
 	}
 }
 
@@ -92,6 +117,69 @@ func (db *downloaderDB) persistError(id, objName, errMsg string) {
 	db.errCache[id] = db.errCache[id][:0] // clear cache
 }
 
+func (db *downloaderDB) deadLetter(id string) (*deadLetter, error) {
+	dl := &deadLetter{}
+	key := path.Join(downloaderDeadLetter, id)
+	if err := db.driver.Get(downloaderCollection, key, dl); err != nil {
+		if !dbdriver.IsErrNotFound(err) {
+			glog.Error(err)
+			return nil, err
+		}
+	}
+	if cached, ok := db.failedCache[id]; ok {
+		dl.Def = cached.Def
+		dl.Objs = append(dl.Objs, cached.Objs...)
+	}
+	return dl, nil
+}
+
+func (db *downloaderDB) getDeadLetter(id string) (*deadLetter, error) {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+	return db.deadLetter(id)
+}
+
+// persistFailed adds failed download to the job's dead-letter list.
+func (db *downloaderDB) persistFailed(id string, def *DlBase, obj deadLetterObj) {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	cached, ok := db.failedCache[id]
+	if !ok {
+		cached = &deadLetter{Def: def.persistable(), NoSecrets: def.inlineSecrets()}
+		db.failedCache[id] = cached
+	}
+	cached.Objs = append(cached.Objs, obj)
+	if len(cached.Objs) < errCacheSize {
+		return
+	}
+	if err := db.flushFailed(id); err != nil {
+		glog.Error(err)
+	}
+}
+
+func (db *downloaderDB) flushFailed(id string) error {
+	dl, err := db.deadLetter(id) // it will also append failed downloads from cache
+	if err != nil {
+		return err
+	}
+	key := path.Join(downloaderDeadLetter, id)
+	if err := db.driver.Set(downloaderCollection, key, dl); err != nil {
+		glog.Error(err)
+		return err
+	}
+	delete(db.failedCache, id)
+	return nil
+}
+
+func (db *downloaderDB) deleteDeadLetter(id string) {
+	db.mtx.Lock()
+	key := path.Join(downloaderDeadLetter, id)
+	db.driver.Delete(downloaderCollection, key)
+	delete(db.failedCache, id)
+	db.mtx.Unlock()
+}
+
 func (db *downloaderDB) tasks(id string) (tasks []TaskDlInfo, err error) {
 	key := path.Join(downloaderTasks, id)
 	if err := db.driver.Get(downloaderCollection, key, &tasks); err != nil {
@@ -171,14 +259,114 @@ func (db *downloaderDB) flush(id string) error {
 
 		db.taskInfoCache[id] = db.taskInfoCache[id][:0] // clear cache
 	}
+
+	if _, ok := db.failedCache[id]; ok {
+		return db.flushFailed(id)
+	}
+	return nil
+}
+
+func (db *downloaderDB) persistSyncJob(def *syncDef) error {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	key := path.Join(downloaderSync, def.ID)
+	return db.driver.Set(downloaderCollection, key, def)
+}
+
+func (db *downloaderDB) deleteSyncJob(id string) {
+	db.mtx.Lock()
+	key := path.Join(downloaderSync, id)
+	db.driver.Delete(downloaderCollection, key)
+	db.mtx.Unlock()
+}
+
+func (db *downloaderDB) getSyncJobs() ([]*syncDef, error) {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+	values, err := db.driver.GetAll(downloaderCollection, downloaderSync+"/")
+	if err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defs := make([]*syncDef, 0, len(values))
+	for key, value := range values {
+		def := &syncDef{}
+		if err := jsoniter.UnmarshalFromString(value, def); err != nil {
+			glog.Errorf("failed to load sync download job %q: %v", key, err)
+			continue
+		}
+		defs = append(defs, def)
+	}
+	return defs, nil
+}
+
+func (db *downloaderDB) syncRuns(id string) (runs []DlSyncRun, err error) {
+	key := path.Join(downloaderSyncRuns, id)
+	if err := db.driver.Get(downloaderCollection, key, &runs); err != nil {
+		if !dbdriver.IsErrNotFound(err) {
+			glog.Error(err)
+			return nil, err
+		}
+		return nil, nil
+	}
+	return
+}
+
+func (db *downloaderDB) getSyncRuns(id string) (runs []DlSyncRun, err error) {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+	return db.syncRuns(id)
+}
+
+func (db *downloaderDB) persistSyncRun(id string, run DlSyncRun) error {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	runs, err := db.syncRuns(id)
+	if err != nil {
+		return err
+	}
+	runs = append(runs, run)
+	if len(runs) > syncRunsMax {
+		runs = runs[len(runs)-syncRunsMax:]
+	}
+	key := path.Join(downloaderSyncRuns, id)
+	if err := db.driver.Set(downloaderCollection, key, runs); err != nil {
+		glog.Error(err)
+		return err
+	}
 	return nil
 }
 
+// removes tasks, errors, and failed downloads of the previous run of a sync job
+func (db *downloaderDB) clearTasks(id string) {
+	db.mtx.Lock()
+	key := path.Join(downloaderErrors, id)
+	db.driver.Delete(downloaderCollection, key)
+	key = path.Join(downloaderTasks, id)
+	db.driver.Delete(downloaderCollection, key)
+	key = path.Join(downloaderDeadLetter, id)
+	db.driver.Delete(downloaderCollection, key)
+	delete(db.errCache, id)
+	delete(db.taskInfoCache, id)
+	delete(db.failedCache, id)
+	db.mtx.Unlock()
+}
+
 func (db *downloaderDB) delete(id string) {
 	db.mtx.Lock()
 	key := path.Join(downloaderErrors, id)
 	db.driver.Delete(downloaderCollection, key)
 	key = path.Join(downloaderTasks, id)
 	db.driver.Delete(downloaderCollection, key)
+	key = path.Join(downloaderSync, id)
+	db.driver.Delete(downloaderCollection, key)
+	key = path.Join(downloaderSyncRuns, id)
+	db.driver.Delete(downloaderCollection, key)
+	key = path.Join(downloaderDeadLetter, id)
+	db.driver.Delete(downloaderCollection, key)
+	delete(db.failedCache, id)
 	db.mtx.Unlock()
 }
@@ -5,6 +5,8 @@
 package downloader
 
 import (
+	"net/http"
+
 	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
@@ -46,12 +48,14 @@ type (
 	WebResource struct {
 		ObjName string
 		Link    string
+		Header  http.Header // request headers of the job (optional)
 	}
 
 	DstElement struct {
 		ObjName string
 		Version string
 		Link    string
+		Header  http.Header
 	}
 
 	DiffResolverResult struct {
@@ -167,6 +171,7 @@ func (dr *DiffResolver) PushDst(v interface{}) {
 		d = &DstElement{
 			ObjName: x.ObjName,
 			Link:    x.Link,
+			Header:  x.Header,
 		}
 	default:
 		cos.Assertf(false, "%T", x)
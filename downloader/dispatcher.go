@@ -240,6 +240,7 @@ func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
 					diffResolver.PushDst(&WebResource{
 						ObjName: obj.objName,
 						Link:    obj.link,
+						Header:  job.header(),
 					})
 				} else {
 					diffResolver.PushDst(&BackendResource{
@@ -295,7 +296,7 @@ func (d *dispatcher) dispatchDownload(job DlJob) (ok bool) {
 			if result.Action == DiffResolverDelete {
 				cos.Assert(job.Sync())
 				if _, err := d.parent.t.EvictObject(result.Src); err != nil {
-					t.markFailed(err.Error())
+					t.fail(err.Error())
 				} else {
 					dlStore.incFinished(job.ID())
 				}
@@ -445,6 +446,12 @@ func (d *dispatcher) handleAbort(req *request) {
 	}
 
 	dlStore.setAborted(req.id)
+	// sync job in-between runs: nothing else to wait for
+	if sj := unregSyncJob(req.id); sj != nil && !sj.running.Load() {
+		if err := dlStore.markFinished(req.id); err != nil {
+			glog.Errorf("%s: %v", sj, err)
+		}
+	}
 	req.writeResp(nil)
 }
 
@@ -475,11 +482,18 @@ func (d *dispatcher) handleStatus(req *request) {
 		sort.Sort(TaskErrByName(dlErrors))
 	}
 
+	syncRuns, err := dlStore.getSyncRuns(req.id)
+	if err != nil {
+		req.writeErrResp(err, http.StatusInternalServerError)
+		return
+	}
+
 	req.writeResp(&DlStatusResp{
 		DlJobInfo:     jInfo.ToDlJobInfo(),
 		CurrentTasks:  currentTasks,
 		FinishedTasks: finishedTasks,
 		Errs:          dlErrors,
+		SyncRuns:      syncRuns,
 	})
 }
 
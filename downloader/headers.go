@@ -0,0 +1,317 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Request headers and credentials of the download job (see `DlBase.Headers` and `DlBase.Auth`).
+//
+// To avoid passing secrets in plain text, header values, bearer token, and password
+// may reference credentials that are resolved by each target when the job starts:
+//   * "env:NAME"  - value of the environment variable NAME; the name must start with
+//                   the configured `downloader.creds_env_prefix`
+//   * "file:NAME" - contents of the file NAME (with trailing whitespace trimmed) in the
+//                   configured `downloader.creds_dir`; no "..", no symbolic links
+// Header value is a template that can embed references, e.g. "Bearer {{env:TOKEN}}".
+// References are disallowed unless configured.
+
+const (
+	DlAuthBearer = "bearer"
+	DlAuthBasic  = "basic"
+
+	credEnvPrefix  = "env:"
+	credFilePrefix = "file:"
+
+	redacted = "<redacted>"
+)
+
+var (
+	credRefRegex = regexp.MustCompile(`\{\{\s*((?:env|file):[^}\s]+)\s*\}\}`)
+
+	// substrings of the header names that carry secrets
+	sensitiveHeaders = []string{"authorization", "cookie", "token", "secret", "key", "signature", "password"}
+)
+
+type DlAuth struct {
+	Type     string `json:"type,omitempty"`     // one of: "bearer", "basic"
+	Token    string `json:"token,omitempty"`    // bearer token or credential reference
+	Username string `json:"username,omitempty"` // basic auth
+	Password string `json:"password,omitempty"` // basic auth: password or credential reference
+}
+
+func (a *DlAuth) Validate() error {
+	switch a.Type {
+	case "":
+		if a.Token != "" || a.Username != "" || a.Password != "" {
+			return errors.New("missing 'auth.type'")
+		}
+	case DlAuthBearer:
+		if a.Token == "" {
+			return errors.New("missing 'auth.token' (bearer)")
+		}
+	case DlAuthBasic:
+		if a.Username == "" {
+			return errors.New("missing 'auth.username' (basic)")
+		}
+	default:
+		return fmt.Errorf("invalid 'auth.type' %q (expecting %q or %q)", a.Type, DlAuthBearer, DlAuthBasic)
+	}
+	return nil
+}
+
+// inlineSecret returns true if the credentials are passed in plain text (rather than referenced)
+func (a *DlAuth) inlineSecret() bool {
+	switch a.Type {
+	case DlAuthBearer:
+		return !isCredRef(a.Token)
+	case DlAuthBasic:
+		return a.Password != "" && !isCredRef(a.Password)
+	}
+	return false
+}
+
+func validateHeaders(headers map[string]string) error {
+	for name := range headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+	return nil
+}
+
+// validateCreds makes sure that the job references only the configured credentials.
+func (b *DlBase) validateCreds(conf *cmn.DownloaderConf) error {
+	for name, tmpl := range b.Headers {
+		for _, m := range credRefRegex.FindAllStringSubmatch(tmpl, -1) {
+			if err := checkCredRef(conf, m[1]); err != nil {
+				return fmt.Errorf("header %q: %v", name, err)
+			}
+		}
+	}
+	for _, v := range []string{b.Auth.Token, b.Auth.Password} {
+		if isCredRef(v) {
+			if err := checkCredRef(conf, v); err != nil {
+				return fmt.Errorf("auth: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// requestHeader builds HTTP headers of the job, resolving credential references.
+func (b *DlBase) requestHeader(conf *cmn.DownloaderConf) (http.Header, error) {
+	if len(b.Headers) == 0 && b.Auth.Type == "" {
+		return nil, nil
+	}
+	header := make(http.Header, len(b.Headers)+1)
+	for name, tmpl := range b.Headers {
+		value, err := expandHeader(conf, tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("header %q: %v", name, err)
+		}
+		header.Set(name, value)
+	}
+	switch b.Auth.Type {
+	case DlAuthBearer:
+		token, err := resolveCred(conf, b.Auth.Token)
+		if err != nil {
+			return nil, fmt.Errorf("bearer token: %v", err)
+		}
+		header.Set(http.CanonicalHeaderKey("Authorization"), "Bearer "+token)
+	case DlAuthBasic:
+		password, err := resolveCred(conf, b.Auth.Password)
+		if err != nil {
+			return nil, fmt.Errorf("basic auth password: %v", err)
+		}
+		creds := base64.StdEncoding.EncodeToString([]byte(b.Auth.Username + ":" + password))
+		header.Set(http.CanonicalHeaderKey("Authorization"), "Basic "+creds)
+	}
+	return header, nil
+}
+
+// redactedHeaders returns request headers of the job (to show in job info and
+// listings) with credentials and values of sensitive headers redacted.
+func (b *DlBase) redactedHeaders() map[string]string {
+	if len(b.Headers) == 0 && b.Auth.Type == "" {
+		return nil
+	}
+	headers := make(map[string]string, len(b.Headers)+1)
+	for name, tmpl := range b.Headers {
+		if isSensitiveHeader(name) {
+			tmpl = redacted
+		}
+		headers[http.CanonicalHeaderKey(name)] = tmpl
+	}
+	switch b.Auth.Type {
+	case DlAuthBearer:
+		headers[http.CanonicalHeaderKey("Authorization")] = "Bearer " + redacted
+	case DlAuthBasic:
+		headers[http.CanonicalHeaderKey("Authorization")] = "Basic " + redacted
+	}
+	return headers
+}
+
+// inlineSecrets returns true if the job carries secrets in plain text - inline
+// credentials or values of sensitive headers that do not reference credentials
+func (b *DlBase) inlineSecrets() bool {
+	if b.Auth.inlineSecret() {
+		return true
+	}
+	for name, tmpl := range b.Headers {
+		if isInlineHeader(name, tmpl) {
+			return true
+		}
+	}
+	return false
+}
+
+// persistable returns a copy of the job's definition that is safe to store on disk:
+// inline secrets are omitted while credential references are kept
+func (b *DlBase) persistable() (def DlBase) {
+	def = *b
+	if def.Auth.inlineSecret() {
+		def.Auth.Token, def.Auth.Password = "", ""
+	}
+	if len(b.Headers) == 0 {
+		return
+	}
+	def.Headers = make(map[string]string, len(b.Headers))
+	for name, tmpl := range b.Headers {
+		if !isInlineHeader(name, tmpl) {
+			def.Headers[name] = tmpl
+		}
+	}
+	return
+}
+
+func isInlineHeader(name, tmpl string) bool {
+	return tmpl != "" && isSensitiveHeader(name) && !credRefRegex.MatchString(tmpl)
+}
+
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveHeaders {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// expands `{{env:NAME}}` and `{{file:PATH}}` references embedded in the header value
+func expandHeader(conf *cmn.DownloaderConf, tmpl string) (value string, err error) {
+	value = credRefRegex.ReplaceAllStringFunc(tmpl, func(ref string) string {
+		if err != nil {
+			return ""
+		}
+		var v string
+		v, err = resolveCred(conf, credRefRegex.FindStringSubmatch(ref)[1])
+		return v
+	})
+	return
+}
+
+func isCredRef(v string) bool {
+	return strings.HasPrefix(v, credEnvPrefix) || strings.HasPrefix(v, credFilePrefix)
+}
+
+// checks that credential reference ("env:NAME" or "file:NAME") is allowed by configuration
+func checkCredRef(conf *cmn.DownloaderConf, v string) error {
+	switch {
+	case strings.HasPrefix(v, credEnvPrefix):
+		name := strings.TrimPrefix(v, credEnvPrefix)
+		if conf.CredsEnvPrefix == "" {
+			return fmt.Errorf("%q: environment variables are not allowed (downloader.creds_env_prefix not configured)", v)
+		}
+		if !strings.HasPrefix(name, conf.CredsEnvPrefix) || name == conf.CredsEnvPrefix {
+			return fmt.Errorf("%q: environment variable name must start with %q", v, conf.CredsEnvPrefix)
+		}
+	case strings.HasPrefix(v, credFilePrefix):
+		name := strings.TrimPrefix(v, credFilePrefix)
+		if conf.CredsDir == "" {
+			return fmt.Errorf("%q: credential files are not allowed (downloader.creds_dir not configured)", v)
+		}
+		if name == "" || filepath.IsAbs(name) || filepath.Clean(name) != name ||
+			name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("%q: expecting file name relative to downloader.creds_dir", v)
+		}
+	default:
+		return fmt.Errorf("invalid credential reference %q", v)
+	}
+	return nil
+}
+
+// resolves credential reference ("env:NAME" or "file:NAME"); any other value is
+// considered an inline secret and is returned as is
+func resolveCred(conf *cmn.DownloaderConf, v string) (string, error) {
+	if !isCredRef(v) {
+		return v, nil
+	}
+	if err := checkCredRef(conf, v); err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(v, credEnvPrefix) {
+		name := strings.TrimPrefix(v, credEnvPrefix)
+		cred, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %q is not set", name)
+		}
+		return cred, nil
+	}
+	fqn, err := credFile(conf.CredsDir, strings.TrimPrefix(v, credFilePrefix))
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(fqn)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), " \t\r\n"), nil
+}
+
+// returns path of the credential file making sure it does not escape the
+// credentials directory via symbolic links
+func credFile(dir, name string) (string, error) {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	fqn := filepath.Join(realDir, name)
+	real, err := filepath.EvalSymlinks(fqn)
+	if err != nil {
+		return "", err
+	}
+	if real != fqn {
+		return "", fmt.Errorf("credential file %q: symbolic links are not allowed", name)
+	}
+	return fqn, nil
+}
+
+// redactLink hides password and query (that may contain signature) of the link
+func redactLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	if _, ok := u.User.Password(); !ok && u.RawQuery == "" {
+		return link
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = redacted
+	}
+	return u.Redacted()
+}
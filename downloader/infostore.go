@@ -77,6 +77,7 @@ func (is *infoStore) setJob(id string, job DlJob) {
 		Total:       job.Len(),
 		Description: job.Description(),
 		StartedTime: time.Now(),
+		Headers:     job.definition().redactedHeaders(),
 	}
 
 	is.Lock()
@@ -84,6 +85,22 @@ func (is *infoStore) setJob(id string, job DlJob) {
 	is.Unlock()
 }
 
+// setSyncJob adds idle (between runs) sync job restored upon target restart
+func (is *infoStore) setSyncJob(id string, body *DlSyncBody) {
+	jInfo := &downloadJobInfo{
+		ID:          id,
+		Total:       -1,
+		Description: body.Describe(),
+		StartedTime: time.Now(),
+		Headers:     body.redactedHeaders(),
+	}
+	jInfo.AllDispatched.Store(true)
+
+	is.Lock()
+	is.jobInfo[id] = jInfo
+	is.Unlock()
+}
+
 func (is *infoStore) incFinished(id string) {
 	jInfo, err := is.getJob(id)
 	debug.AssertNoErr(err)
@@ -144,6 +161,10 @@ func (is *infoStore) housekeep() time.Duration {
 
 	is.Lock()
 	for id, jInfo := range is.jobInfo {
+		// still running (including scheduled sync jobs in-between runs)
+		if jInfo.FinishedTime.Load().IsZero() {
+			continue
+		}
 		if time.Since(jInfo.FinishedTime.Load()) > interval {
 			is.delJob(id)
 		}
@@ -7,6 +7,7 @@ package downloader
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"path"
 	"strings"
 	"time"
@@ -31,6 +32,7 @@ var (
 	_ DlJob = (*sliceDlJob)(nil)
 	_ DlJob = (*backendDlJob)(nil)
 	_ DlJob = (*rangeDlJob)(nil)
+	_ DlJob = (*retryDlJob)(nil)
 )
 
 type (
@@ -38,6 +40,7 @@ type (
 		objName    string
 		link       string
 		fromRemote bool
+		cksum      *cos.Cksum // to verify the downloaded object (optional)
 	}
 
 	DlJob interface {
@@ -66,6 +69,15 @@ type (
 		// via tryAcquire and release
 		throttler() *throttler
 
+		// retry policy for failed downloads
+		retry() *retryPolicy
+
+		// the job's request, to persist along with failed downloads (see `retryDlJob`)
+		definition() *DlBase
+
+		// HTTP headers (including resolved credentials) to download from external source
+		header() http.Header
+
 		// job cleanup
 		cleanup()
 	}
@@ -77,6 +89,9 @@ type (
 		description string
 		t           *throttler
 		dlXact      *Downloader
+		rp          retryPolicy
+		def         DlBase
+		hdr         http.Header
 
 		// notif
 		notif *NotifDownload
@@ -93,6 +108,11 @@ type (
 	singleDlJob struct {
 		*sliceDlJob
 	}
+	// re-queues failed downloads of another (finished) job
+	retryDlJob struct {
+		*sliceDlJob
+		srcID string
+	}
 
 	rangeDlJob struct {
 		baseDlJob
@@ -115,6 +135,13 @@ type (
 		done              bool
 	}
 
+	retryPolicy struct {
+		attempts   int
+		backoff    time.Duration
+		maxBackoff time.Duration
+		codes      map[int]struct{} // retriable HTTP status codes; nil - all but `terminalStatuses`
+	}
+
 	downloadJobInfo struct {
 		ID          string `json:"id"`
 		Description string `json:"description"`
@@ -130,6 +157,8 @@ type (
 
 		StartedTime  time.Time   `json:"started_time"`
 		FinishedTime atomic.Time `json:"finished_time"`
+
+		Headers map[string]string `json:"headers,omitempty"` // redacted
 	}
 )
 
@@ -137,14 +166,19 @@ type (
 // baseDlJob //
 ///////////////
 
-func newBaseDlJob(t cluster.Target, id string, bck *cluster.Bck, timeout, desc string, limits DlLimits, dlXact *Downloader) *baseDlJob {
+func newBaseDlJob(t cluster.Target, id string, bck *cluster.Bck, def *DlBase, desc string, dlXact *Downloader) (*baseDlJob, error) {
 	// TODO: this might be inaccurate if we download 1 or 2 objects because then
 	//  other targets will have limits but will not use them.
+	limits := def.Limits
 	if limits.BytesPerHour > 0 {
 		limits.BytesPerHour /= t.Sowner().Get().CountActiveTargets()
 	}
 
-	td, _ := time.ParseDuration(timeout)
+	hdr, err := def.requestHeader(&cmn.GCO.Get().Downloader)
+	if err != nil {
+		return nil, err
+	}
+	td, _ := time.ParseDuration(def.Timeout)
 	return &baseDlJob{
 		id:          id,
 		bck:         bck,
@@ -152,7 +186,10 @@ func newBaseDlJob(t cluster.Target, id string, bck *cluster.Bck, timeout, desc s
 		description: desc,
 		t:           newThrottler(limits),
 		dlXact:      dlXact,
-	}
+		rp:          newRetryPolicy(&def.Retry),
+		def:         *def,
+		hdr:         hdr,
+	}, nil
 }
 
 func (j *baseDlJob) ID() string             { return j.id }
@@ -193,6 +230,9 @@ func (j *baseDlJob) ActiveStats() (*DlStatusResp, error) {
 
 func (*baseDlJob) checkObj(string) bool    { debug.Assert(false); return false }
 func (j *baseDlJob) throttler() *throttler { return j.t }
+func (j *baseDlJob) retry() *retryPolicy   { return &j.rp }
+func (j *baseDlJob) definition() *DlBase   { return &j.def }
+func (j *baseDlJob) header() http.Header   { return j.hdr }
 
 func (j *baseDlJob) cleanup() {
 	j.throttler().stop()
@@ -241,7 +281,10 @@ func newMultiDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlMul
 		objs cos.SimpleKVs
 		err  error
 	)
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, dlXact)
+	base, err := newBaseDlJob(t, id, bck, &payload.DlBase, payload.Describe(), dlXact)
+	if err != nil {
+		return nil, err
+	}
 	if objs, err = payload.ExtractPayload(); err != nil {
 		return nil, err
 	}
@@ -261,7 +304,10 @@ func newSingleDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlSi
 		objs cos.SimpleKVs
 		err  error
 	)
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, dlXact)
+	base, err := newBaseDlJob(t, id, bck, &payload.DlBase, payload.Describe(), dlXact)
+	if err != nil {
+		return nil, err
+	}
 	if objs, err = payload.ExtractPayload(); err != nil {
 		return nil, err
 	}
@@ -276,6 +322,64 @@ func (j *singleDlJob) String() (s string) {
 	return "single-" + j.baseDlJob.String()
 }
 
+// newRetryDlJob re-queues downloads that failed (and got persisted) in the
+// job `srcID`. Returns empty job when there's nothing to retry on this target.
+func newRetryDlJob(t cluster.Target, id, srcID string, dlXact *Downloader) (*retryDlJob, error) {
+	if jInfo, err := dlStore.getJob(srcID); err == nil && jInfo.ToDlJobInfo().JobRunning() {
+		return nil, fmt.Errorf("download job %q is still running", srcID)
+	}
+	dl, err := dlStore.getDeadLetter(srcID)
+	if err != nil {
+		return nil, err
+	}
+	if dl.NoSecrets {
+		return nil, fmt.Errorf("cannot retry failed downloads of %q: inline credentials of the job are not persisted "+
+			"(resubmit the job with credentials referenced as \"env:NAME\" or \"file:PATH\")", srcID)
+	}
+	var (
+		bck  = cluster.CloneBck(&dl.Def.Bck)
+		objs = make([]dlObj, 0, len(dl.Objs))
+	)
+	if len(dl.Objs) > 0 {
+		if err := bck.Init(t.Bowner()); err != nil {
+			return nil, err
+		}
+		smap := t.Sowner().Get()
+		for _, o := range dl.Objs {
+			obj, err := makeDlObj(smap, t.SID(), bck, o.ObjName, o.Link)
+			if err != nil {
+				if err == errInvalidTarget {
+					continue
+				}
+				return nil, err
+			}
+			if o.CksumValue != "" {
+				obj.cksum = cos.NewCksum(o.CksumType, o.CksumValue)
+			}
+			objs = append(objs, obj)
+		}
+	}
+	base, err := newBaseDlJob(t, id, bck, &dl.Def, fmt.Sprintf("retry failed downloads of %s", srcID), dlXact)
+	if err != nil {
+		return nil, err
+	}
+	return &retryDlJob{sliceDlJob: &sliceDlJob{baseDlJob: *base, objs: objs}, srcID: srcID}, nil
+}
+
+func (j *retryDlJob) String() (s string) {
+	return "retry-" + j.srcID + "-" + j.baseDlJob.String()
+}
+
+// Failed downloads of the source job are removed only when the retry is done - those
+// that fail again are persisted by the retry job itself. Aborted (or interrupted)
+// retry keeps them, to be retried again.
+func (j *retryDlJob) cleanup() {
+	j.sliceDlJob.cleanup()
+	if jInfo, err := dlStore.getJob(j.ID()); err == nil && !jInfo.Aborted.Load() {
+		dlStore.deleteDeadLetter(j.srcID)
+	}
+}
+
 ////////////////
 // rangeDlJob //
 ////////////////
@@ -292,7 +396,10 @@ func newRangeDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlRan
 		return nil, err
 	}
 
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, dlXact)
+	base, err := newBaseDlJob(t, id, bck, &payload.DlBase, payload.Describe(), dlXact)
+	if err != nil {
+		return nil, err
+	}
 	cnt, err := countObjects(t, pt, payload.Subdir, base.bck)
 	if err != nil {
 		return nil, err
@@ -359,7 +466,10 @@ func newBackendDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlB
 	} else if bck.IsHTTP() {
 		return nil, errors.New("bucket download does not support HTTP buckets")
 	}
-	base := newBaseDlJob(t, id, bck, payload.Timeout, payload.Describe(), payload.Limits, dlXact)
+	base, err := newBaseDlJob(t, id, bck, &payload.DlBase, payload.Describe(), dlXact)
+	if err != nil {
+		return nil, err
+	}
 	job := &backendDlJob{
 		baseDlJob: *base,
 		t:         t,
@@ -433,6 +543,60 @@ func (j *backendDlJob) getNextObjs() error {
 	return nil
 }
 
+/////////////////
+// retryPolicy //
+/////////////////
+
+const defaultMaxBackoff = time.Minute
+
+func newRetryPolicy(r *DlRetry) (rp retryPolicy) {
+	rp.attempts = r.MaxAttempts
+	rp.backoff, _ = time.ParseDuration(r.Backoff)
+	rp.maxBackoff, _ = time.ParseDuration(r.MaxBackoff)
+	if rp.maxBackoff == 0 {
+		rp.maxBackoff = defaultMaxBackoff
+	}
+	if len(r.Codes) > 0 {
+		rp.codes = make(map[int]struct{}, len(r.Codes))
+		for _, code := range r.Codes {
+			rp.codes[code] = struct{}{}
+		}
+	}
+	return
+}
+
+// returns the configured number of attempts or, if not specified, the given default
+func (rp *retryPolicy) maxAttempts(dflt int) int {
+	if rp.attempts > 0 {
+		return rp.attempts
+	}
+	return dflt
+}
+
+func (rp *retryPolicy) retriable(status int) bool {
+	if rp.codes != nil {
+		_, ok := rp.codes[status]
+		return ok
+	}
+	_, terminal := terminalStatuses[status]
+	return !terminal
+}
+
+// exponential backoff: delay before the (attempt+1)-th retry
+func (rp *retryPolicy) delay(attempt int) time.Duration {
+	if rp.backoff == 0 {
+		return 0
+	}
+	d := rp.backoff
+	for i := 0; i < attempt && d < rp.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > rp.maxBackoff {
+		d = rp.maxBackoff
+	}
+	return d
+}
+
 /////////////////////
 // downloadJobInfo //
 /////////////////////
@@ -450,6 +614,7 @@ func (d *downloadJobInfo) ToDlJobInfo() DlJobInfo {
 		Aborted:       d.Aborted.Load(),
 		StartedTime:   d.StartedTime,
 		FinishedTime:  d.FinishedTime.Load(),
+		Headers:       d.Headers,
 	}
 }
 
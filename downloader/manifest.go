@@ -0,0 +1,473 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Manifest job downloads the objects listed in the manifest - CSV or JSONL object
+// stored in AIS bucket. Each target stream-reads the entire manifest and downloads
+// only the entries it owns (by HRW), so the job scales to tens of millions of links
+// without passing them in the request body.
+//
+// Manifest entry (CSV column or JSON field):
+//   * "url"           - link to download (required)
+//   * "object_name"   - name of the destination object (default: the last element of the URL's path)
+//   * "checksum"      - (hex) checksum to verify the downloaded object (optional)
+//   * "checksum_type" - type of the checksum (default: "md5")
+// CSV manifest must start with the header row that names the columns.
+//
+// Reading the manifest is paced by downloading (that is, by throttling) and may,
+// therefore, take hours. That's why a target that does not own the manifest
+// first copies it into a local work file.
+
+const (
+	DlManifestCSV   = "csv"
+	DlManifestJSONL = "jsonl"
+
+	maxManifestLine = cos.MiB
+)
+
+// interface guard
+var _ DlJob = (*manifestDlJob)(nil)
+
+type (
+	manifestEntry struct {
+		URL       string `json:"url"`
+		ObjName   string `json:"object_name"`
+		Cksum     string `json:"checksum"`
+		CksumType string `json:"checksum_type"`
+	}
+
+	manifestReader interface {
+		next() (*manifestEntry, error) // returns io.EOF when there are no more entries
+	}
+
+	csvManifest struct {
+		r    *csv.Reader
+		cols map[string]int
+	}
+	jsonlManifest struct {
+		sc *bufio.Scanner
+	}
+
+	manifestDlJob struct {
+		baseDlJob
+		t       cluster.Target
+		src     string // manifest bucket/object
+		format  string
+		rc      io.ReadCloser
+		mr      manifestReader // nil until the remote manifest gets copied (see `spool`)
+		workFQN string         // local copy of the remote manifest
+		objs    []dlObj        // objects' metas which are ready to be downloaded
+		line    int            // manifest entries read so far
+		done    bool
+		closed  bool
+	}
+
+	// manifest that is being read from another target; the request gets
+	// canceled when the other side stalls for longer than `idle`
+	remoteManifest struct {
+		body   io.ReadCloser
+		cancel func()
+		timer  *time.Timer
+		idle   time.Duration
+	}
+
+	// validates checksum (provided by the manifest) upon reading the entire object
+	cksumReader struct {
+		r     io.ReadCloser
+		expct *cos.Cksum
+		h     *cos.CksumHash
+		name  string
+	}
+)
+
+// manifestFormat returns the explicitly specified format or the one determined by
+// the manifest's extension.
+func manifestFormat(format, objName string) (string, error) {
+	if format != "" {
+		if format != DlManifestCSV && format != DlManifestJSONL {
+			return "", fmt.Errorf("invalid manifest format %q (expecting %q or %q)", format, DlManifestCSV, DlManifestJSONL)
+		}
+		return format, nil
+	}
+	switch strings.ToLower(path.Ext(objName)) {
+	case ".csv":
+		return DlManifestCSV, nil
+	case ".jsonl", ".ndjson":
+		return DlManifestJSONL, nil
+	default:
+		return "", fmt.Errorf("cannot determine format of the manifest %q - please specify 'format'", objName)
+	}
+}
+
+func newManifestReader(r io.Reader, format string) (manifestReader, error) {
+	if format == DlManifestJSONL {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*cos.KiB), maxManifestLine)
+		return &jsonlManifest{sc: sc}, nil
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV manifest header: %v", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := cols["url"]; !ok {
+		return nil, errors.New("CSV manifest header does not contain required column \"url\"")
+	}
+	return &csvManifest{r: cr, cols: cols}, nil
+}
+
+/////////////////
+// csvManifest //
+/////////////////
+
+func (m *csvManifest) next() (*manifestEntry, error) {
+	record, err := m.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	return &manifestEntry{
+		URL:       m.col(record, "url"),
+		ObjName:   m.col(record, "object_name"),
+		Cksum:     m.col(record, "checksum"),
+		CksumType: m.col(record, "checksum_type"),
+	}, nil
+}
+
+func (m *csvManifest) col(record []string, name string) string {
+	if i, ok := m.cols[name]; ok && i < len(record) {
+		return strings.TrimSpace(record[i])
+	}
+	return ""
+}
+
+///////////////////
+// jsonlManifest //
+///////////////////
+
+func (m *jsonlManifest) next() (*manifestEntry, error) {
+	for m.sc.Scan() {
+		line := m.sc.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		entry := &manifestEntry{}
+		if err := jsoniter.Unmarshal(line, entry); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	}
+	if err := m.sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+///////////////////
+// manifestEntry //
+///////////////////
+
+func (e *manifestEntry) objName() string {
+	if e.ObjName != "" {
+		return e.ObjName
+	}
+	return path.Base(e.URL)
+}
+
+func (e *manifestEntry) cksum() (*cos.Cksum, error) {
+	if e.Cksum == "" {
+		return nil, nil
+	}
+	ty := e.CksumType
+	if ty == "" {
+		ty = cos.ChecksumMD5
+	}
+	if err := cos.ValidateCksumType(ty); err != nil || ty == cos.ChecksumNone {
+		return nil, fmt.Errorf("invalid checksum type %q", ty)
+	}
+	return cos.NewCksum(ty, strings.ToLower(e.Cksum)), nil
+}
+
+///////////////////
+// manifestDlJob //
+///////////////////
+
+func newManifestDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlManifestBody, dlXact *Downloader) (*manifestDlJob, error) {
+	format, err := manifestFormat(payload.Format, payload.ManifestObj)
+	if err != nil {
+		return nil, err
+	}
+	base, err := newBaseDlJob(t, id, bck, &payload.DlBase, payload.Describe(), dlXact)
+	if err != nil {
+		return nil, err
+	}
+	mbck := cluster.CloneBck(&payload.ManifestBck)
+	if err := mbck.Init(t.Bowner()); err != nil {
+		return nil, err
+	}
+	rc, workFQN, err := openManifest(t, mbck, payload.ManifestObj)
+	if err != nil {
+		return nil, err
+	}
+	j := &manifestDlJob{
+		baseDlJob: *base,
+		t:         t,
+		src:       mbck.String() + "/" + payload.ManifestObj,
+		format:    format,
+		rc:        rc,
+		workFQN:   workFQN,
+	}
+	if workFQN == "" {
+		if err := j.initReader(); err != nil {
+			cos.Close(rc)
+			return nil, err
+		}
+	}
+	return j, nil
+}
+
+// Total number of objects to download by a target is not known in advance.
+func (*manifestDlJob) Len() int { return -1 }
+
+func (j *manifestDlJob) String() (s string) {
+	return fmt.Sprintf("manifest-%s-%s", &j.baseDlJob, j.src)
+}
+
+func (j *manifestDlJob) genNext() ([]dlObj, bool, error) {
+	if j.done {
+		return nil, false, nil
+	}
+	if err := j.getNextObjs(); err != nil {
+		return nil, false, err
+	}
+	return j.objs, true, nil
+}
+
+func (j *manifestDlJob) getNextObjs() error {
+	var (
+		smap = j.t.Sowner().Get()
+		sid  = j.t.SID()
+	)
+	if j.mr == nil {
+		if err := j.spool(); err != nil {
+			return err
+		}
+	}
+	j.objs = j.objs[:0]
+	for len(j.objs) < downloadBatchSize {
+		entry, err := j.mr.next()
+		if err == io.EOF {
+			j.done = true
+			j.close()
+			break
+		}
+		j.line++
+		if err != nil {
+			return fmt.Errorf("%s: failed to read entry #%d: %v", j.src, j.line, err)
+		}
+		if entry.URL == "" {
+			return fmt.Errorf("%s: entry #%d: missing \"url\"", j.src, j.line)
+		}
+		cksum, err := entry.cksum()
+		if err != nil {
+			return fmt.Errorf("%s: entry #%d: %v", j.src, j.line, err)
+		}
+		obj, err := makeDlObj(smap, sid, j.bck, entry.objName(), entry.URL)
+		if err != nil {
+			if err == errInvalidTarget {
+				continue
+			}
+			return err
+		}
+		obj.cksum = cksum
+		j.objs = append(j.objs, obj)
+	}
+	return nil
+}
+
+func (j *manifestDlJob) initReader() (err error) {
+	j.mr, err = newManifestReader(bufio.NewReader(j.rc), j.format)
+	return
+}
+
+// spool copies the remote manifest into the local work file, and then reads the
+// manifest from the latter
+func (j *manifestDlJob) spool() error {
+	fh, err := cos.CreateFile(j.workFQN)
+	if err != nil {
+		return err
+	}
+	buf, slab := j.t.PageMM().Alloc()
+	_, err = io.CopyBuffer(fh, j.rc, buf)
+	slab.Free(buf)
+	cos.Close(j.rc)
+	j.rc = fh // (closed and removed by `close`)
+	if err == nil {
+		_, err = fh.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: failed to copy manifest: %v", j.src, err)
+	}
+	return j.initReader()
+}
+
+func (j *manifestDlJob) close() {
+	if !j.closed {
+		cos.Close(j.rc)
+		if j.workFQN != "" {
+			if err := cos.RemoveFile(j.workFQN); err != nil {
+				glog.Error(err)
+			}
+		}
+		j.closed = true
+	}
+}
+
+func (j *manifestDlJob) cleanup() {
+	j.close()
+	j.baseDlJob.cleanup()
+}
+
+// openManifest opens the manifest locally or, if need be, requests it from the
+// target that owns it - in the latter case, it also returns the work file to
+// copy the manifest into.
+func openManifest(t cluster.Target, bck *cluster.Bck, objName string) (rc io.ReadCloser, workFQN string, err error) {
+	lom := cluster.AllocLOM(objName)
+	defer cluster.FreeLOM(lom)
+	if err = lom.InitBck(bck.Bucket()); err != nil {
+		return
+	}
+	lom.Lock(false)
+	if err = lom.Load(false /*cache it*/, true /*locked*/); err == nil {
+		rc, err = cos.NewFileHandle(lom.FQN)
+		lom.Unlock(false)
+		return
+	} else if !cmn.IsObjNotExist(err) {
+		lom.Unlock(false)
+		return
+	}
+	lom.Unlock(false)
+
+	tsi, err := cluster.HrwTarget(lom.Uname(), t.Sowner().Get())
+	if err != nil {
+		return
+	}
+	if tsi.ID() == t.SID() {
+		err = cmn.NewErrNotFound("%s: manifest %s", t, lom.FullName())
+		return
+	}
+	if rc, err = getRemoteManifest(t, tsi, bck, objName); err != nil {
+		return
+	}
+	if glog.V(4) {
+		glog.Infof("%s: reading manifest %s from %s", t, lom, tsi)
+	}
+	workFQN = fs.CSM.Gen(lom, fs.WorkfileType, fs.WorkfileDlManifest)
+	return
+}
+
+// NOTE: not using `t.DataClient()` as its (overall) timeout includes reading
+// the response body
+func getRemoteManifest(t cluster.Target, tsi *cluster.Snode, bck *cluster.Bck, objName string) (io.ReadCloser, error) {
+	config := cmn.GCO.Get()
+	client := cmn.NewClient(cmn.TransportArgs{
+		UseHTTPS:   config.Net.HTTP.UseHTTPS,
+		SkipVerify: config.Net.HTTP.SkipVerify,
+	})
+	reqArgs := cmn.HreqArgs{
+		Method: http.MethodGet,
+		Base:   tsi.URL(cmn.NetIntraData),
+		Path:   apc.URLPathObjects.Join(bck.Name, objName),
+		Query:  bck.AddToQuery(nil),
+		Header: http.Header{apc.HdrCallerID: []string{t.SID()}},
+	}
+	req, _, cancel, err := reqArgs.ReqWithCancel()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req) // nolint:bodyclose // closed by the job
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		cancel()
+		return nil, fmt.Errorf("%s: failed to read manifest %s/%s from %s: %s (%d)", t, bck, objName, tsi,
+			string(b), resp.StatusCode)
+	}
+	return &remoteManifest{body: resp.Body, cancel: cancel, idle: config.Timeout.MaxHostBusy.D()}, nil
+}
+
+////////////////////
+// remoteManifest //
+////////////////////
+
+func (rm *remoteManifest) Read(p []byte) (int, error) {
+	if rm.timer == nil {
+		rm.timer = time.AfterFunc(rm.idle, rm.cancel)
+	} else {
+		rm.timer.Reset(rm.idle)
+	}
+	return rm.body.Read(p)
+}
+
+func (rm *remoteManifest) Close() error {
+	if rm.timer != nil {
+		rm.timer.Stop()
+	}
+	err := rm.body.Close()
+	rm.cancel()
+	return err
+}
+
+/////////////////
+// cksumReader //
+/////////////////
+
+func newCksumReader(r io.ReadCloser, expct *cos.Cksum, name string) *cksumReader {
+	return &cksumReader{r: r, expct: expct, h: cos.NewCksumHash(expct.Type()), name: name}
+}
+
+func (cr *cksumReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.h.H.Write(p[:n])
+	if err == io.EOF {
+		cr.h.Finalize()
+		if !cr.h.Equal(cr.expct) {
+			err = cos.NewBadDataCksumError(cr.expct, &cr.h.Cksum, cr.name)
+		}
+	}
+	return
+}
+
+func (cr *cksumReader) Close() error { return cr.r.Close() }
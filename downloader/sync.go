@@ -0,0 +1,259 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/nl"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Sync job mirrors remote bucket on a schedule. Each run is a regular backend
+// download job (with the same ID) that downloads new and changed objects
+// (see `DiffResolver`) and, optionally, removes local objects that vanished
+// upstream. Between runs the job remains "running"; it finishes only when aborted.
+// Job definition and per-run stats are persisted, and the schedule is restored
+// upon target restart (see `InitSync`).
+
+// after restart, delay the first run to let the cluster settle
+const syncRestartDelay = time.Minute
+
+// interface guard
+var _ DlJob = (*syncDlJob)(nil)
+
+type (
+	// persistent definition of the sync job
+	syncDef struct {
+		ID   string     `json:"id"`
+		Body DlSyncBody `json:"body"`
+	}
+
+	syncJob struct {
+		t        cluster.Target
+		statsT   stats.Tracker
+		def      syncDef
+		interval time.Duration
+		notif    *NotifDownload // to clone for scheduled runs
+		run      atomic.Int64   // sequence number of the last run
+		running  atomic.Bool
+		stopped  atomic.Bool
+	}
+
+	// single run of the sync job
+	syncDlJob struct {
+		*backendDlJob
+		sj  *syncJob
+		run int64
+	}
+)
+
+var syncJobs = struct {
+	sync.Mutex
+	m map[string]*syncJob
+}{m: make(map[string]*syncJob, 4)}
+
+// InitSync restores and schedules sync jobs persisted prior to target restart.
+func InitSync(t cluster.Target, statsT stats.Tracker) {
+	initInfoStore(t.DB()) // it will be initialized only once
+
+	defs, err := dlStore.getSyncJobs()
+	if err != nil {
+		glog.Errorf("%s: failed to load sync download jobs: %v", t, err)
+		return
+	}
+	for _, def := range defs {
+		interval, err := ParseSchedule(def.Body.Schedule)
+		if err != nil {
+			glog.Errorf("%s: sync download job %q: %v", t, def.ID, err)
+			continue
+		}
+		sj := &syncJob{t: t, statsT: statsT, def: *def, interval: interval}
+		next := interval
+		if runs, err := dlStore.getSyncRuns(def.ID); err == nil && len(runs) > 0 {
+			last := runs[len(runs)-1]
+			sj.run.Store(last.Run)
+			next = time.Until(last.StartedTime.Add(interval))
+		}
+		if next < syncRestartDelay {
+			next = syncRestartDelay
+		}
+		dlStore.setSyncJob(def.ID, &def.Body)
+		sj.schedule(next)
+		glog.Infof("%s: restored %s, next run in %v", t, sj, next)
+	}
+}
+
+func newSyncDlJob(t cluster.Target, id string, bck *cluster.Bck, payload *DlSyncBody, dlXact *Downloader) (*syncDlJob, error) {
+	interval, err := ParseSchedule(payload.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	sj := &syncJob{t: t, statsT: dlXact.statsT, def: syncDef{ID: id, Body: *payload}, interval: interval}
+	job, err := sj.newRun(bck, dlXact)
+	if err != nil {
+		return nil, err
+	}
+	if err := dlStore.persistSyncJob(&sj.def); err != nil {
+		return nil, err
+	}
+	sj.running.Store(true)
+	sj.schedule(interval)
+	return job, nil
+}
+
+func unregSyncJob(id string) (sj *syncJob) {
+	syncJobs.Lock()
+	sj = syncJobs.m[id]
+	delete(syncJobs.m, id)
+	syncJobs.Unlock()
+	if sj != nil {
+		sj.stopped.Store(true) // see housekeep
+		dlStore.deleteSyncJob(id)
+	}
+	return
+}
+
+/////////////
+// syncJob //
+/////////////
+
+func (sj *syncJob) String() string {
+	return fmt.Sprintf("sync-dl-job[%s]-%s-every-%v", sj.def.ID, sj.def.Body.Bck, sj.interval)
+}
+
+func (sj *syncJob) schedule(interval time.Duration) {
+	syncJobs.Lock()
+	syncJobs.m[sj.def.ID] = sj
+	syncJobs.Unlock()
+	hk.Reg("dl-sync-"+sj.def.ID+hk.NameSuffix, sj.housekeep, interval)
+}
+
+func (sj *syncJob) housekeep() time.Duration {
+	if sj.stopped.Load() {
+		return hk.UnregInterval
+	}
+	if !sj.running.CAS(false, true) {
+		glog.Warningf("%s: previous run #%d is still in progress - skipping", sj, sj.run.Load())
+		return sj.interval
+	}
+	go sj.start()
+	return sj.interval
+}
+
+func (sj *syncJob) start() {
+	if err := sj._start(); err != nil {
+		glog.Errorf("%s: failed to start run #%d: %v", sj, sj.run.Load(), err)
+		sj.running.Store(false)
+	}
+}
+
+func (sj *syncJob) _start() error {
+	bck := cluster.CloneBck(&sj.def.Body.Bck)
+	if err := bck.Init(sj.t.Bowner()); err != nil {
+		return err
+	}
+	rns := xreg.RenewDownloader(sj.t, sj.statsT)
+	if rns.Err != nil {
+		return rns.Err
+	}
+	dlXact := rns.Entry.Get().(*Downloader)
+	job, err := sj.newRun(bck, dlXact)
+	if err != nil {
+		return err
+	}
+	resp, statusCode, err := dlXact.Download(job)
+	if err == nil && statusCode != http.StatusOK {
+		err = fmt.Errorf("%v", resp)
+	}
+	return err
+}
+
+func (sj *syncJob) newRun(bck *cluster.Bck, dlXact *Downloader) (*syncDlJob, error) {
+	body := &sj.def.Body
+	backendBody := &DlBackendBody{DlBase: body.DlBase, Sync: body.Delete, Prefix: body.Prefix, Suffix: body.Suffix}
+	backendJob, err := newBackendDlJob(sj.t, sj.def.ID, bck, backendBody, dlXact)
+	if err != nil {
+		return nil, err
+	}
+	backendJob.description = body.Describe()
+	job := &syncDlJob{backendDlJob: backendJob, sj: sj, run: sj.run.Inc()}
+	if n := sj.notif; n != nil {
+		job.notif = &NotifDownload{
+			NotifBase: nl.NotifBase{When: n.When, Interval: n.Interval, Dsts: n.Dsts, F: n.F, P: n.P},
+			DlJob:     job,
+		}
+	}
+	dlStore.clearTasks(sj.def.ID)
+	return job, nil
+}
+
+///////////////
+// syncDlJob //
+///////////////
+
+func (j *syncDlJob) String() string {
+	return fmt.Sprintf("sync-%s-run-%d", j.backendDlJob, j.run)
+}
+
+// NOTE: runs scheduled after target restart have no notification
+func (j *syncDlJob) Notif() cluster.Notif {
+	if j.notif == nil {
+		return nil
+	}
+	return j.notif
+}
+
+func (j *syncDlJob) AddNotif(n cluster.Notif, job DlJob) {
+	j.baseDlJob.AddNotif(n, job)
+	j.sj.notif = j.notif
+}
+
+// Unlike other jobs, the run of the sync job records its stats and keeps the job
+// running until the next one. The job gets finished only when aborted.
+func (j *syncDlJob) cleanup() {
+	j.throttler().stop()
+	dlStore.flush(j.ID())
+
+	jInfo, err := dlStore.getJob(j.ID())
+	if err != nil {
+		glog.Errorf("%s: %v", j, err)
+		j.sj.running.Store(false)
+		return
+	}
+	info := jInfo.ToDlJobInfo()
+	run := DlSyncRun{
+		Run:          j.run,
+		FinishedCnt:  info.FinishedCnt,
+		ScheduledCnt: info.ScheduledCnt,
+		SkippedCnt:   info.SkippedCnt,
+		ErrorCnt:     info.ErrorCnt,
+		Aborted:      info.Aborted,
+		StartedTime:  info.StartedTime,
+		FinishedTime: time.Now(),
+	}
+	if err := dlStore.persistSyncRun(j.ID(), run); err != nil {
+		glog.Errorf("%s: failed to persist run stats: %v", j, err)
+	}
+
+	if info.Aborted || j.sj.stopped.Load() {
+		unregSyncJob(j.ID())
+		dlStore.setAborted(j.ID())
+		err = dlStore.markFinished(j.ID())
+		if err != nil {
+			glog.Errorf("%s: %v", j, err)
+		}
+		nl.OnFinished(j.Notif(), err)
+	}
+	j.sj.running.Store(false)
+}
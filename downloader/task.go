@@ -23,13 +23,13 @@ import (
 )
 
 const (
-	retryCnt         = 10  // number of retries to external resource
+	retryCnt         = 10  // default number of attempts to download from external resource (see `DlRetry`)
 	reqTimeoutFactor = 1.2 // newTimeout = prevTimeout * reqTimeoutFactor
 	internalErrorMsg = "internal server error"
 )
 
-// List of HTTP status codes on which we should
-// not retry and just mark job as failed.
+// List of HTTP status codes on which we should not retry
+// and just mark job as failed (unless `DlRetry.Codes` specified).
 var terminalStatuses = map[int]struct{}{
 	http.StatusNotFound:          {},
 	http.StatusPaymentRequired:   {},
@@ -118,6 +118,10 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 	if cos.IsGoogleStorageURL(req.URL) {
 		req.Header.Add("User-Agent", cmn.GcsUA)
 	}
+	// Job's custom headers and credentials (may override the above).
+	for name, values := range t.job.header() {
+		req.Header[name] = values
+	}
 
 	resp, err := clientForURL(t.obj.link).Do(req)
 	if err != nil {
@@ -130,6 +134,9 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 	}
 
 	r := t.wrapReader(ctx, resp.Body)
+	if t.obj.cksum != nil {
+		r = newCksumReader(r, t.obj.cksum, t.obj.objName)
+	}
 	size := attrsFromLink(t.obj.link, resp, lom)
 	t.setTotalSize(size)
 
@@ -154,10 +161,15 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 
 func (t *singleObjectTask) downloadLocal(lom *cluster.LOM) (err error) {
 	var (
-		timeout = t.initialTimeout()
-		fatal   bool
+		timeout  = t.initialTimeout()
+		rp       = t.job.retry()
+		attempts = rp.maxAttempts(retryCnt)
+		fatal    bool
 	)
-	for i := 0; i < retryCnt; i++ {
+	for i := 0; i < attempts; i++ {
+		if i > 0 && !t.backoff(i-1) {
+			return err
+		}
 		fatal, err = t.tryDownloadLocal(lom, timeout)
 		if err == nil || fatal {
 			return err
@@ -168,20 +180,20 @@ func (t *singleObjectTask) downloadLocal(lom *cluster.LOM) (err error) {
 		}
 		if errors.Is(err, context.DeadlineExceeded) {
 			glog.Warningf("%s [retries: %d/%d]: timeout (%v) - increasing and retrying...",
-				t, i, retryCnt, timeout)
+				t, i, attempts, timeout)
 			timeout = time.Duration(float64(timeout) * reqTimeoutFactor)
 		} else if httpErr := cmn.Err2HTTPErr(err); httpErr != nil {
-			glog.Warningf("%s [retries: %d/%d]: failed to perform request: %v (code: %d)", t, i, retryCnt, err,
+			glog.Warningf("%s [retries: %d/%d]: failed to perform request: %v (code: %d)", t, i, attempts, err,
 				httpErr.Status)
-			if _, exists := terminalStatuses[httpErr.Status]; exists {
+			if !rp.retriable(httpErr.Status) {
 				// Nothing we can do...
 				return err
 			}
 			// Otherwise retry...
 		} else if cos.IsRetriableConnErr(err) {
-			glog.Warningf("%s [retries: %d/%d]: connection failed with (%v), retrying...", t, i, retryCnt, err)
+			glog.Warningf("%s [retries: %d/%d]: connection failed with (%v), retrying...", t, i, attempts, err)
 		} else {
-			glog.Warningf("%s [retries: %d/%d]: unexpected error (%v), retrying...", t, i, retryCnt, err)
+			glog.Warningf("%s [retries: %d/%d]: unexpected error (%v), retrying...", t, i, attempts, err)
 		}
 
 		t.reset()
@@ -214,7 +226,52 @@ func (t *singleObjectTask) reset() {
 	t.currentSize.Store(0)
 }
 
-func (t *singleObjectTask) downloadRemote(lom *cluster.LOM) error {
+// NOTE: by default, downloading from remote bucket is not retried (see `DlRetry`).
+func (t *singleObjectTask) downloadRemote(lom *cluster.LOM) (err error) {
+	var (
+		rp       = t.job.retry()
+		attempts = rp.maxAttempts(1)
+		errCode  int
+	)
+	for i := 0; i < attempts; i++ {
+		if i > 0 && !t.backoff(i-1) {
+			return err
+		}
+		if errCode, err = t.tryDownloadRemote(lom); err == nil {
+			return nil
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, errThrottlerStopped) {
+			return err
+		}
+		switch {
+		case errors.Is(err, context.DeadlineExceeded), cos.IsRetriableConnErr(err):
+		case errCode != 0 && rp.retriable(errCode):
+		default:
+			return err
+		}
+		glog.Warningf("%s [retries: %d/%d]: failed to get remote object: %v (code: %d)", t, i, attempts, err, errCode)
+		t.reset()
+	}
+	return err
+}
+
+// waits before the next attempt; returns false if the download gets canceled meanwhile
+func (t *singleObjectTask) backoff(attempt int) bool {
+	d := t.job.retry().delay(attempt)
+	if d == 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-t.downloadCtx.Done():
+		return false
+	}
+}
+
+func (t *singleObjectTask) tryDownloadRemote(lom *cluster.LOM) (int, error) {
 	// Set custom context values (used by `ais/backend/*`).
 	ctx, cancel := context.WithTimeout(t.downloadCtx, t.initialTimeout())
 	defer cancel()
@@ -223,8 +280,7 @@ func (t *singleObjectTask) downloadRemote(lom *cluster.LOM) error {
 	ctx = context.WithValue(ctx, cos.CtxSetSize, cos.SetSizeFunc(t.setTotalSize))
 
 	// Do final GET (prefetch) request.
-	_, err := t.parent.t.GetCold(ctx, lom, cmn.OwtGetTryLock)
-	return err
+	return t.parent.t.GetCold(ctx, lom, cmn.OwtGetTryLock)
 }
 
 func (t *singleObjectTask) initialTimeout() time.Duration {
@@ -239,6 +295,17 @@ func (t *singleObjectTask) initialTimeout() time.Duration {
 // Probably we need to extend the persistent database (db.go) so that it will contain
 // also information about specific tasks.
 func (t *singleObjectTask) markFailed(statusMsg string) {
+	t.fail(statusMsg)
+	// add to the dead-letter list, to retry later
+	obj := deadLetterObj{ObjName: t.obj.objName, Link: t.obj.link}
+	if t.obj.cksum != nil {
+		obj.CksumType, obj.CksumValue = t.obj.cksum.Get()
+	}
+	dlStore.persistFailed(t.jobID(), t.job.definition(), obj)
+}
+
+// same as `markFailed` but not retriable (e.g., failure to evict object when syncing)
+func (t *singleObjectTask) fail(statusMsg string) {
 	t.parent.statsT.Add(stats.ErrDownloadCount, 1)
 
 	dlStore.persistError(t.jobID(), t.obj.objName, statusMsg)
@@ -271,6 +338,6 @@ func (t *singleObjectTask) ToTaskDlInfo() TaskDlInfo {
 func (t *singleObjectTask) String() (str string) {
 	return fmt.Sprintf(
 		"{id: %q, obj_name: %q, link: %q, from_remote: %v, bucket: %q}",
-		t.jobID(), t.obj.objName, t.obj.link, t.obj.fromRemote, t.job.Bck(),
+		t.jobID(), t.obj.objName, redactLink(t.obj.link), t.obj.fromRemote, t.job.Bck(),
 	)
 }
@@ -121,6 +121,26 @@ func ParseStartDownloadRequest(t cluster.Target, bck *cluster.Bck, id string, dl
 			return nil, err
 		}
 		return newBackendDlJob(t, id, bck, dp, dlXact)
+	case DlTypeSync:
+		dp := &DlSyncBody{}
+		err := jsoniter.Unmarshal(dlb.RawMessage, dp)
+		if err != nil {
+			return nil, err
+		}
+		if err := dp.Validate(); err != nil {
+			return nil, err
+		}
+		return newSyncDlJob(t, id, bck, dp, dlXact)
+	case DlTypeManifest:
+		dp := &DlManifestBody{}
+		err := jsoniter.Unmarshal(dlb.RawMessage, dp)
+		if err != nil {
+			return nil, err
+		}
+		if err := dp.Validate(); err != nil {
+			return nil, err
+		}
+		return newManifestDlJob(t, id, bck, dp, dlXact)
 	case DlTypeMulti:
 		dp := &DlMultiBody{}
 		err := jsoniter.Unmarshal(dlb.RawMessage, dp)
@@ -152,10 +172,28 @@ func ParseStartDownloadRequest(t cluster.Target, bck *cluster.Bck, id string, dl
 		}
 		return newSingleDlJob(t, id, bck, dp, dlXact)
 	default:
-		return nil, errors.New("input does not match any of the supported formats (single, range, multi, backend)")
+		return nil, errors.New("input does not match any of the supported formats (single, range, multi, manifest, backend, sync)")
 	}
 }
 
+// ParseRetryDownloadRequest creates job `id` to retry failed downloads of the finished job `srcID`.
+func ParseRetryDownloadRequest(t cluster.Target, id, srcID string, dlXact *Downloader) (DlJob, error) {
+	return newRetryDlJob(t, id, srcID, dlXact)
+}
+
+// RetryDownloadBck returns the destination bucket of the failed (and persisted)
+// downloads of the job `srcID` - the bucket that retrying the job will write to.
+func RetryDownloadBck(t cluster.Target, srcID string) (*cmn.Bck, error) {
+	dl, err := dlStore.getDeadLetter(srcID)
+	if err != nil {
+		return nil, err
+	}
+	if len(dl.Objs) == 0 {
+		return nil, cmn.NewErrNotFound("%s: failed downloads of job %q", t, srcID)
+	}
+	return &dl.Def.Bck, nil
+}
+
 // Given URL (link) and response header parse object attrs for GCP, S3 and Azure.
 func attrsFromLink(link string, resp *http.Response, oah cmn.ObjAttrsHolder) (size int64) {
 	u, err := url.Parse(link)
@@ -218,13 +256,16 @@ func parseGoogleCksumHeader(hdr []string) cos.SimpleKVs {
 	return cksums
 }
 
-func headLink(link string) (*http.Response, error) {
+func headLink(link string, header http.Header) (*http.Response, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), headReqTimeout)
 	defer cancel()
 	req, err := http.NewRequestWithContext(ctx, http.MethodHead, link, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
 	resp, err := clientForURL(link).Do(req)
 	if err != nil {
 		return nil, err
@@ -238,7 +279,7 @@ func headLink(link string) (*http.Response, error) {
 func CompareObjects(lom *cluster.LOM, dst *DstElement) (equal bool, err error) {
 	var oa *cmn.ObjAttrs
 	if dst.Link != "" {
-		resp, errHead := headLink(dst.Link)
+		resp, errHead := headLink(dst.Link, dst.Header)
 		if errHead != nil {
 			return false, errHead
 		}
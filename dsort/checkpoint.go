@@ -0,0 +1,392 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package dsort
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/dsort/filetype"
+	"github.com/OneOfOne/xxhash"
+	"github.com/pkg/errors"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Resumable dSort job checkpoints its finished phases on each target:
+//   - phaseExtracted:   local records (metadata) extracted from the input shards;
+//     the content of the records is always extracted to disk and retained on abort
+//   - phaseDistributed: sorted and distributed shards that are to be created locally;
+//     in addition, the names of the already created shards are appended as we go
+//
+// The checkpoint itself is stored in the dSort collection of the target's DB while
+// the (potentially large) records and shards are stored as dSort workfiles.
+// When resumed, the job continues from the minimal phase checkpointed by all targets.
+
+const checkpointsKey = "checkpoints"
+
+// checkpointed phases (in order)
+const (
+	phaseNone = iota
+	phaseExtracted
+	phaseDistributed
+)
+
+// checkpoint workfiles
+const (
+	ckptRecords = "records"
+	ckptShards  = "shards"
+	ckptCreated = "created"
+)
+
+var errResumeTargetsChanged = errors.New("cannot resume: targets have changed")
+
+type checkpoint struct {
+	ManagerUUID  string             `json:"manager_uuid"`
+	Phase        int                `json:"phase"`
+	Targets      []string           `json:"targets"` // IDs of the active targets at the time of the checkpoint
+	RS           *ParsedRequestSpec `json:"rs"`
+	Compressed   int64              `json:"compressed,string"`
+	Uncompressed int64              `json:"uncompressed,string"`
+	Extraction   *LocalExtraction   `json:"extraction"`
+}
+
+// resumableSalt returns target order salt that, unlike the default one, remains
+// the same when the job with the given ID gets resumed.
+func resumableSalt(managerUUID string) []byte {
+	salt := make([]byte, cos.SizeofI64)
+	binary.BigEndian.PutUint64(salt, xxhash.ChecksumString64(managerUUID))
+	return salt
+}
+
+func activeTargets(smap *cluster.Smap) []string {
+	targets := make([]string, 0, len(smap.Tmap))
+	for sid, si := range smap.Tmap {
+		if !smap.PresentInMaint(si) {
+			targets = append(targets, sid)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+func ckptKey(managerUUID string) string { return path.Join(checkpointsKey, managerUUID) }
+
+func ckptFQN(bck *cmn.Bck, managerUUID, name string) (string, error) {
+	ct, err := cluster.NewCTFromBO(bck, path.Join(managerUUID, name), nil)
+	if err != nil {
+		return "", err
+	}
+	return ct.Make(filetype.DSortWorkfileType), nil
+}
+
+////////////////////////////////
+// Manager: save checkpoints  //
+////////////////////////////////
+
+// loadCheckpoint validates the checkpoint of the job that is being resumed and
+// returns the phase it has reached on this target.
+//
+// PRECONDITION: `m.mu` must be locked.
+func (m *Manager) loadCheckpoint() (phase int, err error) {
+	ckpt := &checkpoint{}
+	if err := m.mg.db.Get(dsortCollection, ckptKey(m.ManagerUUID), ckpt); err != nil {
+		if dbdriver.IsErrNotFound(err) {
+			glog.Warningf("[dsort] %s: no checkpoint to resume from, starting over", m.ManagerUUID)
+			return phaseNone, nil
+		}
+		return phaseNone, err
+	}
+	if !cos.StrSlicesEqual(ckpt.Targets, activeTargets(m.smap)) {
+		return phaseNone, errResumeTargetsChanged
+	}
+	if !sameSpec(ckpt.RS, m.rs) {
+		return phaseNone, fmt.Errorf("cannot resume %s: specification differs from the one of the job being resumed",
+			m.ManagerUUID)
+	}
+	m.resume.ckpt = ckpt
+	return ckpt.Phase, nil
+}
+
+func sameSpec(a, b *ParsedRequestSpec) bool {
+	ca, cb := *a, *b
+	ca.Resume, cb.Resume = "", ""
+	return string(cos.MustMarshal(&ca)) == string(cos.MustMarshal(&cb))
+}
+
+func (m *Manager) saveCheckpoint(phase int) error {
+	ckpt := &checkpoint{
+		ManagerUUID:  m.ManagerUUID,
+		Phase:        phase,
+		Targets:      activeTargets(m.smap),
+		RS:           m.rs,
+		Compressed:   m.compression.compressed.Load(),
+		Uncompressed: m.compression.uncompressed.Load(),
+		Extraction:   m.Metrics.Extraction,
+	}
+	m.Metrics.Extraction.mu.Lock()
+	err := m.mg.db.Set(dsortCollection, ckptKey(m.ManagerUUID), ckpt)
+	m.Metrics.Extraction.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	m.resume.ckpt = ckpt
+	return nil
+}
+
+// checkpointExtracted saves local records once extraction phase has finished.
+func (m *Manager) checkpointExtracted() error {
+	if !m.rs.Resumable {
+		return nil
+	}
+	if err := m.saveMsg(ckptRecords, m.recManager.Records); err != nil {
+		return err
+	}
+	return m.saveCheckpoint(phaseExtracted)
+}
+
+// checkpointDistributed saves the shards to be created locally. It also resets
+// the list of the created shards (if any) as the distribution may have changed.
+func (m *Manager) checkpointDistributed() error {
+	if !m.rs.Resumable {
+		return nil
+	}
+	if err := m.saveMsg(ckptShards, &m.creationPhase.metadata); err != nil {
+		return err
+	}
+	fqn, err := ckptFQN(&m.rs.Bck, m.ManagerUUID, ckptCreated)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fqn); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return m.saveCheckpoint(phaseDistributed)
+}
+
+// checkpointCreated records the name of the newly created shard.
+func (m *Manager) checkpointCreated(shardName string) error {
+	if !m.rs.Resumable {
+		return nil
+	}
+	fqn, err := ckptFQN(&m.rs.Bck, m.ManagerUUID, ckptCreated)
+	if err != nil {
+		return err
+	}
+	m.resume.mu.Lock()
+	defer m.resume.mu.Unlock()
+	f, err := os.OpenFile(fqn, os.O_APPEND|os.O_CREATE|os.O_WRONLY, cos.PermRWR)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(shardName + "\n")
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	return err
+}
+
+func (m *Manager) saveMsg(name string, v msgp.Encodable) (err error) {
+	fqn, err := ckptFQN(&m.rs.Bck, m.ManagerUUID, name)
+	if err != nil {
+		return err
+	}
+	var (
+		f         *os.File
+		tmp       = fqn + ".tmp"
+		buf, slab = mm.AllocSize(serializationBufSize)
+	)
+	defer slab.Free(buf)
+	if f, err = cos.CreateFile(tmp); err != nil {
+		return err
+	}
+	w := msgp.NewWriterBuf(f, buf)
+	if err = v.EncodeMsg(w); err == nil {
+		err = w.Flush()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(tmp, fqn)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return errors.Wrapf(err, "failed to checkpoint %s", name)
+	}
+	return nil
+}
+
+func (m *Manager) loadMsg(name string, v msgp.Decodable) error {
+	return loadMsg(&m.rs.Bck, m.ManagerUUID, name, v)
+}
+
+func loadMsg(bck *cmn.Bck, managerUUID, name string, v msgp.Decodable) error {
+	fqn, err := ckptFQN(bck, managerUUID, name)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fqn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	buf, slab := mm.AllocSize(serializationBufSize)
+	defer slab.Free(buf)
+	if err := v.DecodeMsg(msgp.NewReaderBuf(f, buf)); err != nil {
+		return errors.Wrapf(err, "failed to load checkpointed %s", name)
+	}
+	return nil
+}
+
+//////////////////////////////////
+// Manager: resume checkpoints  //
+//////////////////////////////////
+
+// resumeExtracted restores the local records (and extraction metrics) in place
+// of running the extraction phase.
+func (m *Manager) resumeExtracted() error {
+	ckpt := m.resume.ckpt
+	records := extract.NewRecords(int(ckpt.Extraction.ExtractedRecordCnt))
+	if err := m.loadMsg(ckptRecords, records); err != nil {
+		return err
+	}
+	m.recManager.RestoreRecords(records)
+	m.incrementRef(int64(records.TotalObjectCount()))
+	m.addCompressionSizes(ckpt.Compressed-1, ckpt.Uncompressed-1) // minus initial 1 (see `init`)
+
+	metrics := m.Metrics.Extraction
+	metrics.mu.Lock()
+	metrics.TotalCnt = ckpt.Extraction.TotalCnt
+	metrics.ExtractedCnt = ckpt.Extraction.ExtractedCnt
+	metrics.ExtractedSize = ckpt.Extraction.ExtractedSize
+	metrics.ExtractedRecordCnt = ckpt.Extraction.ExtractedRecordCnt
+	metrics.ExtractedToDiskCnt = ckpt.Extraction.ExtractedToDiskCnt
+	metrics.ExtractedToDiskSize = ckpt.Extraction.ExtractedToDiskSize
+	metrics.mu.Unlock()
+	metrics.begin()
+	metrics.finish()
+
+	m.dsorter.postExtraction()
+	glog.Infof("[dsort] %s resumed extraction stage: %d records", m.ManagerUUID, records.Len())
+	return nil
+}
+
+// resumeDistributed restores the shards to be created locally skipping those
+// that have already been created.
+func (m *Manager) resumeDistributed() error {
+	// local records are still needed to clean up their extracted contents
+	records := extract.NewRecords(int(m.resume.ckpt.Extraction.ExtractedRecordCnt))
+	if err := m.loadMsg(ckptRecords, records); err != nil {
+		return err
+	}
+	m.recManager.RestoreRecords(records)
+	m.recManager.Records.Drain()
+
+	md := &CreationPhaseMetadata{}
+	if err := m.loadMsg(ckptShards, md); err != nil {
+		return err
+	}
+	created, err := m.loadCreated()
+	if err != nil {
+		return err
+	}
+	shards := md.Shards[:0]
+	for _, s := range md.Shards {
+		if _, ok := created[s.Name]; !ok {
+			shards = append(shards, s)
+		}
+	}
+	md.Shards = shards
+	m.creationPhase.metadata = *md
+	glog.Infof("[dsort] %s resumed creation stage: %d shards created, %d to create",
+		m.ManagerUUID, len(created), len(shards))
+	return nil
+}
+
+func (m *Manager) loadCreated() (map[string]struct{}, error) {
+	fqn, err := ckptFQN(&m.rs.Bck, m.ManagerUUID, ckptCreated)
+	if err != nil {
+		return nil, err
+	}
+	created := make(map[string]struct{})
+	f, err := os.Open(fqn)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return created, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			created[name] = struct{}{}
+		}
+	}
+	return created, scanner.Err()
+}
+
+// removeCheckpoint removes the checkpoint of the job and its workfiles, if exist.
+func (m *Manager) removeCheckpoint(extracted bool) {
+	m.mg.removeCheckpoint(m.ManagerUUID, &m.rs.Bck, extracted)
+	m.resume.ckpt = nil
+}
+
+// removeCheckpoint removes the checkpoint and, optionally, the contents extracted
+// by the (aborted) job and retained for the job to be resumed.
+func (mg *ManagerGroup) removeCheckpoint(managerUUID string, bck *cmn.Bck, extracted bool) {
+	if extracted {
+		removeExtracted(managerUUID, bck)
+	}
+	if err := mg.db.Delete(dsortCollection, ckptKey(managerUUID)); err != nil && !dbdriver.IsErrNotFound(err) {
+		glog.Error(err)
+	}
+	for _, name := range []string{ckptRecords, ckptShards, ckptCreated} {
+		fqn, err := ckptFQN(bck, managerUUID, name)
+		if err != nil {
+			glog.Error(err)
+			return
+		}
+		if err := os.Remove(fqn); err != nil && !os.IsNotExist(err) {
+			glog.Error(err)
+		}
+	}
+}
+
+func removeExtracted(managerUUID string, bck *cmn.Bck) {
+	records := extract.NewRecords(0)
+	if err := loadMsg(bck, managerUUID, ckptRecords, records); err != nil {
+		if !os.IsNotExist(errors.Cause(err)) {
+			glog.Error(err)
+		}
+		return
+	}
+	for _, r := range records.All() {
+		for _, obj := range r.Objects {
+			if obj.StoreType != extract.DiskStoreType {
+				continue
+			}
+			ct, err := cluster.NewCTFromBO(bck, obj.ContentPath, nil)
+			if err != nil {
+				glog.Error(err)
+				return
+			}
+			if err := os.Remove(ct.Make(filetype.DSortFileType)); err != nil && !os.IsNotExist(err) {
+				glog.Error(err)
+			}
+		}
+	}
+}
@@ -77,12 +77,47 @@ func (m *Manager) start() (err error) {
 		return err
 	}
 
+	if m.rs.Resumable && m.resume.phase == phaseNone && m.resume.ckpt != nil {
+		m.removeCheckpoint(true /*extracted*/) // starting over
+	}
+
 	// Phase 1.
-	glog.Infof("[dsort] %s started extraction stage", m.ManagerUUID)
-	if err := m.extractLocalShards(); err != nil {
+	if m.resume.phase >= phaseExtracted {
+		if err := m.resumeExtracted(); err != nil {
+			return err
+		}
+	} else {
+		glog.Infof("[dsort] %s started extraction stage", m.ManagerUUID)
+		if err := m.extractLocalShards(); err != nil {
+			return err
+		}
+		if err := m.checkpointExtracted(); err != nil {
+			return err
+		}
+	}
+
+	if m.resume.phase >= phaseDistributed {
+		if err := m.resumeDistributed(); err != nil {
+			return err
+		}
+	} else if err := m.sortAndDistribute(); err != nil {
 		return err
 	}
 
+	// After each target participates in the cluster-wide record distribution,
+	// start listening for the signal to start creating shards locally.
+	glog.Infof("[dsort] %s started creation stage", m.ManagerUUID)
+	if err := m.dsorter.createShardsLocally(); err != nil {
+		return err
+	}
+
+	glog.Infof("[dsort] %s finished successfully", m.ManagerUUID)
+	return nil
+}
+
+// sortAndDistribute runs phases 2 and 3 and waits for the shards to be created
+// locally to arrive.
+func (m *Manager) sortAndDistribute() error {
 	s := binary.BigEndian.Uint64(m.rs.TargetOrderSalt)
 	targetOrder := randomTargetOrder(s, m.smap.Tmap)
 	if glog.V(4) {
@@ -100,7 +135,7 @@ func (m *Manager) start() (err error) {
 	// Phase 3. - run only by the final target
 	if curTargetIsFinal {
 		shardSize := m.rs.OutputShardSize
-		if m.extractCreator.UsingCompression() {
+		if m.extractCreator.UsingCompression() && m.rs.OutputShardRecords == 0 {
 			// By making the assumption that the input content is reasonably
 			// uniform across all shards, the output shard size required (such
 			// that each gzip compressed output shard will have a size close to
@@ -129,16 +164,7 @@ func (m *Manager) start() (err error) {
 	case <-m.listenAborted():
 		return newDSortAbortedError(m.ManagerUUID)
 	}
-
-	// After each target participates in the cluster-wide record distribution,
-	// start listening for the signal to start creating shards locally.
-	glog.Infof("[dsort] %s started creation stage", m.ManagerUUID)
-	if err := m.dsorter.createShardsLocally(); err != nil {
-		return err
-	}
-
-	glog.Infof("[dsort] %s finished successfully", m.ManagerUUID)
-	return nil
+	return m.checkpointDistributed()
 }
 
 func (m *Manager) startDSorter() error {
@@ -210,6 +236,9 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 
 		expectedUncompressedSize := uint64(float64(lom.SizeBytes()) / m.avgCompressionRatio())
 		toDisk := m.dsorter.preShardExtraction(expectedUncompressedSize)
+		if m.rs.Resumable {
+			toDisk = true // in-memory contents would not survive restart
+		}
 
 		beforeExtraction := mono.NanoTime()
 
@@ -456,6 +485,10 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 	}
 
 exit:
+	if err := m.checkpointCreated(shardName); err != nil {
+		return err
+	}
+
 	metrics.mu.Lock()
 	metrics.CreatedCnt++
 	if si.DaemonID != m.ctx.node.DaemonID {
@@ -626,7 +659,7 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 		numLocalRecords = make(map[string]int, m.smap.CountActiveTargets())
 	)
 
-	if maxSize <= 0 {
+	if maxSize <= 0 && m.rs.OutputShardRecords == 0 {
 		// Heuristic: to count desired size of shard in case when maxSize is not specified.
 		maxSize = int64(math.Ceil(float64(m.totalUncompressedSize()) / float64(shardCount)))
 	}
@@ -634,7 +667,7 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 	for i, r := range m.recManager.Records.All() {
 		numLocalRecords[r.DaemonID]++
 		curShardSize += r.TotalSize()
-		if curShardSize < maxSize && i < n-1 {
+		if !m.shardFull(curShardSize, i+1-start, maxSize) && i < n-1 {
 			continue
 		}
 
@@ -668,7 +701,7 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 		shardsBuilder  = make(map[string][]*extract.Shard)
 	)
 
-	if maxSize <= 0 {
+	if maxSize <= 0 && m.rs.OutputShardRecords == 0 {
 		return nil, errors.New("invalid max size of shard was specified when using external key map")
 	}
 
@@ -733,7 +766,16 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 		shards := shardsBuilder[shardNameFmt]
 		recordSize := r.TotalSize() + m.extractCreator.MetadataSize()*int64(len(r.Objects))
 		shardCount := len(shards)
-		if shardCount == 0 || shards[shardCount-1].Size > maxSize {
+		full := shardCount == 0
+		if !full {
+			lastShard := shards[shardCount-1]
+			if m.rs.OutputShardRecords > 0 {
+				full = m.shardFull(lastShard.Size, lastShard.Records.Len(), maxSize)
+			} else {
+				full = lastShard.Size > maxSize
+			}
+		}
+		if full {
 			shard := &extract.Shard{
 				Name:    fmt.Sprintf(shardNameFmt, shardCount),
 				Size:    recordSize,
@@ -756,6 +798,15 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 	return shards, nil
 }
 
+// shardFull returns true when the output shard of the given size (in bytes) and number
+// of records is complete - see ParsedRequestSpec.OutputShardSize and OutputShardRecords
+func (m *Manager) shardFull(size int64, numRecords int, maxSize int64) bool {
+	if m.rs.OutputShardRecords > 0 {
+		return int64(numRecords) >= m.rs.OutputShardRecords
+	}
+	return size >= maxSize
+}
+
 // distributeShardRecords creates Shard structs in the order of
 // dsortManager.Records corresponding to a maximum size maxSize. Each Shard is
 // sent in an HTTP request to the appropriate target to create the actual file
@@ -7,13 +7,18 @@ package extract
 import (
 	"bytes"
 	"crypto/md5"
+	"encoding/json"
 	"fmt"
 	"hash"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
+	"github.com/tinylib/msgp/msgp"
 )
 
 const (
@@ -26,6 +31,9 @@ var (
 	supportedFormatTypes = []string{FormatTypeInt, FormatTypeFloat, FormatTypeString}
 
 	errInvalidAlgorithmFormatTypes = fmt.Errorf("invalid algorithm format type provided, shoule be one of: %+v", supportedFormatTypes)
+
+	// decodes JSON numbers as `json.Number` so that int64 keys do not lose precision
+	jsonNumber = jsoniter.Config{UseNumber: true}.Froze()
 )
 
 type (
@@ -50,6 +58,27 @@ type (
 		ty  string // type of key extracted, supported: supportedFormatTypes
 		ext string // extension of object record whose content will be read
 	}
+
+	// FieldKey is a key stored inside JSON or msgpack content of the record object.
+	FieldKey struct {
+		Path       string // JSONPath-style, e.g. "$.label", "$.meta.ids[0]", or "$['class name']"
+		FormatType string // one of supportedFormatTypes
+	}
+	fieldKeyExtractor struct {
+		ext     string // extension of object record whose content will be read
+		msgpack bool   // content is msgpack (otherwise, JSON)
+		keys    []fieldKey
+	}
+	fieldKey struct {
+		path fieldPath
+		ty   string
+	}
+	fieldPath []fieldPathElem
+	// either object member (name) or array element (idx >= 0)
+	fieldPathElem struct {
+		name string
+		idx  int
+	}
 )
 
 func NewMD5KeyExtractor() (KeyExtractor, error) {
@@ -120,6 +149,211 @@ func (ke *contentKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{},
 	}
 }
 
+// NewFieldKeyExtractor returns extractor that reads the content of the record object
+// with the given extension as JSON (or msgpack, if the extension is ".msgpack") and
+// extracts the keys by their paths. With multiple keys, the extracted key is a slice
+// of (primary, secondary, ...) keys.
+func NewFieldKeyExtractor(ext string, keys ...FieldKey) (KeyExtractor, error) {
+	debug.Assert(len(keys) > 0)
+	ke := &fieldKeyExtractor{
+		ext:     ext,
+		msgpack: strings.HasSuffix(ext, cos.ExtMsgpack),
+		keys:    make([]fieldKey, 0, len(keys)),
+	}
+	for _, key := range keys {
+		if err := ValidateAlgorithmFormatType(key.FormatType); err != nil {
+			return nil, err
+		}
+		path, err := parseFieldPath(key.Path)
+		if err != nil {
+			return nil, err
+		}
+		ke.keys = append(ke.keys, fieldKey{path: path, ty: key.FormatType})
+	}
+	return ke, nil
+}
+
+func (ke *fieldKeyExtractor) PrepareExtractor(name string, r cos.ReadSizer, ext string) (cos.ReadSizer, *SingleKeyExtractor, bool) {
+	if ke.ext != ext {
+		return r, nil, false
+	}
+
+	buf := &bytes.Buffer{}
+	tee := cos.NewSizedReader(io.TeeReader(r, buf), r.Size())
+	return tee, &SingleKeyExtractor{name: name, buf: buf}, true
+}
+
+func (ke *fieldKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{}, error) {
+	if ske == nil { // is not valid to be read
+		return nil, nil
+	}
+
+	b := ske.buf.Bytes()
+	ske.buf = nil
+
+	var (
+		doc interface{}
+		err error
+	)
+	if ke.msgpack {
+		doc, _, err = msgp.ReadIntfBytes(b)
+	} else {
+		err = jsonNumber.Unmarshal(b, &doc)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %q", ske.name)
+	}
+
+	keys := make([]interface{}, 0, len(ke.keys))
+	for _, fk := range ke.keys {
+		v, ok := fk.path.lookup(doc)
+		if !ok {
+			return nil, errors.Errorf("key %q not found in %q", fk.path, ske.name)
+		}
+		key, err := convertFieldKey(v, fk.ty)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %q in %q", fk.path, ske.name)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 1 {
+		return keys[0], nil
+	}
+	return keys, nil
+}
+
+// convertFieldKey converts decoded JSON or msgpack value to the key of the given type
+func convertFieldKey(v interface{}, ty string) (interface{}, error) {
+	switch ty {
+	case FormatTypeInt:
+		switch x := v.(type) {
+		case json.Number:
+			return x.Int64()
+		case int64:
+			return x, nil
+		case uint64:
+			return int64(x), nil
+		case string:
+			return strconv.ParseInt(x, 10, 64)
+		}
+	case FormatTypeFloat:
+		switch x := v.(type) {
+		case json.Number:
+			return x.Float64()
+		case float64:
+			return x, nil
+		case float32:
+			return float64(x), nil
+		case int64:
+			return float64(x), nil
+		case uint64:
+			return float64(x), nil
+		case string:
+			return strconv.ParseFloat(x, 64)
+		}
+	case FormatTypeString:
+		switch x := v.(type) {
+		case string:
+			return x, nil
+		case []byte:
+			return string(x), nil
+		case json.Number, int64, uint64, float64, float32, bool:
+			return fmt.Sprint(x), nil
+		}
+	default:
+		return nil, errors.Errorf("not implemented extractor type: %s", ty)
+	}
+	return nil, errors.Errorf("cannot convert %T to %s", v, ty)
+}
+
+// ValidateFieldPath returns non-nil error if the path is not a valid JSONPath-style path.
+func ValidateFieldPath(path string) error {
+	_, err := parseFieldPath(path)
+	return err
+}
+
+// parseFieldPath parses JSONPath-style path consisting of object members and array
+// indices: "$.a.b[1]['c d']". The root (`$`) is optional: "a.b[1]" is the same path.
+func parseFieldPath(path string) (fp fieldPath, err error) {
+	s := strings.TrimSpace(path)
+	if strings.HasPrefix(s, "$") {
+		s = s[1:]
+	} else if s != "" && s[0] != '[' {
+		s = "." + s
+	}
+	for s != "" {
+		switch s[0] {
+		case '.':
+			i := strings.IndexAny(s[1:], ".[")
+			if i < 0 {
+				i = len(s) - 1
+			}
+			name := s[1 : i+1]
+			if name == "" {
+				return nil, errors.Errorf("invalid field path %q: empty member name", path)
+			}
+			fp = append(fp, fieldPathElem{name: name, idx: -1})
+			s = s[i+1:]
+		case '[':
+			i := strings.IndexByte(s, ']')
+			if i < 0 {
+				return nil, errors.Errorf("invalid field path %q: missing ']'", path)
+			}
+			sub := s[1:i]
+			if l := len(sub); l >= 2 && (sub[0] == '\'' || sub[0] == '"') && sub[l-1] == sub[0] {
+				fp = append(fp, fieldPathElem{name: sub[1 : l-1], idx: -1})
+			} else {
+				idx, err := strconv.Atoi(sub)
+				if err != nil || idx < 0 {
+					return nil, errors.Errorf("invalid field path %q: invalid index %q", path, sub)
+				}
+				fp = append(fp, fieldPathElem{idx: idx})
+			}
+			s = s[i+1:]
+		default:
+			return nil, errors.Errorf("invalid field path %q: unexpected %q", path, s[0])
+		}
+	}
+	if len(fp) == 0 {
+		return nil, errors.Errorf("invalid field path %q: empty", path)
+	}
+	return fp, nil
+}
+
+func (fp fieldPath) lookup(v interface{}) (interface{}, bool) {
+	for _, elem := range fp {
+		if elem.idx >= 0 {
+			arr, ok := v.([]interface{})
+			if !ok || elem.idx >= len(arr) {
+				return nil, false
+			}
+			v = arr[elem.idx]
+			continue
+		}
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[elem.name]; !ok {
+			return nil, false
+		}
+	}
+	return v, v != nil
+}
+
+func (fp fieldPath) String() string {
+	var sb strings.Builder
+	sb.WriteByte('$')
+	for _, elem := range fp {
+		if elem.idx >= 0 {
+			sb.WriteString("[" + strconv.Itoa(elem.idx) + "]")
+		} else {
+			sb.WriteString("." + elem.name)
+		}
+	}
+	return sb.String()
+}
+
 func ValidateAlgorithmFormatType(ty string) error {
 	if !cos.StringInSlice(ty, supportedFormatTypes) {
 		return errInvalidAlgorithmFormatTypes
@@ -0,0 +1,101 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+
+	"github.com/NVIDIA/aistore/cmn/cos"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tinylib/msgp/msgp"
+)
+
+var _ = Describe("KeyExtractor", func() {
+	extractKey := func(ke KeyExtractor, ext string, content []byte) (interface{}, error) {
+		r, ske, needRead := ke.PrepareExtractor("record"+ext, cos.NewSizedReader(bytes.NewReader(content), int64(len(content))), ext)
+		Expect(needRead).To(BeTrue())
+		_, err := r.Read(make([]byte, len(content)+1))
+		Expect(err).NotTo(HaveOccurred())
+		return ke.ExtractKey(ske)
+	}
+
+	Context("field path", func() {
+		It("should parse valid paths", func() {
+			for path, expected := range map[string]string{
+				"$.label":           "$.label",
+				"label":             "$.label",
+				"$.meta.ids[1]":     "$.meta.ids[1]",
+				"meta['class'][0]":  "$.meta.class[0]",
+				"$[\"a b\"].c":      "$.a b.c",
+				" $.a.b.c ":         "$.a.b.c",
+				"[2].name":          "$[2].name",
+				"$.with_underscore": "$.with_underscore",
+			} {
+				fp, err := parseFieldPath(path)
+				Expect(err).NotTo(HaveOccurred(), path)
+				Expect(fp.String()).To(Equal(expected))
+			}
+		})
+
+		It("should fail to parse invalid paths", func() {
+			for _, path := range []string{"", "$", "$.", "$..a", "$.a[", "$.a[-1]", "$.a[x]", "$a"} {
+				_, err := parseFieldPath(path)
+				Expect(err).To(HaveOccurred(), path)
+			}
+		})
+	})
+
+	Context("field key", func() {
+		It("should extract key from JSON", func() {
+			ke, err := NewFieldKeyExtractor(".json", FieldKey{Path: "$.meta.label", FormatType: FormatTypeInt})
+			Expect(err).NotTo(HaveOccurred())
+			key, err := extractKey(ke, ".json", []byte(`{"meta": {"label": 9007199254740993}}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(int64(9007199254740993)))
+		})
+
+		It("should extract primary and secondary keys from msgpack", func() {
+			ke, err := NewFieldKeyExtractor(".msgpack",
+				FieldKey{Path: "$.label", FormatType: FormatTypeString},
+				FieldKey{Path: "$.scores[1]", FormatType: FormatTypeFloat},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			b := msgp.AppendMapHeader(nil, 2)
+			b = msgp.AppendString(b, "label")
+			b = msgp.AppendString(b, "cat")
+			b = msgp.AppendString(b, "scores")
+			b = msgp.AppendArrayHeader(b, 2)
+			b = msgp.AppendFloat64(b, 0.1)
+			b = msgp.AppendInt64(b, 7)
+
+			key, err := extractKey(ke, ".msgpack", b)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal([]interface{}{"cat", float64(7)}))
+		})
+
+		It("should fail when key is missing or has invalid type", func() {
+			ke, err := NewFieldKeyExtractor(".json", FieldKey{Path: "$.label", FormatType: FormatTypeFloat})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = extractKey(ke, ".json", []byte(`{"id": 1}`))
+			Expect(err).To(HaveOccurred())
+			_, err = extractKey(ke, ".json", []byte(`{"label": [1.5]}`))
+			Expect(err).To(HaveOccurred())
+			_, err = extractKey(ke, ".json", []byte(`{"label"`))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should skip records with other extensions", func() {
+			ke, err := NewFieldKeyExtractor(".json", FieldKey{Path: "$.label", FormatType: FormatTypeString})
+			Expect(err).NotTo(HaveOccurred())
+			_, ske, needRead := ke.PrepareExtractor("record.jpg", cos.NewSizedReader(bytes.NewReader(nil), 0), ".jpg")
+			Expect(needRead).To(BeFalse())
+			key, err := ke.ExtractKey(ske)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(BeNil())
+		})
+	})
+})
@@ -68,6 +68,7 @@ type (
 		t                   cluster.Target
 		bck                 cmn.Bck
 		extension           string
+		recordExt           ExtFunc
 		onDuplicatedRecords func(string) error
 
 		extractCreator  Creator
@@ -82,7 +83,7 @@ type (
 	}
 )
 
-func NewRecordManager(t cluster.Target, bck cmn.Bck, extension string, extractCreator Creator,
+func NewRecordManager(t cluster.Target, bck cmn.Bck, extension string, recordExt ExtFunc, extractCreator Creator,
 	keyExtractor KeyExtractor, onDuplicatedRecords func(string) error) *RecordManager {
 	return &RecordManager{
 		Records: NewRecords(1000),
@@ -90,6 +91,7 @@ func NewRecordManager(t cluster.Target, bck cmn.Bck, extension string, extractCr
 		t:                   t,
 		bck:                 bck,
 		extension:           extension,
+		recordExt:           recordExt,
 		onDuplicatedRecords: onDuplicatedRecords,
 
 		extractCreator:  extractCreator,
@@ -106,7 +108,7 @@ func (rm *RecordManager) ExtractRecordWithBuffer(args extractRecordArgs) (size i
 		fullContentPath string
 		mdSize          int64
 
-		ext              = Ext(args.recordName)
+		ext              = rm.recordExt(args.recordName)
 		recordUniqueName = rm.genRecordUniqueName(args.shardName, args.recordName)
 	)
 
@@ -235,7 +237,7 @@ func (rm *RecordManager) MergeEnqueuedRecords() {
 
 func (rm *RecordManager) genRecordUniqueName(shardName, recordName string) string {
 	shardWithoutExt := strings.TrimSuffix(shardName, rm.extension)
-	recordWithoutExt := strings.TrimSuffix(recordName, Ext(recordName))
+	recordWithoutExt := strings.TrimSuffix(recordName, rm.recordExt(recordName))
 	return shardWithoutExt + "|" + recordWithoutExt
 }
 
@@ -255,14 +257,14 @@ func (rm *RecordManager) encodeRecordName(storeType, shardName, recordName strin
 		// For sgl:
 		//  * contentPath = recordUniqueName with extension (eg. shard_1-record_name.cls)
 		//  * fullContentPath = recordUniqueName with extension (eg. shard_1-record_name.cls)
-		recordExt := Ext(recordName)
+		recordExt := rm.recordExt(recordName)
 		contentPath := rm.genRecordUniqueName(shardName, recordName) + recordExt
 		return contentPath, contentPath // unique key for record
 	case DiskStoreType:
 		// For disk:
 		//  * contentPath = recordUniqueName with extension  (eg. shard_1-record_name.cls)
 		//  * fullContentPath = fqn to recordUniqueName with extension (eg. <bucket_fqn>/shard_1-record_name.cls)
-		recordExt := Ext(recordName)
+		recordExt := rm.recordExt(recordName)
 		contentPath := rm.genRecordUniqueName(shardName, recordName) + recordExt
 		ct, err := cluster.NewCTFromBO(&rm.bck, contentPath, nil)
 		cos.Assert(err == nil)
@@ -301,7 +303,7 @@ func (rm *RecordManager) FullContentPath(obj *RecordObj) string {
 func (rm *RecordManager) ChangeStoreType(fullContentPath, newStoreType string, value interface{}, buf []byte) (n int64) {
 	sgl := value.(*memsys.SGL)
 
-	recordObjExt := Ext(fullContentPath)
+	recordObjExt := rm.recordExt(fullContentPath)
 	contentPath := strings.TrimSuffix(fullContentPath, recordObjExt)
 
 	rm.Records.Lock()
@@ -360,15 +362,33 @@ func (rm *RecordManager) ExtractionPaths() *sync.Map {
 	return rm.extractionPaths
 }
 
-func (rm *RecordManager) Cleanup() {
-	rm.Records.Drain()
-	rm.extractionPaths.Range(func(k, v interface{}) bool {
-		if err := os.RemoveAll(k.(string)); err != nil {
-			glog.Errorf("could not remove extraction path (%v) from previous run, err: %v", k, err)
+// RestoreRecords replaces records with the ones extracted by the previous
+// (aborted) run of the same job. Records contents must have been extracted to disk.
+func (rm *RecordManager) RestoreRecords(records *Records) {
+	for _, r := range records.All() {
+		for _, obj := range r.Objects {
+			cos.Assertf(obj.StoreType != SGLStoreType, "%s: cannot restore in-memory record", r.Name)
+			if obj.StoreType == DiskStoreType {
+				rm.extractionPaths.Store(rm.FullContentPath(obj), struct{}{})
+			}
 		}
-		rm.extractionPaths.Delete(k)
-		return true
-	})
+	}
+	rm.Records = records
+}
+
+// Cleanup frees all records and their contents. When `keepExtracted` is set
+// the contents extracted to disk are retained (so that the job can be resumed).
+func (rm *RecordManager) Cleanup(keepExtracted bool) {
+	rm.Records.Drain()
+	if !keepExtracted {
+		rm.extractionPaths.Range(func(k, v interface{}) bool {
+			if err := os.RemoveAll(k.(string)); err != nil {
+				glog.Errorf("could not remove extraction path (%v) from previous run, err: %v", k, err)
+			}
+			rm.extractionPaths.Delete(k)
+			return true
+		})
+	}
 	rm.extractionPaths = nil
 	rm.contents.Range(func(k, v interface{}) bool {
 		if sgl, ok := v.(*memsys.SGL); ok {
@@ -0,0 +1,194 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"io"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/pkg/errors"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// msgpack shard is a single msgpack map: file name => file content (see also
+// `cmn.GenShard` and xs/archive.go). There are no per-file headers and, therefore,
+// the metadata of each extracted record is simply its name.
+
+// interface guard
+var _ Creator = (*msgpackExtractCreator)(nil)
+
+type (
+	msgpackExtractCreator struct {
+		t cluster.Target
+	}
+
+	// msgpackRecordDataReader is used for writing metadata as well as data to the buffer.
+	msgpackRecordDataReader struct {
+		slab *memsys.Slab
+
+		metadataSize int64
+		size         int64
+		written      int64
+		metadataBuf  []byte
+		hdrBuf       []byte
+		w            io.Writer
+	}
+)
+
+func newMsgpackRecordDataReader(t cluster.Target) *msgpackRecordDataReader {
+	rd := &msgpackRecordDataReader{}
+	rd.metadataBuf, rd.slab = t.ByteMM().Alloc()
+	return rd
+}
+
+func (rd *msgpackRecordDataReader) reinit(w io.Writer, size, metadataSize int64) {
+	rd.w = w
+	rd.written = 0
+	rd.size = size
+	rd.metadataSize = metadataSize
+}
+
+func (rd *msgpackRecordDataReader) free() {
+	rd.slab.Free(rd.metadataBuf)
+}
+
+func (rd *msgpackRecordDataReader) Write(p []byte) (int, error) {
+	// Write header: name (key) and the size of the content (value)
+	remainingMetadataSize := rd.metadataSize - rd.written
+	if remainingMetadataSize > 0 {
+		writeN := int64(len(p))
+		if writeN < remainingMetadataSize {
+			debug.Assert(int64(len(rd.metadataBuf))-rd.written >= writeN)
+			copy(rd.metadataBuf[rd.written:], p)
+			rd.written += writeN
+			return len(p), nil
+		}
+
+		debug.Assert(int64(len(rd.metadataBuf))-rd.written >= remainingMetadataSize)
+		copy(rd.metadataBuf[rd.written:], p[:remainingMetadataSize])
+		rd.written += remainingMetadataSize
+		p = p[remainingMetadataSize:]
+
+		rd.hdrBuf = msgp.AppendStringFromBytes(rd.hdrBuf[:0], rd.metadataBuf[:rd.metadataSize])
+		rd.hdrBuf = msgp.AppendBytesHeader(rd.hdrBuf, uint32(rd.size))
+		if _, err := rd.w.Write(rd.hdrBuf); err != nil {
+			return int(remainingMetadataSize), err
+		}
+	} else {
+		remainingMetadataSize = 0
+	}
+
+	n, err := rd.w.Write(p)
+	rd.written += int64(n)
+	return n + int(remainingMetadataSize), err
+}
+
+// ExtractShard reads the msgpack-formatted shard and extracts its records.
+func (m *msgpackExtractCreator) ExtractShard(lom *cluster.LOM, r cos.ReadReaderAt, extractor RecordExtractor,
+	toDisk bool) (extractedSize int64, extractedCount int, err error) {
+	var (
+		size int64
+		cnt  uint32
+		mr   = msgp.NewReaderSize(r, memsys.DefaultBufSize)
+	)
+	if cnt, err = mr.ReadMapHeader(); err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+
+	buf, slab := m.t.PageMM().AllocSize(lom.SizeBytes())
+	defer slab.Free(buf)
+
+	for i := uint32(0); i < cnt; i++ {
+		var (
+			name string
+			vsz  uint32
+			typ  msgp.Type
+		)
+		if name, err = mr.ReadString(); err != nil {
+			return extractedSize, extractedCount, errors.WithStack(err)
+		}
+		if typ, err = mr.NextType(); err != nil {
+			return extractedSize, extractedCount, errors.WithStack(err)
+		}
+		switch typ {
+		case msgp.BinType:
+			vsz, err = mr.ReadBytesHeader()
+		case msgp.StrType:
+			vsz, err = mr.ReadStringHeader()
+		default:
+			err = errors.Errorf("%s: unexpected msgpack type %q of %q (expecting binary or string)", lom, typ, name)
+		}
+		if err != nil {
+			return extractedSize, extractedCount, errors.WithStack(err)
+		}
+
+		extractMethod := ExtractToMem
+		if toDisk {
+			extractMethod = ExtractToDisk
+		}
+		lr := &io.LimitedReader{R: mr, N: int64(vsz)}
+		args := extractRecordArgs{
+			shardName:     lom.ObjName,
+			fileType:      fs.ObjectType,
+			recordName:    name,
+			r:             cos.NewSizedReader(lr, int64(vsz)),
+			metadata:      []byte(name),
+			extractMethod: extractMethod,
+			buf:           buf,
+		}
+		if size, err = extractor.ExtractRecordWithBuffer(args); err != nil {
+			return extractedSize, extractedCount, err
+		}
+		// skip the remaining content (e.g., duplicated record that was not read),
+		// so that the next read starts at the next key
+		if lr.N > 0 {
+			if _, err = io.CopyBuffer(io.Discard, lr, buf); err != nil {
+				return extractedSize, extractedCount, errors.WithStack(err)
+			}
+		}
+		extractedSize += size
+		extractedCount++
+	}
+	return extractedSize, extractedCount, nil
+}
+
+func NewMsgpackExtractCreator(t cluster.Target) Creator {
+	return &msgpackExtractCreator{t: t}
+}
+
+// CreateShard creates a new shard locally based on the Shard.
+func (m *msgpackExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadContentFunc) (written int64, err error) {
+	var (
+		n   int64
+		cnt int
+	)
+	for _, rec := range s.Records.All() {
+		cnt += len(rec.Objects)
+	}
+	if _, err = w.Write(msgp.AppendMapHeader(nil, uint32(cnt))); err != nil {
+		return
+	}
+
+	rdReader := newMsgpackRecordDataReader(m.t)
+	defer rdReader.free()
+	for _, rec := range s.Records.All() {
+		for _, obj := range rec.Objects {
+			rdReader.reinit(w, obj.Size, obj.MetadataSize)
+			if n, err = loadContent(rdReader, rec, obj); err != nil {
+				return written + n, err
+			}
+			written += n
+		}
+	}
+	return written, nil
+}
+
+func (*msgpackExtractCreator) UsingCompression() bool { return false }
+func (*msgpackExtractCreator) SupportsOffset() bool   { return false }
+func (*msgpackExtractCreator) MetadataSize() int64    { return 0 } // msgpack does not have per-file headers
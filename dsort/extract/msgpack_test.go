@@ -0,0 +1,71 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cluster/mock"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/tinylib/msgp/msgp"
+)
+
+var _ = Describe("Msgpack", func() {
+	const shardName = "shard.msgpack"
+
+	It("should skip duplicated records and keep extracting", func() {
+		var (
+			bck   = cmn.Bck{Name: "msgpackBck", Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
+			t     = mock.NewTarget(mock.NewBaseBownerMock())
+			files = []struct{ name, content string }{
+				{"a.txt", "first"},
+				{"a.txt", "duplicated content"},
+				{"b.txt", "bbbb"},
+				{"c.txt", "cccccc"},
+			}
+		)
+		// small records are allocated by the page allocator's (byte) sibling
+		_ = t.ByteMM()
+
+		b := msgp.AppendMapHeader(nil, uint32(len(files)))
+		for _, f := range files {
+			b = msgp.AppendString(b, f.name)
+			b = msgp.AppendBytes(b, []byte(f.content))
+		}
+
+		lom := cluster.AllocLOM(shardName)
+		defer cluster.FreeLOM(lom)
+		lom.SetSize(int64(len(b)))
+
+		keyExtractor, err := NewNameKeyExtractor()
+		Expect(err).NotTo(HaveOccurred())
+		extractCreator := NewMsgpackExtractCreator(t)
+		rm := NewRecordManager(t, bck, cos.ExtMsgpack, Ext, extractCreator, keyExtractor,
+			func(string) error { return nil } /*ignore*/)
+
+		_, _, err = extractCreator.ExtractShard(lom, bytes.NewReader(b), rm, false /*toDisk*/)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, f := range files[2:] {
+			name := rm.genRecordUniqueName(shardName, f.name)
+			record, exists := rm.Records.Find(name)
+			Expect(exists).To(BeTrue(), name)
+			Expect(record.Objects).To(HaveLen(1))
+			Expect(record.Objects[0].Size).To(BeEquivalentTo(len(f.content)))
+
+			_, fullContentPath := rm.encodeRecordName(SGLStoreType, shardName, f.name)
+			v, ok := rm.contents.Load(fullContentPath)
+			Expect(ok).To(BeTrue())
+			Expect(string(v.(*memsys.SGL).Bytes())).To(Equal(f.name + f.content))
+		}
+		Expect(rm.Records.Exists(rm.genRecordUniqueName(shardName, "a.txt"), ".txt")).To(BeTrue())
+	})
+})
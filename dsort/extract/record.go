@@ -7,6 +7,7 @@ package extract
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"sync"
 	"unsafe"
 
@@ -27,6 +28,14 @@ var (
 	_ json.Unmarshaler = (*Records)(nil)
 )
 
+const (
+	// record formats: how names of archived files are split into record key and extension
+	RecordFormatDefault = ""    // extension begins at the first dot of the basename (see `Ext`)
+	RecordFormatWDS     = "wds" // WebDataset: same as default unless the name ends with one of the record extensions
+)
+
+var SupportedRecordFormats = []string{RecordFormatDefault, RecordFormatWDS}
+
 const (
 	// Values are small to save memory.
 	OffsetStoreType = "o"
@@ -35,6 +44,9 @@ const (
 )
 
 type (
+	// ExtFunc returns the extension of the record object (see `Ext` and `NewExtFunc`).
+	ExtFunc func(path string) string
+
 	// RecordObj describes single object of record. Objects inside single record
 	// differs by extension.
 	RecordObj struct {
@@ -214,20 +226,31 @@ func (r *Records) Len() int {
 func (r *Records) Swap(i, j int) { r.arr[i], r.arr[j] = r.arr[j], r.arr[i] }
 
 func (r *Records) Less(i, j int, formatType string) (bool, error) {
-	lhs, rhs := r.arr[i].Key, r.arr[j].Key
+	lhs, rhs, err := r.Keys(i, j)
+	if err != nil {
+		return false, err
+	}
+	return CompareKeys(lhs, rhs, formatType) < 0, nil
+}
+
+// Keys returns sorting keys of the i-th and j-th records.
+func (r *Records) Keys(i, j int) (lhs, rhs interface{}, err error) {
+	lhs, rhs = r.arr[i].Key, r.arr[j].Key
 	if lhs == nil {
-		return false, errors.Errorf("key is missing for %q", r.arr[i].Name)
+		return nil, nil, errors.Errorf("key is missing for %q", r.arr[i].Name)
 	} else if rhs == nil {
-		return false, errors.Errorf("key is missing for %q", r.arr[j].Name)
+		return nil, nil, errors.Errorf("key is missing for %q", r.arr[j].Name)
 	}
+	return
+}
 
+// CompareKeys returns -1, 0, or +1 depending on whether lhs key is less than,
+// equal to, or greater than rhs key of the given format type.
+func CompareKeys(lhs, rhs interface{}, formatType string) int {
 	switch formatType {
 	case FormatTypeInt:
 		ilhs, lok := lhs.(int64)
 		irhs, rok := rhs.(int64)
-		if lok && rok {
-			return ilhs < irhs, nil
-		}
 
 		// One side was parsed as float64 - javascript does not support
 		// int64 type and it fallback to float64
@@ -237,16 +260,27 @@ func (r *Records) Less(i, j int, formatType string) (bool, error) {
 		if !rok {
 			irhs = int64(rhs.(float64))
 		}
-
-		return ilhs < irhs, nil
+		return compare(ilhs < irhs, ilhs > irhs)
 	case FormatTypeFloat:
-		return lhs.(float64) < rhs.(float64), nil
+		flhs, frhs := lhs.(float64), rhs.(float64)
+		return compare(flhs < frhs, flhs > frhs)
 	case FormatTypeString:
-		return lhs.(string) < rhs.(string), nil
+		slhs, srhs := lhs.(string), rhs.(string)
+		return compare(slhs < srhs, slhs > srhs)
 	}
 
-	cos.Assertf(false, "lhs: %v, rhs: %v, arr[i]: %v, arr[j]: %v", lhs, rhs, r.arr[i], r.arr[j])
-	return false, nil
+	cos.Assertf(false, "lhs: %v, rhs: %v, format: %q", lhs, rhs, formatType)
+	return 0
+}
+
+func compare(less, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
 }
 
 func (r *Records) TotalObjectCount() int {
@@ -301,3 +335,33 @@ func Ext(path string) string {
 	}
 	return path[dotIndex:]
 }
+
+// NewExtFunc returns the function that splits record names as per the given record format.
+//
+// With WebDataset (RecordFormatWDS), the record key is the name up to the first dot
+// of the basename - same as `Ext` - unless the name ends with one of the specified
+// record extensions (the longest wins). In other words, given exts = [".jpg", ".seg.png"]:
+//   - "a/img.0001.jpg"     => "a/img.0001" + ".jpg"
+//   - "a/img.0001.seg.png" => "a/img.0001" + ".seg.png"
+//   - "a/img.cls"          => "a/img" + ".cls"
+func NewExtFunc(format string, exts []string) ExtFunc {
+	if format != RecordFormatWDS || len(exts) == 0 {
+		return Ext
+	}
+	return func(path string) string {
+		var ext string
+		for _, e := range exts {
+			if len(e) <= len(ext) || !strings.HasSuffix(path, e) {
+				continue
+			}
+			// the key (basename without extension) must not be empty
+			if key := path[:len(path)-len(e)]; key != "" && !os.IsPathSeparator(key[len(key)-1]) {
+				ext = e
+			}
+		}
+		if ext == "" {
+			ext = Ext(path)
+		}
+		return ext
+	}
+}
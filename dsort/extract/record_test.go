@@ -148,4 +148,25 @@ var _ = Describe("Records", func() {
 			Expect(records.All()[0].TotalSize()).To(BeEquivalentTo(objectSize))
 		})
 	})
+
+	Context("record format", func() {
+		It("should use default extension with no record extensions", func() {
+			ext := NewExtFunc(RecordFormatWDS, nil)
+			Expect(ext("a/img.0001.seg.png")).To(Equal(".0001.seg.png"))
+			ext = NewExtFunc(RecordFormatDefault, nil)
+			Expect(ext("a/img.jpg")).To(Equal(".jpg"))
+		})
+
+		It("should split WebDataset names with dotted basenames", func() {
+			ext := NewExtFunc(RecordFormatWDS, []string{".png", ".seg.png", ".jpg"})
+			Expect(ext("a/img.0001.jpg")).To(Equal(".jpg"))
+			Expect(ext("a/img.0001.seg.png")).To(Equal(".seg.png"))
+			Expect(ext("a/img.0001.png")).To(Equal(".png"))
+			// fall back to the default when none of the record extensions match
+			Expect(ext("a/img.0001.cls")).To(Equal(".0001.cls"))
+			// record key cannot be empty
+			Expect(ext("a/.jpg")).To(Equal(".jpg"))
+			Expect(ext("a/.seg.png")).To(Equal(".png"))
+		})
+	})
 })
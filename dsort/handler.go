@@ -40,8 +40,19 @@ type response struct {
 
 // POST /v1/sort
 func ProxyStartSortHandler(w http.ResponseWriter, r *http.Request, parsedRS *ParsedRequestSpec) {
-	var err error
-	parsedRS.TargetOrderSalt = []byte(time.Now().Format("15:04:05.000000"))
+	var (
+		err         error
+		managerUUID = cos.GenUUID()
+	)
+	if parsedRS.Resume != "" {
+		managerUUID = parsedRS.Resume
+	}
+	if parsedRS.Resumable {
+		// must be the same every time the job is (re)started
+		parsedRS.TargetOrderSalt = resumableSalt(managerUUID)
+	} else {
+		parsedRS.TargetOrderSalt = []byte(time.Now().Format("15:04:05.000000"))
+	}
 
 	// TODO: handle case when bucket was removed during dSort job - this should
 	// stop whole operation. Maybe some listeners as we have on smap change?
@@ -61,10 +72,7 @@ func ProxyStartSortHandler(w http.ResponseWriter, r *http.Request, parsedRS *Par
 		return
 	}
 
-	var (
-		managerUUID = cos.GenUUID()
-		smap        = ctx.smapOwner.Get()
-	)
+	smap := ctx.smapOwner.Get()
 	checkResponses := func(responses []response) error {
 		for _, resp := range responses {
 			if resp.err == nil {
@@ -105,11 +113,27 @@ func ProxyStartSortHandler(w http.ResponseWriter, r *http.Request, parsedRS *Par
 		return
 	}
 
+	// When resuming, each target responds with the phase it has checkpointed -
+	// the job continues from the earliest one.
+	var query url.Values
+	if parsedRS.Resume != "" {
+		phase := phaseDistributed
+		for _, resp := range responses {
+			p, err := strconv.Atoi(string(resp.res))
+			if err != nil {
+				p = phaseNone
+			}
+			phase = cos.Min(phase, p)
+		}
+		glog.Infof("[dsort] %s resuming from phase %d", managerUUID, phase)
+		query = url.Values{apc.QparamResumePhase: []string{strconv.Itoa(phase)}}
+	}
+
 	if glog.V(4) {
 		glog.Infof("[dsort] %s broadcasting start request to all targets", managerUUID)
 	}
 	path = apc.URLPathdSortStart.Join(managerUUID)
-	responses = broadcastTargets(http.MethodPost, path, nil, nil, smap)
+	responses = broadcastTargets(http.MethodPost, path, query, nil, smap)
 	if err := checkResponses(responses); err != nil {
 		return
 	}
@@ -394,6 +418,14 @@ func initSortHandler(w http.ResponseWriter, r *http.Request) {
 		cmn.WriteErr(w, r, err)
 		return
 	}
+	if rs.Resume != "" {
+		phase, err := dsortManager.loadCheckpoint()
+		if err != nil {
+			cmn.WriteErr(w, r, err)
+			return
+		}
+		w.Write([]byte(strconv.Itoa(phase)))
+	}
 }
 
 // startSortHandler is the handler called for the HTTP endpoint /v1/sort/start.
@@ -417,6 +449,14 @@ func startSortHandler(w http.ResponseWriter, r *http.Request) {
 		cmn.WriteErrMsg(w, r, s, http.StatusNotFound)
 		return
 	}
+	if rph := r.URL.Query().Get(apc.QparamResumePhase); rph != "" {
+		phase, err := strconv.Atoi(rph)
+		if err != nil || phase < phaseNone || phase > phaseDistributed {
+			cmn.WriteErrMsg(w, r, fmt.Sprintf("invalid resume phase %q", rph))
+			return
+		}
+		dsortManager.resume.phase = phase
+	}
 
 	go dsortManager.startDSort()
 }
@@ -796,6 +836,9 @@ func determineDSorterType(parsedRS *ParsedRequestSpec) (string, error) {
 	if parsedRS.DSorterType != "" {
 		return parsedRS.DSorterType, nil // in case the dsorter type is already set, we need to respect it
 	}
+	if parsedRS.Resumable {
+		return DSorterGeneralType, nil // only general dsorter can skip already created shards
+	}
 
 	// Get memory stats from targets
 	var (
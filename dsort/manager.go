@@ -130,6 +130,11 @@ type (
 		creationPhase struct {
 			metadata CreationPhaseMetadata
 		}
+		resume struct {
+			ckpt  *checkpoint // last saved (or loaded) checkpoint, if any
+			phase int         // phase to resume from (see checkpoint.go)
+			mu    sync.Mutex  // serializes appending created shards
+		}
 		finishedAck struct {
 			mu sync.Mutex
 			m  map[string]struct{} // finished acks: daemonID -> ack
@@ -373,7 +378,14 @@ func (m *Manager) finalCleanup() {
 	// The reason why this is not in regular cleanup is because we are only sure
 	// that this can be freed once we cleanup streams - streams are asynchronous
 	// and we may have race between in-flight request and cleanup.
-	m.recManager.Cleanup()
+	//
+	// Aborted resumable job retains its extracted records and checkpoints
+	// so that it can be resumed later.
+	keep := m.rs.Resumable && m.aborted()
+	m.recManager.Cleanup(keep)
+	if m.rs.Resumable && !keep {
+		m.removeCheckpoint(false /*extracted*/) // already removed (above)
+	}
 
 	m.creationPhase.metadata.SendOrder = nil
 	m.creationPhase.metadata.Shards = nil
@@ -452,7 +464,16 @@ func (m *Manager) setExtractCreator() (err error) {
 
 	switch m.rs.Algorithm.Kind {
 	case SortKindContent:
-		keyExtractor, err = extract.NewContentKeyExtractor(m.rs.Algorithm.FormatType, m.rs.Algorithm.Extension)
+		algo := m.rs.Algorithm
+		if algo.FieldPath == "" {
+			keyExtractor, err = extract.NewContentKeyExtractor(algo.FormatType, algo.Extension)
+			break
+		}
+		keys := []extract.FieldKey{{Path: algo.FieldPath, FormatType: algo.FormatType}}
+		if algo.Secondary != nil {
+			keys = append(keys, extract.FieldKey{Path: algo.Secondary.FieldPath, FormatType: algo.Secondary.FormatType})
+		}
+		keyExtractor, err = extract.NewFieldKeyExtractor(algo.Extension, keys...)
 	case SortKindMD5:
 		keyExtractor, err = extract.NewMD5KeyExtractor()
 	default:
@@ -475,6 +496,8 @@ func (m *Manager) setExtractCreator() (err error) {
 		extractCreator = extract.NewTargzExtractCreator(m.ctx.t)
 	case cos.ExtZip:
 		extractCreator = extract.NewZipExtractCreator(m.ctx.t)
+	case cos.ExtMsgpack:
+		extractCreator = extract.NewMsgpackExtractCreator(m.ctx.t)
 	default:
 		cos.Assertf(false, "unknown extension %s", m.rs.Extension)
 	}
@@ -487,7 +510,7 @@ func (m *Manager) setExtractCreator() (err error) {
 
 	m.recManager = extract.NewRecordManager(
 		m.ctx.t, m.rs.Bck,
-		m.rs.Extension, m.extractCreator,
+		m.rs.Extension, extract.NewExtFunc(m.rs.RecordFormat, m.rs.RecordExts), m.extractCreator,
 		keyExtractor, onDuplicatedRecords,
 	)
 
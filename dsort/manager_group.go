@@ -142,6 +142,12 @@ func (mg *ManagerGroup) Remove(managerUUID string) error {
 
 	key := path.Join(managersKey, managerUUID)
 	_ = mg.db.Delete(dsortCollection, key) // Delete only returns err when record does not exist, which should be ignored
+
+	// aborted resumable job will no longer be resumed
+	ckpt := &checkpoint{}
+	if err := mg.db.Get(dsortCollection, ckptKey(managerUUID), ckpt); err == nil {
+		mg.removeCheckpoint(managerUUID, &ckpt.RS.Bck, true /*extracted*/)
+	}
 	return nil
 }
 
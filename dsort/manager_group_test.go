@@ -12,8 +12,11 @@ import (
 	"github.com/NVIDIA/aistore/cluster/mock"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/dsort/filetype"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/memsys"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -119,6 +122,94 @@ var _ = Describe("ManagerGroup", func() {
 		})
 	})
 
+	Context("checkpoint", func() {
+		var resumableRS *ParsedRequestSpec
+
+		BeforeEach(func() {
+			mm = memsys.PageMM()
+			fs.CSM.Reg(fs.ObjectType, &fs.ObjectContentResolver{})
+			fs.CSM.Reg(filetype.DSortWorkfileType, &filetype.DSortFile{})
+			rs := *validRS
+			rs.Bck = cmn.Bck{Name: "bck", Provider: apc.ProviderAIS}
+			rs.Resumable = true
+			resumableRS = &rs
+		})
+
+		initManager := func(mg *ManagerGroup, rs *ParsedRequestSpec) *Manager {
+			m, err := mg.Add("uuid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(m.init(rs)).NotTo(HaveOccurred())
+			return m
+		}
+
+		It("should resume from the checkpointed phases skipping created shards", func() {
+			m := initManager(mgrp, resumableRS)
+			m.recManager.Records.Insert(&extract.Record{Key: "a", Name: "a", Objects: []*extract.RecordObj{{
+				ContentPath: "shard-a", StoreType: extract.OffsetStoreType, Extension: ".txt",
+			}}})
+			m.Metrics.Extraction.ExtractedRecordCnt = 1
+			Expect(m.checkpointExtracted()).NotTo(HaveOccurred())
+
+			m.creationPhase.metadata.Shards = []*extract.Shard{{Name: "out-1"}, {Name: "out-2"}, {Name: "out-3"}}
+			Expect(m.checkpointDistributed()).NotTo(HaveOccurred())
+			Expect(m.checkpointCreated("out-2")).NotTo(HaveOccurred())
+			m.unlock()
+
+			// same DB, new (restarted) manager group
+			rs := *resumableRS
+			rs.Resume = "uuid"
+			m = initManager(NewManagerGroup(mgrp.db, true), &rs)
+			defer m.unlock()
+			phase, err := m.loadCheckpoint()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(phaseDistributed))
+
+			Expect(m.resumeExtracted()).NotTo(HaveOccurred())
+			Expect(m.recManager.Records.Len()).To(Equal(1))
+			Expect(m.Metrics.Extraction.ExtractedRecordCnt).To(BeEquivalentTo(1))
+
+			Expect(m.resumeDistributed()).NotTo(HaveOccurred())
+			Expect(m.creationPhase.metadata.Shards).To(HaveLen(2))
+			Expect(m.creationPhase.metadata.Shards[0].Name).To(Equal("out-1"))
+			Expect(m.creationPhase.metadata.Shards[1].Name).To(Equal("out-3"))
+
+			m.removeCheckpoint(true /*extracted*/)
+			phase, err = m.loadCheckpoint()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal(phaseNone))
+		})
+
+		It("should not resume when targets have changed", func() {
+			m := initManager(mgrp, resumableRS)
+			Expect(m.checkpointExtracted()).NotTo(HaveOccurred())
+			m.unlock()
+
+			ctx.smapOwner = newTestSmap("target", "new-target")
+			defer func() { ctx.smapOwner = newTestSmap("target") }()
+
+			rs := *resumableRS
+			rs.Resume = "uuid"
+			m = initManager(NewManagerGroup(mgrp.db, true), &rs)
+			defer m.unlock()
+			_, err := m.loadCheckpoint()
+			Expect(err).To(Equal(errResumeTargetsChanged))
+		})
+
+		It("should not resume when specification differs", func() {
+			m := initManager(mgrp, resumableRS)
+			Expect(m.checkpointExtracted()).NotTo(HaveOccurred())
+			m.unlock()
+
+			rs := *resumableRS
+			rs.Resume = "uuid"
+			rs.OutputShardSize = 1024
+			m = initManager(NewManagerGroup(mgrp.db, true), &rs)
+			defer m.unlock()
+			_, err := m.loadCheckpoint()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	Context("housekeep", func() {
 		persistManager := func(uuid string, finishedAgo time.Duration) {
 			m, err := mgrp.Add(uuid)
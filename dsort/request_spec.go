@@ -34,7 +34,7 @@ var (
 
 var (
 	errMissingBucket            = errors.New("missing field 'bucket'")
-	errInvalidExtension         = errors.New("extension must be one of '.tar', '.tar.gz', or '.tgz'")
+	errInvalidExtension         = errors.New("extension must be one of '.tar', '.tar.gz', '.tgz', '.zip', or '.msgpack'")
 	errNegOutputShardSize       = errors.New("output shard size must be >= 0")
 	errEmptyOutputShardSize     = errors.New("output shard size must be set (cannot be 0)")
 	errNegativeConcurrencyLimit = errors.New("concurrency max limit must be 0 (limits will be calculated) or > 0")
@@ -44,8 +44,17 @@ var (
 	errInvalidAlgorithm          = errors.New("invalid algorithm specified")
 	errInvalidSeed               = errors.New("invalid seed provided, should be int")
 	errInvalidAlgorithmExtension = errors.New("invalid extension provided, should be in the format: .ext")
+	errInvalidSecondaryKey       = errors.New("secondary key requires content algorithm with field path")
+	errResumableDSorterType      = errors.New("resumable job cannot use '" + DSorterMemType + "' dsorter type")
+
+	errInvalidRecordFormat = fmt.Errorf("invalid record format, should be one of: %q", extract.SupportedRecordFormats)
+	errInvalidRecordExts   = errors.New("record extensions require WebDataset record format ('" +
+		extract.RecordFormatWDS + "') and must be in the format: .ext")
 )
 
+// output shard size can be specified as a number of records, e.g. "1000 records"
+const outputShardRecordsSuffix = "records"
+
 // supportedExtensions is a list of extensions (archives) supported by dSort
 var supportedExtensions = cos.ArchExtensions
 
@@ -77,7 +86,7 @@ type RequestSpec struct {
 	Extension       string  `json:"extension" yaml:"extension"`
 	InputFormat     string  `json:"input_format" yaml:"input_format"`
 	OutputFormat    string  `json:"output_format" yaml:"output_format"`
-	OutputShardSize string  `json:"output_shard_size" yaml:"output_shard_size"`
+	OutputShardSize string  `json:"output_shard_size" yaml:"output_shard_size"` // bytes (e.g. "10MiB") or "N records"
 
 	// Optional
 	Description string `json:"description" yaml:"description"`
@@ -85,6 +94,10 @@ type RequestSpec struct {
 	OutputBck cmn.Bck `json:"output_bck" yaml:"output_bck"`
 	// Default: alphanumeric, increasing
 	Algorithm SortAlgorithm `json:"algorithm" yaml:"algorithm"`
+	// Default: "" - record object's extension begins at the first dot of its basename
+	RecordFormat string `json:"record_format" yaml:"record_format"`
+	// Default: none (used with WebDataset record format)
+	RecordExts []string `json:"record_exts" yaml:"record_exts"`
 	// Default: ""
 	OrderFileURL string `json:"order_file" yaml:"order_file"`
 	// Default: "\t"
@@ -99,6 +112,12 @@ type RequestSpec struct {
 	StreamMultiplier int `json:"stream_multiplier" yaml:"stream_multiplier"`
 	// Default: false
 	ExtendedMetrics bool `json:"extended_metrics" yaml:"extended_metrics"`
+	// Default: false - when true, finished phases are checkpointed on each target
+	// so that the job, if aborted, can be resumed (see `Resume`)
+	Resumable bool `json:"resumable" yaml:"resumable"`
+	// Default: "" - ID of the previously aborted resumable job to resume
+	// from its last checkpointed phase (requires the same specification)
+	Resume string `json:"resume" yaml:"resume"`
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -113,6 +132,9 @@ type ParsedRequestSpec struct {
 	OutputBck           cmn.Bck               `json:"output_bck"`
 	Extension           string                `json:"extension"`
 	OutputShardSize     int64                 `json:"output_shard_size,string"`
+	OutputShardRecords  int64                 `json:"output_shard_records,string"` // when non-zero, overrides the size in bytes
+	RecordFormat        string                `json:"record_format"`
+	RecordExts          []string              `json:"record_exts"`
 	InputFormat         *parsedInputTemplate  `json:"input_format"`
 	OutputFormat        *parsedOutputTemplate `json:"output_format"`
 	Algorithm           *SortAlgorithm        `json:"algorithm"`
@@ -124,6 +146,8 @@ type ParsedRequestSpec struct {
 	CreateConcMaxLimit  int                   `json:"create_concurrency_max_limit"`
 	StreamMultiplier    int                   `json:"stream_multiplier"` // TODO: should be removed
 	ExtendedMetrics     bool                  `json:"extended_metrics"`
+	Resumable           bool                  `json:"resumable"`
+	Resume              string                `json:"resume"`
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -144,6 +168,21 @@ type SortAlgorithm struct {
 	// Kind: content
 	Extension  string `json:"extension"`
 	FormatType string `json:"format_type"`
+
+	// Kind: content (optional)
+	// JSONPath-style path to the key inside JSON (or msgpack, when the extension
+	// is ".msgpack") content of the file with provided extension, e.g. "$.label"
+	FieldPath string `json:"field_path"`
+	// Secondary key used to sort the records which have equal (primary) keys
+	// (requires `FieldPath`)
+	Secondary *SecondaryKey `json:"secondary,omitempty"`
+}
+
+// SecondaryKey is the key located in the same content as the primary one (see `SortAlgorithm`).
+type SecondaryKey struct {
+	FieldPath  string `json:"field_path"`
+	FormatType string `json:"format_type"`
+	Decreasing bool   `json:"decreasing"`
 }
 
 // Parse returns a non-nil error if a RequestSpec is invalid. When RequestSpec
@@ -183,12 +222,11 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 	}
 	parsedRS.Extension = rs.Extension
 
-	parsedRS.OutputShardSize, err = cos.S2B(rs.OutputShardSize)
-	if err != nil {
+	if err := parsedRS.parseOutputShardSize(rs.OutputShardSize); err != nil {
 		return nil, err
 	}
-	if parsedRS.OutputShardSize < 0 {
-		return nil, errNegOutputShardSize
+	if err := parsedRS.parseRecordFormat(rs.RecordFormat, rs.RecordExts); err != nil {
+		return nil, err
 	}
 
 	parsedRS.Algorithm, err = parseAlgorithm(rs.Algorithm)
@@ -204,13 +242,13 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 		}
 		if parsedRS.OutputFormat.Template.Count() > math.MaxInt32 {
 			// If the count is not defined then the output shard size must be set.
-			if parsedRS.OutputShardSize == 0 {
+			if !parsedRS.hasOutputShardSize() {
 				return nil, errEmptyOutputShardSize
 			}
 		}
 	} else { // Valid and not empty.
 		// For the order file the output shard size must be set.
-		if parsedRS.OutputShardSize == 0 {
+		if !parsedRS.hasOutputShardSize() {
 			return nil, errEmptyOutputShardSize
 		}
 
@@ -245,6 +283,12 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 	parsedRS.DSorterType = rs.DSorterType
 	parsedRS.DryRun = rs.DryRun
 
+	parsedRS.Resume = strings.TrimSpace(rs.Resume)
+	parsedRS.Resumable = rs.Resumable || parsedRS.Resume != ""
+	if parsedRS.Resumable && parsedRS.DSorterType == DSorterMemType {
+		return nil, errResumableDSorterType
+	}
+
 	// Check for values that override the global config.
 	if err := rs.DSortConf.ValidateWithOpts(true); err != nil {
 		return nil, err
@@ -269,6 +313,53 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 	return parsedRS, nil
 }
 
+// parseOutputShardSize parses the output shard size that is either in bytes
+// (raw number or suffixed, e.g. "10KB") or in records (e.g. "1000 records")
+func (parsedRS *ParsedRequestSpec) parseOutputShardSize(size string) (err error) {
+	size = strings.TrimSpace(size)
+	if !strings.HasSuffix(size, outputShardRecordsSuffix) {
+		if parsedRS.OutputShardSize, err = cos.S2B(size); err != nil {
+			return
+		}
+		if parsedRS.OutputShardSize < 0 {
+			return errNegOutputShardSize
+		}
+		return
+	}
+	cnt := strings.TrimSpace(strings.TrimSuffix(size, outputShardRecordsSuffix))
+	if parsedRS.OutputShardRecords, err = strconv.ParseInt(cnt, 10, 64); err != nil {
+		return fmt.Errorf("invalid output shard size %q: %v", size, err)
+	}
+	if parsedRS.OutputShardRecords < 0 {
+		return errNegOutputShardSize
+	}
+	return
+}
+
+func (parsedRS *ParsedRequestSpec) hasOutputShardSize() bool {
+	return parsedRS.OutputShardSize != 0 || parsedRS.OutputShardRecords != 0
+}
+
+// parseRecordFormat validates the record format and (WebDataset) record extensions
+func (parsedRS *ParsedRequestSpec) parseRecordFormat(format string, exts []string) error {
+	if !cos.StringInSlice(format, extract.SupportedRecordFormats) {
+		return errInvalidRecordFormat
+	}
+	if len(exts) > 0 && format != extract.RecordFormatWDS {
+		return errInvalidRecordExts
+	}
+	for i, ext := range exts {
+		ext = strings.TrimSpace(ext)
+		if len(ext) < 2 || ext[0] != '.' || strings.ContainsRune(ext, '/') {
+			return errInvalidRecordExts
+		}
+		exts[i] = ext
+	}
+	parsedRS.RecordFormat = format
+	parsedRS.RecordExts = exts
+	return nil
+}
+
 // validateExtension checks if extension is supported by dsort
 func validateExtension(ext string) bool {
 	return cos.StringInSlice(ext, supportedExtensions)
@@ -325,10 +416,32 @@ func parseAlgorithm(algo SortAlgorithm) (parsedAlgo *SortAlgorithm, err error) {
 		if err := extract.ValidateAlgorithmFormatType(algo.FormatType); err != nil {
 			return nil, err
 		}
+
+		algo.FieldPath = strings.TrimSpace(algo.FieldPath)
+		if algo.FieldPath != "" {
+			if err := extract.ValidateFieldPath(algo.FieldPath); err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		algo.FormatType = extract.FormatTypeString
 	}
 
+	if algo.Secondary != nil {
+		if algo.Kind != SortKindContent || algo.FieldPath == "" {
+			return nil, errInvalidSecondaryKey
+		}
+		secondary := *algo.Secondary
+		secondary.FieldPath = strings.TrimSpace(secondary.FieldPath)
+		if err := extract.ValidateFieldPath(secondary.FieldPath); err != nil {
+			return nil, err
+		}
+		if err := extract.ValidateAlgorithmFormatType(secondary.FormatType); err != nil {
+			return nil, err
+		}
+		algo.Secondary = &secondary
+	}
+
 	return &algo, nil
 }
 
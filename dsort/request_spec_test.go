@@ -13,6 +13,7 @@ import (
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/fs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -158,6 +159,95 @@ var _ = Describe("RequestSpec", func() {
 			Expect(parsed.Extension).To(Equal(cos.ExtZip))
 		})
 
+		It("should parse spec with .msgpack extension", func() {
+			rs := RequestSpec{
+				Bck:             cmn.Bck{Name: "test"},
+				Extension:       cos.ExtMsgpack,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.Extension).To(Equal(cos.ExtMsgpack))
+		})
+
+		It("should parse spec with output shard size in records", func() {
+			rs := RequestSpec{
+				Bck:             cmn.Bck{Name: "test"},
+				Extension:       cos.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-%06d-suffix",
+				OutputShardSize: "1000 records",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.OutputShardSize).To(BeEquivalentTo(0))
+			Expect(parsed.OutputShardRecords).To(BeEquivalentTo(1000))
+		})
+
+		It("should parse spec with WebDataset record format", func() {
+			rs := RequestSpec{
+				Bck:             cmn.Bck{Name: "test"},
+				Extension:       cos.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				RecordFormat:    extract.RecordFormatWDS,
+				RecordExts:      []string{".jpg", " .seg.png"},
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.RecordFormat).To(Equal(extract.RecordFormatWDS))
+			Expect(parsed.RecordExts).To(Equal([]string{".jpg", ".seg.png"}))
+		})
+
+		It("should parse spec with content algorithm and secondary key", func() {
+			rs := RequestSpec{
+				Bck:             cmn.Bck{Name: "test"},
+				Extension:       cos.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm: SortAlgorithm{
+					Kind:       SortKindContent,
+					Extension:  ".json",
+					FormatType: extract.FormatTypeString,
+					FieldPath:  " $.label ",
+					Secondary:  &SecondaryKey{FieldPath: "$.ids[0]", FormatType: extract.FormatTypeInt},
+				},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.Algorithm.FieldPath).To(Equal("$.label"))
+			Expect(parsed.Algorithm.Secondary.FieldPath).To(Equal("$.ids[0]"))
+			Expect(parsed.Algorithm.Secondary.FormatType).To(Equal(extract.FormatTypeInt))
+		})
+
+		It("should parse resumable spec", func() {
+			rs := RequestSpec{
+				Bck:             cmn.Bck{Name: "test"},
+				Extension:       cos.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+				Resume:          " job-id ",
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.Resume).To(Equal("job-id"))
+			Expect(parsed.Resumable).To(BeTrue())
+		})
+
 		It("should parse spec with %06d syntax", func() {
 			rs := RequestSpec{
 				Bck:             cmn.Bck{Name: "test"},
@@ -384,6 +474,107 @@ var _ = Describe("RequestSpec", func() {
 			Expect(err).To(Equal(errInvalidExtension))
 		})
 
+		It("should fail due to invalid output shard size in records", func() {
+			for _, size := range []string{"-10 records", "ten records", "1.5 records"} {
+				rs := RequestSpec{
+					Bck:             cmn.Bck{Name: "test"},
+					Extension:       cos.ExtTar,
+					InputFormat:     "prefix-{0010..0111}-suffix",
+					OutputFormat:    "prefix-{0010..0111}-suffix",
+					OutputShardSize: size,
+					Algorithm:       SortAlgorithm{Kind: SortKindNone},
+				}
+				_, err := rs.Parse()
+				Expect(err).Should(HaveOccurred(), size)
+			}
+		})
+
+		It("should fail due to invalid record format", func() {
+			rs := RequestSpec{
+				Bck:             cmn.Bck{Name: "test"},
+				Extension:       cos.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				RecordFormat:    "something",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errInvalidRecordFormat))
+		})
+
+		It("should fail due to invalid record extensions", func() {
+			for _, tc := range []struct {
+				format string
+				exts   []string
+			}{
+				{format: extract.RecordFormatDefault, exts: []string{".jpg"}},
+				{format: extract.RecordFormatWDS, exts: []string{"jpg"}},
+				{format: extract.RecordFormatWDS, exts: []string{"."}},
+				{format: extract.RecordFormatWDS, exts: []string{".a/b"}},
+			} {
+				rs := RequestSpec{
+					Bck:             cmn.Bck{Name: "test"},
+					Extension:       cos.ExtTar,
+					InputFormat:     "prefix-{0010..0111}-suffix",
+					OutputFormat:    "prefix-{0010..0111}-suffix",
+					OutputShardSize: "10KB",
+					RecordFormat:    tc.format,
+					RecordExts:      tc.exts,
+					Algorithm:       SortAlgorithm{Kind: SortKindNone},
+				}
+				_, err := rs.Parse()
+				Expect(err).Should(HaveOccurred())
+				Expect(err).To(Equal(errInvalidRecordExts))
+			}
+		})
+
+		It("should fail due to invalid content algorithm keys", func() {
+			for _, algo := range []SortAlgorithm{
+				{Kind: SortKindContent, Extension: ".json", FormatType: extract.FormatTypeInt, FieldPath: "$.a["},
+				{
+					Kind: SortKindContent, Extension: ".json", FormatType: extract.FormatTypeInt,
+					Secondary: &SecondaryKey{FieldPath: "$.b", FormatType: extract.FormatTypeInt},
+				},
+				{
+					Kind:      SortKindAlphanumeric,
+					Secondary: &SecondaryKey{FieldPath: "$.b", FormatType: extract.FormatTypeInt},
+				},
+				{
+					Kind: SortKindContent, Extension: ".json", FormatType: extract.FormatTypeInt, FieldPath: "$.a",
+					Secondary: &SecondaryKey{FieldPath: "$.b", FormatType: "bytes"},
+				},
+			} {
+				rs := RequestSpec{
+					Bck:             cmn.Bck{Name: "test"},
+					Extension:       cos.ExtTar,
+					InputFormat:     "prefix-{0010..0111}-suffix",
+					OutputFormat:    "prefix-{0010..0111}-suffix",
+					OutputShardSize: "10KB",
+					Algorithm:       algo,
+				}
+				_, err := rs.Parse()
+				Expect(err).Should(HaveOccurred())
+			}
+		})
+
+		It("should fail due to resumable job with memory dsorter", func() {
+			rs := RequestSpec{
+				Bck:             cmn.Bck{Name: "test"},
+				Extension:       cos.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+				Resumable:       true,
+				DSorterType:     DSorterMemType,
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errResumableDSorterType))
+		})
+
 		It("should fail due to invalid mem usage specification", func() {
 			rs := RequestSpec{
 				Bck:             cmn.Bck{Name: "test"},
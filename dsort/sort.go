@@ -5,6 +5,7 @@
 package dsort
 
 import (
+	"fmt"
 	"math/rand"
 	"sort"
 	"strconv"
@@ -34,6 +35,7 @@ type (
 		*extract.Records
 		decreasing bool
 		formatType string
+		secondary  *SecondaryKey // when set, the keys are (primary, secondary) pairs
 		err        error
 	}
 )
@@ -47,9 +49,12 @@ func (s *alphaByKey) Less(i, j int) bool {
 		err  error
 	)
 
-	if s.decreasing {
+	switch {
+	case s.secondary != nil:
+		less, err = s.multiKeyLess(i, j)
+	case s.decreasing:
 		less, err = s.Records.Less(j, i, s.formatType)
-	} else {
+	default:
 		less, err = s.Records.Less(i, j, s.formatType)
 	}
 
@@ -59,6 +64,33 @@ func (s *alphaByKey) Less(i, j int) bool {
 	return less
 }
 
+// multiKeyLess compares primary keys of the records and, if equal, their
+// secondary keys - each in its own (increasing or decreasing) order.
+func (s *alphaByKey) multiKeyLess(i, j int) (bool, error) {
+	lhs, rhs, err := s.Records.Keys(i, j)
+	if err != nil {
+		return false, err
+	}
+	lkeys, lok := lhs.([]interface{})
+	rkeys, rok := rhs.([]interface{})
+	if !lok || !rok || len(lkeys) != 2 || len(rkeys) != 2 {
+		return false, fmt.Errorf("expecting (primary, secondary) keys, got %v and %v", lhs, rhs)
+	}
+
+	c := extract.CompareKeys(lkeys[0], rkeys[0], s.formatType)
+	if s.decreasing {
+		c = -c
+	}
+	if c != 0 {
+		return c < 0, nil
+	}
+	c = extract.CompareKeys(lkeys[1], rkeys[1], s.secondary.FormatType)
+	if s.secondary.Decreasing {
+		c = -c
+	}
+	return c < 0, nil
+}
+
 // sortRecords sorts records by each Record.Key in the order determined by sort algorithm.
 func sortRecords(r *extract.Records, algo *SortAlgorithm) (err error) {
 	if algo.Kind == SortKindNone {
@@ -78,7 +110,7 @@ func sortRecords(r *extract.Records, algo *SortAlgorithm) (err error) {
 			r.Swap(i, j)
 		}
 	} else {
-		keys := &alphaByKey{r, algo.Decreasing, algo.FormatType, nil}
+		keys := &alphaByKey{r, algo.Decreasing, algo.FormatType, algo.Secondary, nil}
 		sort.Sort(keys)
 
 		if keys.err != nil {
@@ -95,4 +95,41 @@ var _ = Describe("SortRecords", func() {
 		err := sortRecords(fm, &SortAlgorithm{Decreasing: true, FormatType: extract.FormatTypeString})
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("should sort records by secondary key when primary keys are equal", func() {
+		key := func(primary string, secondary int64) []interface{} { return []interface{}{primary, secondary} }
+		expected := createRecords(key("abc", 2), key("abc", 1), key("def", 3), key("def", 1))
+		fm := createRecords(key("def", 1), key("abc", 1), key("def", 3), key("abc", 2))
+		err := sortRecords(fm, &SortAlgorithm{
+			Kind:       SortKindContent,
+			FormatType: extract.FormatTypeString,
+			Secondary:  &SecondaryKey{FormatType: extract.FormatTypeInt, Decreasing: true},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fm).To(Equal(expected))
+	})
+
+	It("should sort records by secondary key when primary keys were decoded as floats", func() {
+		key := func(primary, secondary float64) []interface{} { return []interface{}{primary, secondary} }
+		expected := createRecords(key(20, 1), key(20, 2), key(10, 1))
+		fm := createRecords(key(10, 1), key(20, 2), key(20, 1))
+		err := sortRecords(fm, &SortAlgorithm{
+			Kind:       SortKindContent,
+			Decreasing: true,
+			FormatType: extract.FormatTypeInt,
+			Secondary:  &SecondaryKey{FormatType: extract.FormatTypeInt},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fm).To(Equal(expected))
+	})
+
+	It("should return error when secondary key is missing", func() {
+		fm := createRecords([]interface{}{"abc", int64(1)}, "def")
+		err := sortRecords(fm, &SortAlgorithm{
+			Kind:       SortKindContent,
+			FormatType: extract.FormatTypeString,
+			Secondary:  &SecondaryKey{FormatType: extract.FormatTypeInt},
+		})
+		Expect(err).To(HaveOccurred())
+	})
 })
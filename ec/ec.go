@@ -27,6 +27,8 @@ import (
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xact/xreg"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/klauspost/reedsolomon"
 )
 
 // EC module provides data protection on a per bucket basis. By default, the
@@ -36,6 +38,13 @@ import (
 //		DataSlices: [1-32]    # the number of data slices
 //		ParitySlices: [1-32]  # the number of parity slices
 //		ObjSizeLimit: 0       # replication versus erasure coding
+//		Codec: reed-solomon   # or reed-solomon-cauchy
+//		Policies: [...]       # per-prefix and/or per-size-class layouts
+//
+// NOTE: EC policies override the layout (and the replication threshold) above
+// for the matching objects, e.g. 8+2 for large shards and 3 replicas for small
+// files. The layout (and codec) an object was written with is recorded in its
+// metadata - restoring and rebalancing use the metadata, not the current config.
 //
 // NOTE: replicating small object is cheaper than erasure encoding.
 // The ObjSizeLimit option sets the corresponding threshold. Set it to the
@@ -114,8 +123,9 @@ const (
 	ActClearRequests  = "clear-requests"
 	ActEnableRequests = "enable-requests"
 
-	URLCT   = "ct"   // for using in URL path - requests for slices/replicas
-	URLMeta = "meta" /// .. - metadata requests
+	URLCT    = "ct"    // for using in URL path - requests for slices/replicas
+	URLMeta  = "meta"  /// .. - metadata requests
+	URLMetas = "metas" /// .. - metadata of multiple objects (in a single request)
 
 	// EC switches to disk from SGL when memory pressure is high and the amount of
 	// memory required to encode an object exceeds the limit
@@ -130,10 +140,11 @@ type (
 		ErrCh    chan error  // for final EC result (used only in restore)
 		Callback cluster.OnFinishObj
 
-		putTime time.Time // time when the object is put into main queue
-		tm      time.Time // to measure different steps
-		IsCopy  bool      // replicate or use erasure coding
-		rebuild bool      // true - internal request to reencode, e.g., from ec-encode xaction
+		putTime time.Time    // time when the object is put into main queue
+		tm      time.Time    // to measure different steps
+		policy  cmn.ECPolicy // layout to encode the object with
+		IsCopy  bool         // replicate or use erasure coding
+		rebuild bool         // true - internal request to reencode, e.g., from ec-encode xaction
 	}
 
 	RequestsControlMsg struct {
@@ -279,6 +290,7 @@ func Init(t cluster.Target) {
 	xreg.RegBckXact(&putFactory{})
 	xreg.RegBckXact(&rspFactory{})
 	xreg.RegBckXact(&encFactory{})
+	xreg.RegBckXact(&scrubFactory{})
 
 	if err := initManager(t); err != nil {
 		cos.ExitLogf("Failed to init manager: %v", err)
@@ -302,6 +314,18 @@ func IsECCopy(size int64, ecConf *cmn.ECConf) bool {
 	return size < ecConf.ObjSizeLimit
 }
 
+// newCodec returns streaming encoder/decoder of a given codec (see cmn.ECCodecRS)
+func newCodec(codec string, dataSlices, paritySlices int) (reedsolomon.StreamEncoder, error) {
+	switch codec {
+	case "", cmn.ECCodecRS:
+		return reedsolomon.NewStreamC(dataSlices, paritySlices, true, true)
+	case cmn.ECCodecRSCauchy:
+		return reedsolomon.NewStreamC(dataSlices, paritySlices, true, true, reedsolomon.WithCauchyMatrix())
+	default:
+		return nil, fmt.Errorf("unknown EC codec %q", codec)
+	}
+}
+
 // returns whether EC must use disk instead of keeping everything in memory.
 // Depends on available free memory and size of an object to process
 func useDisk(objSize int64) bool {
@@ -376,6 +400,35 @@ func RequestECMeta(bck *cmn.Bck, objName string, si *cluster.Snode, client *http
 	return MetaFromReader(resp.Body)
 }
 
+// RequestECMetas returns EC metadata of the (named) objects found on a remote target.
+// Objects that the target does not have (or whose metadata it fails to load) are
+// not included.
+func RequestECMetas(bck *cmn.Bck, objNames []string, si *cluster.Snode,
+	client *http.Client) (mds map[string]*Metadata, err error) {
+	path := apc.URLPathEC.Join(URLMetas, bck.Name)
+	query := url.Values{}
+	query = bck.AddToQuery(query)
+	url := si.URL(cmn.NetIntraData) + path
+	rq, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(cos.MustMarshal(objNames)))
+	if err != nil {
+		return nil, err
+	}
+	rq.URL.RawQuery = query.Encode()
+	rq.Header.Set(cmn.HdrContentType, cmn.ContentJSON)
+	resp, err := client.Do(rq) // nolint:bodyclose // closed inside cos.Close
+	if err != nil {
+		return nil, err
+	}
+	defer cos.Close(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read metadata of %d object(s) from %s: status %d",
+			len(objNames), si, resp.StatusCode)
+	}
+	mds = make(map[string]*Metadata, len(objNames))
+	err = jsoniter.NewDecoder(resp.Body).Decode(&mds)
+	return
+}
+
 // Saves the main replica to local drives
 func writeObject(t cluster.Target, lom *cluster.LOM, reader io.Reader, size int64, xctn cluster.Xact) error {
 	if size > 0 {
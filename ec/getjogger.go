@@ -23,7 +23,6 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/transport"
-	"github.com/klauspost/reedsolomon"
 )
 
 type (
@@ -525,7 +524,7 @@ func (c *getJogger) restoreMainObj(ctx *restoreCtx) ([]*slice, error) {
 	if glog.FastV(4, glog.SmoduleEC) {
 		glog.Infof("Reconstructing %s", ctx.lom)
 	}
-	stream, err := reedsolomon.NewStreamC(ctx.meta.Data, ctx.meta.Parity, true, true)
+	stream, err := newCodec(ctx.meta.Codec, ctx.meta.Data, ctx.meta.Parity)
 	if err != nil {
 		return restored, err
 	}
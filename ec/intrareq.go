@@ -23,6 +23,9 @@ const (
 	// a target cleans up the object and notifies all other targets to do
 	// cleanup as well. Destinations do not have to respond
 	reqDel
+	// a target has found its slice or replica corrupted (or missing), and asks
+	// the main target to repair the object. The destination does not respond
+	reqRepair
 )
 
 type (
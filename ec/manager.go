@@ -281,12 +281,12 @@ func (mgr *Manager) EncodeObject(lom *cluster.LOM, cb ...cluster.OnFinishObj) er
 	if cs := fs.GetCapStatus(); cs.Err != nil {
 		return cs.Err
 	}
-	isECCopy := IsECCopy(lom.SizeBytes(), &lom.Bprops().EC)
+	policy := lom.Bprops().EC.Policy(lom.ObjName, lom.SizeBytes())
 	targetCnt := mgr.targetCnt.Load()
 
 	// tradeoff: encoding small object might require just 1 additional target available
 	// we will start xaction to satisfy this request
-	if required := lom.Bprops().EC.RequiredEncodeTargets(); !isECCopy && int(targetCnt) < required {
+	if required := policy.RequiredEncodeTargets(); !policy.Replicate && int(targetCnt) < required {
 		glog.Warningf("not enough targets to encode the object; actual: %v, required: %v", targetCnt, required)
 		return cmn.ErrNotEnoughTargets
 	}
@@ -296,7 +296,8 @@ func (mgr *Manager) EncodeObject(lom *cluster.LOM, cb ...cluster.OnFinishObj) er
 	}
 
 	req := allocateReq(ActSplit, lom.LIF())
-	req.IsCopy = IsECCopy(lom.SizeBytes(), &lom.Bprops().EC)
+	req.policy = policy
+	req.IsCopy = policy.Replicate
 	if len(cb) != 0 {
 		req.rebuild = true
 		req.Callback = cb[0]
@@ -327,7 +328,7 @@ func (mgr *Manager) RestoreObject(lom *cluster.LOM) error {
 	}
 	targetCnt := mgr.targetCnt.Load()
 	// NOTE: Restore replica object is done with GFN, safe to always abort.
-	if required := lom.Bprops().EC.RequiredRestoreTargets(); int(targetCnt) < required {
+	if required := RequiredRestoreTargets(lom); int(targetCnt) < required {
 		glog.Warningf("not enough targets to restore the object; actual: %v, required: %v", targetCnt, required)
 		return cmn.ErrNotEnoughTargets
 	}
@@ -417,7 +418,8 @@ func (mgr *Manager) ListenSmapChanged() {
 		if !bckProps.EC.Enabled {
 			return false
 		}
-		if required := bckProps.EC.RequiredEncodeTargets(); targetCnt < required {
+		// (the layout of each object is checked when encoding - see EncodeObject)
+		if required := bckProps.EC.MinEncodeTargets(); targetCnt < required {
 			glog.Warningf("not enough targets for EC encoding for bucket %s; actual: %v, expected: %v",
 				bckName, targetCnt, required)
 			bckXacts.AbortPut()
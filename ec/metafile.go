@@ -15,7 +15,10 @@ import (
 	"github.com/OneOfOne/xxhash"
 )
 
-const MDVersionLast = 1 // current version of metadata
+const (
+	mdVersion1    = 1 // (no codec and policy)
+	MDVersionLast = 2 // current version of metadata
+)
 
 // Metadata - EC information stored in metafiles for every encoded object
 type Metadata struct {
@@ -29,6 +32,8 @@ type Metadata struct {
 	Daemons     cos.MapStrUint16 `json:"nodes"`         // Locations of all slices: DaemonID <-> SliceID
 	Data        int              `json:"data_slices"`   // the number of data slices
 	Parity      int              `json:"parity_slices"` // the number of parity slices
+	Codec       string           `json:"codec"`         // codec that has generated parity slices (empty: cmn.ECCodecRS)
+	Policy      string           `json:"policy"`        // name of the EC policy (empty: bucket's default)
 	SliceID     int              `json:"slice_id"`      // 0 for full replica, 1 to N for slices
 	MDVersion   uint32           `json:"md_version"`    // Metadata format version
 	IsCopy      bool             `json:"is_copy"`       // object is replicated(true) or encoded(false)
@@ -119,17 +124,33 @@ func ObjectMetadata(bck *cluster.Bck, objName string) (*Metadata, error) {
 	return LoadMetadata(fqn)
 }
 
+// RequiredRestoreTargets returns the number of targets to restore the object
+// according to its recorded layout or, if the object's metadata is not available
+// locally, the minimum for the bucket.
+func RequiredRestoreTargets(lom *cluster.LOM) int {
+	ecConf := &lom.Bprops().EC
+	if ecConf.Enabled {
+		if md, err := ObjectMetadata(lom.Bck(), lom.ObjName); err == nil {
+			if md.IsCopy {
+				return 1
+			}
+			return md.Data
+		}
+	}
+	return ecConf.RequiredRestoreTargets()
+}
+
 func (md *Metadata) Unpack(unpacker *cos.ByteUnpack) (err error) {
 	var cksum uint64
 	if md.MDVersion, err = unpacker.ReadUint32(); err != nil {
 		return
 	}
 	switch md.MDVersion {
-	case MDVersionLast:
+	case mdVersion1, MDVersionLast:
 		err = md.unpackLastVersion(unpacker)
 	default:
-		err = fmt.Errorf("unsupported metadata format version %d. Only %d supported",
-			md.MDVersion, MDVersionLast)
+		err = fmt.Errorf("unsupported metadata format version %d. Only %d and %d supported",
+			md.MDVersion, mdVersion1, MDVersionLast)
 	}
 	if err != nil {
 		return
@@ -184,7 +205,17 @@ func (md *Metadata) unpackLastVersion(unpacker *cos.ByteUnpack) (err error) {
 	if md.CksumValue, err = unpacker.ReadString(); err != nil {
 		return
 	}
-	md.Daemons, err = unpacker.ReadMapStrUint16()
+	if md.Daemons, err = unpacker.ReadMapStrUint16(); err != nil {
+		return
+	}
+	if md.MDVersion == mdVersion1 {
+		md.MDVersion = MDVersionLast // upgrade (written with default codec and policy)
+		return
+	}
+	if md.Codec, err = unpacker.ReadString(); err != nil {
+		return
+	}
+	md.Policy, err = unpacker.ReadString()
 	return
 }
 
@@ -202,6 +233,8 @@ func (md *Metadata) Pack(packer *cos.BytePack) {
 	packer.WriteString(md.CksumType)
 	packer.WriteString(md.CksumValue)
 	packer.WriteMapStrUint16(md.Daemons)
+	packer.WriteString(md.Codec)
+	packer.WriteString(md.Policy)
 	h := xxhash.Checksum64S(packer.Bytes(), cos.MLCG32)
 	packer.WriteUint64(h)
 }
@@ -214,5 +247,6 @@ func (md *Metadata) PackedSize() int {
 	return cos.SizeofI32 + cos.SizeofI64*2 + cos.SizeofI16*3 + 1 /*isCopy*/ +
 		cos.PackedStrLen(md.ObjCksum) + cos.PackedStrLen(md.ObjVersion) +
 		cos.PackedStrLen(md.CksumType) + cos.PackedStrLen(md.CksumValue) +
-		cos.PackedStrLen(md.FullReplica) + daemonListSz + cos.SizeofI64 /*md cksum*/
+		cos.PackedStrLen(md.FullReplica) + daemonListSz +
+		cos.PackedStrLen(md.Codec) + cos.PackedStrLen(md.Policy) + cos.SizeofI64 /*md cksum*/
 }
@@ -22,7 +22,6 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/transport"
-	"github.com/klauspost/reedsolomon"
 )
 
 type (
@@ -80,8 +79,8 @@ func (ctx *encodeCtx) freeReplica() {
 func (*putJogger) newCtx(lom *cluster.LOM, meta *Metadata) (ctx *encodeCtx, err error) {
 	ctx = allocCtx()
 	ctx.lom = lom
-	ctx.dataSlices = lom.Bprops().EC.DataSlices
-	ctx.paritySlices = lom.Bprops().EC.ParitySlices
+	ctx.dataSlices = meta.Data
+	ctx.paritySlices = meta.Parity
 	ctx.meta = meta
 
 	totalCnt := ctx.paritySlices + ctx.dataSlices
@@ -122,8 +121,8 @@ func (c *putJogger) processRequest(req *request) {
 		if err = lom.Load(false /*cache it*/, false /*locked*/); err != nil {
 			return
 		}
-		ecConf := lom.Bprops().EC
-		memRequired = lom.SizeBytes() * int64(ecConf.DataSlices+ecConf.ParitySlices) / int64(ecConf.ParitySlices)
+		policy := &req.policy
+		memRequired = lom.SizeBytes() * int64(policy.DataSlices+policy.ParitySlices) / int64(policy.ParitySlices)
 		c.toDisk = useDisk(memRequired)
 	}
 
@@ -208,7 +207,7 @@ func (c *putJogger) splitAndDistribute(ctx *encodeCtx) error {
 func (c *putJogger) encode(req *request, lom *cluster.LOM) error {
 	var (
 		cksumValue, cksumType string
-		ecConf                = lom.Bprops().EC
+		policy                = &req.policy
 	)
 	if glog.FastV(4, glog.SmoduleEC) {
 		glog.Infof("Encoding %q...", lom.FQN)
@@ -216,9 +215,9 @@ func (c *putJogger) encode(req *request, lom *cluster.LOM) error {
 	if lom.Checksum() != nil {
 		cksumType, cksumValue = lom.Checksum().Get()
 	}
-	reqTargets := ecConf.ParitySlices + 1
+	reqTargets := policy.ParitySlices + 1
 	if !req.IsCopy {
-		reqTargets += ecConf.DataSlices
+		reqTargets += policy.DataSlices
 	}
 	targetCnt := len(c.parent.smap.Get().Tmap)
 	if targetCnt < reqTargets {
@@ -232,8 +231,10 @@ func (c *putJogger) encode(req *request, lom *cluster.LOM) error {
 		MDVersion:   MDVersionLast,
 		Generation:  generation,
 		Size:        lom.SizeBytes(),
-		Data:        ecConf.DataSlices,
-		Parity:      ecConf.ParitySlices,
+		Data:        policy.DataSlices,
+		Parity:      policy.ParitySlices,
+		Codec:       policy.Codec,
+		Policy:      policy.Name,
 		IsCopy:      req.IsCopy,
 		ObjCksum:    cksumValue,
 		CksumType:   cksumType,
@@ -404,7 +405,7 @@ func initializeSlices(ctx *encodeCtx) (err error) {
 }
 
 func finalizeSlices(ctx *encodeCtx, writers []io.Writer) error {
-	stream, err := reedsolomon.NewStreamC(ctx.dataSlices, ctx.paritySlices, true, true)
+	stream, err := newCodec(ctx.meta.Codec, ctx.dataSlices, ctx.paritySlices)
 	if err != nil {
 		return err
 	}
@@ -106,13 +106,13 @@ func (r *XactRespond) Run(*sync.WaitGroup) {
 }
 
 // Utility function to cleanup both object/slice and its meta on the local node
-// Used when processing object deletion request
-func (r *XactRespond) removeObjAndMeta(bck *cluster.Bck, objName string) error {
+// Used when processing object deletion request (and by EC scrubber)
+func removeObjAndMeta(t cluster.Target, bck *cluster.Bck, objName string) error {
 	if glog.FastV(4, glog.SmoduleEC) {
 		glog.Infof("Delete request for %s/%s", bck.Name, objName)
 	}
 
-	ct, err := cluster.NewCTFromBO(bck.Bucket(), objName, r.t.Bowner(), fs.ECSliceType)
+	ct, err := cluster.NewCTFromBO(bck.Bucket(), objName, t.Bowner(), fs.ECSliceType)
 	if err != nil {
 		return err
 	}
@@ -169,9 +169,13 @@ func (r *XactRespond) DispatchReq(iReq intraReq, hdr *transport.ObjHdr, bck *clu
 	switch hdr.Opcode {
 	case reqDel:
 		// object cleanup request: delete replicas, slices and metafiles
-		if err := r.removeObjAndMeta(bck, hdr.ObjName); err != nil {
+		if err := removeObjAndMeta(r.t, bck, hdr.ObjName); err != nil {
 			glog.Errorf("%s failed to delete %s/%s: %v", r.t, bck.Name, hdr.ObjName, err)
 		}
+	case reqRepair:
+		if err := r.repair(iReq, hdr, bck); err != nil {
+			glog.Errorf("%s failed to repair %s/%s: %v", r.t, bck.Name, hdr.ObjName, err)
+		}
 	case reqGet:
 		err := r.trySendCT(iReq, hdr, bck)
 		if err != nil {
@@ -183,6 +187,30 @@ func (r *XactRespond) DispatchReq(iReq intraReq, hdr *transport.ObjHdr, bck *clu
 	}
 }
 
+// Re-encodes the object at the request of a target that has found its slice
+// (or replica) corrupted - unless the object has been re-encoded since.
+func (*XactRespond) repair(iReq intraReq, hdr *transport.ObjHdr, bck *cluster.Bck) error {
+	lom := cluster.AllocLOM(hdr.ObjName)
+	defer cluster.FreeLOM(lom)
+	if err := lom.InitBck(bck.Bucket()); err != nil {
+		return err
+	}
+	if err := lom.Load(true /*cache it*/, false /*locked*/); err != nil {
+		if cmn.IsObjNotExist(err) {
+			err = nil // (restoring the main replica is up to this target's scrubber)
+		}
+		return err
+	}
+	md, err := LoadMetadata(cluster.NewCTFromLOM(lom, fs.ECMetaType).FQN())
+	if err == nil && iReq.meta != nil && md.Generation > iReq.meta.Generation {
+		return nil
+	}
+	if glog.FastV(4, glog.SmoduleEC) {
+		glog.Infof("Repair request for %s from %s", lom, hdr.SID)
+	}
+	return ECM.EncodeObject(lom, scrubRepairCb(bck))
+}
+
 func (r *XactRespond) DispatchResp(iReq intraReq, hdr *transport.ObjHdr, object io.Reader) {
 	r.IncPending()
 	defer r.DecPending() // no async operation, so DecPending is deferred
@@ -0,0 +1,468 @@
+// Package ec provides erasure coding (EC) based data protection for AIStore.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package ec
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/fs/mpather"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/transport"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// EC scrubber detects and repairs silent corruption of erasure coded (and
+// replicated) objects. The xaction runs on all targets, a jogger per mountpath,
+// and walks the bucket's EC metafiles:
+//   - slice (or replica) that belongs to another (main) target: the scrubber
+//     validates its checksum against EC metadata. Corrupted or missing content
+//     is removed, and the main target is requested to repair the object (`reqRepair`);
+//   - main replica: the scrubber validates its checksum, and restores corrupted
+//     (or missing) object from slices, as if it were lost. Next, the scrubber
+//     checks that all slices (replicas) are present, and that all of them are of the
+//     same generation. Inconsistent object gets re-encoded, which rebuilds all its
+//     slices (replicas).
+//     The latter checks are batched: a single metadata request per target for up to
+//     `scrubBatchSize` objects (see `checkRemote`).
+
+const scrubBatchSize = 128 // max number of objects checked with a single metadata request
+
+type (
+	scrubFactory struct {
+		xreg.RenewBase
+		xctn *XactBckScrub
+	}
+	XactBckScrub struct {
+		xact.BckJog
+		t      cluster.Target
+		smap   *cluster.Smap
+		client *http.Client
+		wg     sync.WaitGroup // to wait for pending repairs
+		counts struct {
+			corrupted atomic.Int64
+			repaired  atomic.Int64
+		}
+		pending struct {
+			sync.Mutex
+			checks []*scrubCheck // main replicas to check remote slices (replicas) of
+		}
+	}
+	scrubCheck struct {
+		md      *Metadata
+		objName string
+		missing int // (so far)
+	}
+	// EC scrubber's extended stats (see xact.SnapExt)
+	ScrubStatsExt struct {
+		Corrupted int64 `json:"corrupted,string"` // number of corrupted or missing slices and replicas
+		Repaired  int64 `json:"repaired,string"`  // number of restored and re-encoded objects
+	}
+)
+
+// interface guard
+var (
+	_ cluster.Xact   = (*XactBckScrub)(nil)
+	_ xreg.Renewable = (*scrubFactory)(nil)
+)
+
+//////////////////
+// scrubFactory //
+//////////////////
+
+func (*scrubFactory) New(args xreg.Args, bck *cluster.Bck) xreg.Renewable {
+	return &scrubFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *scrubFactory) Start() error {
+	if !p.Bck.Props.EC.Enabled {
+		return fmt.Errorf("%s: bucket %s does not have EC enabled", p.Kind(), p.Bck)
+	}
+	slab, err := p.T.PageMM().GetSlab(memsys.MaxPageSlabSize)
+	if err != nil {
+		return err
+	}
+	p.xctn = newXactBckScrub(p.T, p.UUID(), p.Bck, slab)
+	return nil
+}
+
+func (*scrubFactory) Kind() string        { return apc.ActECScrub }
+func (p *scrubFactory) Get() cluster.Xact { return p.xctn }
+
+func (*scrubFactory) WhenPrevIsRunning(prevEntry xreg.Renewable) (xreg.WPR, error) {
+	return xreg.WprUse, cmn.NewErrUsePrevXaction(prevEntry.Get().String())
+}
+
+//////////////////
+// XactBckScrub //
+//////////////////
+
+func newXactBckScrub(t cluster.Target, uuid string, bck *cluster.Bck, slab *memsys.Slab) (r *XactBckScrub) {
+	config := cmn.GCO.Get()
+	r = &XactBckScrub{
+		t:    t,
+		smap: t.Sowner().Get(),
+		client: cmn.NewClient(cmn.TransportArgs{
+			Timeout:    config.Client.Timeout.D(),
+			UseHTTPS:   config.Net.HTTP.UseHTTPS,
+			SkipVerify: config.Net.HTTP.SkipVerify,
+		}),
+	}
+	mpopts := &mpather.JoggerGroupOpts{
+		T:        t,
+		CTs:      []string{fs.ECMetaType},
+		VisitCT:  r.visit,
+		Slab:     slab,
+		Throttle: true,
+	}
+	mpopts.Bck.Copy(bck.Bucket())
+	r.BckJog.Init(uuid, apc.ActECScrub, bck, mpopts)
+	return
+}
+
+func (r *XactBckScrub) Run(*sync.WaitGroup) {
+	glog.Infoln(r.Name())
+	r.BckJog.Run()
+	err := r.BckJog.Wait()
+	r.pending.Lock()
+	checks := r.pending.checks
+	r.pending.checks = nil
+	r.pending.Unlock()
+	r.checkRemote(checks)
+	r.wg.Wait()
+	glog.Infof("%s finished: %s", r, r.ext())
+	r.Finish(err)
+}
+
+func (r *XactBckScrub) Snap() cluster.XactSnap {
+	snap := &xact.SnapExt{}
+	r.ToSnap(&snap.Snap)
+	snap.Ext = r.ext()
+	return snap
+}
+
+func (r *XactBckScrub) ext() *ScrubStatsExt {
+	return &ScrubStatsExt{Corrupted: r.counts.corrupted.Load(), Repaired: r.counts.repaired.Load()}
+}
+
+func (ext *ScrubStatsExt) String() string {
+	return fmt.Sprintf("corrupted %d, repaired %d", ext.Corrupted, ext.Repaired)
+}
+
+// NOTE: errors of individual objects are logged - and do not stop the joggers
+func (r *XactBckScrub) visit(ct *cluster.CT, buf []byte) error {
+	md, err := LoadMetadata(ct.FQN())
+	if err != nil {
+		if !os.IsNotExist(err) { // (removed in the meantime)
+			r.scrubDamagedMeta(ct, err)
+		}
+		return nil
+	}
+	switch {
+	case md.SliceID != 0:
+		r.scrubSlice(ct, md, buf)
+	case md.FullReplica != r.t.SID():
+		r.scrubReplica(ct, md)
+	default:
+		r.scrubMain(ct, md)
+	}
+	return nil
+}
+
+func (r *XactBckScrub) scrubDamagedMeta(ct *cluster.CT, err error) {
+	glog.Errorf("%s: %v", r, err)
+	r.counts.corrupted.Inc()
+	tsi, errHrw := cluster.HrwTarget(ct.Bck().MakeUname(ct.ObjectName()), r.smap)
+	if errHrw != nil {
+		glog.Error(errHrw)
+		return
+	}
+	if tsi.ID() != r.t.SID() {
+		r.removeAndRequestRepair(ct, nil /*md*/, tsi.ID())
+		return
+	}
+	lom := cluster.AllocLOM(ct.ObjectName())
+	defer cluster.FreeLOM(lom)
+	if err := lom.InitBck(ct.Bucket()); err != nil {
+		glog.Error(err)
+		return
+	}
+	if err := cos.RemoveFile(ct.FQN()); err != nil {
+		glog.Error(err)
+		return
+	}
+	if err := lom.Load(false /*cache it*/, false /*locked*/); err != nil {
+		glog.Errorf("%s: %s (main replica with damaged metafile): %v", r, lom, err)
+		return
+	}
+	r.repair(lom)
+}
+
+// validates checksum of the slice against its metadata
+func (r *XactBckScrub) scrubSlice(ct *cluster.CT, md *Metadata, buf []byte) {
+	var n int64
+	ct.Lock(false)
+	fh, err := os.Open(ct.Make(fs.ECSliceType))
+	if err == nil {
+		var cksum *cos.CksumHash
+		n, cksum, err = cos.CopyAndChecksum(io.Discard, fh, buf, md.CksumType)
+		cos.Close(fh)
+		switch {
+		case err != nil:
+		case n != SliceSize(md.Size, md.Data):
+			err = fmt.Errorf("slice %d of %s/%s: invalid size %d (expected %d)", md.SliceID, ct.Bck(),
+				ct.ObjectName(), n, SliceSize(md.Size, md.Data))
+		case cksum != nil && md.CksumValue != "" && cksum.Value() != md.CksumValue:
+			err = cos.NewBadDataCksumError(cos.NewCksum(md.CksumType, md.CksumValue), &cksum.Cksum,
+				ct.ObjectName())
+		}
+	}
+	ct.Unlock(false)
+	r.ObjsAdd(1, n)
+	if err != nil {
+		glog.Errorf("%s: %v", r, err)
+		r.counts.corrupted.Inc()
+		r.removeAndRequestRepair(ct, md, md.FullReplica)
+	}
+}
+
+// validates checksum of the replica (that belongs to another target)
+func (r *XactBckScrub) scrubReplica(ct *cluster.CT, md *Metadata) {
+	lom := cluster.AllocLOM(ct.ObjectName())
+	defer cluster.FreeLOM(lom)
+	if err := lom.InitBck(ct.Bucket()); err != nil {
+		glog.Error(err)
+		return
+	}
+	err := r.validate(lom)
+	if err == nil && !r.sameCksum(lom, md) {
+		err = cos.NewBadDataCksumError(cos.NewCksum(md.CksumType, md.ObjCksum), lom.Checksum(), lom.FullName())
+	}
+	if err != nil {
+		glog.Errorf("%s: %v", r, err)
+		r.counts.corrupted.Inc()
+		r.removeAndRequestRepair(ct, md, md.FullReplica)
+	}
+}
+
+// validates checksum of the main replica, and then checks the object's
+// slices (replicas) stored by other targets
+func (r *XactBckScrub) scrubMain(ct *cluster.CT, md *Metadata) {
+	lom := cluster.AllocLOM(ct.ObjectName())
+	defer cluster.FreeLOM(lom)
+	if err := lom.InitBck(ct.Bucket()); err != nil {
+		glog.Error(err)
+		return
+	}
+	if err := r.validate(lom); err != nil {
+		if !cmn.IsObjNotExist(err) && !cos.IsErrBadCksum(err) {
+			glog.Errorf("%s: %s: %v", r, lom, err)
+			return
+		}
+		glog.Errorf("%s: %v - restoring", r, err)
+		r.counts.corrupted.Inc()
+		if err := r.restore(lom); err != nil {
+			glog.Errorf("%s: failed to restore %s: %v", r, lom, err)
+			return
+		}
+		r.counts.repaired.Inc()
+		return
+	}
+	check := &scrubCheck{md: md, objName: lom.ObjName}
+	if !r.sameCksum(lom, md) {
+		check.missing++ // stale metadata of the main replica
+	}
+	r.pending.Lock()
+	r.pending.checks = append(r.pending.checks, check)
+	if len(r.pending.checks) < scrubBatchSize {
+		r.pending.Unlock()
+		return
+	}
+	checks := r.pending.checks
+	r.pending.checks = make([]*scrubCheck, 0, scrubBatchSize)
+	r.pending.Unlock()
+	r.checkRemote(checks)
+}
+
+// validates object's content against its checksum
+func (r *XactBckScrub) validate(lom *cluster.LOM) (err error) {
+	lom.Lock(false)
+	if err = lom.Load(false /*cache it*/, true /*locked*/); err == nil {
+		err = lom.ValidateContentChecksum()
+	}
+	lom.Unlock(false)
+	if err == nil {
+		r.ObjsAdd(1, lom.SizeBytes())
+	}
+	return
+}
+
+// whether the object's checksum is the one recorded in its EC metadata
+func (*XactBckScrub) sameCksum(lom *cluster.LOM, md *Metadata) bool {
+	cksum := lom.Checksum()
+	return md.ObjCksum == "" || cksum == nil || cksum.Value() == md.ObjCksum
+}
+
+// removes corrupted main replica, and restores it from slices (other replicas)
+func (r *XactBckScrub) restore(lom *cluster.LOM) error {
+	lom.Lock(true)
+	err := lom.Remove()
+	lom.Unlock(true)
+	if err != nil {
+		return err
+	}
+	return ECM.RestoreObject(lom)
+}
+
+// Counts slices (replicas) of the objects that are either missing or do not have
+// the same generation as the main replica, and re-encodes the objects that have any.
+// Remote metadata is requested in a single batch per target.
+func (r *XactBckScrub) checkRemote(checks []*scrubCheck) {
+	if len(checks) == 0 {
+		return
+	}
+	var (
+		bck      = r.Bck()
+		objNames = make(map[string][]string, len(r.smap.Tmap))
+	)
+	for _, check := range checks {
+		for tid := range check.md.Daemons {
+			if tid != r.t.SID() {
+				objNames[tid] = append(objNames[tid], check.objName)
+			}
+		}
+	}
+	remote := make(map[string]map[string]*Metadata, len(objNames))
+	for tid, names := range objNames {
+		si := r.smap.GetTarget(tid)
+		if si == nil {
+			continue
+		}
+		mds, err := RequestECMetas(bck.Bucket(), names, si, r.client)
+		if err != nil {
+			glog.Errorf("%s: %v", r, err)
+			continue
+		}
+		remote[tid] = mds
+	}
+	for _, check := range checks {
+		md := check.md
+		for tid, sliceID := range md.Daemons {
+			if tid == r.t.SID() {
+				continue
+			}
+			rmd, ok := remote[tid][check.objName]
+			if !ok || rmd.Generation != md.Generation || rmd.SliceID != int(sliceID) {
+				if glog.FastV(4, glog.SmoduleEC) {
+					glog.Infof("%s: %s/%s from %s: %+v", r, bck, check.objName, tid, rmd)
+				}
+				check.missing++
+			}
+		}
+		// all slices (replicas) must be recorded in the main replica's metadata
+		expected := md.Parity + 1
+		if !md.IsCopy {
+			expected += md.Data
+		}
+		if len(md.Daemons) < expected {
+			check.missing += expected - len(md.Daemons)
+		}
+		if check.missing > 0 {
+			r.repairMissing(check)
+		}
+	}
+}
+
+func (r *XactBckScrub) repairMissing(check *scrubCheck) {
+	lom := cluster.AllocLOM(check.objName)
+	defer cluster.FreeLOM(lom)
+	if err := lom.InitBck(r.Bck().Bucket()); err != nil {
+		glog.Error(err)
+		return
+	}
+	// skip objects that have been re-encoded (e.g., overwritten) since checked
+	if md, err := ObjectMetadata(lom.Bck(), lom.ObjName); err != nil || md.Generation != check.md.Generation {
+		return
+	}
+	glog.Warningf("%s: %s has %d missing or stale slice(s) (replica(s)) - re-encoding", r, lom, check.missing)
+	r.counts.corrupted.Add(int64(check.missing))
+	r.repair(lom)
+}
+
+// re-encodes the object, which rebuilds all its slices (replicas)
+func (r *XactBckScrub) repair(lom *cluster.LOM) {
+	r.wg.Add(1)
+	if err := ECM.EncodeObject(lom, r.afterRepair); err != nil {
+		r.afterRepair(lom, err)
+	}
+}
+
+func (r *XactBckScrub) afterRepair(lom *cluster.LOM, err error) {
+	if err == nil {
+		r.counts.repaired.Inc()
+	} else if err != errSkipped {
+		glog.Errorf("%s: failed to repair %s: %v", r, lom, err)
+	}
+	r.wg.Done()
+}
+
+// removes corrupted slice (replica) along with its metafile, and asks the main
+// target to repair the object
+func (r *XactBckScrub) removeAndRequestRepair(ct *cluster.CT, md *Metadata, tid string) {
+	if err := removeObjAndMeta(r.t, ct.Bck(), ct.ObjectName()); err != nil {
+		glog.Errorf("%s: %v", r, err)
+	}
+	if ECM.req() == nil {
+		return
+	}
+	si := r.smap.GetTarget(tid)
+	if si == nil {
+		glog.Errorf("%s: main target %s of %s/%s not found", r, tid, ct.Bck(), ct.ObjectName())
+		return
+	}
+	mm := r.t.ByteMM()
+	o := transport.AllocSend()
+	o.Hdr = transport.ObjHdr{
+		ObjName: ct.ObjectName(),
+		Opaque:  newIntraReq(reqRepair, md, ct.Bck()).NewPack(mm),
+		Opcode:  reqRepair,
+	}
+	o.Hdr.Bck.Copy(ct.Bucket())
+	o.Callback = func(hdr transport.ObjHdr, _ io.ReadCloser, _ interface{}, err error) {
+		mm.Free(hdr.Opaque)
+		if err != nil {
+			glog.Errorf("failed to request repair of %s: %v", hdr.FullName(), err)
+		}
+	}
+	if err := ECM.req().Send(o, nil, si); err != nil {
+		glog.Errorf("%s: %v", r, err)
+	}
+}
+
+// Returns callback that accounts for the object re-encoded at the request of
+// another target - in the stats of this target's scrubber, if running.
+func scrubRepairCb(bck *cluster.Bck) cluster.OnFinishObj {
+	entry := xreg.GetRunning(xreg.XactFilter{Kind: apc.ActECScrub, Bck: bck})
+	if entry == nil {
+		return nil
+	}
+	r := entry.Get().(*XactBckScrub)
+	return func(_ *cluster.LOM, err error) {
+		if err == nil {
+			r.counts.repaired.Inc()
+		}
+	}
+}
@@ -5,12 +5,14 @@
 package etl
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/feat"
 	"github.com/NVIDIA/aistore/cmn/k8s"
 	"github.com/NVIDIA/aistore/etl/runtime"
 	jsoniter "github.com/json-iterator/go"
@@ -32,6 +34,22 @@ type (
 		Code    []byte `json:"code"`
 		Deps    []byte `json:"dependencies"`
 		Runtime string `json:"runtime"`
+
+		// limits of the in-process "wasm" runtime (see `InitWasm`)
+		MemLimit     int64        `json:"mem_limit,omitempty"`     // memory of a single module instance (bytes)
+		ExecTimeout  cos.Duration `json:"exec_timeout,omitempty"`  // time to transform a single object
+		MaxInstances int          `json:"max_instances,omitempty"` // concurrently running instances (default: number of CPUs)
+	}
+
+	// InitProcMsg starts the transformer as a local child process on each
+	// target (no Kubernetes required). The process must serve the same HTTP
+	// protocol as ETL containers, listening on the unix socket or localhost
+	// address provided via `AIS_ETL_SOCKET` or `AIS_ETL_ADDR` env variables.
+	InitProcMsg struct {
+		InitMsgBase
+		Command []string          `json:"command"`           // executable and its arguments
+		Env     map[string]string `json:"env,omitempty"`     // additional env variables
+		Network string            `json:"network,omitempty"` // "unix" (default) or "tcp"
 	}
 
 	InfoList []Info
@@ -74,6 +92,7 @@ type (
 var (
 	_ InitMsg = (*InitCodeMsg)(nil)
 	_ InitMsg = (*InitSpecMsg)(nil)
+	_ InitMsg = (*InitProcMsg)(nil)
 )
 
 func (m InitMsgBase) CommType() string { return m.CommTypeX }
@@ -89,18 +108,67 @@ func (m *InitCodeMsg) Validate() error {
 	if m.Runtime == "" {
 		return fmt.Errorf("runtime is not specified")
 	}
+	if m.Runtime == runtime.Wasm {
+		return m.validateWasm()
+	}
+	if m.MemLimit != 0 || m.ExecTimeout != 0 || m.MaxInstances != 0 {
+		return fmt.Errorf("limits are only supported by %q runtime", runtime.Wasm)
+	}
 	if _, ok := runtime.Runtimes[m.Runtime]; !ok {
 		return fmt.Errorf("unsupported runtime provided: %s", m.Runtime)
 	}
 	if m.CommTypeX == "" {
 		m.CommTypeX = PushCommType
 	}
-	if !cos.StringInSlice(m.CommTypeX, commTypes) {
+	// runtimes do not implement stream framing
+	if !cos.StringInSlice(m.CommTypeX, commTypes) || m.CommTypeX == StreamCommType {
 		return fmt.Errorf("unsupported communication type provided: %s", m.CommTypeX)
 	}
 	return nil
 }
 
+func (m *InitCodeMsg) validateWasm() error {
+	if !bytes.HasPrefix(m.Code, wasmMagic) {
+		return fmt.Errorf("code is not a WebAssembly module (binary format)")
+	}
+	if len(m.Deps) != 0 {
+		return fmt.Errorf("dependencies are not supported by %q runtime", runtime.Wasm)
+	}
+	// the module runs in-process, hence no communication
+	if m.CommTypeX != "" && m.CommTypeX != PushCommType {
+		return fmt.Errorf("communication type %q is not supported by %q runtime", m.CommTypeX, runtime.Wasm)
+	}
+	if m.MemLimit < 0 || m.MemLimit > wasmMaxMemLimit {
+		return fmt.Errorf("invalid memory limit %d (expecting at most %s)", m.MemLimit, cos.B2S(wasmMaxMemLimit, 0))
+	}
+	if m.ExecTimeout < 0 || m.MaxInstances < 0 {
+		return fmt.Errorf("invalid (negative) exec timeout or max instances")
+	}
+	return nil
+}
+
+// CheckProcEnabled returns an error unless local-process ETL is explicitly
+// enabled in the cluster configuration (it is disabled by default).
+func CheckProcEnabled(config *cmn.Config) error {
+	if config.Features.IsSet(feat.EnableProcETL) {
+		return nil
+	}
+	return fmt.Errorf("local-process ETL is disabled (to enable, set feature flag %q)", feat.EnableProcETL)
+}
+
+// IsLocal returns true if the ETL runs on targets themselves - as a local
+// process or in-process WebAssembly module - rather than in K8s pods.
+func IsLocal(msg InitMsg) bool {
+	switch msg := msg.(type) {
+	case *InitProcMsg:
+		return true
+	case *InitCodeMsg:
+		return msg.Runtime == runtime.Wasm
+	default:
+		return false
+	}
+}
+
 func (*InitCodeMsg) InitType() string {
 	return apc.ETLInitCode
 }
@@ -162,6 +230,36 @@ func (m *InitSpecMsg) Validate() (err error) {
 	return nil
 }
 
+func (*InitProcMsg) InitType() string {
+	return apc.ETLInitProc
+}
+
+func (m *InitProcMsg) Validate() error {
+	if err := cos.ValidateEtlID(m.IDX); err != nil {
+		return fmt.Errorf("invalid etl ID: %v", err)
+	}
+	if len(m.Command) == 0 || m.Command[0] == "" {
+		return fmt.Errorf("command is not specified")
+	}
+	if m.CommTypeX == "" {
+		m.CommTypeX = PushCommType
+	}
+	// The process listens on the target's localhost, so redirecting
+	// clients to it is not an option.
+	if m.CommTypeX != PushCommType && m.CommTypeX != RevProxyCommType {
+		return fmt.Errorf("unsupported communication type for local process: %q (expected %q or %q)",
+			m.CommTypeX, PushCommType, RevProxyCommType)
+	}
+	switch m.Network {
+	case "":
+		m.Network = procNetUnix
+	case procNetUnix, procNetTCP:
+	default:
+		return fmt.Errorf("unsupported network %q (expected %q or %q)", m.Network, procNetUnix, procNetTCP)
+	}
+	return nil
+}
+
 func (p PodsLogsMsg) Len() int           { return len(p) }
 func (p PodsLogsMsg) Less(i, j int) bool { return p[i].TargetID < p[j].TargetID }
 func (p PodsLogsMsg) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
@@ -200,6 +298,11 @@ func UnmarshalInitMsg(b []byte) (msg InitMsg, err error) {
 		err = jsoniter.Unmarshal(b, msg)
 		return
 	}
+	if _, ok := msgInf["command"]; ok {
+		msg = &InitProcMsg{}
+		err = jsoniter.Unmarshal(b, msg)
+		return
+	}
 	err = fmt.Errorf("invalid response body: %s", b)
 	return
 }
@@ -110,7 +110,8 @@ var _ = Describe("CommunicatorTest", func() {
 			pod.SetName("somename")
 
 			xctn := mock.NewXact(apc.ActETLInline)
-			comm = makeCommunicator(commArgs{
+			var err error
+			comm, err = makeCommunicator(commArgs{
 				bootstraper: &etlBootstraper{
 					t: tMock,
 					msg: InitSpecMsg{
@@ -124,6 +125,7 @@ var _ = Describe("CommunicatorTest", func() {
 					xctn: xctn,
 				},
 			})
+			Expect(err).NotTo(HaveOccurred())
 			resp, err := http.Get(proxyServer.URL)
 			Expect(err).NotTo(HaveOccurred())
 			defer resp.Body.Close()
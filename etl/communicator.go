@@ -6,6 +6,7 @@ package etl
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
@@ -13,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cluster"
@@ -47,6 +49,11 @@ type (
 		// with GET requests from users (such as training models and apps)
 		// to perform on-the-fly transformation.
 		OfflineTransform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error)
+
+		// TransformStream pushes the input stream (size < 0 when unknown) to the ETL
+		// container ("PUT /") and returns the transformed output. It is used to chain
+		// transformers (see `Pipeline`) without staging intermediate results.
+		TransformStream(r io.Reader, size int64, timeout time.Duration) (cos.ReadCloseSizer, error)
 		Stop()
 
 		CommStats
@@ -64,7 +71,8 @@ type (
 		name    string
 		podName string
 
-		xctn cluster.Xact
+		xctn   cluster.Xact
+		client *http.Client // when nil, target's data client is used
 	}
 
 	pushComm struct {
@@ -82,6 +90,12 @@ type (
 		rp  *httputil.ReverseProxy
 		uri string
 	}
+	// procComm communicates (via push or reverse proxy) with the transformer
+	// running as a local child process, and owns the process.
+	procComm struct {
+		Communicator
+		proc *etlProc
+	}
 
 	// TODO: Generalize and move to `cos` package
 	cbWriter struct {
@@ -95,6 +109,7 @@ var (
 	_ Communicator = (*pushComm)(nil)
 	_ Communicator = (*redirectComm)(nil)
 	_ Communicator = (*revProxyComm)(nil)
+	_ Communicator = (*procComm)(nil)
 
 	_ io.Writer = (*cbWriter)(nil)
 )
@@ -103,7 +118,7 @@ var (
 // baseComm //
 //////////////
 
-func makeCommunicator(args commArgs) Communicator {
+func makeCommunicator(args commArgs) (Communicator, error) {
 	baseComm := baseComm{
 		Slistener: args.listener,
 		t:         args.bootstraper.t,
@@ -111,18 +126,22 @@ func makeCommunicator(args commArgs) Communicator {
 		podName:   args.bootstraper.pod.Name,
 		xctn:      args.bootstraper.xctn,
 	}
+	return newCommunicator(baseComm, args.bootstraper.msg.CommTypeX, args.bootstraper.uri,
+		args.bootstraper.originalCommand)
+}
 
-	switch args.bootstraper.msg.CommTypeX {
+func newCommunicator(baseComm baseComm, commType, uri string, command []string) (Communicator, error) {
+	switch commType {
 	case PushCommType:
 		return &pushComm{
 			baseComm: baseComm,
-			mem:      args.bootstraper.t.PageMM(),
-			uri:      args.bootstraper.uri,
-		}
+			mem:      baseComm.t.PageMM(),
+			uri:      uri,
+		}, nil
 	case RedirectCommType:
-		return &redirectComm{baseComm: baseComm, uri: args.bootstraper.uri}
+		return &redirectComm{baseComm: baseComm, uri: uri}, nil
 	case RevProxyCommType:
-		transformerURL, err := url.Parse(args.bootstraper.uri)
+		transformerURL, err := url.Parse(uri)
 		cos.AssertNoErr(err)
 		rp := &httputil.ReverseProxy{
 			Director: func(req *http.Request) {
@@ -136,18 +155,23 @@ func makeCommunicator(args commArgs) Communicator {
 				}
 			},
 		}
-		return &revProxyComm{baseComm: baseComm, rp: rp, uri: args.bootstraper.uri}
+		if baseComm.client != nil {
+			rp.Transport = baseComm.client.Transport
+		}
+		return &revProxyComm{baseComm: baseComm, rp: rp, uri: uri}, nil
+	case StreamCommType:
+		return newStreamComm(baseComm, uri)
 	case IOCommType:
 		return &pushComm{
 			baseComm: baseComm,
-			mem:      args.bootstraper.t.PageMM(),
-			uri:      args.bootstraper.uri,
-			command:  args.bootstraper.originalCommand,
-		}
+			mem:      baseComm.t.PageMM(),
+			uri:      uri,
+			command:  command,
+		}, nil
 	default:
-		cos.AssertMsg(false, args.bootstraper.msg.CommTypeX)
+		cos.AssertMsg(false, commType)
 	}
-	return nil
+	return nil, nil
 }
 
 func (c baseComm) Name() string    { return c.name }
@@ -162,27 +186,19 @@ func (c *baseComm) Stop() {
 	c.xctn.Finish(nil)
 }
 
+func (c *baseComm) httpClient() *http.Client {
+	if c.client != nil {
+		return c.client
+	}
+	return c.t.DataClient()
+}
+
 //////////////
 // pushComm //
 //////////////
 
 func (pc *pushComm) doRequest(bck *cluster.Bck, objName string, timeout time.Duration) (r cos.ReadCloseSizer, err error) {
-	lom := cluster.AllocLOM(objName)
-	defer cluster.FreeLOM(lom)
-
-	if err := lom.InitBck(bck.Bucket()); err != nil {
-		return nil, err
-	}
-
-	r, err = pc.tryDoRequest(lom, timeout)
-	if err != nil && cmn.IsObjNotExist(err) && bck.IsRemote() {
-		_, err = pc.t.GetCold(context.Background(), lom, cmn.OwtGetLock)
-		if err != nil {
-			return nil, err
-		}
-		r, err = pc.tryDoRequest(lom, timeout)
-	}
-	return
+	return transformLOM(pc.t, bck, objName, timeout, pc.tryDoRequest)
 }
 
 func (pc *pushComm) tryDoRequest(lom *cluster.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
@@ -203,50 +219,7 @@ func (pc *pushComm) tryDoRequest(lom *cluster.LOM, timeout time.Duration) (cos.R
 	if err != nil {
 		return nil, err
 	}
-
-	var (
-		req    *http.Request
-		resp   *http.Response
-		cancel func()
-	)
-	if timeout != 0 {
-		var ctx context.Context
-		ctx, cancel = context.WithTimeout(context.Background(), timeout)
-		req, err = http.NewRequestWithContext(ctx, http.MethodPut, pc.uri, fh)
-	} else {
-		req, err = http.NewRequest(http.MethodPut, pc.uri, fh)
-	}
-	if err != nil {
-		cos.Close(fh)
-		goto finish
-	}
-	if len(pc.command) != 0 {
-		q := req.URL.Query()
-		q["command"] = []string{"bash", "-c", strings.Join(pc.command, " ")}
-		req.URL.RawQuery = q.Encode()
-	}
-	req.ContentLength = size
-	req.Header.Set(cmn.HdrContentType, cmn.ContentBinary)
-	resp, err = pc.t.DataClient().Do(req) // nolint:bodyclose // Closed by the caller.
-finish:
-	if err != nil {
-		if cancel != nil {
-			cancel()
-		}
-		return nil, err
-	}
-
-	return cos.NewReaderWithArgs(cos.ReaderArgs{
-		R:      resp.Body,
-		Size:   resp.ContentLength,
-		ReadCb: func(i int, err error) { pc.xctn.OutObjsAdd(1, int64(i)) },
-		DeferCb: func() {
-			if cancel != nil {
-				cancel()
-			}
-			pc.xctn.InObjsAdd(1, size)
-		},
-	}), nil
+	return pc.push(pc.uri, pc.command, fh, size, timeout)
 }
 
 func (pc *pushComm) OnlineTransform(w http.ResponseWriter, _ *http.Request, bck *cluster.Bck, objName string) error {
@@ -271,6 +244,13 @@ func (pc *pushComm) OfflineTransform(bck *cluster.Bck, objName string, timeout t
 	return pc.doRequest(bck, objName, timeout)
 }
 
+func (pc *pushComm) TransformStream(r io.Reader, size int64, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := pc.xctn.AbortErr(); err != nil {
+		return nil, cmn.NewErrAborted(pc.xctn.Name(), "push-comm-stream", err)
+	}
+	return pc.push(pc.uri, pc.command, r, size, timeout)
+}
+
 //////////////////
 // redirectComm //
 //////////////////
@@ -302,6 +282,13 @@ func (rc *redirectComm) OfflineTransform(bck *cluster.Bck, objName string, timeo
 	return rc.getWithTimeout(etlURL, size, timeout)
 }
 
+func (rc *redirectComm) TransformStream(r io.Reader, size int64, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := rc.xctn.AbortErr(); err != nil {
+		return nil, cmn.NewErrAborted(rc.xctn.Name(), "redirect-comm-stream", err)
+	}
+	return rc.push(rc.uri, nil, r, size, timeout)
+}
+
 //////////////////
 // revProxyComm //
 //////////////////
@@ -331,6 +318,22 @@ func (pc *revProxyComm) OfflineTransform(bck *cluster.Bck, objName string, timeo
 	return pc.getWithTimeout(etlURL, size, timeout)
 }
 
+func (pc *revProxyComm) TransformStream(r io.Reader, size int64, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := pc.xctn.AbortErr(); err != nil {
+		return nil, cmn.NewErrAborted(pc.xctn.Name(), "rev-proxy-comm-stream", err)
+	}
+	return pc.push(pc.uri, nil, r, size, timeout)
+}
+
+//////////////
+// procComm //
+//////////////
+
+func (pc *procComm) Stop() {
+	pc.proc.stop()
+	pc.Communicator.Stop()
+}
+
 //////////////
 // cbWriter //
 //////////////
@@ -364,6 +367,85 @@ func transformerPath(bck *cluster.Bck, objName string) string {
 	return "/" + url.PathEscape(bck.MakeUname(objName))
 }
 
+// transformLOM transforms the object with `try`, and retries once upon cold GET
+// if the object is remote and not present.
+func transformLOM(t cluster.Target, bck *cluster.Bck, objName string, timeout time.Duration,
+	try func(lom *cluster.LOM, timeout time.Duration) (cos.ReadCloseSizer, error)) (r cos.ReadCloseSizer, err error) {
+	lom := cluster.AllocLOM(objName)
+	defer cluster.FreeLOM(lom)
+
+	if err := lom.InitBck(bck.Bucket()); err != nil {
+		return nil, err
+	}
+
+	r, err = try(lom, timeout)
+	if err != nil && cmn.IsObjNotExist(err) && bck.IsRemote() {
+		_, err = t.GetCold(context.Background(), lom, cmn.OwtGetLock)
+		if err != nil {
+			return nil, err
+		}
+		r, err = try(lom, timeout)
+	}
+	return
+}
+
+// push PUTs the data to the ETL container and returns the transformed output.
+// The request body `r` gets closed by Do(req) if it implements `io.Closer`.
+func (c *baseComm) push(uri string, command []string, r io.Reader, size int64, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	var (
+		req    *http.Request
+		resp   *http.Response
+		cancel func()
+		err    error
+		in     atomic.Int64 // written by the transport while sending the body
+		body   = cos.NewReaderWithArgs(cos.ReaderArgs{R: r, Size: size, ReadCb: func(i int, _ error) { in.Add(int64(i)) }})
+	)
+	if timeout != 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPut, uri, body)
+	} else {
+		req, err = http.NewRequest(http.MethodPut, uri, body)
+	}
+	if err != nil {
+		cos.Close(body)
+		goto finish
+	}
+	if len(command) != 0 {
+		q := req.URL.Query()
+		q["command"] = []string{"bash", "-c", strings.Join(command, " ")}
+		req.URL.RawQuery = q.Encode()
+	}
+	req.ContentLength = size // when negative, the body is sent chunked
+	req.Header.Set(cmn.HdrContentType, cmn.ContentBinary)
+	resp, err = c.httpClient().Do(req) // nolint:bodyclose // Closed by the caller.
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		// do not pass the error message downstream as the transformed output
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, cos.KiB))
+		resp.Body.Close()
+		err = fmt.Errorf("%s: %s (%d)", uri, strings.TrimSpace(string(b)), resp.StatusCode)
+	}
+finish:
+	if err != nil {
+		if cancel != nil {
+			cancel()
+		}
+		return nil, err
+	}
+
+	return cos.NewReaderWithArgs(cos.ReaderArgs{
+		R:      resp.Body,
+		Size:   resp.ContentLength,
+		ReadCb: func(i int, err error) { c.xctn.OutObjsAdd(1, int64(i)) },
+		DeferCb: func() {
+			if cancel != nil {
+				cancel()
+			}
+			c.xctn.InObjsAdd(1, in.Load())
+		},
+	}), nil
+}
+
 func (c *baseComm) getWithTimeout(url string, size int64, timeout time.Duration) (r cos.ReadCloseSizer, err error) {
 	if err := c.xctn.AbortErr(); err != nil {
 		return nil, cmn.NewErrAborted(c.xctn.Name(), "transform-get", err)
@@ -384,7 +466,7 @@ func (c *baseComm) getWithTimeout(url string, size int64, timeout time.Duration)
 	if err != nil {
 		goto finish
 	}
-	resp, err = c.t.DataClient().Do(req) // nolint:bodyclose // Closed by the caller.
+	resp, err = c.httpClient().Do(req) // nolint:bodyclose // Closed by the caller.
 finish:
 	if err != nil {
 		if cancel != nil {
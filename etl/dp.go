@@ -15,7 +15,7 @@ import (
 
 type OfflineDataProvider struct {
 	tcbMsg         *apc.TCBMsg
-	comm           Communicator
+	pipeline       *Pipeline
 	requestTimeout time.Duration
 }
 
@@ -23,15 +23,18 @@ type OfflineDataProvider struct {
 var _ cluster.DP = (*OfflineDataProvider)(nil)
 
 func NewOfflineDataProvider(msg *apc.TCBMsg, lsnode *cluster.Snode) (*OfflineDataProvider, error) {
-	comm, err := GetCommunicator(msg.ID, lsnode)
+	pipeline, err := NewPipeline(msg.ETLs(), lsnode)
 	if err != nil {
 		return nil, err
 	}
-	pr := &OfflineDataProvider{tcbMsg: msg, comm: comm}
+	pr := &OfflineDataProvider{tcbMsg: msg, pipeline: pipeline}
 	pr.requestTimeout = time.Duration(msg.RequestTimeout)
 	return pr, nil
 }
 
+// StatsExt returns cumulative per-stage (per-ETL) counters (see Pipeline.StatsExt).
+func (dp *OfflineDataProvider) StatsExt() map[string]string { return dp.pipeline.StatsExt() }
+
 // Returns reader resulting from lom ETL transformation.
 func (dp *OfflineDataProvider) Reader(lom *cluster.LOM) (cos.ReadOpenCloser, cmn.ObjAttrsHolder, error) {
 	var (
@@ -39,7 +42,7 @@ func (dp *OfflineDataProvider) Reader(lom *cluster.LOM) (cos.ReadOpenCloser, cmn
 		err error
 	)
 	call := func() (int, error) {
-		r, err = dp.comm.OfflineTransform(lom.Bck(), lom.ObjName, dp.requestTimeout)
+		r, err = dp.pipeline.OfflineTransform(lom.Bck(), lom.ObjName, dp.requestTimeout)
 		return 0, err
 	}
 	// TODO: Check if ETL pod is healthy and wait some more if not (yet).
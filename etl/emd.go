@@ -108,6 +108,8 @@ func (e *MD) UnmarshalJSON(data []byte) (err error) {
 			e.ETLs[k] = &InitCodeMsg{}
 		} else if v.Type == apc.ETLInitSpec {
 			e.ETLs[k] = &InitSpecMsg{}
+		} else if v.Type == apc.ETLInitProc {
+			e.ETLs[k] = &InitProcMsg{}
 		}
 		if err = jsoniter.Unmarshal(v.Msg, e.ETLs[k]); err != nil {
 			break
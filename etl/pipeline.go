@@ -0,0 +1,229 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// Pipeline chains ETLs: the first transformer reads the object (the same way
+// a single ETL does), while the output of each transformer is streamed straight
+// into the input of the next one (see `Communicator.TransformStream`) - with no
+// intermediate results staged to disk.
+
+// PipelineSep separates ETL IDs of the pipeline in the inline GET request, e.g. `?uuid=a,b,c`.
+const PipelineSep = ","
+
+type (
+	Pipeline struct {
+		ids    []string
+		comms  []Communicator
+		totals []stageTotals // cumulative (all transformations), by stage
+	}
+	stageTotals struct {
+		in, out, errs atomic.Int64
+	}
+
+	// StageStats reports the bytes transferred to and from a given stage (ETL)
+	// of the pipeline, and the error (if any) that the stage has failed with.
+	StageStats struct {
+		ID       string `json:"id"`
+		InBytes  int64  `json:"in_bytes"`
+		OutBytes int64  `json:"out_bytes"`
+		Err      string `json:"error,omitempty"`
+	}
+
+	// single transformation by the pipeline
+	pipelineRun struct {
+		p     *Pipeline
+		in    []atomic.Int64
+		out   []atomic.Int64
+		mu    sync.Mutex // protects errs and stage (stages fail in different goroutines)
+		errs  []error
+		stage int // the stage that has failed first
+	}
+
+	// counts the output of a given stage, and attributes read errors to it
+	stageReader struct {
+		r     cos.ReadCloseSizer
+		run   *pipelineRun
+		stage int
+	}
+)
+
+// interface guard
+var _ cos.ReadCloseSizer = (*stageReader)(nil)
+
+// ParsePipeline splits comma-separated list of ETL IDs.
+func ParsePipeline(s string) (ids []string) {
+	for _, id := range strings.Split(s, PipelineSep) {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+func NewPipeline(ids []string, lsnode *cluster.Snode) (*Pipeline, error) {
+	if len(ids) == 0 {
+		return nil, apc.ErrETLMissingUUID
+	}
+	p := &Pipeline{ids: ids, comms: make([]Communicator, 0, len(ids)), totals: make([]stageTotals, len(ids))}
+	for _, id := range ids {
+		comm, err := GetCommunicator(id, lsnode)
+		if err != nil {
+			return nil, err
+		}
+		p.comms = append(p.comms, comm)
+	}
+	return p, nil
+}
+
+func (p *Pipeline) String() string { return "etl-pipeline[" + strings.Join(p.ids, "->") + "]" }
+
+// Comm returns the communicator of the given stage.
+func (p *Pipeline) Comm(stage int) Communicator { return p.comms[stage] }
+
+// OnlineTransform writes transformed object into the response. Per-stage stats
+// of the multi-stage pipeline are sent in the `apc.HdrETLPipelineStats` trailer.
+func (p *Pipeline) OnlineTransform(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, objName string) error {
+	if len(p.comms) == 1 {
+		return p.comms[0].OnlineTransform(w, r, bck, objName)
+	}
+	run := p.newRun()
+	rc, err := run.transform(bck, objName, 0 /*timeout*/)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Trailer", apc.HdrETLPipelineStats)
+	size := rc.Size()
+	if size < 0 {
+		size = memsys.DefaultBufSize
+	}
+	buf, slab := memsys.PageMM().AllocSize(size)
+	_, err = io.CopyBuffer(w, rc, buf)
+	slab.Free(buf)
+	rc.Close()
+	w.Header().Set(apc.HdrETLPipelineStats, string(cos.MustMarshal(run.stats())))
+	return err
+}
+
+// OfflineTransform returns the reader of the transformed object.
+// NOTE: unlike OnlineTransform, counts (per-stage) bytes and errors even when the pipeline
+// has a single stage (see StatsExt).
+func (p *Pipeline) OfflineTransform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	return p.newRun().transform(bck, objName, timeout)
+}
+
+// StatsExt returns cumulative per-stage counters, e.g.: "etl.1.<ETL ID>.in.size"
+// (as extended stats of the offline transformation - see mirror.XactTCB)
+func (p *Pipeline) StatsExt() map[string]string {
+	ext := make(map[string]string, 3*len(p.ids))
+	for i, id := range p.ids {
+		prefix := fmt.Sprintf("etl.%d.%s.", i+1, id)
+		ext[prefix+"in.size"] = strconv.FormatInt(p.totals[i].in.Load(), 10)
+		ext[prefix+"out.size"] = strconv.FormatInt(p.totals[i].out.Load(), 10)
+		ext[prefix+"err.n"] = strconv.FormatInt(p.totals[i].errs.Load(), 10)
+	}
+	return ext
+}
+
+func (p *Pipeline) newRun() *pipelineRun {
+	n := len(p.comms)
+	return &pipelineRun{p: p, in: make([]atomic.Int64, n), out: make([]atomic.Int64, n), errs: make([]error, n), stage: -1}
+}
+
+/////////////////
+// pipelineRun //
+/////////////////
+
+func (run *pipelineRun) transform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	size, err := determineSize(bck, objName)
+	if err != nil {
+		return nil, run.fail(0, err)
+	}
+	run.in[0].Store(size)
+	run.p.totals[0].in.Add(size)
+	r, err := run.p.comms[0].OfflineTransform(bck, objName, timeout)
+	if err != nil {
+		return nil, run.fail(0, err)
+	}
+	for i := 1; i < len(run.p.comms); i++ {
+		in := &stageReader{r: r, run: run, stage: i - 1}
+		if r, err = run.p.comms[i].TransformStream(in, in.Size(), timeout); err != nil {
+			in.Close()
+			// NOTE: if the upstream stage has failed while streaming, `fail` returns its error
+			return nil, run.fail(i, err)
+		}
+	}
+	return &stageReader{r: r, run: run, stage: len(run.p.comms) - 1}, nil
+}
+
+func (run *pipelineRun) fail(stage int, err error) error {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if run.stage >= 0 {
+		return run.errs[run.stage]
+	}
+	run.stage = stage
+	run.p.totals[stage].errs.Inc()
+	if len(run.p.ids) == 1 {
+		run.errs[stage] = err // (single ETL: as is)
+		return err
+	}
+	run.errs[stage] = cmn.NewErrETL(&cmn.ETLErrorContext{
+		UUID:    run.p.ids[stage],
+		PodName: run.p.comms[stage].PodName(),
+		SvcName: run.p.comms[stage].SvcName(),
+	}, "pipeline stage %d/%d: %v", stage+1, len(run.p.ids), err)
+	return run.errs[stage]
+}
+
+func (run *pipelineRun) stats() []StageStats {
+	stats := make([]StageStats, len(run.p.ids))
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	for i, id := range run.p.ids {
+		stats[i] = StageStats{ID: id, InBytes: run.in[i].Load(), OutBytes: run.out[i].Load()}
+		if run.errs[i] != nil {
+			stats[i].Err = run.errs[i].Error()
+		}
+	}
+	return stats
+}
+
+/////////////////
+// stageReader //
+/////////////////
+
+func (sr *stageReader) Read(b []byte) (n int, err error) {
+	n, err = sr.r.Read(b)
+	sr.run.out[sr.stage].Add(int64(n))
+	sr.run.p.totals[sr.stage].out.Add(int64(n))
+	if next := sr.stage + 1; next < len(sr.run.in) {
+		sr.run.in[next].Add(int64(n))
+		sr.run.p.totals[next].in.Add(int64(n))
+	}
+	if err != nil && err != io.EOF {
+		err = sr.run.fail(sr.stage, err)
+	}
+	return
+}
+
+func (sr *stageReader) Size() int64  { return sr.r.Size() }
+func (sr *stageReader) Close() error { return sr.r.Close() }
@@ -0,0 +1,185 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cluster/mock"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+	jsoniter "github.com/json-iterator/go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("PipelineTest", func() {
+	var (
+		tmpDir     string
+		tMock      cluster.Target
+		objData    []byte
+		servers    []*httptest.Server
+		dataSize   = int64(cos.MiB)
+		bck        = cmn.Bck{Name: "pipelineBck", Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
+		objName    = "pipelineObj"
+		clusterBck = cluster.NewBck(
+			bck.Name, bck.Provider, bck.Ns,
+			&cmn.BucketProps{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}},
+		)
+		bmdMock = mock.NewBaseBownerMock(clusterBck)
+	)
+
+	invert := func(b []byte) []byte {
+		out := make([]byte, len(b))
+		for i := range b {
+			out[i] = ^b[i]
+		}
+		return out
+	}
+	// transformer that applies `f` to the pushed object
+	newTransformer := func(f func([]byte) []byte) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write(f(b))
+			Expect(err).NotTo(HaveOccurred())
+		}))
+		servers = append(servers, srv)
+		return srv.URL
+	}
+	newPipeline := func(uris ...string) *Pipeline {
+		p := &Pipeline{}
+		for i, uri := range uris {
+			pod := &corev1.Pod{}
+			pod.SetName("pod" + string(rune('a'+i)))
+			p.ids = append(p.ids, "etl-"+string(rune('a'+i)))
+			comm, err := makeCommunicator(commArgs{
+				bootstraper: &etlBootstraper{
+					t:    tMock,
+					msg:  InitSpecMsg{InitMsgBase: InitMsgBase{CommTypeX: PushCommType}},
+					pod:  pod,
+					uri:  uri,
+					xctn: mock.NewXact(apc.ActETLInline),
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			p.comms = append(p.comms, comm)
+		}
+		p.totals = make([]stageTotals, len(uris))
+		return p
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		mpath := filepath.Join(tmpDir, "mpath")
+		err = cos.CreateDir(mpath)
+		Expect(err).NotTo(HaveOccurred())
+		fs.TestNew(nil)
+		fs.TestDisableValidation()
+		_, err = fs.Add(mpath, "daeID")
+		Expect(err).NotTo(HaveOccurred())
+
+		tMock = mock.NewTarget(bmdMock)
+
+		lom := &cluster.LOM{ObjName: objName}
+		err = lom.InitBck(clusterBck.Bucket())
+		Expect(err).NotTo(HaveOccurred())
+		err = createRandomFile(lom.FQN, dataSize)
+		Expect(err).NotTo(HaveOccurred())
+		lom.SetAtimeUnix(time.Now().UnixNano())
+		lom.SetSize(dataSize)
+		err = lom.Persist()
+		Expect(err).NotTo(HaveOccurred())
+		objData, err = os.ReadFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(tmpDir)
+		for _, srv := range servers {
+			srv.Close()
+		}
+		servers = servers[:0]
+	})
+
+	It("should parse pipeline", func() {
+		Expect(ParsePipeline("a")).To(Equal([]string{"a"}))
+		Expect(ParsePipeline("a, b,,c")).To(Equal([]string{"a", "b", "c"}))
+		Expect(ParsePipeline("")).To(BeEmpty())
+	})
+
+	It("should chain transformers offline", func() {
+		p := newPipeline(
+			newTransformer(invert),
+			newTransformer(func(b []byte) []byte { return append(b, b...) }),
+		)
+		r, err := p.OfflineTransform(clusterBck, objName, 0)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Close()).NotTo(HaveOccurred())
+
+		inverted := invert(objData)
+		Expect(b).To(Equal(append(inverted, inverted...)))
+
+		ext := p.StatsExt()
+		Expect(ext).To(HaveKeyWithValue("etl.1.etl-a.in.size", strconv.FormatInt(dataSize, 10)))
+		Expect(ext).To(HaveKeyWithValue("etl.2.etl-b.in.size", strconv.FormatInt(dataSize, 10)))
+		Expect(ext).To(HaveKeyWithValue("etl.2.etl-b.out.size", strconv.FormatInt(2*dataSize, 10)))
+		Expect(ext).To(HaveKeyWithValue("etl.2.etl-b.err.n", "0"))
+	})
+
+	It("should report per-stage stats in trailer", func() {
+		p := newPipeline(
+			newTransformer(invert),
+			newTransformer(func(b []byte) []byte { return b[:len(b)/2] }),
+		)
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(p.OnlineTransform(w, r, clusterBck, objName)).NotTo(HaveOccurred())
+		}))
+		defer targetServer.Close()
+
+		resp, err := http.Get(targetServer.URL)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(int64(len(b))).To(Equal(dataSize / 2))
+
+		var stats []StageStats
+		err = jsoniter.Unmarshal([]byte(resp.Trailer.Get(apc.HdrETLPipelineStats)), &stats)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stats).To(Equal([]StageStats{
+			{ID: "etl-a", InBytes: dataSize, OutBytes: dataSize},
+			{ID: "etl-b", InBytes: dataSize, OutBytes: dataSize / 2},
+		}))
+	})
+
+	It("should fail with the stage that has failed", func() {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad input", http.StatusBadRequest)
+		}))
+		servers = append(servers, failing)
+		p := newPipeline(newTransformer(invert), failing.URL)
+
+		_, err := p.OfflineTransform(clusterBck, objName, 0)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("pipeline stage 2/2"))
+		Expect(err.Error()).To(ContainSubstring("etl-b"))
+	})
+})
@@ -0,0 +1,360 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Local-process ETL: instead of deploying a K8s pod, each target starts
+// the transformer as its own child process. The process serves the same
+// HTTP protocol as ETL containers (see `Communicator`), listening either
+// on a unix socket or on a localhost TCP port. The target supervises the
+// process: output is captured (for `PodLogs`), unexpected exits are followed
+// by a (limited) number of restarts, after which the ETL gets stopped.
+
+const (
+	procNetUnix = "unix"
+	procNetTCP  = "tcp"
+
+	procSocketName = "etl.sock"
+
+	procMaxRestarts   = 3
+	procRestartDelay  = time.Second // multiplied by the number of restarts so far
+	procStopTimeout   = 10 * time.Second
+	procProbeInterval = 200 * time.Millisecond
+	procDefaultWait   = time.Minute
+	procMaxLogSize    = cos.MiB
+
+	// env variables provided to the process
+	procEnvSocket    = "AIS_ETL_SOCKET" // unix socket path (network "unix")
+	procEnvAddr      = "AIS_ETL_ADDR"   // host:port to listen on (network "tcp")
+	procEnvPort      = "AIS_ETL_PORT"   // port to listen on (network "tcp")
+	procEnvTargetURL = "AIS_TARGET_URL"
+)
+
+type (
+	etlProc struct {
+		errCtx  *cmn.ETLErrorContext
+		command []string
+		env     []string
+		dir     string // working directory (also contains the unix socket)
+		network string
+		addr    string // unix socket path or localhost TCP address
+		logs    *procLogs
+		onFail  func(err error) // called when the process can no longer be restarted
+
+		mtx      sync.Mutex
+		cmd      *exec.Cmd
+		exited   chan struct{} // closed when the current `cmd` exits
+		restarts int
+		stopping bool
+	}
+
+	// procLogs keeps the most recent (combined) output of the process.
+	procLogs struct {
+		mtx sync.Mutex
+		buf []byte
+	}
+)
+
+// InitProc starts local-process ETL on the target.
+func InitProc(t cluster.Target, msg InitProcMsg) error {
+	errCtx := &cmn.ETLErrorContext{
+		TID:     t.SID(),
+		UUID:    msg.IDX,
+		ETLName: msg.IDX,
+		PodName: msg.IDX + "-" + t.SID(),
+	}
+	if err := CheckProcEnabled(cmn.GCO.Get()); err != nil {
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+	targetURL := t.Snode().URL(cmn.NetPublic) + apc.URLPathETLObject.Join(reqSecret)
+	proc, err := newProc(targetURL, &msg, errCtx)
+	if err != nil {
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+	proc.onFail = func(err error) {
+		if err := Stop(t, msg.IDX, err); err != nil {
+			glog.Error(err)
+		}
+	}
+	if err := proc.start(); err != nil {
+		proc.cleanup()
+		return cmn.NewErrETL(errCtx, "failed to start %q: %v", msg.Command[0], err)
+	}
+	if err := proc.waitReady(time.Duration(msg.WaitTimeout)); err != nil {
+		proc.stop()
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+
+	rns := xreg.RenewETL(t, &msg)
+	debug.AssertNoErr(rns.Err)
+	debug.Assert(!rns.IsRunning())
+
+	baseComm := baseComm{
+		Slistener: newAborter(t, msg.IDX),
+		t:         t,
+		name:      msg.IDX,
+		podName:   errCtx.PodName,
+		xctn:      rns.Entry.Get(),
+		client:    proc.httpClient(),
+	}
+	comm, err := newCommunicator(baseComm, msg.CommTypeX, proc.uri(), nil)
+	if err != nil {
+		baseComm.xctn.Finish(err)
+		proc.stop()
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+	c := &procComm{Communicator: comm, proc: proc}
+	if err := reg.put(msg.IDX, c); err != nil {
+		c.Stop()
+		return err
+	}
+	t.Sowner().Listeners().Reg(c)
+	return nil
+}
+
+func newProc(targetURL string, msg *InitProcMsg, errCtx *cmn.ETLErrorContext) (p *etlProc, err error) {
+	p = &etlProc{
+		errCtx:  errCtx,
+		command: msg.Command,
+		network: msg.Network,
+		logs:    &procLogs{},
+	}
+	if p.network == "" {
+		p.network = procNetUnix
+	}
+	if p.dir, err = os.MkdirTemp("", "ais-etl-"+msg.IDX+"-"); err != nil {
+		return nil, err
+	}
+	p.env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + os.Getenv("HOME"),
+		procEnvTargetURL + "=" + targetURL,
+	}
+	switch p.network {
+	case procNetUnix:
+		p.addr = filepath.Join(p.dir, procSocketName)
+		p.env = append(p.env, procEnvSocket+"="+p.addr)
+	case procNetTCP:
+		var port string
+		if p.addr, port, err = freeLocalAddr(); err != nil {
+			p.cleanup()
+			return nil, err
+		}
+		p.env = append(p.env, procEnvAddr+"="+p.addr, procEnvPort+"="+port)
+	default:
+		p.cleanup()
+		return nil, fmt.Errorf("unsupported network %q", p.network)
+	}
+	for k, v := range msg.Env {
+		p.env = append(p.env, k+"="+v)
+	}
+	return p, nil
+}
+
+func (p *etlProc) start() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.stopping {
+		return fmt.Errorf("process is stopping")
+	}
+	if p.network == procNetUnix {
+		// remove stale socket (if any) left by the previous incarnation
+		if err := os.Remove(p.addr); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	cmd := exec.Command(p.command[0], p.command[1:]...)
+	cmd.Dir, cmd.Env = p.dir, p.env
+	cmd.Stdout, cmd.Stderr = p.logs, p.logs
+	// separate process group so that `stop` terminates all descendants as well
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	p.cmd, p.exited = cmd, make(chan struct{})
+	go p.supervise(cmd, p.exited)
+	return nil
+}
+
+func (p *etlProc) supervise(cmd *exec.Cmd, exited chan struct{}) {
+	err := cmd.Wait()
+	close(exited)
+
+	p.mtx.Lock()
+	if p.stopping {
+		p.mtx.Unlock()
+		return
+	}
+	p.restarts++
+	restarts := p.restarts
+	p.mtx.Unlock()
+
+	glog.Error(cmn.NewErrETL(p.errCtx, "process (pid %d) exited unexpectedly: %v", cmd.Process.Pid, err))
+	if restarts > procMaxRestarts {
+		p.fail(fmt.Errorf("process exited %d times, giving up (last error: %v)", restarts, err))
+		return
+	}
+	time.Sleep(procRestartDelay * time.Duration(restarts))
+	if err := p.start(); err != nil {
+		p.fail(fmt.Errorf("failed to restart process: %v", err))
+		return
+	}
+	glog.Warningf("%s: restarted (pid %d, restarts %d)", p.errCtx.PodName, p.pid(), restarts)
+}
+
+func (p *etlProc) fail(err error) {
+	p.mtx.Lock()
+	stopping := p.stopping
+	p.mtx.Unlock()
+	if !stopping && p.onFail != nil {
+		p.onFail(cmn.NewErrETL(p.errCtx, err.Error()))
+	}
+}
+
+// waitReady waits until the process starts accepting connections.
+func (p *etlProc) waitReady(timeout time.Duration) error {
+	if timeout == 0 {
+		timeout = procDefaultWait
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout(p.network, p.addr, procProbeInterval)
+		if err == nil {
+			cos.Close(conn)
+			return nil
+		}
+		p.mtx.Lock()
+		exited := p.exited
+		p.mtx.Unlock()
+		select {
+		case <-exited:
+			return fmt.Errorf("process exited before accepting connections, logs:\n%s", p.logs.bytes())
+		case <-time.After(procProbeInterval):
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for the process to listen on %s %q: %v", p.network, p.addr, err)
+		}
+	}
+}
+
+// stop terminates the process group (SIGTERM, then SIGKILL after a timeout)
+// and removes the working directory.
+func (p *etlProc) stop() {
+	p.mtx.Lock()
+	if p.stopping {
+		p.mtx.Unlock()
+		return
+	}
+	p.stopping = true
+	cmd, exited := p.cmd, p.exited
+	p.mtx.Unlock()
+
+	if cmd != nil {
+		pgid := -cmd.Process.Pid
+		_ = syscall.Kill(pgid, syscall.SIGTERM)
+		select {
+		case <-exited:
+		case <-time.After(procStopTimeout):
+			glog.Warningf("%s: process (pid %d) did not terminate in %v, killing", p.errCtx.PodName,
+				cmd.Process.Pid, procStopTimeout)
+			_ = syscall.Kill(pgid, syscall.SIGKILL)
+			<-exited
+		}
+	}
+	p.cleanup()
+}
+
+func (p *etlProc) cleanup() {
+	if err := os.RemoveAll(p.dir); err != nil {
+		glog.Error(err)
+	}
+}
+
+func (p *etlProc) pid() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.cmd == nil {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+// httpClient returns the client to talk to the process over unix socket;
+// nil for TCP (in which case the target's data client gets used).
+func (p *etlProc) httpClient() *http.Client {
+	if p.network != procNetUnix {
+		return nil
+	}
+	var (
+		addr   = p.addr
+		dialer = &net.Dialer{Timeout: cmn.Timeout.MaxKeepalive()}
+	)
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, procNetUnix, addr)
+			},
+		},
+	}
+}
+
+func (p *etlProc) uri() string {
+	if p.network == procNetUnix {
+		return "http://localhost" // the host is ignored - see `httpClient`
+	}
+	return "http://" + p.addr
+}
+
+func freeLocalAddr() (addr, port string, err error) {
+	l, err := net.Listen(procNetTCP, "127.0.0.1:0")
+	if err != nil {
+		return "", "", err
+	}
+	addr = l.Addr().String()
+	cos.Close(l)
+	_, port, err = net.SplitHostPort(addr)
+	return
+}
+
+//////////////
+// procLogs //
+//////////////
+
+func (l *procLogs) Write(b []byte) (int, error) {
+	l.mtx.Lock()
+	l.buf = append(l.buf, b...)
+	if over := len(l.buf) - procMaxLogSize; over > 0 {
+		l.buf = append(l.buf[:0], l.buf[over:]...)
+	}
+	l.mtx.Unlock()
+	return len(b), nil
+}
+
+func (l *procLogs) bytes() []byte {
+	l.mtx.Lock()
+	b := append([]byte(nil), l.buf...)
+	l.mtx.Unlock()
+	return b
+}
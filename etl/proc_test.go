@@ -0,0 +1,132 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// When set, the test binary acts as a local ETL process (see `init` below).
+const testProcEnv = "AIS_ETL_TEST_PROC"
+
+func init() {
+	if os.Getenv(testProcEnv) == "" {
+		return
+	}
+	var (
+		l   net.Listener
+		err error
+	)
+	if socket := os.Getenv(procEnvSocket); socket != "" {
+		l, err = net.Listen(procNetUnix, socket)
+	} else {
+		l, err = net.Listen(procNetTCP, os.Getenv(procEnvAddr))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println("transformer started")
+	err = http.Serve(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Write(bytes.ToUpper(b))
+	}))
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+var _ = Describe("LocalProcess", func() {
+	newTestProc := func(network string) *etlProc {
+		exe, err := os.Executable()
+		Expect(err).NotTo(HaveOccurred())
+		msg := &InitProcMsg{
+			InitMsgBase: InitMsgBase{IDX: "test-proc"},
+			Command:     []string{exe},
+			Env:         map[string]string{testProcEnv: "1"},
+			Network:     network,
+		}
+		Expect(msg.Validate()).NotTo(HaveOccurred())
+		proc, err := newProc("http://localhost:8080", msg, &cmn.ETLErrorContext{UUID: msg.IDX})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proc.start()).NotTo(HaveOccurred())
+		Expect(proc.waitReady(time.Minute)).NotTo(HaveOccurred())
+		return proc
+	}
+
+	transform := func(proc *etlProc, data string) string {
+		client := proc.httpClient()
+		if client == nil {
+			client = http.DefaultClient
+		}
+		req, err := http.NewRequest(http.MethodPut, proc.uri(), strings.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := client.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	for _, network := range []string{procNetUnix, procNetTCP} {
+		network := network
+		It("should transform via local process over "+network, func() {
+			proc := newTestProc(network)
+			Expect(transform(proc, "abc")).To(Equal("ABC"))
+			Expect(string(proc.logs.bytes())).To(ContainSubstring("transformer started"))
+
+			proc.stop()
+			_, err := os.Stat(proc.dir)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	}
+
+	It("should restart process that exited unexpectedly", func() {
+		proc := newTestProc(procNetUnix)
+		defer proc.stop()
+
+		pid := proc.pid()
+		Expect(syscall.Kill(pid, syscall.SIGKILL)).NotTo(HaveOccurred())
+		Eventually(proc.pid, 10*time.Second, 100*time.Millisecond).ShouldNot(Equal(pid))
+		Expect(proc.waitReady(time.Minute)).NotTo(HaveOccurred())
+		Expect(transform(proc, "xyz")).To(Equal("XYZ"))
+	})
+
+	It("should fail to start process that exits immediately", func() {
+		msg := &InitProcMsg{
+			InitMsgBase: InitMsgBase{IDX: "test-proc-fail"},
+			Command:     []string{"sh", "-c", "echo bad-transformer; exit 1"},
+		}
+		Expect(msg.Validate()).NotTo(HaveOccurred())
+		proc, err := newProc("http://localhost:8080", msg, &cmn.ETLErrorContext{UUID: msg.IDX})
+		Expect(err).NotTo(HaveOccurred())
+		defer proc.stop()
+		Expect(proc.start()).NotTo(HaveOccurred())
+		err = proc.waitReady(time.Minute)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("bad-transformer"))
+	})
+
+	It("should validate init message", func() {
+		msg := &InitProcMsg{InitMsgBase: InitMsgBase{IDX: "test-proc", CommTypeX: RedirectCommType}, Command: []string{"x"}}
+		Expect(msg.Validate()).To(HaveOccurred())
+		msg = &InitProcMsg{InitMsgBase: InitMsgBase{IDX: "test-proc"}}
+		Expect(msg.Validate()).To(HaveOccurred())
+		msg = &InitProcMsg{InitMsgBase: InitMsgBase{IDX: "test-proc"}, Command: []string{"x"}, Network: "udp"}
+		Expect(msg.Validate()).To(HaveOccurred())
+	})
+})
@@ -24,9 +24,13 @@ const (
 	RevProxyCommType = "hrev://"
 	// Stdin/stdout communication.
 	IOCommType = "io://"
+	// Target keeps a long-lived intra-cluster transport stream to the ETL
+	// container and pipelines objects through it; the container sends transformed
+	// objects back to the target via another stream (see `streamComm`).
+	StreamCommType = "hstream://"
 )
 
-var commTypes = []string{PushCommType, RedirectCommType, RevProxyCommType, IOCommType}
+var commTypes = []string{PushCommType, RedirectCommType, RevProxyCommType, IOCommType, StreamCommType}
 
 type (
 	registry struct {
@@ -11,8 +11,12 @@ const (
 	Python36  = "python3.6"
 	Python38  = "python3.8"
 	Python310 = "python3.10"
+
+	// WebAssembly module that runs in-process on each target (no pod - see `etl.InitWasm`)
+	Wasm = "wasm"
 )
 
+// pod-based runtimes
 var Runtimes map[string]runtime
 
 type (
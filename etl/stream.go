@@ -0,0 +1,325 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/transport"
+)
+
+// Stream communication (`StreamCommType`) avoids per-object HTTP request overhead
+// of offline transformation of many (small) objects. Target keeps a long-lived
+// transport stream to the ETL container (the same header/PDU framing that targets
+// use to talk to each other) and pipelines objects through it, with at most
+// `streamWindow` objects in flight.
+//
+// Protocol (the ETL container side):
+//   * receive objects at `PUT /v1/objstream/<AIS_ETL_TRNAME>`;
+//   * send each transformed object to `AIS_TRANSPORT_URL`, with the header's
+//     `Opaque` copied from the corresponding received object;
+//   * to report failure, send the error message in place of the object, with
+//     the header's `Opcode` set to `StreamOpcodeErr`.
+// Responses may be sent in any order.
+
+const (
+	// ETL container's environment
+	envETLTrname    = "AIS_ETL_TRNAME"
+	envTransportURL = "AIS_TRANSPORT_URL"
+
+	// transformed object (see above) carries the error message
+	StreamOpcodeErr = 1
+
+	streamWindow = 256 // max number of objects sent to the container and not yet transformed
+	reqIDLen     = 8   // `Opaque` of the object header
+)
+
+var errStreamStopped = errors.New("ETL stream stopped")
+
+type (
+	streamComm struct {
+		baseComm
+		mem    *memsys.MMSA
+		client transport.Client
+		uri    string
+		trname string // ETL container receives objects at this transport endpoint
+		window chan struct{}
+		nextID atomic.Uint64
+		mu     sync.Mutex // protects stream and pending
+		stream *transport.Stream
+		// requests waiting to be transformed, by request ID
+		pending map[uint64]chan *streamResp
+		stopped bool
+	}
+
+	// object sent to the container
+	streamReq struct {
+		id uint64
+		in atomic.Int64 // bytes sent
+	}
+	// transformed object received from the container
+	streamResp struct {
+		sgl *memsys.SGL
+		err error
+	}
+)
+
+// interface guard
+var _ Communicator = (*streamComm)(nil)
+
+// transport endpoints: the ETL container receives objects at the first one, and
+// the target receives transformed objects at the second one
+func streamTrname(podName string) string     { return "etl-" + podName }
+func streamRespTrname(podName string) string { return "etl-" + podName + "-resp" }
+
+// NOTE: fails if the ETL (and its transport endpoint) is already running
+func newStreamComm(baseComm baseComm, uri string) (*streamComm, error) {
+	sc := &streamComm{
+		baseComm: baseComm,
+		mem:      baseComm.t.PageMM(),
+		client:   transport.NewIntraDataClient(),
+		uri:      uri,
+		trname:   streamTrname(baseComm.podName),
+		window:   make(chan struct{}, streamWindow),
+		pending:  make(map[uint64]chan *streamResp, streamWindow),
+	}
+	if err := transport.HandleObjStream(streamRespTrname(baseComm.podName), sc.recv, sc.mem); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (sc *streamComm) OnlineTransform(w http.ResponseWriter, _ *http.Request, bck *cluster.Bck, objName string) error {
+	r, err := sc.OfflineTransform(bck, objName, 0 /*timeout*/)
+	if err != nil {
+		return err
+	}
+	buf, slab := sc.mem.AllocSize(r.Size())
+	_, err = io.CopyBuffer(w, r, buf)
+	slab.Free(buf)
+	r.Close()
+	return err
+}
+
+func (sc *streamComm) OfflineTransform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	return transformLOM(sc.t, bck, objName, timeout, sc.tryTransform)
+}
+
+func (sc *streamComm) tryTransform(lom *cluster.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := sc.xctn.AbortErr(); err != nil {
+		return nil, cmn.NewErrAborted(sc.xctn.Name(), "try-stream-comm", err)
+	}
+
+	// NOTE: keeping the object locked until it gets transformed
+	lom.Lock(false)
+	defer lom.Unlock(false)
+
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		return nil, err
+	}
+	fh, err := cos.NewFileHandle(lom.FQN)
+	if err != nil {
+		return nil, err
+	}
+	hdr := &transport.ObjHdr{Bck: *lom.Bucket(), ObjName: lom.ObjName, ObjAttrs: cmn.ObjAttrs{Size: lom.SizeBytes()}}
+	return sc.transform(hdr, fh, timeout)
+}
+
+func (sc *streamComm) TransformStream(r io.Reader, size int64, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := sc.xctn.AbortErr(); err != nil {
+		return nil, cmn.NewErrAborted(sc.xctn.Name(), "stream-comm-stream", err)
+	}
+	if size < 0 {
+		size = transport.SizeUnknown
+	}
+	hdr := &transport.ObjHdr{ObjAttrs: cmn.ObjAttrs{Size: size}}
+	return sc.transform(hdr, r, timeout)
+}
+
+func (sc *streamComm) Stop() {
+	sc.stop()
+	sc.baseComm.Stop()
+}
+
+func (sc *streamComm) stop() {
+	sc.mu.Lock()
+	sc.stopped = true
+	if sc.stream != nil {
+		sc.stream.Stop()
+	}
+	for id, rch := range sc.pending {
+		delete(sc.pending, id)
+		rch <- &streamResp{err: errStreamStopped}
+	}
+	sc.mu.Unlock()
+	if err := transport.Unhandle(streamRespTrname(sc.podName)); err != nil {
+		glog.Error(err)
+	}
+}
+
+// transform sends the object to the ETL container and waits for the transformed one.
+// The reader `r` is always closed (if it is `io.Closer`).
+func (sc *streamComm) transform(hdr *transport.ObjHdr, r io.Reader, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	var (
+		req     = &streamReq{id: sc.nextID.Inc()}
+		rc      = cos.NewReaderWithArgs(cos.ReaderArgs{R: r, Size: hdr.ObjAttrs.Size, ReadCb: func(n int, _ error) { req.in.Add(int64(n)) }})
+		expired <-chan time.Time
+	)
+	if timeout != 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case sc.window <- struct{}{}:
+	case <-expired:
+		cos.Close(rc)
+		return nil, fmt.Errorf("%s: timed out (%v) waiting for the in-flight window", sc.trname, timeout)
+	case <-sc.xctn.ChanAbort():
+		cos.Close(rc)
+		return nil, cmn.NewErrAborted(sc.xctn.Name(), "stream-comm", sc.xctn.AbortErr())
+	}
+	defer func() { <-sc.window }()
+
+	stream, rch, err := sc.pend(req.id)
+	if err != nil {
+		cos.Close(rc)
+		return nil, err
+	}
+	hdr.Opaque = make([]byte, reqIDLen)
+	binary.BigEndian.PutUint64(hdr.Opaque, req.id)
+	obj := &transport.Obj{
+		Hdr:      *hdr,
+		Reader:   rc,
+		Callback: sc.sent,
+		CmplArg:  req,
+	}
+	// NOTE: upon failure to send, the callback delivers the error
+	_ = stream.Send(obj)
+
+	select {
+	case resp := <-rch:
+		if resp.err != nil {
+			return nil, resp.err
+		}
+		sc.xctn.OutObjsAdd(1, resp.sgl.Size())
+		return cos.NewReaderWithArgs(cos.ReaderArgs{R: resp.sgl, Size: resp.sgl.Size(), DeferCb: resp.sgl.Free}), nil
+	case <-expired:
+		err = fmt.Errorf("%s: timed out (%v) waiting for transformed object", sc.trname, timeout)
+	case <-sc.xctn.ChanAbort():
+		err = cmn.NewErrAborted(sc.xctn.Name(), "stream-comm", sc.xctn.AbortErr())
+	}
+	sc.cancel(req.id, rch)
+	return nil, err
+}
+
+// pend registers the request and returns the stream to send it; the stream is
+// (re)created if need be - e.g., after the container has restarted
+func (sc *streamComm) pend(id uint64) (*transport.Stream, chan *streamResp, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.stopped {
+		return nil, nil, errStreamStopped
+	}
+	if sc.stream == nil || sc.stream.IsTerminated() {
+		extra := &transport.Extra{SizePDU: transport.DefaultSizePDU, SenderID: sc.t.SID()}
+		sc.stream = transport.NewObjStream(sc.client, sc.uri+transport.ObjURLPath(sc.trname), sc.podName, extra)
+	}
+	rch := make(chan *streamResp, 1)
+	sc.pending[id] = rch
+	return sc.stream, rch, nil
+}
+
+// deliver hands over the response to the waiting request, if any
+func (sc *streamComm) deliver(id uint64, resp *streamResp) {
+	sc.mu.Lock()
+	rch, ok := sc.pending[id]
+	delete(sc.pending, id)
+	sc.mu.Unlock()
+	if ok {
+		rch <- resp
+	} else if resp.sgl != nil {
+		resp.sgl.Free()
+	}
+}
+
+// cancel unregisters the request, and frees the response that may have arrived in the meantime
+func (sc *streamComm) cancel(id uint64, rch chan *streamResp) {
+	sc.mu.Lock()
+	_, ok := sc.pending[id]
+	delete(sc.pending, id)
+	sc.mu.Unlock()
+	if !ok {
+		if resp := <-rch; resp.sgl != nil {
+			resp.sgl.Free()
+		}
+	}
+}
+
+func (sc *streamComm) isPending(id uint64) (ok bool) {
+	sc.mu.Lock()
+	_, ok = sc.pending[id]
+	sc.mu.Unlock()
+	return
+}
+
+// object-sent callback
+func (sc *streamComm) sent(_ transport.ObjHdr, _ io.ReadCloser, arg interface{}, err error) {
+	req := arg.(*streamReq)
+	if err != nil {
+		sc.deliver(req.id, &streamResp{err: fmt.Errorf("%s: failed to send: %v", sc.trname, err)})
+		return
+	}
+	sc.xctn.InObjsAdd(1, req.in.Load())
+}
+
+// receives transformed objects from the container
+func (sc *streamComm) recv(hdr transport.ObjHdr, objReader io.Reader, err error) error {
+	defer transport.DrainAndFreeReader(objReader)
+	if err != nil {
+		glog.Errorf("%s: %v", sc.trname, err)
+		return err
+	}
+	if len(hdr.Opaque) != reqIDLen {
+		return fmt.Errorf("%s: invalid response header (opaque %v)", sc.trname, hdr.Opaque)
+	}
+	id := binary.BigEndian.Uint64(hdr.Opaque)
+	if !sc.isPending(id) {
+		return nil // timed out or aborted
+	}
+	resp := &streamResp{}
+	if hdr.Opcode == StreamOpcodeErr {
+		b, errR := io.ReadAll(objReader)
+		if errR != nil {
+			resp.err = errR
+		} else {
+			resp.err = fmt.Errorf("%s: %s", sc.trname, string(b))
+		}
+	} else {
+		var size int64
+		if !hdr.IsUnsized() {
+			size = hdr.ObjAttrs.Size
+		}
+		resp.sgl = sc.mem.NewSGL(size)
+		if _, errR := io.Copy(resp.sgl, objReader); errR != nil {
+			resp.sgl.Free()
+			resp.sgl, resp.err = nil, errR
+		}
+	}
+	sc.deliver(id, resp)
+	return nil
+}
@@ -0,0 +1,195 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cluster/mock"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/transport"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("StreamCommTest", func() {
+	const (
+		podName  = "stream-pod"
+		numObjs  = 300 // more than `streamWindow`
+		badObj   = "bad-obj"
+		objSize  = 10 * cos.KiB
+		badInput = "bad input"
+	)
+	var (
+		tmpDir     string
+		comm       Communicator
+		boot       *etlBootstraper
+		server     *httptest.Server
+		respStream *transport.Stream
+		objData    map[string][]byte
+		bck        = cmn.Bck{Name: "streamBck", Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
+		clusterBck = cluster.NewBck(
+			bck.Name, bck.Provider, bck.Ns,
+			&cmn.BucketProps{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}},
+		)
+		bmdMock = mock.NewBaseBownerMock(clusterBck)
+	)
+
+	// ETL container: reverses each received object and sends it back (via
+	// another stream) to the target, fails objects named `badObj`
+	newTransformer := func() {
+		server = httptest.NewServer(http.HandlerFunc(transport.RxAnyStream))
+		respStream = transport.NewObjStream(transport.NewIntraDataClient(),
+			server.URL+transport.ObjURLPath(streamRespTrname(podName)), "transformer",
+			&transport.Extra{SizePDU: transport.DefaultSizePDU})
+		recv := func(hdr transport.ObjHdr, r io.Reader, err error) error {
+			Expect(err).NotTo(HaveOccurred())
+			b, err := io.ReadAll(r)
+			Expect(err).NotTo(HaveOccurred())
+			// NOTE: copying opaque - the header is only valid within the callback
+			resp := transport.ObjHdr{Opaque: append([]byte(nil), hdr.Opaque...)}
+			if hdr.ObjName == badObj {
+				resp.Opcode = StreamOpcodeErr
+				b = []byte(badInput)
+			} else {
+				for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+					b[i], b[j] = b[j], b[i]
+				}
+			}
+			sgl := memsys.PageMM().NewSGL(int64(len(b)))
+			sgl.Write(b)
+			resp.ObjAttrs.Size = sgl.Size()
+			return respStream.Send(&transport.Obj{Hdr: resp, Reader: sgl})
+		}
+		err := transport.HandleObjStream(streamTrname(podName), recv)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	createObj := func(objName string) {
+		lom := &cluster.LOM{ObjName: objName}
+		err := lom.InitBck(clusterBck.Bucket())
+		Expect(err).NotTo(HaveOccurred())
+		err = createRandomFile(lom.FQN, objSize)
+		Expect(err).NotTo(HaveOccurred())
+		lom.SetAtimeUnix(time.Now().UnixNano())
+		lom.SetSize(objSize)
+		err = lom.Persist()
+		Expect(err).NotTo(HaveOccurred())
+		objData[objName], err = os.ReadFile(lom.FQN)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		mpath := filepath.Join(tmpDir, "mpath")
+		err = cos.CreateDir(mpath)
+		Expect(err).NotTo(HaveOccurred())
+		fs.TestNew(nil)
+		fs.TestDisableValidation()
+		_, err = fs.Add(mpath, "daeID")
+		Expect(err).NotTo(HaveOccurred())
+
+		tMock := mock.NewTarget(bmdMock)
+		objData = make(map[string][]byte, numObjs+1)
+		for i := 0; i < numObjs; i++ {
+			createObj(fmt.Sprintf("obj-%d", i))
+		}
+		createObj(badObj)
+
+		newTransformer()
+		pod := &corev1.Pod{}
+		pod.SetName(podName)
+		boot = &etlBootstraper{
+			t:    tMock,
+			msg:  InitSpecMsg{InitMsgBase: InitMsgBase{CommTypeX: StreamCommType}},
+			pod:  pod,
+			uri:  server.URL,
+			xctn: mock.NewXact(apc.ActETLBck),
+		}
+		comm, err = makeCommunicator(commArgs{bootstraper: boot})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		comm.(*streamComm).stop()
+		respStream.Stop()
+		Expect(transport.Unhandle(streamTrname(podName))).NotTo(HaveOccurred())
+		server.Close()
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("should pipeline many objects through the stream", func() {
+		var (
+			wg   sync.WaitGroup
+			errs = make(chan error, numObjs)
+		)
+		for i := 0; i < numObjs; i++ {
+			wg.Add(1)
+			go func(objName string) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				r, err := comm.OfflineTransform(clusterBck, objName, time.Minute)
+				if err != nil {
+					errs <- err
+					return
+				}
+				b, err := io.ReadAll(r)
+				r.Close()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(int64(len(b))).To(Equal(r.Size()))
+
+				expected := append([]byte(nil), objData[objName]...)
+				for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+					expected[i], expected[j] = expected[j], expected[i]
+				}
+				Expect(bytes.Equal(b, expected)).To(BeTrue())
+			}(fmt.Sprintf("obj-%d", i))
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(comm.OutBytes()).To(Equal(int64(numObjs * objSize)))
+		Eventually(comm.InBytes).Should(Equal(int64(numObjs * objSize)))
+	})
+
+	It("should transform stream of unknown size", func() {
+		r, err := comm.TransformStream(bytes.NewReader([]byte("abc")), -1, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		b, err := io.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Close()).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("cba"))
+	})
+
+	It("should fail to start the same ETL twice", func() {
+		_, err := makeCommunicator(commArgs{bootstraper: boot})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return transformer's error", func() {
+		_, err := comm.OfflineTransform(clusterBck, badObj, time.Minute)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(badInput))
+	})
+})
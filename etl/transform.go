@@ -19,6 +19,8 @@ import (
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/k8s"
 	"github.com/NVIDIA/aistore/etl/runtime"
+	"github.com/NVIDIA/aistore/sys"
+	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xact/xreg"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
@@ -158,6 +160,10 @@ func InitSpec(t cluster.Target, msg InitSpecMsg, opts StartOpts) (err error) {
 }
 
 func InitCode(t cluster.Target, msg InitCodeMsg) error {
+	if msg.Runtime == runtime.Wasm {
+		return InitWasm(t, &msg)
+	}
+
 	// Initialize runtime.
 	r, exists := runtime.Runtimes[msg.Runtime]
 	cos.Assert(exists) // Runtime should be checked in proxy during validation.
@@ -265,12 +271,17 @@ func tryStart(t cluster.Target, msg InitSpecMsg, opts StartOpts) (errCtx *cmn.ET
 
 	b.setupXaction()
 
-	c := makeCommunicator(commArgs{
+	c, err := makeCommunicator(commArgs{
 		listener:    newAborter(t, msg.IDX),
 		bootstraper: b,
 	})
+	if err != nil {
+		b.xctn.Finish(err)
+		return
+	}
 	// NOTE: Communicator is put to registry only if the whole tryStart was successful.
 	if err = reg.put(msg.IDX, c); err != nil {
+		c.Stop()
 		return
 	}
 	t.Sowner().Listeners().Reg(c)
@@ -376,8 +387,13 @@ func Stop(t cluster.Target, id string, errCause error) error {
 	errCtx.PodName = c.PodName()
 	errCtx.SvcName = c.SvcName()
 
-	if err := cleanupEntities(errCtx, c.PodName(), c.SvcName()); err != nil {
-		return err
+	// Local process (and in-process wasm runtime) is terminated by `c.Stop()` below.
+	switch c.(type) {
+	case *procComm, *wasmComm:
+	default:
+		if err := cleanupEntities(errCtx, c.PodName(), c.SvcName()); err != nil {
+			return err
+		}
 	}
 
 	if c := reg.removeByUUID(id); c != nil {
@@ -391,9 +407,6 @@ func Stop(t cluster.Target, id string, errCause error) error {
 
 // StopAll terminates all running ETLs.
 func StopAll(t cluster.Target) {
-	if k8s.Detect() != nil {
-		return
-	}
 	for _, e := range List() {
 		if err := Stop(t, e.ID, nil); err != nil {
 			glog.Error(err)
@@ -416,6 +429,12 @@ func PodLogs(t cluster.Target, transformID string) (logs PodLogsMsg, err error)
 	if err != nil {
 		return logs, err
 	}
+	if pc, ok := c.(*procComm); ok {
+		return PodLogsMsg{TargetID: t.SID(), Logs: pc.proc.logs.bytes()}, nil
+	}
+	if wc, ok := c.(*wasmComm); ok {
+		return PodLogsMsg{TargetID: t.SID(), Logs: wc.logs.bytes()}, nil
+	}
 	client, err := k8s.GetClient()
 	if err != nil {
 		return logs, err
@@ -438,6 +457,12 @@ func PodHealth(t cluster.Target, etlID string) (stats *PodHealthMsg, err error)
 	if c, err = GetCommunicator(etlID, t.Snode()); err != nil {
 		return
 	}
+	if pc, ok := c.(*procComm); ok {
+		return procHealth(t, pc.proc)
+	}
+	if _, ok := c.(*wasmComm); ok {
+		return nil, fmt.Errorf("%s: health is not available for in-process %q runtime", c.Name(), runtime.Wasm)
+	}
 	if client, err = k8s.GetClient(); err != nil {
 		return
 	}
@@ -456,6 +481,22 @@ func PodHealth(t cluster.Target, etlID string) (stats *PodHealthMsg, err error)
 	}, nil
 }
 
+func procHealth(t cluster.Target, proc *etlProc) (*PodHealthMsg, error) {
+	pid := proc.pid()
+	if pid == 0 {
+		return nil, cmn.NewErrETL(proc.errCtx, "process is not running")
+	}
+	stats, err := sys.ProcessStats(pid)
+	if err != nil {
+		return nil, cmn.NewErrETL(proc.errCtx, err.Error())
+	}
+	return &PodHealthMsg{
+		TargetID: t.SID(),
+		CPU:      stats.CPU.Percent,
+		Mem:      int64(stats.Mem.Resident),
+	}, nil
+}
+
 // Sets pods node affinity, so pod will be scheduled on the same node as a target creating it.
 func (b *etlBootstraper) setTransformAffinity() error {
 	if b.pod.Spec.Affinity == nil {
@@ -557,6 +598,15 @@ func (b *etlBootstraper) setPodEnvVariables() {
 			Name:  "AIS_TARGET_URL",
 			Value: b.t.Snode().URL(cmn.NetPublic) + apc.URLPathETLObject.Join(reqSecret),
 		})
+		if b.msg.CommTypeX == StreamCommType {
+			containers[idx].Env = append(containers[idx].Env,
+				corev1.EnvVar{Name: envETLTrname, Value: streamTrname(b.pod.Name)},
+				corev1.EnvVar{
+					Name:  envTransportURL,
+					Value: b.t.Snode().URL(cmn.NetIntraData) + transport.ObjURLPath(streamRespTrname(b.pod.Name)),
+				},
+			)
+		}
 		for k, v := range b.env {
 			containers[idx].Env = append(containers[idx].Env, corev1.EnvVar{
 				Name:  k,
@@ -7,6 +7,10 @@ package etl
 import (
 	"testing"
 
+	"github.com/NVIDIA/aistore/cluster/mock"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/hk"
+	"github.com/NVIDIA/aistore/transport"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -15,6 +19,10 @@ func TestETLTransform(t *testing.T) {
 	if testing.Short() {
 		t.Skipf("skipping %s in short mode", t.Name())
 	}
+	hk.TestInit()
+	sc := transport.Init(mock.NewStatsTracker(), cmn.GCO.Get())
+	go sc.Run()
+
 	RegisterFailHandler(Fail)
 	RunSpecs(t, t.Name())
 }
@@ -0,0 +1,250 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/memsys"
+	"github.com/NVIDIA/aistore/sys"
+	"github.com/NVIDIA/aistore/xact/xreg"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	wasmsys "github.com/tetratelabs/wazero/sys"
+)
+
+// WebAssembly ETL ("wasm" runtime of the `InitCodeMsg`) runs in-process, with no
+// container at all. Each target compiles the module once, and then transforms
+// each object by a new instance of the module, in a pure-Go sandbox (wazero).
+// The module is a WASI command (e.g., built with `GOOS=wasip1`, TinyGo, or
+// `wasm32-wasi` Rust target) that reads the object from stdin and writes the
+// transformed one to stdout; non-zero exit code fails the transformation.
+// Stderr is captured (for `PodLogs`).
+//
+// Limits: memory of each instance (`MemLimit`), time to transform an object
+// (`ExecTimeout`), and the number of concurrently running instances (`MaxInstances`).
+
+const (
+	wasmPageSize       = 64 * cos.KiB
+	wasmDfltMemLimit   = 64 * cos.MiB
+	wasmMaxMemLimit    = 4 * cos.GiB // (wasm32)
+	wasmDfltExecTimout = time.Minute
+)
+
+var wasmMagic = []byte{0x00, 'a', 's', 'm'}
+
+type wasmComm struct {
+	baseComm
+	mem      *memsys.MMSA
+	rt       wazero.Runtime
+	compiled wazero.CompiledModule
+	sema     chan struct{} // limits concurrently running instances
+	timeout  time.Duration
+	logs     *procLogs // stderr of all instances
+	stopCtx  context.Context
+	stopAll  context.CancelFunc // terminates running instances
+}
+
+// interface guard
+var _ Communicator = (*wasmComm)(nil)
+
+// InitWasm compiles WebAssembly module and starts in-process ETL on the target.
+func InitWasm(t cluster.Target, msg *InitCodeMsg) error {
+	errCtx := &cmn.ETLErrorContext{
+		TID:     t.SID(),
+		UUID:    msg.IDX,
+		ETLName: msg.IDX,
+		PodName: msg.IDX + "-" + t.SID(),
+	}
+	wc, err := newWasmComm(t, msg)
+	if err != nil {
+		return cmn.NewErrETL(errCtx, err.Error())
+	}
+
+	rns := xreg.RenewETL(t, msg)
+	debug.AssertNoErr(rns.Err)
+	debug.Assert(!rns.IsRunning())
+	wc.baseComm = baseComm{
+		Slistener: newAborter(t, msg.IDX),
+		t:         t,
+		name:      msg.IDX,
+		podName:   errCtx.PodName,
+		xctn:      rns.Entry.Get(),
+	}
+	if err := reg.put(msg.IDX, wc); err != nil {
+		wc.Stop()
+		return err
+	}
+	t.Sowner().Listeners().Reg(wc)
+	return nil
+}
+
+func newWasmComm(t cluster.Target, msg *InitCodeMsg) (*wasmComm, error) {
+	memLimit := msg.MemLimit
+	if memLimit == 0 {
+		memLimit = wasmDfltMemLimit
+	}
+	timeout := time.Duration(msg.ExecTimeout)
+	if timeout == 0 {
+		timeout = wasmDfltExecTimout
+	}
+	maxInstances := msg.MaxInstances
+	if maxInstances == 0 {
+		maxInstances = sys.NumCPU()
+	}
+
+	ctx := context.Background()
+	cfg := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(cos.DivCeil(memLimit, wasmPageSize))).
+		WithCloseOnContextDone(true) // to enforce `timeout`
+	rt := wazero.NewRuntimeWithConfig(ctx, cfg)
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, rt); err != nil {
+		rt.Close(ctx)
+		return nil, err
+	}
+	compiled, err := rt.CompileModule(ctx, msg.Code)
+	if err != nil {
+		rt.Close(ctx)
+		return nil, fmt.Errorf("failed to compile WebAssembly module: %v", err)
+	}
+	wc := &wasmComm{
+		mem:      t.PageMM(),
+		rt:       rt,
+		compiled: compiled,
+		sema:     make(chan struct{}, maxInstances),
+		timeout:  timeout,
+		logs:     &procLogs{},
+	}
+	wc.stopCtx, wc.stopAll = context.WithCancel(ctx)
+	return wc, nil
+}
+
+func (wc *wasmComm) OnlineTransform(w http.ResponseWriter, _ *http.Request, bck *cluster.Bck, objName string) error {
+	r, err := wc.OfflineTransform(bck, objName, 0 /*timeout*/)
+	if err != nil {
+		return err
+	}
+	buf, slab := wc.mem.Alloc()
+	_, err = io.CopyBuffer(w, r, buf)
+	slab.Free(buf)
+	if errC := r.Close(); err == nil {
+		err = errC
+	}
+	return err
+}
+
+func (wc *wasmComm) OfflineTransform(bck *cluster.Bck, objName string, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	return transformLOM(wc.t, bck, objName, timeout, wc.tryTransform)
+}
+
+func (wc *wasmComm) tryTransform(lom *cluster.LOM, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := wc.xctn.AbortErr(); err != nil {
+		return nil, cmn.NewErrAborted(wc.xctn.Name(), "try-wasm-comm", err)
+	}
+	lom.Lock(false)
+	err := lom.Load(false /*cache it*/, true /*locked*/)
+	if err != nil {
+		lom.Unlock(false)
+		return nil, err
+	}
+	// NOTE: the module reads the opened file, and so the object can be unlocked
+	fh, err := cos.NewFileHandle(lom.FQN)
+	lom.Unlock(false)
+	if err != nil {
+		return nil, err
+	}
+	return wc.transform(fh, timeout)
+}
+
+func (wc *wasmComm) TransformStream(r io.Reader, _ int64, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if err := wc.xctn.AbortErr(); err != nil {
+		return nil, cmn.NewErrAborted(wc.xctn.Name(), "wasm-comm-stream", err)
+	}
+	return wc.transform(r, timeout)
+}
+
+func (wc *wasmComm) Stop() {
+	wc.stop()
+	wc.baseComm.Stop()
+}
+
+func (wc *wasmComm) stop() {
+	wc.stopAll()
+	if err := wc.rt.Close(context.Background()); err != nil {
+		glog.Error(err)
+	}
+}
+
+// transform runs a new instance of the module that reads `r` (and closes it, if
+// it is `io.Closer`). The returned reader streams the module's output; the reader
+// fails if the module does.
+func (wc *wasmComm) transform(r io.Reader, timeout time.Duration) (cos.ReadCloseSizer, error) {
+	if timeout == 0 || timeout > wc.timeout {
+		timeout = wc.timeout
+	}
+	var (
+		ctx, cancel = context.WithTimeout(wc.stopCtx, timeout)
+		inBytes     atomic.Int64
+		in          = cos.NewReaderWithArgs(cos.ReaderArgs{R: r, Size: -1, ReadCb: func(n int, _ error) { inBytes.Add(int64(n)) }})
+		pr, pw      = io.Pipe()
+	)
+	select {
+	case wc.sema <- struct{}{}:
+	case <-ctx.Done():
+		cancel()
+		cos.Close(in)
+		return nil, fmt.Errorf("%s: timed out (%v) waiting for available instance", wc.name, timeout)
+	}
+	cfg := wazero.NewModuleConfig().
+		WithName(""). // anonymous, to run instances concurrently
+		WithStdin(in).
+		WithStdout(pw).
+		WithStderr(wc.logs)
+	go func() {
+		mod, err := wc.rt.InstantiateModule(ctx, wc.compiled, cfg)
+		if mod != nil {
+			mod.Close(ctx)
+		}
+		if err != nil {
+			err = wc.execErr(ctx, err)
+		}
+		cancel()
+		cos.Close(in)
+		<-wc.sema
+		wc.xctn.InObjsAdd(1, inBytes.Load())
+		pw.CloseWithError(err) // (nil => io.EOF)
+	}()
+	readCb := func(n int, err error) {
+		wc.xctn.OutObjsAdd(0, int64(n))
+		if err == io.EOF {
+			wc.xctn.OutObjsAdd(1, 0)
+		}
+	}
+	return cos.NewReaderWithArgs(cos.ReaderArgs{R: pr, Size: -1, ReadCb: readCb}), nil
+}
+
+func (wc *wasmComm) execErr(ctx context.Context, err error) error {
+	if exitErr, ok := err.(*wasmsys.ExitError); ok {
+		switch exitErr.ExitCode() {
+		case wasmsys.ExitCodeDeadlineExceeded:
+			return fmt.Errorf("%s: timed out: %v", wc.name, ctx.Err())
+		case wasmsys.ExitCodeContextCanceled:
+			return fmt.Errorf("%s: stopped", wc.name)
+		default:
+			return fmt.Errorf("%s: exited with code %d", wc.name, exitErr.ExitCode())
+		}
+	}
+	return fmt.Errorf("%s: %v", wc.name, err)
+}
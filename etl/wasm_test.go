@@ -0,0 +1,225 @@
+// Package etl provides utilities to initialize and use transformation pods.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package etl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cluster/mock"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/etl/runtime"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// wasmModule assembles a minimal WASI command: imports `fd_read` (func 0),
+// `fd_write` (func 1), and `proc_exit` (func 2), exports one page of "memory"
+// and "_start" (func 3) with the given locals and body.
+func wasmModule(locals, body []byte) []byte {
+	var (
+		uleb = func(n int) (b []byte) {
+			for ; n >= 0x80; n >>= 7 {
+				b = append(b, byte(n)|0x80)
+			}
+			return append(b, byte(n))
+		}
+		str  = func(s string) []byte { return append(uleb(len(s)), s...) }
+		sect = func(id byte, b ...[]byte) []byte {
+			c := bytes.Join(b, nil)
+			return append(append([]byte{id}, uleb(len(c))...), c...)
+		}
+		code = append(append([]byte(nil), locals...), body...)
+	)
+	code = append(code, 0x0b) // end
+	return bytes.Join([][]byte{
+		{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00},
+		// types: (i32 i32 i32 i32) -> i32; (i32) -> (); () -> ()
+		sect(1, []byte{3, 0x60, 4, 0x7f, 0x7f, 0x7f, 0x7f, 1, 0x7f, 0x60, 1, 0x7f, 0, 0x60, 0, 0}),
+		sect(2, []byte{3},
+			str("wasi_snapshot_preview1"), str("fd_read"), []byte{0x00, 0},
+			str("wasi_snapshot_preview1"), str("fd_write"), []byte{0x00, 0},
+			str("wasi_snapshot_preview1"), str("proc_exit"), []byte{0x00, 1}),
+		sect(3, []byte{1, 2}),
+		sect(5, []byte{1, 0x00, 1}),
+		sect(7, []byte{2}, str("memory"), []byte{0x02, 0}, str("_start"), []byte{0x00, 3}),
+		sect(10, []byte{1}, uleb(len(code)), code),
+	}, nil)
+}
+
+var (
+	// inverts stdin to stdout, 4KiB at a time: iovec at 0, nread/nwritten at 8, buffer at 16
+	wasmInvert = wasmModule(
+		[]byte{1, 2, 0x7f}, // locals: n, i (i32)
+		[]byte{
+			0x02, 0x40, 0x03, 0x40, // block loop
+			0x41, 0x00, 0x41, 0x10, 0x36, 0x02, 0x00, // iov.buf = 16
+			0x41, 0x04, 0x41, 0x80, 0x20, 0x36, 0x02, 0x00, // iov.len = 4096
+			0x41, 0x00, 0x41, 0x00, 0x41, 0x01, 0x41, 0x08, 0x10, 0x00, // fd_read(stdin, iov, 1, 8)
+			0x04, 0x40, 0x41, 0x01, 0x10, 0x02, 0x0b, // if errno: proc_exit(1)
+			0x41, 0x08, 0x28, 0x02, 0x00, 0x22, 0x00, 0x45, 0x0d, 0x01, // n = nread; if n == 0: break
+			0x41, 0x00, 0x21, 0x01, // i = 0
+			0x02, 0x40, 0x03, 0x40, // block loop
+			0x20, 0x01, 0x20, 0x00, 0x4f, 0x0d, 0x01, // if i >= n: break
+			0x20, 0x01, 0x20, 0x01, 0x2d, 0x00, 0x10, 0x41, 0x7f, 0x73, 0x3a, 0x00, 0x10, // buf[i] ^= 0xff
+			0x20, 0x01, 0x41, 0x01, 0x6a, 0x21, 0x01, 0x0c, 0x00, // i++; continue
+			0x0b, 0x0b, // end end
+			0x41, 0x04, 0x20, 0x00, 0x36, 0x02, 0x00, // iov.len = n
+			0x41, 0x01, 0x41, 0x00, 0x41, 0x01, 0x41, 0x08, 0x10, 0x01, // fd_write(stdout, iov, 1, 8)
+			0x04, 0x40, 0x41, 0x01, 0x10, 0x02, 0x0b, // if errno: proc_exit(1)
+			0x0c, 0x00, // continue
+			0x0b, 0x0b, // end end
+		},
+	)
+	// exits with code 3
+	wasmFail = wasmModule([]byte{0}, []byte{0x41, 0x03, 0x10, 0x02})
+	// never returns
+	wasmHang = wasmModule([]byte{0}, []byte{0x03, 0x40, 0x0c, 0x00, 0x0b})
+)
+
+var _ = Describe("WasmTest", func() {
+	const (
+		numObjs = 50
+		objSize = 100 * cos.KiB
+	)
+	var (
+		tmpDir     string
+		tMock      cluster.Target
+		comms      []*wasmComm
+		objData    map[string][]byte
+		bck        = cmn.Bck{Name: "wasmBck", Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
+		clusterBck = cluster.NewBck(
+			bck.Name, bck.Provider, bck.Ns,
+			&cmn.BucketProps{Cksum: cmn.CksumConf{Type: cos.ChecksumXXHash}},
+		)
+		bmdMock = mock.NewBaseBownerMock(clusterBck)
+	)
+
+	newComm := func(msg *InitCodeMsg) *wasmComm {
+		wc, err := newWasmComm(tMock, msg)
+		Expect(err).NotTo(HaveOccurred())
+		wc.baseComm = baseComm{t: tMock, name: "wasm", podName: "wasm-pod", xctn: mock.NewXact(apc.ActETLBck)}
+		comms = append(comms, wc)
+		return wc
+	}
+	invert := func(b []byte) []byte {
+		out := make([]byte, len(b))
+		for i := range b {
+			out[i] = ^b[i]
+		}
+		return out
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		mpath := filepath.Join(tmpDir, "mpath")
+		err = cos.CreateDir(mpath)
+		Expect(err).NotTo(HaveOccurred())
+		fs.TestNew(nil)
+		fs.TestDisableValidation()
+		_, err = fs.Add(mpath, "daeID")
+		Expect(err).NotTo(HaveOccurred())
+
+		tMock = mock.NewTarget(bmdMock)
+		objData = make(map[string][]byte, numObjs)
+		for i := 0; i < numObjs; i++ {
+			lom := &cluster.LOM{ObjName: fmt.Sprintf("obj-%d", i)}
+			err = lom.InitBck(clusterBck.Bucket())
+			Expect(err).NotTo(HaveOccurred())
+			err = createRandomFile(lom.FQN, objSize)
+			Expect(err).NotTo(HaveOccurred())
+			lom.SetAtimeUnix(time.Now().UnixNano())
+			lom.SetSize(objSize)
+			err = lom.Persist()
+			Expect(err).NotTo(HaveOccurred())
+			objData[lom.ObjName], err = os.ReadFile(lom.FQN)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		for _, wc := range comms {
+			wc.stop()
+		}
+		comms = comms[:0]
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("should validate wasm init message", func() {
+		msg := InitCodeMsg{InitMsgBase: InitMsgBase{IDX: "wasm-etl"}, Code: wasmInvert, Runtime: runtime.Wasm}
+		Expect(msg.Validate()).NotTo(HaveOccurred())
+		Expect(IsLocal(&msg)).To(BeTrue())
+
+		bad := msg
+		bad.Code = []byte("def transform(b): return b")
+		Expect(bad.Validate()).To(HaveOccurred())
+		bad = msg
+		bad.Deps = []byte("numpy")
+		Expect(bad.Validate()).To(HaveOccurred())
+		bad = msg
+		bad.CommTypeX = StreamCommType
+		Expect(bad.Validate()).To(HaveOccurred())
+		bad = msg
+		bad.MemLimit = 8 * cos.GiB
+		Expect(bad.Validate()).To(HaveOccurred())
+
+		bad = InitCodeMsg{InitMsgBase: msg.InitMsgBase, Code: []byte("code"), Runtime: runtime.Python38, MaxInstances: 2}
+		Expect(bad.Validate()).To(HaveOccurred())
+		Expect(IsLocal(&bad)).To(BeFalse())
+	})
+
+	It("should transform objects concurrently", func() {
+		wc := newComm(&InitCodeMsg{Code: wasmInvert, Runtime: runtime.Wasm, MaxInstances: 4})
+		var wg sync.WaitGroup
+		for i := 0; i < numObjs; i++ {
+			wg.Add(1)
+			go func(objName string) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				r, err := wc.OfflineTransform(clusterBck, objName, time.Minute)
+				Expect(err).NotTo(HaveOccurred())
+				b, err := io.ReadAll(r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(r.Close()).NotTo(HaveOccurred())
+				Expect(bytes.Equal(b, invert(objData[objName]))).To(BeTrue())
+			}(fmt.Sprintf("obj-%d", i))
+		}
+		wg.Wait()
+		Expect(wc.OutBytes()).To(Equal(int64(numObjs * objSize)))
+		Eventually(wc.InBytes).Should(Equal(int64(numObjs * objSize)))
+	})
+
+	It("should fail when module exits with non-zero code", func() {
+		wc := newComm(&InitCodeMsg{Code: wasmFail, Runtime: runtime.Wasm})
+		r, err := wc.TransformStream(bytes.NewReader([]byte("abc")), 3, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = io.ReadAll(r)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("exited with code 3"))
+		r.Close()
+	})
+
+	It("should terminate module that exceeds exec timeout", func() {
+		wc := newComm(&InitCodeMsg{Code: wasmHang, Runtime: runtime.Wasm, ExecTimeout: cos.Duration(100 * time.Millisecond)})
+		r, err := wc.TransformStream(bytes.NewReader([]byte("abc")), 3, 0)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = io.ReadAll(r)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("timed out"))
+		r.Close()
+	})
+})
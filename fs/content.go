@@ -37,6 +37,9 @@ const (
 	WorkfileType = "wk"
 	ECSliceType  = "ec"
 	ECMetaType   = "mt"
+
+	DeletedObjType = "dl" // soft-deleted objects (see fs/deleted.go)
+	ArchIdxType    = "ai" // archived objects' (shards') member indexes (see cluster/archidx.go)
 )
 
 type (
@@ -167,6 +170,9 @@ type (
 	WorkfileContentResolver struct{}
 	ECSliceContentResolver  struct{}
 	ECMetaContentResolver   struct{}
+
+	DeletedObjContentResolver struct{}
+	ArchIdxContentResolver    struct{}
 )
 
 func (*ObjectContentResolver) PermToMove() bool                   { return true }
@@ -235,3 +241,24 @@ func (*ECMetaContentResolver) GenUniqueFQN(base, _ string) string { return base
 func (*ECMetaContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
 	return base, false, true
 }
+
+func (*DeletedObjContentResolver) PermToMove() bool    { return false }
+func (*DeletedObjContentResolver) PermToEvict() bool   { return false }
+func (*DeletedObjContentResolver) PermToProcess() bool { return false }
+
+func (*DeletedObjContentResolver) GenUniqueFQN(base, _ string) string { return base }
+
+func (*DeletedObjContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
+	return base, false, true
+}
+
+// (archive indexes are never moved - a new owner rebuilds the index upon first access)
+func (*ArchIdxContentResolver) PermToMove() bool    { return false }
+func (*ArchIdxContentResolver) PermToEvict() bool   { return true }
+func (*ArchIdxContentResolver) PermToProcess() bool { return false }
+
+func (*ArchIdxContentResolver) GenUniqueFQN(base, _ string) string { return base }
+
+func (*ArchIdxContentResolver) ParseUniqueFQN(base string) (orig string, old, ok bool) {
+	return base, false, true
+}
@@ -9,14 +9,22 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/cmn/mono"
 )
 
-// TODO: undelete (feature)
+// There are two kinds of 'deleted' content:
+// 1. destroyed (or renamed) buckets and other directories that get removed
+//    in the background - see `MoveToDeleted` and `RemoveDeleted` below;
+// 2. soft-deleted objects of the buckets with `cmn.SoftDelConf` enabled;
+//    those are stored as `DeletedObjType` content in the same bucket and on the
+//    same mountpath, can be listed and undeleted until the retention period
+//    expires, and get removed by the storage cleanup after that.
 
 const deletedRoot = ".$deleted"
 
@@ -102,3 +110,31 @@ rm:
 	}
 	return err
 }
+
+//
+// soft-deleted objects
+//
+
+// SoftDeleteObj moves object's file (`fqn`) to the `DeletedObjType` content of its bucket;
+// the time of deletion is recorded as the file's mtime.
+// NOTE: a subsequent deletion of the same-name object replaces the previously deleted one.
+func (mi *MountpathInfo) SoftDeleteObj(bck *cmn.Bck, objName, fqn string) error {
+	dfqn := mi.MakePathFQN(bck, DeletedObjType, objName)
+	if err := cos.Rename(fqn, dfqn); err != nil {
+		return err
+	}
+	now := time.Now()
+	return os.Chtimes(dfqn, now, now)
+}
+
+// FindDeletedObj returns the location of the soft-deleted object, if any.
+func FindDeletedObj(bck *cmn.Bck, objName string) (mi *MountpathInfo, dfqn string) {
+	availablePaths := GetAvail()
+	for _, mi := range availablePaths {
+		dfqn = mi.MakePathFQN(bck, DeletedObjType, objName)
+		if err := cos.Stat(dfqn); err == nil {
+			return mi, dfqn
+		}
+	}
+	return nil, ""
+}
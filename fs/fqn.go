@@ -29,6 +29,8 @@ const (
 	WorkfileAppend       = "append"         // APPEND to object (as file)
 	WorkfileAppendToArch = "append-to-arch" // APPEND to existing archive
 	WorkfileCreateArch   = "create-arch"    // CREATE multi-object archive
+	WorkfileMptPart      = "mpt-part"       // S3 multipart upload: uploaded part
+	WorkfileDlManifest   = "dl-manifest"    // downloader: local copy of the manifest owned by another target
 )
 
 type ParsedFQN struct {
@@ -271,6 +271,32 @@ func TestMoveToDeleted(t *testing.T) {
 	}
 }
 
+func TestSoftDeleteObj(t *testing.T) {
+	initFS()
+
+	var (
+		mi      = createMountpath(t)
+		bck     = cmn.Bck{Name: "bck", Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
+		objName = "dir/obj"
+		fqn     = mi.MakePathFQN(&bck, fs.ObjectType, objName)
+	)
+	f, err := cos.CreateFile(fqn)
+	tassert.CheckFatal(t, err)
+	f.Close()
+
+	dmi, dfqn := fs.FindDeletedObj(&bck, objName)
+	tassert.Fatalf(t, dmi == nil && dfqn == "", "unexpected deleted object %q", dfqn)
+
+	err = mi.SoftDeleteObj(&bck, objName, fqn)
+	tassert.CheckFatal(t, err)
+	tutils.CheckPathNotExists(t, fqn)
+
+	dmi, dfqn = fs.FindDeletedObj(&bck, objName)
+	tassert.Fatalf(t, dmi != nil && dmi.Path == mi.Path, "deleted object not found")
+	tassert.Errorf(t, dfqn == mi.MakePathFQN(&bck, fs.DeletedObjType, objName), "unexpected FQN %q", dfqn)
+	tutils.CheckPathExists(t, dfqn, false /*dir*/)
+}
+
 func TestMoveMarkers(t *testing.T) {
 	tests := []struct {
 		name string
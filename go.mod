@@ -25,6 +25,7 @@ require (
 	github.com/prometheus/client_golang v1.12.1
 	github.com/seiflotfy/cuckoofilter v0.0.0-20220312154859-af7fbb8e765b
 	github.com/teris-io/shortid v0.0.0-20201117134242-e59966efd125
+	github.com/tetratelabs/wazero v1.2.1
 	github.com/tidwall/buntdb v1.2.7
 	github.com/tinylib/msgp v1.1.6
 	github.com/urfave/cli v1.22.5
@@ -14,4 +14,6 @@ func Init() {
 	xreg.RegBckXact(&tcbFactory{kind: apc.ActETLBck})
 	xreg.RegBckXact(&mncFactory{})
 	xreg.RegBckXact(&putFactory{})
+	xreg.RegBckXact(&replFactory{})
+	replInit()
 }
@@ -115,7 +115,7 @@ func (r *xactMNC) visitObj(lom *cluster.LOM, buf []byte) (err error) {
 	if n := lom.NumCopies(); n == r.copies {
 		return nil
 	} else if n > r.copies {
-		size, err = delCopies(lom, r.copies)
+		size, err = DelCopies(lom, r.copies)
 	} else {
 		size, err = addCopies(lom, r.copies, buf)
 	}
@@ -0,0 +1,331 @@
+// Package mirror provides local mirroring and replica management
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package mirror
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/dbdriver"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Asynchronous bucket-to-bucket replication (see cmn.ReplicationConf).
+//
+// Writes and deletes are recorded in the target's local database - the durable
+// per-target replication queue - under the keys "<bucket-uname><sequence>", where
+// the (hex-encoded, fixed-width) sequence numbers preserve the order of operations.
+// The queue is drained, in order, by the bucket's on-demand replication xaction:
+// a failed operation stays at the head of the queue and is retried later. Hence,
+// operations survive restarts, and the queue size is the replication backlog
+// (see stats.ReplBacklog).
+//
+// The xaction terminates when idle and is (re)started by the producers (see ReplEnqueue)
+// and, periodically, by the target for any bucket with a non-empty queue.
+
+const ReplCollection = "replication"
+
+// replication ops
+const (
+	ReplPut = "put"
+	ReplDel = "delete"
+)
+
+const replRetryInterval = 10 * time.Second
+
+type (
+	ReplOp struct {
+		Op      string `json:"op"`   // ReplPut | ReplDel
+		ObjName string `json:"name"` // object name
+		Time    int64  `json:"time"` // when enqueued (Unix time, nanoseconds)
+	}
+	replFactory struct {
+		xreg.RenewBase
+		xctn *XactRepl
+	}
+	XactRepl struct {
+		xact.DemandBase
+		t      cluster.Target
+		statsT cos.StatsTracker
+		kickCh chan struct{}
+	}
+)
+
+// interface guard
+var (
+	_ cluster.Xact   = (*XactRepl)(nil)
+	_ xreg.Renewable = (*replFactory)(nil)
+)
+
+// initialized with the current time to keep the sequence monotonic across restarts
+var replSeq atomic.Int64
+
+func replInit() { replSeq.Store(time.Now().UnixNano()) }
+
+// ReplEnqueue adds replication op to the durable queue of a given bucket.
+func ReplEnqueue(db dbdriver.Driver, bck *cmn.Bck, op string, objName string) error {
+	key := bck.MakeUname(fmt.Sprintf("%016x", replSeq.Inc()))
+	return db.Set(ReplCollection, key, &ReplOp{Op: op, ObjName: objName, Time: time.Now().UnixNano()})
+}
+
+// ReplQueued returns the queued (not yet replicated) ops, in order, of all buckets or (non-nil bck)
+// of a given bucket; use cmn.ParseUname to extract the bucket from a key.
+func ReplQueued(db dbdriver.Driver, bck *cmn.Bck) (keys []string, err error) {
+	var prefix string
+	if bck != nil {
+		prefix = bck.MakeUname("")
+	}
+	keys, err = db.List(ReplCollection, prefix)
+	if dbdriver.IsErrNotFound(err) {
+		err = nil
+	}
+	return
+}
+
+/////////////////
+// replFactory //
+/////////////////
+
+func (*replFactory) New(args xreg.Args, bck *cluster.Bck) xreg.Renewable {
+	return &replFactory{RenewBase: xreg.RenewBase{Args: args, Bck: bck}}
+}
+
+func (p *replFactory) Start() error {
+	statsT, ok := p.Args.Custom.(cos.StatsTracker)
+	debug.Assert(ok)
+	r := &XactRepl{t: p.T, statsT: statsT, kickCh: make(chan struct{}, 1)}
+	r.DemandBase.Init(cos.GenUUID(), apc.ActReplicate, p.Bck, 0 /*use default*/)
+	p.xctn = r
+	go r.Run(nil)
+	return nil
+}
+
+func (*replFactory) Kind() string        { return apc.ActReplicate }
+func (p *replFactory) Get() cluster.Xact { return p.xctn }
+
+func (p *replFactory) WhenPrevIsRunning(xprev xreg.Renewable) (xreg.WPR, error) {
+	debug.Assertf(false, "%s vs %s", p.Str(p.Kind()), xprev) // xreg.usePrev() must've returned true
+	return xreg.WprUse, nil
+}
+
+//////////////
+// XactRepl //
+//////////////
+
+// Kick wakes up the xaction to drain the queue.
+func (r *XactRepl) Kick() {
+	r.IncPending() // (via base) to postpone self-termination
+	select {
+	case r.kickCh <- struct{}{}:
+	default:
+	}
+	r.DecPending()
+}
+
+func (r *XactRepl) Run(*sync.WaitGroup) {
+	var (
+		retry    = time.NewTimer(replRetryInterval)
+		retrying bool
+	)
+	glog.Infoln(r.Name())
+	retry.Stop()
+	for {
+		select {
+		case <-r.kickCh:
+			if retrying {
+				continue // wait for the retry timer
+			}
+		case <-retry.C:
+			retrying = false
+		case <-r.IdleTimer():
+			retry.Stop()
+			r.DemandBase.Stop()
+			r.Finish(nil)
+			return
+		case errCause := <-r.ChanAbort():
+			retry.Stop()
+			r.DemandBase.Stop()
+			r.Finish(cmn.NewErrAborted(r.Name(), "", errCause))
+			return
+		}
+		if err := r.drain(); err != nil {
+			if !cmn.IsErrAborted(err) {
+				glog.Errorf("%s: %v - will retry in %v", r, err, replRetryInterval)
+			}
+			retrying = true
+			retry.Reset(replRetryInterval)
+		}
+	}
+}
+
+func (r *XactRepl) Stats() cluster.XactSnap { return r.DemandBase.ExtSnap() }
+
+// replicate queued ops in order, one at a time; stop at the first failure
+func (r *XactRepl) drain() error {
+	var (
+		db      = r.t.DB()
+		bowner  = r.t.Bowner()
+		bck     = cluster.CloneBck(r.Bck().Bucket())
+		dst     *cluster.Bck
+		keys    []string
+		size    int64
+		err     error
+		verbose = bool(glog.FastV(4, glog.SmoduleXs))
+	)
+	if err = bck.Init(bowner); err != nil || !bck.Props.Replication.Enabled {
+		return nil // the target discards the queue (see ais/tgtrepl.go)
+	}
+	dstBck, err := bck.Props.Replication.DstBck()
+	if err != nil {
+		return err
+	}
+	dst = cluster.CloneBck(&dstBck)
+	if err = dst.Init(bowner); err != nil {
+		return err
+	}
+	if keys, err = ReplQueued(db, bck.Bucket()); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		select {
+		case errCause := <-r.ChanAbort():
+			return cmn.NewErrAborted(r.Name(), "", errCause)
+		default:
+		}
+		op := &ReplOp{}
+		if err = db.Get(ReplCollection, key, op); err != nil {
+			if dbdriver.IsErrNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if size, err = r.do(bck, dst, op); err != nil {
+			r.statsT.Add(stats.ReplErrCount, 1)
+			return fmt.Errorf("failed to replicate %s %s/%s => %s: %v", op.Op, bck, op.ObjName, dst, err)
+		}
+		if err = db.Delete(ReplCollection, key); err != nil && !dbdriver.IsErrNotFound(err) {
+			return err
+		}
+		r.statsT.AddMany(
+			cos.NamedVal64{Name: stats.ReplCount, Value: 1},
+			cos.NamedVal64{Name: stats.ReplSize, Value: size},
+			cos.NamedVal64{Name: stats.ReplLatency, Value: time.Now().UnixNano() - op.Time},
+			cos.NamedVal64{Name: stats.ReplBacklog, Value: -1},
+		)
+		r.ObjsAdd(1, size)
+		if verbose {
+			glog.Infof("%s: %s %s/%s => %s", r, op.Op, bck, op.ObjName, dst)
+		}
+	}
+	return nil
+}
+
+func (r *XactRepl) do(bck, dstBck *cluster.Bck, op *ReplOp) (size int64, err error) {
+	dst := cluster.AllocLOM(op.ObjName)
+	defer cluster.FreeLOM(dst)
+	if err = dst.InitBck(dstBck.Bucket()); err != nil {
+		return
+	}
+	backend := r.t.Backend(dstBck)
+	switch op.Op {
+	case ReplPut:
+		var (
+			reader cos.ReadOpenCloser
+			locked bool
+			lom    = cluster.AllocLOM(op.ObjName)
+		)
+		defer cluster.FreeLOM(lom)
+		if err = lom.InitBck(bck.Bucket()); err != nil {
+			return
+		}
+		reader, locked, err = r.open(lom)
+		if locked {
+			defer lom.Unlock(false) // (keep reading the local replica until sent)
+		}
+		if err != nil || reader == nil {
+			return // (nil reader: the object has been deleted since)
+		}
+		size = lom.SizeBytes(true)
+		dst.SetSize(size)
+		dst.SetCksum(lom.Checksum())
+		_, err = backend.PutObj(reader, dst)
+	case ReplDel:
+		var errCode int
+		if errCode, err = backend.DeleteObj(dst); errCode == http.StatusNotFound || cmn.IsObjNotExist(err) {
+			err = nil
+		}
+	default:
+		debug.AssertMsg(false, op.Op)
+	}
+	return
+}
+
+// open the source object (and load its metadata): locally or, if need be, from its
+// current owner (e.g., renamed, or relocated by rebalance since the op was queued)
+// NOTE: when opened locally, the object remains rlocked (`locked`) - the caller unlocks
+// upon sending it
+func (r *XactRepl) open(lom *cluster.LOM) (_ cos.ReadOpenCloser, locked bool, err error) {
+	lom.Lock(false)
+	if err = lom.Load(false /*cache it*/, true /*locked*/); err == nil {
+		fh, err := cos.NewFileHandle(lom.FQN)
+		if err != nil {
+			lom.Unlock(false)
+			return nil, false, err
+		}
+		return fh, true, nil
+	}
+	lom.Unlock(false)
+	if !cmn.IsObjNotExist(err) {
+		return nil, false, err
+	}
+
+	tsi, err := cluster.HrwTarget(lom.Uname(), r.t.Sowner().Get())
+	if err != nil || tsi.ID() == r.t.SID() {
+		return nil, false, err
+	}
+	query := lom.Bck().AddToQuery(nil)
+	query.Set(apc.QparamIsGFNRequest, "true")
+	reqArgs := cmn.HreqArgs{
+		Method: http.MethodGet,
+		Base:   tsi.URL(cmn.NetIntraData),
+		Path:   apc.URLPathObjects.Join(lom.Bck().Name, lom.ObjName),
+		Query:  query,
+		Header: http.Header{apc.HdrCallerID: []string{r.t.SID()}},
+	}
+	req, err := reqArgs.Req()
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := r.t.DataClient().Do(req) // nolint:bodyclose // closed by the backend's PutObj
+	if err != nil {
+		return nil, false, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		cos.DrainReader(resp.Body)
+		resp.Body.Close()
+		return nil, false, nil
+	default:
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, false, fmt.Errorf("%s: failed to read %s from %s: %s (%d)", r, lom, tsi, string(b), resp.StatusCode)
+	}
+	lom.SetCksum(lom.ObjAttrs().FromHeader(resp.Header))
+	lom.SetSize(resp.ContentLength)
+	return cos.NopOpener(resp.Body), false, nil
+}
@@ -34,6 +34,10 @@ type (
 		phase string // (see "transition")
 		args  *xreg.TCBArgs
 	}
+	// data provider that reports extended stats (e.g., etl.OfflineDataProvider)
+	dpStatsExt interface {
+		StatsExt() map[string]string
+	}
 	XactTCB struct {
 		xact.BckJog
 		t    cluster.Target
@@ -174,6 +178,16 @@ func newXactTCB(e *tcbFactory, slab *memsys.Slab) (r *XactTCB) {
 
 func (r *XactTCB) FromTo() (*cluster.Bck, *cluster.Bck) { return r.args.BckFrom, r.args.BckTo }
 
+// (offline ETL: includes per-stage counters)
+func (r *XactTCB) Snap() cluster.XactSnap {
+	snap := &xact.SnapExt{}
+	r.ToSnap(&snap.Snap)
+	if dp, ok := r.args.DP.(dpStatsExt); ok {
+		snap.Ext = dp.StatsExt()
+	}
+	return snap
+}
+
 func (r *XactTCB) WaitRunning() { r.wg.Wait() }
 
 func (r *XactTCB) Run(wg *sync.WaitGroup) {
@@ -12,7 +12,9 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 )
 
-func delCopies(lom *cluster.LOM, copies int) (size int64, err error) {
+// DelCopies reduces the number of the object's local copies down to the specified `copies`
+// and returns the total size of the removed replicas.
+func DelCopies(lom *cluster.LOM, copies int) (size int64, err error) {
 	lom.Lock(true)
 	defer lom.Unlock(true)
 
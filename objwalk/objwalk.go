@@ -49,7 +49,7 @@ func (w *Walk) DefaultLocalObjPage() (*cmn.BucketList, error) {
 	}
 
 	opts := &fs.WalkBckOpts{
-		WalkOpts: fs.WalkOpts{CTs: []string{fs.ObjectType}, Callback: cb, Sorted: true},
+		WalkOpts: fs.WalkOpts{CTs: []string{wi.ContentType()}, Callback: cb, Sorted: true},
 	}
 	opts.WalkOpts.Bck.Copy(w.bck.Bucket())
 	opts.ValidateCallback = func(fqn string, de fs.DirEntry) error {
@@ -91,6 +91,7 @@ func (w *Walk) RemoteObjPage() (*cmn.BucketList, error) {
 		needCksum       = w.msg.WantProp(apc.GetPropsChecksum)
 		needVersion     = w.msg.WantProp(apc.GetPropsVersion)
 		needCopies      = w.msg.WantProp(apc.GetPropsCopies)
+		needCustom      = w.msg.WantProp(apc.GetPropsCustom)
 	)
 	for _, e := range objList.Entries {
 		si, _ := cluster.HrwTarget(w.bck.MakeUname(e.Name), smap)
@@ -131,6 +132,9 @@ func (w *Walk) RemoteObjPage() (*cmn.BucketList, error) {
 		if needCopies {
 			e.Copies = int16(lom.NumCopies())
 		}
+		if needCustom {
+			e.SetCustom(lom.GetCustomMD())
+		}
 		if postCallback != nil {
 			postCallback(lom)
 		}
@@ -7,6 +7,7 @@ package walkinfo
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/fs"
 )
 
@@ -49,6 +51,7 @@ var wiProps = []string{
 	apc.GetPropsStatus,
 	apc.GetPropsCopies,
 	apc.GetTargetURL,
+	apc.GetPropsCustom,
 }
 
 func isObjMoved(status uint16) bool {
@@ -75,10 +78,20 @@ func NewWalkInfo(ctx context.Context, t cluster.Target, msg *apc.ListObjsMsg) *W
 	for _, prop := range wiProps {
 		propNeeded[prop] = msg.WantProp(prop)
 	}
+	// custom metadata predicate (validated by the proxy)
+	var objectFilter cluster.ObjectFilter
+	if msg.Filter != "" {
+		f, err := cmn.ParseObjFilter(msg.Filter)
+		debug.AssertNoErr(err)
+		if err == nil {
+			objectFilter = func(lom *cluster.LOM) bool { return f.Match(lom) }
+		}
+	}
 	return &WalkInfo{
 		t:            t, // targetrunner
 		smap:         t.Sowner().Get(),
 		postCallback: postCallback,
+		objectFilter: objectFilter,
 		prefix:       msg.Prefix,
 		Marker:       msg.ContinuationToken,
 		markerDir:    markerDir,
@@ -95,6 +108,15 @@ func (wi *WalkInfo) needVersion() bool   { return wi.propNeeded[apc.GetPropsVers
 func (wi *WalkInfo) needStatus() bool    { return wi.propNeeded[apc.GetPropsStatus] } //nolint:unused // left for consistency
 func (wi *WalkInfo) needCopies() bool    { return wi.propNeeded[apc.GetPropsCopies] }
 func (wi *WalkInfo) needTargetURL() bool { return wi.propNeeded[apc.GetTargetURL] }
+func (wi *WalkInfo) needCustom() bool    { return wi.propNeeded[apc.GetPropsCustom] }
+
+// ContentType to traverse: objects or soft-deleted objects (apc.LsDeleted)
+func (wi *WalkInfo) ContentType() string {
+	if wi.msg.IsFlagSet(apc.LsDeleted) {
+		return fs.DeletedObjType
+	}
+	return fs.ObjectType
+}
 
 // Checks if the directory should be processed by cache list call
 // Does checks:
@@ -179,6 +201,9 @@ func (wi *WalkInfo) lsObject(lom *cluster.LOM, objStatus uint16) *cmn.BucketEntr
 	if wi.needSize() {
 		fileInfo.Size = lom.SizeBytes()
 	}
+	if wi.needCustom() {
+		fileInfo.SetCustom(lom.GetCustomMD())
+	}
 	if wi.postCallback != nil {
 		wi.postCallback(lom)
 	}
@@ -193,6 +218,9 @@ func (wi *WalkInfo) Callback(fqn string, de fs.DirEntry) (entry *cmn.BucketEntry
 	if de.IsDir() {
 		return
 	}
+	if wi.msg.IsFlagSet(apc.LsDeleted) {
+		return wi.lsDeleted(fqn)
+	}
 	lom := cluster.AllocLOM("")
 	entry, err = wi.cb(lom, fqn)
 	cluster.FreeLOM(lom)
@@ -233,3 +261,40 @@ func (wi *WalkInfo) cb(lom *cluster.LOM, fqn string) (*cmn.BucketEntry, error) {
 	}
 	return wi.lsObject(lom, objStatus), nil
 }
+
+// Soft-deleted object: name, size, and the time of deletion reported as atime
+// (no metadata loading)
+func (wi *WalkInfo) lsDeleted(fqn string) (*cmn.BucketEntry, error) {
+	parsedFQN, err := fs.ParseFQN(fqn)
+	if err != nil {
+		return nil, nil
+	}
+	objName := parsedFQN.ObjName
+	if !cmn.ObjNameContainsPrefix(objName, wi.prefix) {
+		return nil, nil
+	}
+	if wi.Marker != "" && cmn.TokenIncludesObject(wi.Marker, objName) {
+		return nil, nil
+	}
+	fileInfo := &cmn.BucketEntry{Name: objName, Flags: apc.ObjStatusDeleted}
+	if wi.msg.IsFlagSet(apc.LsNameOnly) {
+		return fileInfo, nil
+	}
+	finfo, err := os.Stat(fqn)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if wi.needSize() {
+		fileInfo.Size = finfo.Size()
+	}
+	if wi.needAtime() {
+		fileInfo.Atime = cos.FormatUnixNano(finfo.ModTime().UnixNano(), wi.timeFormat)
+	}
+	if wi.needTargetURL() {
+		fileInfo.TargetURL = wi.t.Snode().URL(cmn.NetPublic)
+	}
+	return fileInfo, nil
+}
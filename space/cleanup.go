@@ -309,7 +309,7 @@ func (j *clnJ) jogBck() (size int64, err error) {
 	opts := &fs.WalkOpts{
 		Mi:       j.mi,
 		Bck:      j.bck,
-		CTs:      []string{fs.WorkfileType, fs.ObjectType, fs.ECSliceType, fs.ECMetaType},
+		CTs:      []string{fs.WorkfileType, fs.ObjectType, fs.ECSliceType, fs.ECMetaType, fs.DeletedObjType, fs.ArchIdxType},
 		Callback: j.walk,
 		Sorted:   false,
 	}
@@ -372,6 +372,29 @@ func (j *clnJ) visitCT(parsedFQN fs.ParsedFQN, fqn string) {
 			return
 		}
 		j.oldWork = append(j.oldWork, fqn)
+	case fs.DeletedObjType:
+		// soft-deleted objects (see cmn.SoftDelConf):
+		// - soft delete enabled: remove upon expiration of the retention period
+		// - soft delete disabled: remove all
+		b := cluster.CloneBck(&parsedFQN.Bck)
+		if err := b.Init(j.ini.T.Bowner()); err != nil || !b.Props.SoftDelete.Enabled {
+			j.oldWork = append(j.oldWork, fqn)
+			return
+		}
+		finfo, err := os.Stat(fqn)
+		if err != nil {
+			return
+		}
+		if finfo.ModTime().UnixNano()+int64(b.Props.SoftDelete.RetentionTime()) < j.now {
+			j.oldWork = append(j.oldWork, fqn)
+		}
+	case fs.ArchIdxType:
+		// archive indexes: remove those without the corresponding (indexed) object
+		// (e.g., objects relocated by rebalance or removed out of band)
+		objFQN := j.mi.MakePathFQN(&parsedFQN.Bck, fs.ObjectType, parsedFQN.ObjName)
+		if err := cos.Stat(objFQN); os.IsNotExist(err) {
+			j.oldWork = append(j.oldWork, fqn)
+		}
 	default:
 		debug.Assertf(false, "Unsupported content type: %s", parsedFQN.ContentType)
 	}
@@ -0,0 +1,351 @@
+// Package space provides storage cleanup and eviction functionality (the latter based on the
+// least recently used cache replacement). It also serves as a built-in garbage-collection
+// mechanism for orphaned workfiles.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package space
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api/apc"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/mirror"
+	"github.com/NVIDIA/aistore/xact"
+	"github.com/NVIDIA/aistore/xact/xreg"
+)
+
+// Lifecycle xaction executes per-bucket lifecycle rules (see cmn.LifecycleConf).
+//
+// The xaction runs periodically on every target (see ais/tgtspace.go) and can be
+// started via api.StartXaction. It runs a single jogger per mountpath, whereby each jogger
+// walks the buckets with lifecycle enabled and applies the rules to the (main replicas of)
+// the objects.
+//
+// In dry-run mode, the objects that would be affected are only counted (and logged, if verbose).
+
+type (
+	IniLcycle struct {
+		T       cluster.Target
+		Xaction *XactLcycle
+		Buckets []cmn.Bck // optional list of buckets (default: all buckets with lifecycle enabled)
+		WG      *sync.WaitGroup
+	}
+	XactLcycle struct {
+		xact.Base
+		counts struct {
+			deleted atomic.Int64
+			evicted atomic.Int64
+			reduced atomic.Int64
+		}
+		dryRun bool
+	}
+	// lifecycle xaction's extended stats (see xact.SnapExt)
+	LcycleStatsExt struct {
+		Deleted int64 `json:"deleted,string"`        // number of deleted objects
+		Evicted int64 `json:"evicted,string"`        // number of evicted objects
+		Reduced int64 `json:"copies_reduced,string"` // number of objects with reduced number of copies
+		DryRun  bool  `json:"dry_run"`
+	}
+)
+
+// private
+type (
+	// lcycleJ is a single /jogger/ that traverses a single given mountpath
+	lcycleJ struct {
+		ini  *IniLcycle
+		mi   *fs.MountpathInfo
+		bcks []*cluster.Bck
+		bck  *cluster.Bck
+		now  time.Time
+	}
+	lcycleFactory struct {
+		xreg.RenewBase
+		xctn *XactLcycle
+	}
+)
+
+// interface guard
+var (
+	_ xreg.Renewable = (*lcycleFactory)(nil)
+	_ cluster.Xact   = (*XactLcycle)(nil)
+)
+
+///////////////////
+// lcycleFactory //
+///////////////////
+
+func (*lcycleFactory) New(args xreg.Args, _ *cluster.Bck) xreg.Renewable {
+	return &lcycleFactory{RenewBase: xreg.RenewBase{Args: args}}
+}
+
+func (p *lcycleFactory) Start() error {
+	p.xctn = &XactLcycle{}
+	p.xctn.dryRun, _ = p.Args.Custom.(bool)
+	p.xctn.InitBase(p.UUID(), apc.ActLifecycle, nil)
+	return nil
+}
+
+func (*lcycleFactory) Kind() string        { return apc.ActLifecycle }
+func (p *lcycleFactory) Get() cluster.Xact { return p.xctn }
+
+func (*lcycleFactory) WhenPrevIsRunning(prevEntry xreg.Renewable) (wpr xreg.WPR, err error) {
+	return xreg.WprUse, cmn.NewErrUsePrevXaction(prevEntry.Get().String())
+}
+
+////////////////
+// XactLcycle //
+////////////////
+
+func (*XactLcycle) Run(*sync.WaitGroup) { debug.Assert(false) }
+
+func (r *XactLcycle) Snap() cluster.XactSnap {
+	snap := &xact.SnapExt{}
+	r.ToSnap(&snap.Snap)
+	snap.Ext = r.ext()
+	return snap
+}
+
+func (r *XactLcycle) ext() *LcycleStatsExt {
+	return &LcycleStatsExt{
+		Deleted: r.counts.deleted.Load(),
+		Evicted: r.counts.evicted.Load(),
+		Reduced: r.counts.reduced.Load(),
+		DryRun:  r.dryRun,
+	}
+}
+
+func (r *XactLcycle) add(action string, size int64) {
+	switch action {
+	case cmn.LcycleDelete:
+		r.counts.deleted.Inc()
+	case cmn.LcycleEvict:
+		r.counts.evicted.Inc()
+	default:
+		r.counts.reduced.Inc()
+	}
+	r.ObjsAdd(1, size)
+}
+
+func (ext *LcycleStatsExt) String() string {
+	s := fmt.Sprintf("deleted %d, evicted %d, copies-reduced %d", ext.Deleted, ext.Evicted, ext.Reduced)
+	if ext.DryRun {
+		s = "dry-run: " + s
+	}
+	return s
+}
+
+func RunLifecycle(ini *IniLcycle) {
+	var (
+		wg             sync.WaitGroup
+		xlc            = ini.Xaction
+		availablePaths = fs.GetAvail()
+	)
+	defer func() {
+		if ini.WG != nil {
+			ini.WG.Done()
+		}
+	}()
+	if len(availablePaths) == 0 {
+		glog.Warning(cmn.ErrNoMountpaths)
+		xlc.Finish(cmn.ErrNoMountpaths)
+		return
+	}
+	bcks := lcycleBcks(ini)
+	if len(bcks) == 0 {
+		if verbose {
+			glog.Infof("%s: no buckets with lifecycle enabled, nothing to do", xlc)
+		}
+		xlc.Finish(nil)
+		return
+	}
+	glog.Infof("%s started: %d bucket%s, dry-run %t", xlc, len(bcks), cos.Plural(len(bcks)), xlc.dryRun)
+	if ini.WG != nil {
+		ini.WG.Done()
+		ini.WG = nil
+	}
+	now := time.Now()
+	for _, mi := range availablePaths {
+		j := &lcycleJ{ini: ini, mi: mi, bcks: bcks, now: now}
+		wg.Add(1)
+		go j.run(&wg)
+	}
+	wg.Wait()
+	xlc.Finish(nil)
+	glog.Infof("%s finished: %s", xlc, xlc.ext())
+}
+
+// buckets to run lifecycle rules on
+func lcycleBcks(ini *IniLcycle) (bcks []*cluster.Bck) {
+	var (
+		xlc    = ini.Xaction
+		bowner = ini.T.Bowner()
+	)
+	if len(ini.Buckets) == 0 {
+		bowner.Get().Range(nil, nil, func(bck *cluster.Bck) bool {
+			if bck.Props.Lifecycle.Enabled && bck.Allow(apc.AceObjDELETE) == nil {
+				bcks = append(bcks, bck)
+			}
+			return false
+		})
+		return
+	}
+	for i := range ini.Buckets {
+		bck := cluster.CloneBck(&ini.Buckets[i])
+		if err := bck.Init(bowner); err != nil {
+			glog.Errorf("%s: %v - skipping %s", xlc, err, bck)
+			continue
+		}
+		if !bck.Props.Lifecycle.Enabled {
+			glog.Warningf("%s: %s does not have lifecycle enabled - skipping", xlc, bck)
+			continue
+		}
+		if err := bck.Allow(apc.AceObjDELETE); err != nil {
+			glog.Errorf("%s: %v - skipping %s", xlc, err, bck)
+			continue
+		}
+		bcks = append(bcks, bck)
+	}
+	return
+}
+
+//////////////////////
+// mountpath jogger //
+//////////////////////
+
+func (j *lcycleJ) String() string {
+	return fmt.Sprintf("%s: jog-%s", j.ini.Xaction, j.mi)
+}
+
+func (j *lcycleJ) run(wg *sync.WaitGroup) {
+	defer wg.Done()
+	for _, bck := range j.bcks {
+		j.bck = bck
+		opts := &fs.WalkOpts{
+			Mi:       j.mi,
+			Bck:      *bck.Bucket(),
+			CTs:      []string{fs.ObjectType},
+			Callback: j.walk,
+			Sorted:   false,
+		}
+		err := fs.Walk(opts)
+		if err == nil || cmn.IsErrBucketNought(err) || cmn.IsErrObjNought(err) {
+			continue
+		}
+		if cmn.IsErrAborted(err) {
+			return
+		}
+		glog.Errorf("%s: failed to traverse %s: %v", j, bck, err)
+	}
+}
+
+func (j *lcycleJ) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if err := j.yieldTerm(); err != nil {
+		return err
+	}
+	lom := cluster.AllocLOM("")
+	j.visit(lom, fqn)
+	cluster.FreeLOM(lom)
+	return nil
+}
+
+func (j *lcycleJ) visit(lom *cluster.LOM, fqn string) {
+	// the main replica only (copies and misplaced objects are skipped)
+	if err := lom.InitFQN(fqn, j.bck.Bucket()); err != nil || !lom.IsHRW() {
+		return
+	}
+	rule, size := j.eval(lom)
+	if rule == nil {
+		return
+	}
+	xlc := j.ini.Xaction
+	if xlc.dryRun {
+		if verbose {
+			glog.Infof("%s: %s (%s) - would %s", j, lom, cos.B2S(size, 1), rule)
+		}
+		xlc.add(rule.Action, size)
+		return
+	}
+	size, err := j.do(lom, rule)
+	if err != nil {
+		if !cmn.IsObjNotExist(err) {
+			glog.Errorf("%s: failed to %s %s: %v", j, rule.Action, lom, err)
+		}
+		return
+	}
+	if verbose {
+		glog.Infof("%s: %s - %s", j, lom, rule)
+	}
+	xlc.add(rule.Action, size)
+}
+
+// under rlock, load the object and select the rule that applies (nil if none)
+func (j *lcycleJ) eval(lom *cluster.LOM) (rule *cmn.LifecycleRule, size int64) {
+	lom.Lock(false)
+	defer lom.Unlock(false)
+	finfo, err := os.Stat(lom.FQN)
+	if err != nil {
+		return
+	}
+	rule = j.bck.Props.Lifecycle.Rule(lom.ObjName, j.now.Sub(finfo.ModTime()))
+	if rule == nil {
+		return
+	}
+	if err := lom.Load(false /*cache it*/, true /*locked*/); err != nil {
+		return nil, 0
+	}
+	size = lom.SizeBytes()
+	switch rule.Action {
+	case cmn.LcycleDelete, cmn.LcycleEvict:
+		if lom.CheckRetained(false /*bypass governance*/) != nil { // object lock
+			return nil, 0
+		}
+	case cmn.LcycleReduceCopies:
+		if lom.NumCopies() <= rule.Copies {
+			return nil, 0
+		}
+		size *= int64(lom.NumCopies() - rule.Copies)
+	}
+	return
+}
+
+func (j *lcycleJ) do(lom *cluster.LOM, rule *cmn.LifecycleRule) (size int64, err error) {
+	switch rule.Action {
+	case cmn.LcycleDelete, cmn.LcycleEvict:
+		size = lom.SizeBytes()
+		if _, err = j.ini.T.DeleteObject(lom, rule.Action == cmn.LcycleEvict); err == nil {
+			ec.ECM.CleanupObject(lom) // (no-op when EC is disabled)
+		}
+	default:
+		size, err = mirror.DelCopies(lom, rule.Copies)
+	}
+	return
+}
+
+func (j *lcycleJ) yieldTerm() error {
+	xlc := j.ini.Xaction
+	select {
+	case errCause := <-xlc.ChanAbort():
+		return cmn.NewErrAborted(xlc.Name(), "", errCause)
+	default:
+		break
+	}
+	if xlc.Finished() {
+		return cmn.NewErrAborted(xlc.Name(), "", nil)
+	}
+	return nil
+}
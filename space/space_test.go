@@ -20,6 +20,7 @@ import (
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/hk"
 	"github.com/NVIDIA/aistore/space"
+	"github.com/NVIDIA/aistore/xact"
 	"github.com/NVIDIA/aistore/xact/xreg"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -35,6 +36,7 @@ const (
 	basePath             = "/tmp/space-tests"
 	bucketName           = "space-bck"
 	bucketNameAnother    = bucketName + "-another"
+	bucketNameLcycle     = bucketName + "-lifecycle"
 )
 
 type fileMetadata struct {
@@ -46,6 +48,7 @@ var gT *testing.T
 
 func TestEvictCleanup(t *testing.T) {
 	xreg.Init()
+	space.Init()
 	hk.TestInit()
 	cos.InitShortID(0)
 
@@ -282,6 +285,39 @@ var _ = Describe("space evict/cleanup tests", func() {
 				Expect(len(files)).To(Equal(0))
 			})
 		})
+
+		Describe("lifecycle", func() {
+			It("should apply lifecycle rules to aged objects only", func() {
+				var (
+					availablePaths = fs.GetAvail()
+					bck            = cmn.Bck{Name: bucketNameLcycle, Provider: apc.ProviderAIS, Ns: cmn.NsGlobal}
+					fp             = availablePaths[basePath].MakePathCT(&bck, fs.ObjectType)
+					past           = time.Now().Add(-2 * time.Hour)
+				)
+				cos.CreateDir(fp)
+				for i, name := range []string{"tmp-0", "tmp-1", "tmp-2", "tmp-3", "data-0"} {
+					fqn := path.Join(fp, name)
+					saveRandomFile(fqn, cos.KiB)
+					if i%2 == 0 { // aged: tmp-0, tmp-2, and data-0
+						Expect(os.Chtimes(fqn, past, past)).NotTo(HaveOccurred())
+					}
+				}
+				for _, dryRun := range []bool{true, false} {
+					rns := xreg.RenewLifecycle(cos.GenUUID(), dryRun)
+					Expect(rns.Err).NotTo(HaveOccurred())
+					xlc := rns.Entry.Get().(*space.XactLcycle)
+
+					space.RunLifecycle(&space.IniLcycle{T: t, Xaction: xlc})
+
+					snap := xlc.Snap().(*xact.SnapExt)
+					ext := snap.Ext.(*space.LcycleStatsExt)
+					Expect(ext.DryRun).To(Equal(dryRun))
+					Expect(ext.Deleted).To(Equal(int64(2)))
+					Expect(snap.Stats.Objs).To(Equal(int64(2)))
+					Expect(snap.Stats.Bytes).To(Equal(int64(2 * cos.KiB)))
+				}
+			})
+		})
 	})
 })
 
@@ -334,6 +370,18 @@ func newTargetLRUMock() *mock.TargetMock {
 					BID:    0xf4e3d2c1,
 				},
 			),
+			cluster.NewBck(
+				bucketNameLcycle, apc.ProviderAIS, cmn.NsGlobal,
+				&cmn.BucketProps{
+					Cksum: cmn.CksumConf{Type: cos.ChecksumNone},
+					Lifecycle: cmn.LifecycleConf{
+						Enabled: true,
+						Rules:   []cmn.LifecycleRule{{Prefix: "tmp-", Action: cmn.LcycleDelete, Age: cos.Duration(time.Hour)}},
+					},
+					Access: apc.AccessAll,
+					BID:    0xe1d2c3b4,
+				},
+			),
 		)
 		tMock = mock.NewTarget(bmdMock)
 	)
@@ -0,0 +1,294 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
+	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Per-bucket (usage) statistics: optional (see `cmn.BckStatsConf`) target-only
+// counters and latencies that are tracked separately for each bucket.
+// Unlike the node's own stats, per-bucket values get updated directly (via
+// atomics) rather than through the runner's work channel. To keep the number
+// of tracked buckets (and, therefore, the number of Prometheus time series)
+// bounded, buckets in excess of the configured maximum are all accounted as
+// a single `BckStatsOther` bucket.
+
+// per-bucket stats names (in addition to GetCount, GetLatency, PutCount, PutLatency,
+// DeleteCount, and ErrCount)
+const (
+	GetSize = "get.size"
+	PutSize = "put.size"
+)
+
+// the name under which stats of the buckets in excess of the cardinality cap are reported
+const BckStatsOther = "_other"
+
+type (
+	// REST API: cumulative counters and average latencies (during the last stats interval)
+	// NOTE: latency sums and numbers of samples are included to average across targets (see Merge)
+	BckStats struct {
+		GetCount      int64 `json:"get.n,string"`
+		GetSize       int64 `json:"get.size,string"`
+		GetLatency    int64 `json:"get.ns,string"`
+		GetLatencySum int64 `json:"get.ns.sum,string"`
+		GetLatencyCnt int64 `json:"get.ns.n,string"`
+		PutCount      int64 `json:"put.n,string"`
+		PutSize       int64 `json:"put.size,string"`
+		PutLatency    int64 `json:"put.ns,string"`
+		PutLatencySum int64 `json:"put.ns.sum,string"`
+		PutLatencyCnt int64 `json:"put.ns.n,string"`
+		DeleteCount   int64 `json:"del.n,string"`
+		ErrCount      int64 `json:"err.n,string"`
+	}
+	BckStatsMap map[string]*BckStats // by bucket name (as in `cmn.Bck.String()`) or `BckStatsOther`
+
+	bckStatsValue struct {
+		bck cmn.Bck // NOTE: zero value for `BckStatsOther`
+		// counters
+		getCount, getSize  atomic.Int64
+		putCount, putSize  atomic.Int64
+		delCount, errCount atomic.Int64
+		// latencies: cumulative during the current stats interval...
+		getLat, getLatN atomic.Int64
+		putLat, putLatN atomic.Int64
+		// ... and the same during the previous one
+		getLatPrev, getLatPrevN atomic.Int64
+		putLatPrev, putLatPrevN atomic.Int64
+	}
+	bckTracker struct {
+		mtx   sync.RWMutex
+		m     map[string]*bckStatsValue // by bucket uname
+		other *bckStatsValue
+		desc  promDesc // Prometheus descriptors (by stats name)
+	}
+)
+
+// stats name => Prometheus kind
+var bckPromNames = map[string]prometheus.ValueType{
+	GetCount:    prometheus.CounterValue,
+	GetSize:     prometheus.CounterValue,
+	GetLatency:  prometheus.GaugeValue,
+	PutCount:    prometheus.CounterValue,
+	PutSize:     prometheus.CounterValue,
+	PutLatency:  prometheus.GaugeValue,
+	DeleteCount: prometheus.CounterValue,
+	ErrCount:    prometheus.CounterValue,
+}
+
+var bckPromLabels = []string{"bucket", "provider", "namespace"}
+
+func newBckTracker() *bckTracker {
+	return &bckTracker{m: make(map[string]*bckStatsValue, 16), other: &bckStatsValue{}}
+}
+
+func (bt *bckTracker) get(bck *cmn.Bck, maxBcks int) (v *bckStatsValue) {
+	uname := bck.MakeUname("")
+	bt.mtx.RLock()
+	v, ok := bt.m[uname]
+	bt.mtx.RUnlock()
+	if ok {
+		return
+	}
+	bt.mtx.Lock()
+	if v, ok = bt.m[uname]; !ok {
+		if len(bt.m) >= maxBcks {
+			v = bt.other
+		} else {
+			v = &bckStatsValue{bck: cmn.Bck{Name: bck.Name, Provider: bck.Provider, Ns: bck.Ns}}
+			bt.m[uname] = v
+		}
+	}
+	bt.mtx.Unlock()
+	return
+}
+
+// called every stats interval to compute average latencies and forget the buckets
+// that no longer exist (or everything, when per-bucket stats get disabled)
+func (bt *bckTracker) housekeep(config *cmn.Config, bmd *cluster.BMD) {
+	bt.mtx.Lock()
+	defer bt.mtx.Unlock()
+	if !config.BckStats.Enabled {
+		if len(bt.m) > 0 {
+			bt.m = make(map[string]*bckStatsValue, 16)
+			bt.other = &bckStatsValue{}
+		}
+		return
+	}
+	for uname, v := range bt.m {
+		if bmd != nil {
+			if _, present := bmd.Get(cluster.CloneBck(&v.bck)); !present {
+				delete(bt.m, uname)
+				continue
+			}
+		}
+		v.updateLatencies()
+	}
+	bt.other.updateLatencies()
+}
+
+func (bt *bckTracker) snap() BckStatsMap {
+	bt.mtx.RLock()
+	out := make(BckStatsMap, len(bt.m)+1)
+	for _, v := range bt.m {
+		out[v.bck.String()] = v.snap()
+	}
+	if s := bt.other.snap(); *s != (BckStats{}) {
+		out[BckStatsOther] = s
+	}
+	bt.mtx.RUnlock()
+	return out
+}
+
+func (bt *bckTracker) initProm(node *cluster.Snode) {
+	id := strings.ReplaceAll(node.ID(), ".", "_")
+	bt.desc = make(promDesc, len(bckPromNames))
+	for name := range bckPromNames {
+		var (
+			label = strings.ReplaceAll(name, ".", "_")
+			help  = "total number of operations"
+		)
+		switch {
+		case strings.HasSuffix(label, "_size"):
+			help = "total size (MB)"
+		case strings.HasSuffix(label, "_ns"):
+			label = strings.TrimSuffix(label, "_ns") + "_ms"
+			help = "latency (milliseconds)"
+		}
+		fullqn := prometheus.BuildFQName("ais", node.Type(), id+"_bucket_"+label)
+		bt.desc[name] = prometheus.NewDesc(fullqn, "per-bucket "+help, bckPromLabels, nil /*constLabels*/)
+	}
+}
+
+func (bt *bckTracker) describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range bt.desc {
+		ch <- desc
+	}
+}
+
+func (bt *bckTracker) collect(ch chan<- prometheus.Metric) {
+	if len(bt.desc) == 0 {
+		return
+	}
+	bt.mtx.RLock()
+	for _, v := range bt.m {
+		v.collect(ch, bt.desc, v.bck.Name, v.bck.Provider, v.bck.Ns.String())
+	}
+	if s := bt.other.snap(); *s != (BckStats{}) {
+		bt.other.collect(ch, bt.desc, BckStatsOther, "", "")
+	}
+	bt.mtx.RUnlock()
+}
+
+///////////////////
+// bckStatsValue //
+///////////////////
+
+func (v *bckStatsValue) add(name string, val int64) {
+	switch name {
+	case GetCount:
+		v.getCount.Add(val)
+	case GetSize:
+		v.getSize.Add(val)
+	case GetLatency:
+		v.getLat.Add(val)
+		v.getLatN.Inc()
+	case PutCount:
+		v.putCount.Add(val)
+	case PutSize:
+		v.putSize.Add(val)
+	case PutLatency:
+		v.putLat.Add(val)
+		v.putLatN.Inc()
+	case DeleteCount:
+		v.delCount.Add(val)
+	case ErrCount:
+		v.errCount.Add(val)
+	default:
+		debug.AssertMsg(false, "invalid per-bucket stats name: "+name)
+	}
+}
+
+func (v *bckStatsValue) updateLatencies() {
+	v.getLatPrevN.Store(v.getLatN.Swap(0))
+	v.getLatPrev.Store(v.getLat.Swap(0))
+	v.putLatPrevN.Store(v.putLatN.Swap(0))
+	v.putLatPrev.Store(v.putLat.Swap(0))
+}
+
+func (v *bckStatsValue) snap() *BckStats {
+	s := &BckStats{
+		GetCount:      v.getCount.Load(),
+		GetSize:       v.getSize.Load(),
+		GetLatencySum: v.getLatPrev.Load(),
+		GetLatencyCnt: v.getLatPrevN.Load(),
+		PutCount:      v.putCount.Load(),
+		PutSize:       v.putSize.Load(),
+		PutLatencySum: v.putLatPrev.Load(),
+		PutLatencyCnt: v.putLatPrevN.Load(),
+		DeleteCount:   v.delCount.Load(),
+		ErrCount:      v.errCount.Load(),
+	}
+	s.avgLatencies()
+	return s
+}
+
+func (v *bckStatsValue) collect(ch chan<- prometheus.Metric, desc promDesc, labels ...string) {
+	s := v.snap()
+	for name, val := range map[string]float64{
+		GetCount:    float64(s.GetCount),
+		GetSize:     roundMBs(s.GetSize),
+		GetLatency:  float64(cos.DivRound(s.GetLatency, int64(time.Millisecond))),
+		PutCount:    float64(s.PutCount),
+		PutSize:     roundMBs(s.PutSize),
+		PutLatency:  float64(cos.DivRound(s.PutLatency, int64(time.Millisecond))),
+		DeleteCount: float64(s.DeleteCount),
+		ErrCount:    float64(s.ErrCount),
+	} {
+		m, err := prometheus.NewConstMetric(desc[name], bckPromNames[name], val, labels...)
+		debug.AssertNoErr(err)
+		ch <- m
+	}
+}
+
+//////////////
+// BckStats //
+//////////////
+
+// Merge adds up counters, latency sums and numbers of samples, and then averages
+// latencies (e.g., to aggregate across targets)
+func (s *BckStats) Merge(other *BckStats) {
+	s.GetCount += other.GetCount
+	s.GetSize += other.GetSize
+	s.GetLatencySum += other.GetLatencySum
+	s.GetLatencyCnt += other.GetLatencyCnt
+	s.PutCount += other.PutCount
+	s.PutSize += other.PutSize
+	s.PutLatencySum += other.PutLatencySum
+	s.PutLatencyCnt += other.PutLatencyCnt
+	s.DeleteCount += other.DeleteCount
+	s.ErrCount += other.ErrCount
+	s.avgLatencies()
+}
+
+func (s *BckStats) avgLatencies() {
+	s.GetLatency, s.PutLatency = 0, 0
+	if s.GetLatencyCnt > 0 {
+		s.GetLatency = s.GetLatencySum / s.GetLatencyCnt
+	}
+	if s.PutLatencyCnt > 0 {
+		s.PutLatency = s.PutLatencySum / s.PutLatencyCnt
+	}
+}
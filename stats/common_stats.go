@@ -36,6 +36,9 @@ import (
 
 const dfltPeriodicFlushTime = 40 * time.Second // when config.Log.FlushTime == 0
 
+// Prometheus: descriptor key suffix for latency histograms (see regHist)
+const histSuffix = ".hist"
+
 // more periodic
 const (
 	logsMaxSizeCheckTime = 48 * time.Minute       // periodically check the logs for max accumulated size
@@ -141,8 +144,10 @@ type (
 		BuildTime   string         `json:"build_time"`  // YYYY-MM-DD HH:MM:SS-TZ
 	}
 	DaemonStats struct {
-		Tracker copyTracker `json:"tracker"`
-		MPCap   fs.MPCap    `json:"capacity"`
+		Tracker   copyTracker  `json:"tracker"`
+		MPCap     fs.MPCap     `json:"capacity"`
+		Buckets   BckStatsMap  `json:"buckets,omitempty"`
+		Latencies LatencyHists `json:"latencies,omitempty"`
 	}
 	ClusterStats struct {
 		Proxy  *DaemonStats            `json:"proxy"`
@@ -185,6 +190,7 @@ type (
 		ticker      *time.Ticker
 		Core        *CoreStats  `json:"core"`
 		ctracker    copyTracker // to avoid making it at runtime
+		bcks        *bckTracker // per-bucket stats (target only)
 		daemon      runnerHost
 		nextLogTime int64 // mono.NanoTime()
 		startedUp   atomic.Bool
@@ -203,7 +209,8 @@ type (
 		}
 		numSamples int64
 		cumulative int64
-		isCommon   bool // optional, common to the proxy and target
+		hist       *histogram // optional, KindLatency only (see regHist)
+		isCommon   bool       // optional, common to the proxy and target
 	}
 	copyValue struct {
 		Value int64 `json:"v,string"`
@@ -364,6 +371,11 @@ func (s *CoreStats) initProm(node *cluster.Snode) {
 
 		fullqn := prometheus.BuildFQName("ais", node.Type(), id+"_"+v.label.prom)
 		s.promDesc[name] = prometheus.NewDesc(fullqn, help, nil /*variableLabels*/, nil /*constLabels*/)
+
+		if v.hist != nil {
+			histqn := strings.TrimSuffix(fullqn, "_ms") + "_latency_ms"
+			s.promDesc[name+histSuffix] = prometheus.NewDesc(histqn, "latency histogram (milliseconds)", nil, nil)
+		}
 	}
 }
 
@@ -396,11 +408,18 @@ func (s *CoreStats) doAdd(name, nameSuffix string, val int64) {
 		v.cumulative += val
 		v.Value += val
 		v.Unlock()
+		if v.hist != nil {
+			v.hist.observe(val)
+		}
 	case KindThroughput:
 		v.Lock()
 		v.cumulative += val
 		v.Value += val
 		v.Unlock()
+	case KindGauge: // e.g. backlog: incremented and decremented
+		v.Lock()
+		v.Value += val
+		v.Unlock()
 	case KindCounter:
 		v.Lock()
 		v.Value += val
@@ -442,6 +461,9 @@ func (s *CoreStats) copyT(ctracker copyTracker, idlePrefs []string) (idle bool)
 			if !s.isPrometheus() && millis > 0 && strings.HasSuffix(name, ".ns") {
 				s.statsdC.AppMetric(metric{Type: statsd.Timer, Name: v.label.stsd, Value: float64(millis)}, s.sgl)
 			}
+			if !s.isPrometheus() && v.hist != nil {
+				s.appHistPcts(v)
+			}
 		case KindThroughput, KindComputedThroughput:
 			var throughput int64
 			v.Lock()
@@ -502,6 +524,22 @@ func (s *CoreStats) copyT(ctracker copyTracker, idlePrefs []string) (idle bool)
 	return
 }
 
+// StatsD: tail latencies during the last stats interval, e.g. "aistarget.<id>.get.p99.ms"
+func (s *CoreStats) appHistPcts(v *statsValue) {
+	lh := v.hist.delta()
+	if lh.Count == 0 {
+		return
+	}
+	prefix := strings.TrimSuffix(v.label.stsd, ".ms")
+	for _, p := range []struct {
+		sfx string
+		val int64
+	}{{".p50.ms", lh.P50}, {".p90.ms", lh.P90}, {".p99.ms", lh.P99}} {
+		millis := float64(p.val) / float64(time.Millisecond)
+		s.statsdC.AppMetric(metric{Type: statsd.Timer, Name: prefix + p.sfx, Value: millis}, s.sgl)
+	}
+}
+
 // serves to satisfy REST API what=stats query
 func (s *CoreStats) copyCumulative(ctracker copyTracker) {
 	for name, v := range s.Tracker {
@@ -598,6 +636,13 @@ func (tracker statsTracker) register(node *cluster.Snode, name, kind string, isC
 	tracker[name] = v
 }
 
+// add latency histogram to an already registered KindLatency metric
+func (tracker statsTracker) regHist(name string) {
+	v, ok := tracker[name]
+	debug.Assertf(ok && v.kind == KindLatency, "invalid latency metric %q", name)
+	v.hist = &histogram{}
+}
+
 // register common metrics; see RegMetrics() in target_stats.go
 func (tracker statsTracker) regCommonMetrics(node *cluster.Snode) {
 	tracker.register(node, GetCount, KindCounter, true)
@@ -634,7 +679,17 @@ var (
 func (r *statsRunner) GetWhatStats() *DaemonStats {
 	ctracker := make(copyTracker, 48)
 	r.Core.copyCumulative(ctracker)
-	return &DaemonStats{Tracker: ctracker}
+	ds := &DaemonStats{Tracker: ctracker}
+	for name, v := range r.Core.Tracker {
+		if v.hist == nil {
+			continue
+		}
+		if ds.Latencies == nil {
+			ds.Latencies = make(LatencyHists, 8)
+		}
+		ds.Latencies[name] = v.hist.snap()
+	}
+	return ds
 }
 
 func (r *statsRunner) IsPrometheus() bool { return r.Core.isPrometheus() }
@@ -643,6 +698,9 @@ func (r *statsRunner) Describe(ch chan<- *prometheus.Desc) {
 	for _, desc := range r.Core.promDesc {
 		ch <- desc
 	}
+	if r.bcks != nil {
+		r.bcks.describe(ch)
+	}
 }
 
 func (r *statsRunner) Collect(ch chan<- prometheus.Metric) {
@@ -655,6 +713,11 @@ func (r *statsRunner) Collect(ch chan<- prometheus.Metric) {
 			val int64
 			fv  float64
 		)
+		if v.hist != nil {
+			m, err := v.hist.promMetric(r.Core.promDesc[name+histSuffix])
+			debug.AssertNoErr(err)
+			ch <- m
+		}
 		copyV, okc := r.ctracker[name]
 		if !okc {
 			continue
@@ -691,6 +754,9 @@ func (r *statsRunner) Collect(ch chan<- prometheus.Metric) {
 		ch <- m
 	}
 	r.Core.promRUnlock()
+	if r.bcks != nil {
+		r.bcks.collect(ch)
+	}
 }
 
 func (r *statsRunner) Name() string { return r.name }
@@ -0,0 +1,180 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2018-2021, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Latency histograms: in addition to the (interval) average, selected datapath
+// latencies (see `Trunner.RegMetrics`) are also accounted in a histogram with
+// fixed exponential buckets. The histograms are cumulative (since node startup)
+// and therefore mergeable across nodes - see `LatencyHist.Merge`.
+
+const (
+	histMinBound = 100 * time.Microsecond // upper bound of the first bucket
+	histNumBnds  = 20                     // doubling => the last bound ~52s
+)
+
+// HistBounds are the (inclusive) upper bounds of the histogram buckets, in nanoseconds;
+// the last bucket (not included) is +Inf.
+var HistBounds = func() []int64 {
+	bnds := make([]int64, histNumBnds)
+	for i := range bnds {
+		bnds[i] = int64(histMinBound) << i
+	}
+	return bnds
+}()
+
+type (
+	// REST API: latency histogram and its (estimated) percentiles
+	LatencyHist struct {
+		Count   int64   `json:"n,string"`
+		Sum     int64   `json:"ns,string"`
+		P50     int64   `json:"p50.ns,string"`
+		P90     int64   `json:"p90.ns,string"`
+		P99     int64   `json:"p99.ns,string"`
+		Buckets []int64 `json:"buckets"` // non-cumulative counts aligned with `HistBounds` (+Inf last)
+	}
+	LatencyHists map[string]*LatencyHist // by stats name (e.g., GetLatency)
+
+	histogram struct {
+		counts [histNumBnds + 1]atomic.Int64
+		sum    atomic.Int64
+		// previous (cumulative) counts - to compute interval percentiles (StatsD)
+		prev [histNumBnds + 1]int64
+	}
+)
+
+func (h *histogram) observe(val int64) {
+	i := sort.Search(histNumBnds, func(i int) bool { return val <= HistBounds[i] })
+	h.counts[i].Inc()
+	h.sum.Add(val)
+}
+
+func (h *histogram) snap() *LatencyHist {
+	lh := &LatencyHist{Buckets: make([]int64, histNumBnds+1), Sum: h.sum.Load()}
+	for i := range h.counts {
+		lh.Buckets[i] = h.counts[i].Load()
+	}
+	lh.update()
+	return lh
+}
+
+// interval histogram (since the previous call); not thread-safe - must be called
+// by the stats runner only
+func (h *histogram) delta() *LatencyHist {
+	lh := &LatencyHist{Buckets: make([]int64, histNumBnds+1)}
+	for i := range h.counts {
+		cnt := h.counts[i].Load()
+		lh.Buckets[i] = cnt - h.prev[i]
+		h.prev[i] = cnt
+	}
+	lh.update()
+	return lh
+}
+
+// Prometheus histogram: cumulative counts by upper bound in milliseconds
+func (h *histogram) promMetric(desc *prometheus.Desc) (prometheus.Metric, error) {
+	var (
+		cnt     uint64
+		buckets = make(map[float64]uint64, histNumBnds)
+	)
+	for i := range h.counts {
+		cnt += uint64(h.counts[i].Load())
+		if i < histNumBnds {
+			buckets[float64(HistBounds[i])/float64(time.Millisecond)] = cnt
+		}
+	}
+	sum := float64(h.sum.Load()) / float64(time.Millisecond)
+	return prometheus.NewConstHistogram(desc, cnt, sum, buckets)
+}
+
+/////////////////
+// LatencyHist //
+/////////////////
+
+// Merge adds up the buckets (e.g., to aggregate across targets) and recomputes the percentiles
+func (lh *LatencyHist) Merge(other *LatencyHist) {
+	if len(lh.Buckets) < len(other.Buckets) {
+		buckets := make([]int64, len(other.Buckets))
+		copy(buckets, lh.Buckets)
+		lh.Buckets = buckets
+	}
+	for i, cnt := range other.Buckets {
+		lh.Buckets[i] += cnt
+	}
+	lh.Sum += other.Sum
+	lh.update()
+}
+
+func (lh *LatencyHist) update() {
+	lh.Count = 0
+	for _, cnt := range lh.Buckets {
+		lh.Count += cnt
+	}
+	lh.P50, lh.P90, lh.P99 = lh.Percentile(50), lh.Percentile(90), lh.Percentile(99)
+}
+
+// Percentile estimates the given percentile (0 < pct <= 100) via linear interpolation
+// within the corresponding bucket; values in the +Inf bucket are reported as the last bound.
+func (lh *LatencyHist) Percentile(pct float64) int64 {
+	if lh.Count == 0 {
+		return 0
+	}
+	var (
+		rank = pct / 100 * float64(lh.Count)
+		cum  float64
+	)
+	for i, cnt := range lh.Buckets {
+		if cnt == 0 {
+			continue
+		}
+		if cum+float64(cnt) < rank {
+			cum += float64(cnt)
+			continue
+		}
+		if i >= len(HistBounds) {
+			break
+		}
+		var lower int64
+		if i > 0 {
+			lower = HistBounds[i-1]
+		}
+		upper := HistBounds[i]
+		return lower + int64(float64(upper-lower)*(rank-cum)/float64(cnt))
+	}
+	return HistBounds[len(HistBounds)-1]
+}
+
+//////////////////
+// ClusterStats //
+//////////////////
+
+// Latencies returns latency histograms (and percentiles) merged across all targets
+func (cs *ClusterStats) Latencies() LatencyHists {
+	out := make(LatencyHists, 8)
+	for _, ds := range cs.Target {
+		out.merge(ds.Latencies)
+	}
+	return out
+}
+
+func (lhs LatencyHists) merge(other LatencyHists) {
+	for name, lh := range other {
+		if total, ok := lhs[name]; ok {
+			total.Merge(lh)
+		} else {
+			cp := *lh
+			cp.Buckets = append([]int64(nil), lh.Buckets...)
+			lhs[name] = &cp
+		}
+	}
+}
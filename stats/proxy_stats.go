@@ -28,8 +28,11 @@ var _ cos.Runner = (*Prunner)(nil)
 
 func (r *Prunner) Run() error { return r.runcommon(r) }
 
-// NOTE: have only common metrics (see regCommon()) - init only the Prometheus part if used
+// NOTE: other than common metrics (see regCommon()), proxy only receives intra-cluster
+// streams (e.g., get-batch - see ais/prxbatch.go)
 func (r *Prunner) RegMetrics(node *cluster.Snode) {
+	r.Core.Tracker.register(node, StreamsInObjCount, KindCounter)
+	r.Core.Tracker.register(node, StreamsInObjSize, KindCounter)
 	r.Core.initProm(node)
 }
 
@@ -41,10 +41,11 @@ const (
 	VerChangeSize     = "vchange.size"
 
 	// intra-cluster transmit & receive
-	StreamsOutObjCount = transport.OutObjCount
-	StreamsOutObjSize  = transport.OutObjSize
-	StreamsInObjCount  = transport.InObjCount
-	StreamsInObjSize   = transport.InObjSize
+	StreamsOutObjCount   = transport.OutObjCount
+	StreamsOutObjSize    = transport.OutObjSize
+	StreamsInObjCount    = transport.InObjCount
+	StreamsInObjSize     = transport.InObjSize
+	StreamsOutObjLatency = transport.OutObjLatency
 
 	// errors
 	ErrCksumCount    = "err.cksum.n"
@@ -57,6 +58,7 @@ const (
 	// KindLatency
 	PutLatency      = "put.ns"
 	AppendLatency   = "append.ns"
+	GetColdLatency  = "get.cold.ns"
 	GetRedirLatency = "get.redir.ns"
 	PutRedirLatency = "put.redir.ns"
 	DownloadLatency = "dl.ns"
@@ -70,6 +72,13 @@ const (
 	// Downloader
 	DownloadSize = "dl.size"
 
+	// Replication (see cmn.ReplicationConf)
+	ReplCount    = "repl.n"
+	ReplSize     = "repl.size"
+	ReplErrCount = "err.repl.n"
+	ReplLatency  = "repl.ns"      // lag: time between enqueuing and replicating
+	ReplBacklog  = "repl.backlog" // KindGauge: number of queued (not yet replicated) operations
+
 	// KindThroughput
 	GetThroughput = "get.bps" // bytes per second
 )
@@ -104,6 +113,7 @@ func (r *Trunner) Init(t cluster.Target) *atomic.Bool {
 	r.Core.init(t.Snode(), 48) // register common (target's own stats are reg()-ed elsewhere)
 
 	r.ctracker = make(copyTracker, 48) // these two are allocated once and only used in serial context
+	r.bcks = newBckTracker()
 	r.lines = make([]string, 0, 16)
 	r.disk = make(ios.AllDiskStats, 16)
 
@@ -162,6 +172,7 @@ func (r *Trunner) RegMetrics(node *cluster.Snode) {
 	r.reg(AppendLatency, KindLatency)
 	r.reg(GetColdCount, KindCounter)
 	r.reg(GetColdSize, KindCounter)
+	r.reg(GetColdLatency, KindLatency)
 	r.reg(GetThroughput, KindThroughput)
 	r.reg(LruEvictSize, KindCounter)
 	r.reg(LruEvictCount, KindCounter)
@@ -184,6 +195,7 @@ func (r *Trunner) RegMetrics(node *cluster.Snode) {
 	r.reg(StreamsOutObjSize, KindCounter)
 	r.reg(StreamsInObjCount, KindCounter)
 	r.reg(StreamsInObjSize, KindCounter)
+	r.reg(StreamsOutObjLatency, KindLatency)
 
 	// special
 	r.reg(RestartCount, KindCounter)
@@ -198,16 +210,48 @@ func (r *Trunner) RegMetrics(node *cluster.Snode) {
 	r.reg(DSortCreationRespCount, KindCounter)
 	r.reg(DSortCreationRespLatency, KindLatency)
 
+	// replication
+	r.reg(ReplCount, KindCounter)
+	r.reg(ReplSize, KindCounter)
+	r.reg(ReplErrCount, KindCounter)
+	r.reg(ReplLatency, KindLatency)
+	r.reg(ReplBacklog, KindGauge)
+
+	// latency histograms (datapath)
+	for _, name := range []string{GetLatency, PutLatency, GetColdLatency, AppendLatency, StreamsOutObjLatency} {
+		r.Core.Tracker.regHist(name)
+	}
+
 	// Prometheus
 	r.Core.initProm(node)
+	if r.Core.isPrometheus() {
+		r.bcks.initProm(node)
+	}
 }
 
 func (r *Trunner) GetWhatStats() (ds *DaemonStats) {
 	ds = r.statsRunner.GetWhatStats()
 	ds.MPCap = r.MPCap
+	if cmn.GCO.Get().BckStats.Enabled {
+		ds.Buckets = r.bcks.snap()
+	}
 	return
 }
 
+// AddBck updates per-bucket stats (no-op unless enabled via `cmn.BckStatsConf`);
+// supported names: GetCount, GetSize, GetLatency, PutCount, PutSize, PutLatency,
+// DeleteCount, and ErrCount.
+func (r *Trunner) AddBck(bck *cmn.Bck, nvs ...cos.NamedVal64) {
+	config := cmn.GCO.Get()
+	if !config.BckStats.Enabled {
+		return
+	}
+	v := r.bcks.get(bck, config.BckStats.MaxBcks())
+	for _, nv := range nvs {
+		v.add(nv.Name, nv.Value)
+	}
+}
+
 func (r *Trunner) log(now int64, uptime time.Duration, config *cmn.Config) {
 	r.lines = r.lines[:0]
 
@@ -269,7 +313,10 @@ func (r *Trunner) log(now int64, uptime time.Duration, config *cmn.Config) {
 		r.lines = append(r.lines, mm.Str(&memStat))
 	}
 
-	// 5. log
+	// 6. per-bucket stats
+	r.bcks.housekeep(config, r.T.Bowner().Get())
+
+	// 7. log
 	for _, ln := range r.lines {
 		glog.Infoln(ln)
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
+	"github.com/NVIDIA/aistore/cmn/mono"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/pierrec/lz4/v3"
 )
@@ -36,9 +37,10 @@ type (
 		frameChecksum bool        // true: checksum lz4 frames
 	}
 	sendoff struct {
-		obj Obj
-		off int64
-		ins int // in-send enum
+		obj     Obj
+		off     int64
+		ins     int   // in-send enum
+		started int64 // mono.NanoTime() when dequeued (stats)
 	}
 	cmpl struct { // send completions => SCQ
 		obj Obj
@@ -227,6 +229,7 @@ repeat:
 			return
 		}
 		s.sendoff.obj = *obj
+		s.sendoff.started = mono.NanoTime()
 		obj = &s.sendoff.obj
 		if obj.Hdr.isIdleTick() {
 			if len(s.workCh) > 0 {
@@ -330,6 +333,7 @@ func (s *Stream) eoObj(err error) {
 	// target stats
 	statsTracker.Add(OutObjCount, 1)
 	statsTracker.Add(OutObjSize, objSize)
+	statsTracker.Add(OutObjLatency, mono.SinceNano(s.sendoff.started))
 exit:
 	if err != nil {
 		glog.Errorln(err)
@@ -11,10 +11,11 @@ import (
 )
 
 const (
-	OutObjCount = "streams.out.obj.n"
-	OutObjSize  = "streams.out.obj.size"
-	InObjCount  = "streams.in.obj.n"
-	InObjSize   = "streams.in.obj.size"
+	OutObjCount   = "streams.out.obj.n"
+	OutObjSize    = "streams.out.obj.size"
+	OutObjLatency = "streams.out.obj.ns" // from dequeuing the object to sending its last byte
+	InObjCount    = "streams.in.obj.n"
+	InObjSize     = "streams.in.obj.size"
 )
 
 type (
@@ -55,6 +55,9 @@ type (
 	QueryMsgLRU struct {
 		Force bool `json:"force"`
 	}
+	QueryMsgLcycle struct {
+		DryRun bool `json:"dry_run"`
+	}
 )
 
 // interface guard
@@ -38,6 +38,7 @@ var Table = map[string]Descriptor{
 	// bucket-less xactions that will typically have a 'cluster' scope (with resilver being a notable exception)
 	apc.ActLRU:          {Scope: ScopeG, Startable: true, Mountpath: true},
 	apc.ActStoreCleanup: {Scope: ScopeG, Startable: true, Mountpath: true},
+	apc.ActLifecycle:    {Scope: ScopeG, Startable: true, Mountpath: true},
 	apc.ActElection:     {Scope: ScopeG, Startable: false},
 	apc.ActResilver:     {Scope: ScopeT, Startable: true, Mountpath: true, Resilver: true},
 	apc.ActRebalance:    {Scope: ScopeG, Startable: true, Metasync: true, Owned: false, Mountpath: true, Rebalance: true},
@@ -50,6 +51,7 @@ var Table = map[string]Descriptor{
 	apc.ActECRespond:       {Scope: ScopeBck, Startable: false},
 	apc.ActMakeNCopies:     {Scope: ScopeBck, Access: apc.AccessRW, Startable: true, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true},
 	apc.ActPutCopies:       {Scope: ScopeBck, Startable: false, Mountpath: true, RefreshCap: true},
+	apc.ActReplicate:       {Scope: ScopeBck, Startable: false},
 	apc.ActArchive:         {Scope: ScopeBck, Startable: false, RefreshCap: true},
 	apc.ActCopyObjects:     {Scope: ScopeBck, Startable: false, RefreshCap: true},
 	apc.ActETLObjects:      {Scope: ScopeBck, Startable: false, RefreshCap: true},
@@ -57,6 +59,7 @@ var Table = map[string]Descriptor{
 	apc.ActCopyBck:         {Scope: ScopeBck, Access: apc.AccessRW, Startable: false, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true, MassiveBck: true},
 	apc.ActETLBck:          {Scope: ScopeBck, Access: apc.AccessRW, Startable: false, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true, MassiveBck: true},
 	apc.ActECEncode:        {Scope: ScopeBck, Access: apc.AccessRW, Startable: true, Metasync: true, Owned: false, RefreshCap: true, Mountpath: true, MassiveBck: true},
+	apc.ActECScrub:         {Scope: ScopeBck, Access: apc.AccessRW, Startable: true, RefreshCap: true, Mountpath: true},
 	apc.ActEvictObjects:    {Scope: ScopeBck, Access: apc.AceObjDELETE, Startable: false, RefreshCap: true, Mountpath: true},
 	apc.ActDeleteObjects:   {Scope: ScopeBck, Access: apc.AceObjDELETE, Startable: false, RefreshCap: true, Mountpath: true},
 	apc.ActLoadLomCache:    {Scope: ScopeBck, Startable: true, Mountpath: true},
@@ -8,6 +8,7 @@ import (
 	"github.com/NVIDIA/aistore/api/apc"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/cos"
 	"github.com/NVIDIA/aistore/cmn/debug"
 	"github.com/NVIDIA/aistore/xact"
 )
@@ -70,6 +71,10 @@ func RenewECEncode(t cluster.Target, bck *cluster.Bck, uuid, phase string) Renew
 	return RenewBucketXact(apc.ActECEncode, bck, Args{t, uuid, &ECEncodeArgs{Phase: phase}})
 }
 
+func RenewECScrub(t cluster.Target, bck *cluster.Bck, uuid string) RenewRes {
+	return RenewBucketXact(apc.ActECScrub, bck, Args{T: t, UUID: uuid})
+}
+
 func RenewMakeNCopies(t cluster.Target, uuid, tag string) {
 	var (
 		cfg      = cmn.GCO.Get()
@@ -116,6 +121,10 @@ func RenewPutMirror(t cluster.Target, lom *cluster.LOM) RenewRes {
 	return RenewBucketXact(apc.ActPutCopies, lom.Bck(), Args{T: t, Custom: lom})
 }
 
+func RenewReplicate(t cluster.Target, bck *cluster.Bck, statsT cos.StatsTracker) RenewRes {
+	return RenewBucketXact(apc.ActReplicate, bck, Args{T: t, Custom: statsT})
+}
+
 func RenewTCB(t cluster.Target, uuid, kind string, custom *TCBArgs) RenewRes {
 	return RenewBucketXact(kind, custom.BckTo /*NOTE: to not from*/, Args{t, uuid, custom})
 }
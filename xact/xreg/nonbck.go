@@ -55,6 +55,11 @@ func RenewStoreCleanup(id string) RenewRes {
 	return dreg.renew(e, nil)
 }
 
+func RenewLifecycle(id string, dryRun bool) RenewRes {
+	e := dreg.nonbckXacts[apc.ActLifecycle].New(Args{UUID: id, Custom: dryRun}, nil)
+	return dreg.renew(e, nil)
+}
+
 func RenewDownloader(t cluster.Target, statsT stats.Tracker) RenewRes {
 	e := dreg.nonbckXacts[apc.ActDownload].New(Args{T: t, Custom: statsT}, nil)
 	return dreg.renew(e, nil)
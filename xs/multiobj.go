@@ -276,7 +276,12 @@ func (r *evictDelete) do(lom *cluster.LOM, _ *lriterator) {
 		return
 	}
 	if err != nil {
-		if !cmn.IsErrObjNought(err) {
+		switch {
+		case cmn.IsErrObjRetained(err): // object lock: skip retained objects
+			if verbose {
+				glog.Infof("%s: %v", r, err)
+			}
+		case !cmn.IsErrObjNought(err):
 			glog.Warning(err)
 		}
 		return
@@ -401,7 +401,7 @@ func (r *ObjListXact) traverseBucket(msg *apc.ListObjsMsg) {
 		if !msg.IsFlagSet(apc.LsArchDir) {
 			return nil
 		}
-		archList, err := listArchive(fqn)
+		archList, err := listArchive(fqn, r.Bck().Bucket())
 		if archList == nil || err != nil {
 			return err
 		}
@@ -421,7 +421,7 @@ func (r *ObjListXact) traverseBucket(msg *apc.ListObjsMsg) {
 		return nil
 	}
 	opts := &fs.WalkBckOpts{
-		WalkOpts: fs.WalkOpts{CTs: []string{fs.ObjectType}, Callback: cb, Sorted: true},
+		WalkOpts: fs.WalkOpts{CTs: []string{wi.ContentType()}, Callback: cb, Sorted: true},
 	}
 	opts.WalkOpts.Bck.Copy(r.Bck().Bucket())
 	opts.ValidateCallback = func(fqn string, de fs.DirEntry) error {
@@ -439,7 +439,7 @@ func (r *ObjListXact) traverseBucket(msg *apc.ListObjsMsg) {
 	close(r.objCache)
 }
 
-func listArchive(fqn string) ([]*archEntry, error) {
+func listArchive(fqn string, bck *cmn.Bck) ([]*archEntry, error) {
 	var arch string
 	for _, ext := range cos.ArchExtensions {
 		if strings.HasSuffix(fqn, ext) {
@@ -450,11 +450,13 @@ func listArchive(fqn string) ([]*archEntry, error) {
 	if arch == "" {
 		return nil, nil
 	}
-	// list the archive content
-	var (
-		archList []*archEntry
-		finfo    os.FileInfo
-	)
+	// use (and build, if need be) the archive's index
+	archList, err := listArchIdx(fqn, bck, arch)
+	if err == nil || !cluster.IsErrArchIdxUnsupported(err) {
+		return archList, err
+	}
+	// otherwise, list the archive content
+	var finfo os.FileInfo
 	f, err := os.Open(fqn)
 	if err == nil {
 		switch arch {
@@ -482,6 +484,25 @@ func listArchive(fqn string) ([]*archEntry, error) {
 	return archList, nil
 }
 
+func listArchIdx(fqn string, bck *cmn.Bck, mime string) ([]*archEntry, error) {
+	lom := cluster.AllocLOM("")
+	defer cluster.FreeLOM(lom)
+	if err := lom.InitFQN(fqn, bck); err != nil {
+		return nil, err
+	}
+	lom.Lock(false)
+	idx, err := lom.ArchIdx(mime)
+	lom.Unlock(false)
+	if err != nil {
+		return nil, err
+	}
+	archList := make([]*archEntry, 0, len(idx.Entries))
+	for _, e := range idx.Entries {
+		archList = append(archList, &archEntry{name: e.Name, size: uint64(e.Size)}) // (sorted)
+	}
+	return archList, nil
+}
+
 //
 // list: tar, tgz, zip, msgpack
 //